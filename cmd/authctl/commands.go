@@ -0,0 +1,382 @@
+package main
+
+import (
+	"auth/internal/repository"
+	"auth/internal/service/audit"
+	"auth/internal/service/useradmin"
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// command runs a command with the arguments after its name.
+type command func(ctx context.Context, e *env, args []string) (*result, error)
+
+var commands = map[string]command{
+	"user": subcommands(map[string]command{
+		"create":          userCreate,
+		"show":            userShow,
+		"disable":         userDisable,
+		"restore":         userRestore,
+		"reset-password":  userResetPassword,
+		"revoke-sessions": userRevokeSessions,
+		"export":          userExport,
+		"import":          userImport,
+	}),
+	"role": subcommands(map[string]command{
+		"add":    roleAdd,
+		"remove": roleRemove,
+	}),
+	"keys": subcommands(map[string]command{
+		"list":   keysList,
+		"rotate": keysRotate,
+	}),
+	"migrate": migrate,
+	"tokens": subcommands(map[string]command{
+		"purge": tokensPurge,
+	}),
+}
+
+func subcommands(cmds map[string]command) command {
+	return func(ctx context.Context, e *env, args []string) (*result, error) {
+		if len(args) == 0 {
+			return nil, fmt.Errorf("%w: missing subcommand", errUsage)
+		}
+		cmd, ok := cmds[args[0]]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown subcommand %q", errUsage, args[0])
+		}
+		return cmd(ctx, e, args[1:])
+	}
+}
+
+// parse parses the flags of a command and checks that nargs positional arguments follow them.
+func parse(fs *flag.FlagSet, args []string, nargs int) error {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() != nargs {
+		return fmt.Errorf("%w: %s takes %d argument(s)", errUsage, fs.Name(), nargs)
+	}
+	return nil
+}
+
+// readPassword reads the first line of stdin, so passwords stay out of the shell history.
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	pw := strings.TrimRight(line, "\r\n")
+	if pw == "" {
+		return "", errors.New("no password on stdin")
+	}
+	return pw, nil
+}
+
+// userText describes a user in one line.
+func userText(u *useradmin.User) string {
+	status := "active"
+	switch {
+	case u.DeletedAt != nil:
+		status = "deleted"
+	case u.DisabledAt != nil:
+		status = "disabled"
+	}
+	roles := "-"
+	if len(u.Roles) > 0 {
+		roles = strings.Join(u.Roles, ",")
+	}
+	return fmt.Sprintf("%d\t%s\t%s\troles=%s", u.ID, u.Email, status, roles)
+}
+
+func userResult(u *useradmin.User) *result {
+	return &result{value: u, text: userText(u)}
+}
+
+func userCreate(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	in := useradmin.CreateUserInput{}
+	fs.StringVar(&in.Email, "email", "", "email")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	roles := fs.String("role", "", "comma separated roles")
+	fs.StringVar(&in.Name, "name", "", "profile name")
+	fs.StringVar(&in.PhoneNumber, "phone", "", "profile phone number")
+	fs.StringVar(&in.BirthDate, "birth-date", "", "profile birth date, YYYY-MM-DD")
+	fs.StringVar(&in.GenderCode, "gender", "", "profile gender code")
+	if err := parse(fs, args, 0); err != nil {
+		return nil, err
+	}
+	if in.Email == "" {
+		return nil, fmt.Errorf("%w: -email is required", errUsage)
+	}
+	if *roles != "" {
+		in.Roles = strings.Split(*roles, ",")
+	}
+	if *passwordStdin {
+		pw, err := readPassword(e.stdin)
+		if err != nil {
+			return nil, err
+		}
+		in.Password = pw
+	}
+	u, generated, err := e.users.CreateUser(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	res := &result{
+		value: struct {
+			User     *useradmin.User `json:"user"`
+			Password string          `json:"password,omitempty"`
+		}{u, generated},
+		text: userText(u),
+	}
+	if generated != "" {
+		res.text += "\npassword: " + generated
+	}
+	return res, nil
+}
+
+// userCommand is a command on one user given as its only argument.
+func userCommand(name string, fn func(*useradmin.Service, context.Context, string) (*useradmin.User, error)) command {
+	return func(ctx context.Context, e *env, args []string) (*result, error) {
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		if err := parse(fs, args, 1); err != nil {
+			return nil, err
+		}
+		u, err := fn(e.users, ctx, fs.Arg(0))
+		if err != nil {
+			return nil, err
+		}
+		return userResult(u), nil
+	}
+}
+
+var (
+	userShow           = userCommand("user show", (*useradmin.Service).Find)
+	userDisable        = userCommand("user disable", (*useradmin.Service).Disable)
+	userRestore        = userCommand("user restore", (*useradmin.Service).Restore)
+	userRevokeSessions = userCommand("user revoke-sessions", (*useradmin.Service).RevokeSessions)
+)
+
+func userResetPassword(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	if err := parse(fs, args, 1); err != nil {
+		return nil, err
+	}
+	var pw string
+	if *passwordStdin {
+		var err error
+		if pw, err = readPassword(e.stdin); err != nil {
+			return nil, err
+		}
+	}
+	generated, err := e.users.ResetPassword(ctx, fs.Arg(0), pw)
+	if err != nil {
+		return nil, err
+	}
+	res := &result{
+		value: struct {
+			Password string `json:"password,omitempty"`
+		}{generated},
+		text: "password reset, sessions revoked",
+	}
+	if generated != "" {
+		res.text += "\npassword: " + generated
+	}
+	return res, nil
+}
+
+func userExport(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("user export", flag.ContinueOnError)
+	out := fs.String("out", "-", `output file ("-" for stdout)`)
+	if err := parse(fs, args, 0); err != nil {
+		return nil, err
+	}
+	if *out == "-" {
+		// 표준 출력은 데이터만 쓰고 결과는 남기지 않는다
+		n, err := e.users.Export(ctx, os.Stdout)
+		if err == nil {
+			fmt.Fprintf(os.Stderr, "exported %d users\n", n)
+		}
+		return nil, err
+	}
+	// 비밀번호 해시가 들어 있으므로 소유자만 읽을 수 있게 만든다
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(f)
+	n, err := e.users.Export(ctx, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result{
+		value: map[string]any{"exported": n, "file": *out},
+		text:  fmt.Sprintf("exported %d users to %s", n, *out),
+	}, nil
+}
+
+func userImport(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("user import", flag.ContinueOnError)
+	in := fs.String("in", "-", `input file ("-" for stdin)`)
+	if err := parse(fs, args, 0); err != nil {
+		return nil, err
+	}
+	r := e.stdin
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		r = f
+	}
+	res, err := e.users.Import(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	text := fmt.Sprintf("created %d, skipped %d existing, failed %d", res.Created, res.Skipped, res.Failed)
+	for _, ie := range res.Errors {
+		text += fmt.Sprintf("\nline %d %s: %s", ie.Line, ie.Email, ie.Error)
+	}
+	if res.Failed > 0 {
+		// 결과도 출력하되 실패한 줄이 있으면 0 이 아닌 상태로 끝낸다
+		return &result{value: res, text: text}, fmt.Errorf("%d users could not be imported", res.Failed)
+	}
+	return &result{value: res, text: text}, nil
+}
+
+func roleCommand(name string, fn func(*useradmin.Service, context.Context, string, string) (*useradmin.User, error)) command {
+	return func(ctx context.Context, e *env, args []string) (*result, error) {
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		if err := parse(fs, args, 2); err != nil {
+			return nil, err
+		}
+		u, err := fn(e.users, ctx, fs.Arg(0), fs.Arg(1))
+		if err != nil {
+			return nil, err
+		}
+		return userResult(u), nil
+	}
+}
+
+var (
+	roleAdd    = roleCommand("role add", (*useradmin.Service).AddRole)
+	roleRemove = roleCommand("role remove", (*useradmin.Service).RemoveRole)
+)
+
+// keyInfo describes a signing key without its private key.
+type keyInfo struct {
+	ID        string     `json:"kid"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RetiredAt *time.Time `json:"retiredAt,omitempty"`
+	Signing   bool       `json:"signing"`
+}
+
+func keysList(ctx context.Context, e *env, args []string) (*result, error) {
+	if err := parse(flag.NewFlagSet("keys list", flag.ContinueOnError), args, 0); err != nil {
+		return nil, err
+	}
+	keys := e.keySet()
+	if err := keys.Load(ctx); err != nil {
+		return nil, err
+	}
+	signing := keys.SigningKey()
+	infos := []keyInfo{}
+	lines := []string{}
+	for _, k := range keys.Keys() {
+		info := keyInfo{ID: k.ID, CreatedAt: k.CreatedAt, RetiredAt: k.RetiredAt, Signing: signing != nil && k.ID == signing.ID}
+		infos = append(infos, info)
+		state := "retired"
+		if info.Signing {
+			state = "signing"
+		}
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s", k.ID, k.CreatedAt.Format(time.RFC3339), state))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "no signing keys")
+	}
+	return &result{value: infos, text: strings.Join(lines, "\n")}, nil
+}
+
+func keysRotate(ctx context.Context, e *env, args []string) (*result, error) {
+	if err := parse(flag.NewFlagSet("keys rotate", flag.ContinueOnError), args, 0); err != nil {
+		return nil, err
+	}
+	k, err := e.keySet().Rotate(ctx)
+	ev := audit.Event{Type: audit.EventAdminKeyRotate, Actor: audit.ActorAdmin, Outcome: audit.OutcomeSuccess}
+	if err != nil {
+		ev.Outcome = audit.OutcomeError
+		e.auditLog.Record(ctx, ev)
+		return nil, err
+	}
+	ev.Details = map[string]string{"kid": k.ID}
+	e.auditLog.Record(ctx, ev)
+	return &result{
+		value: keyInfo{ID: k.ID, CreatedAt: k.CreatedAt, Signing: true},
+		text:  fmt.Sprintf("new signing key %s, servers switch to it within JWT_KEY_RELOAD_INTERVAL_SECONDS", k.ID),
+	}, nil
+}
+
+func migrate(ctx context.Context, e *env, args []string) (*result, error) {
+	if err := parse(flag.NewFlagSet("migrate", flag.ContinueOnError), args, 0); err != nil {
+		return nil, err
+	}
+	// 테이블은 open 에서 저장소를 만들 때 생성되었다
+	missing, err := repository.MissingTables(ctx, e.cfg.DBType, e.dbPool, e.sqliteConn)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("tables could not be created, see the log: %s", strings.Join(missing, ", "))
+	}
+	return &result{
+		value: map[string]any{"tables": repository.Tables},
+		text:  fmt.Sprintf("schema up to date, %d tables", len(repository.Tables)),
+	}, nil
+}
+
+func tokensPurge(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("tokens purge", flag.ContinueOnError)
+	batchSize := fs.Int("batch-size", e.cfg.TokenPurgeBatchSize, "rows deleted per statement")
+	if err := parse(fs, args, 0); err != nil {
+		return nil, err
+	}
+	if *batchSize <= 0 {
+		return nil, fmt.Errorf("%w: -batch-size must be positive", errUsage)
+	}
+	j := e.janitor()
+	j.BatchSize = *batchSize
+	res, ran, err := j.RunOnce(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ran {
+		return &result{
+			value: map[string]any{"skipped": true},
+			text:  "skipped, another instance is purging",
+		}, nil
+	}
+	return &result{
+		value: res,
+		text: fmt.Sprintf("purged %d refresh, %d password reset and %d magic link tokens",
+			res.RefreshTokens, res.PasswordResetTokens, res.MagicLinkTokens),
+	}, nil
+}
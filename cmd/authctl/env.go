@@ -0,0 +1,117 @@
+package main
+
+import (
+	"auth/internal/config"
+	"auth/internal/repository"
+	"auth/internal/service/audit"
+	"auth/internal/service/janitor"
+	"auth/internal/service/jwks"
+	"auth/internal/service/password"
+	"auth/internal/service/phone"
+	"auth/internal/service/useradmin"
+	"auth/pkg/database"
+	"auth/pkg/utils"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// env is the database and the services the commands share, set up like the server's.
+type env struct {
+	cfg        config.Config
+	dbPool     *pgxpool.Pool
+	sqliteConn interface{}
+	stdin      io.Reader
+
+	users       *useradmin.Service
+	admin       repository.UserAdminRepository
+	signingKeys repository.SigningKeyRepository
+	auditLog    *audit.Service
+}
+
+// open connects to the database of cfg. Constructing the repositories creates missing tables,
+// as on server start.
+func open(cfg config.Config) (*env, error) {
+	e := &env{cfg: cfg}
+	switch cfg.DBType {
+	case "sqlite":
+		if err := database.ConnectSqlite(cfg.SqlitePath); err != nil {
+			return nil, err
+		}
+		e.sqliteConn = database.GetSqliteConn()
+	case "postgres":
+		if err := database.Connect(cfg.DatabaseURL); err != nil {
+			return nil, err
+		}
+		e.dbPool = database.GetPool()
+	default:
+		return nil, fmt.Errorf("지원하지 않는 DB_TYPE: %s", cfg.DBType)
+	}
+
+	userRepo := repository.NewUserRepositoryAuto(cfg.DBType, e.dbPool, e.sqliteConn)
+	profileRepo := repository.NewProfileRepositoryAuto(cfg.DBType, e.dbPool, e.sqliteConn)
+	repository.NewPhoneVerificationRepositoryAuto(cfg.DBType, e.dbPool, e.sqliteConn)
+	repository.NewOutboxRepositoryAuto(cfg.DBType, e.dbPool, e.sqliteConn)
+	auditRepo := repository.NewAuditRepositoryAuto(cfg.DBType, e.dbPool, e.sqliteConn)
+	repository.NewWebhookRepositoryAuto(cfg.DBType, e.dbPool, e.sqliteConn)
+	e.signingKeys = repository.NewSigningKeyRepositoryAuto(cfg.DBType, e.dbPool, e.sqliteConn)
+	e.admin = repository.NewUserAdminRepositoryAuto(cfg.DBType, e.dbPool, e.sqliteConn)
+	e.auditLog = audit.NewService(auditRepo)
+
+	argon2Params, err := utils.NewArgon2Params(cfg.Argon2Memory, cfg.Argon2Iterations, cfg.Argon2Parallelism)
+	if err != nil {
+		e.close()
+		return nil, err
+	}
+	hasher, err := utils.NewPasswordHasher(cfg.PasswordHashAlgorithm, cfg.BcryptCost, argon2Params)
+	if err != nil {
+		e.close()
+		return nil, err
+	}
+	phones, err := phone.NewParser(cfg.PhoneDefaultRegion)
+	if err != nil {
+		e.close()
+		return nil, err
+	}
+	e.users = useradmin.NewService(e.dbPool, userRepo, e.admin, profileRepo,
+		useradmin.WithPasswordHasher(hasher),
+		useradmin.WithPhoneParser(phones),
+		useradmin.WithAuditLog(e.auditLog),
+		useradmin.WithPasswordPolicy(password.Policy{
+			MinLength:          cfg.PasswordMinLength,
+			MaxLength:          cfg.PasswordMaxLength,
+			RequireUpper:       cfg.PasswordRequireUpper,
+			RequireLower:       cfg.PasswordRequireLower,
+			RequireDigit:       cfg.PasswordRequireDigit,
+			RequireSymbol:      cfg.PasswordRequireSymbol,
+			MinCharClasses:     cfg.PasswordMinCharClasses,
+			MinStrength:        cfg.PasswordMinStrength,
+			RejectPersonalInfo: true,
+			HistorySize:        cfg.PasswordHistorySize,
+		}),
+	)
+	return e, nil
+}
+
+// janitor returns the token janitor, which skips the purge while a server instance is purging.
+func (e *env) janitor() *janitor.Janitor {
+	return janitor.New(e.admin, repository.NewLockerAuto(e.cfg.DBType, e.dbPool, e.sqliteConn))
+}
+
+// keySet returns the signing keys with the server's retention.
+func (e *env) keySet() *jwks.KeySet {
+	keys := jwks.NewKeySet(e.signingKeys)
+	keys.RetainFor = time.Duration(e.cfg.JWTKeyRetention) * time.Second
+	return keys
+}
+
+func (e *env) close() {
+	if e.dbPool != nil {
+		e.dbPool.Close()
+	}
+	if e.sqliteConn != nil {
+		_ = database.CloseSqlite()
+	}
+}
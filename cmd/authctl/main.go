@@ -0,0 +1,138 @@
+// Package main is authctl, the command line tool for operating the authentication service. It
+// reads the same configuration as the server and works on its database directly, so it can be
+// used while the server is down, e.g. to create the first administrator.
+//
+// Usage:
+//
+//	go run ./cmd/authctl user create -email admin@example.com -role admin
+//	go run ./cmd/authctl -json user show admin@example.com
+//	go run ./cmd/authctl user export -out users.jsonl
+//
+// Run authctl -h for all commands.
+package main
+
+import (
+	"auth/internal/config"
+	"auth/internal/logging"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `usage: authctl [-json] <command> [flags] [args]
+
+Users (USER is a user ID or email):
+  user create -email EMAIL [-password-stdin] [-role ROLE,...]
+              [-name NAME -phone PHONE [-birth-date YYYY-MM-DD] [-gender M|F|O|N|U]]
+  user show USER
+  user disable USER            stop signing in and revoke sessions
+  user restore USER            undo disable or account deletion
+  user reset-password [-password-stdin] USER
+  user revoke-sessions USER
+  user export [-out FILE]      JSON lines with password hashes, keep it safe
+  user import [-in FILE]       skips emails that already exist
+  role add USER ROLE
+  role remove USER ROLE
+
+Operations:
+  keys list                    access token signing keys (JWT_SIGNING_ALGORITHM=ES256)
+  keys rotate
+  migrate                      create missing tables and report the schema
+  tokens purge [-batch-size N] delete expired refresh, reset and magic link tokens
+
+Passwords that are not read from stdin are generated and printed once.
+With -json, results are written to stdout as JSON; errors always go to stderr.
+`
+
+// exitUsage is the exit status for invalid command lines, as with the flag package.
+const exitUsage = 2
+
+// errUsage is returned by commands for invalid arguments; the usage is printed with it.
+var errUsage = errors.New("invalid arguments")
+
+// result is the output of a command: the value written with -json, and the text otherwise.
+// A command may return a result with an error, e.g. an import where some lines failed.
+type result struct {
+	value any
+	text  string
+}
+
+func main() {
+	flags := flag.NewFlagSet("authctl", flag.ExitOnError)
+	jsonOut := flags.Bool("json", false, "write results as JSON")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+	}
+	_ = flags.Parse(os.Args[1:])
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(exitUsage)
+	}
+
+	cfg := config.LoadConfig()
+	logHandler, err := logging.NewHandler(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid logging configuration:", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(slog.New(logHandler))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	res, err := run(ctx, cfg, flags.Args(), os.Stdin)
+	// 일부만 실패한 명령도 결과가 있으면 출력한다
+	if writeErr := write(os.Stdout, res, *jsonOut); writeErr != nil && err == nil {
+		err = writeErr
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "authctl:", err)
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(exitUsage)
+		}
+		os.Exit(1)
+	}
+}
+
+// write prints the result as indented JSON or as its text.
+func write(w io.Writer, res *result, asJSON bool) error {
+	if res == nil {
+		return nil
+	}
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(res.value)
+	}
+	if res.text == "" {
+		return nil
+	}
+	_, err := fmt.Fprintln(w, res.text)
+	return err
+}
+
+// run opens the database and runs the command in args.
+func run(ctx context.Context, cfg config.Config, args []string, stdin io.Reader) (*result, error) {
+	if len(args) == 0 {
+		return nil, errUsage
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown command %q", errUsage, args[0])
+	}
+	e, err := open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer e.close()
+	e.stdin = stdin
+	return cmd(ctx, e, args[1:])
+}
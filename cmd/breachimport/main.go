@@ -0,0 +1,43 @@
+// Package main imports a Have I Been Pwned password hash list into the local breach corpus.
+//
+// Usage:
+//
+//	go run ./cmd/breachimport -in pwned-passwords-sha1-ordered-by-hash.txt
+//	cat hashes.txt | go run ./cmd/breachimport -dir ./data/pwned
+package main
+
+import (
+	"auth/internal/config"
+	"auth/internal/service/password"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	cfg := config.LoadConfig()
+	in := flag.String("in", "-", `"SHA1:COUNT" hash list to import ("-" for stdin)`)
+	dir := flag.String("dir", cfg.BreachCorpusDir, "corpus directory (BREACH_CORPUS_DIR)")
+	flag.Parse()
+
+	var r io.Reader = os.Stdin
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "open input:", err)
+			os.Exit(1)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		r = f
+	}
+
+	n, err := password.ImportCorpus(r, *dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed after %d hashes: %v\n", n, err)
+		os.Exit(1)
+	}
+	fmt.Printf("imported %d hashes into %s\n", n, *dir)
+}
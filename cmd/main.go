@@ -3,14 +3,64 @@ package main
 
 import (
 	"auth/internal/config"
+	"auth/internal/logging"
 	"auth/internal/server"
+	"context"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func main() {
 	cfg := config.LoadConfig()
+	logHandler, err := logging.NewHandler(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		slog.Error("invalid logging configuration", "error", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(slog.New(logHandler))
+	slog.Info("Configuration loaded successfully", "config", cfg)
+
 	server := server.NewServer(cfg)
-	defer server.Close()
-	if err := server.App.Listen(":" + cfg.Port); err != nil {
-		panic(err)
+
+	listenErr := make(chan error, 2)
+	go func() {
+		slog.Info("server listening", "port", cfg.Port)
+		listenErr <- server.App.Listen(":" + cfg.Port)
+	}()
+	if server.GRPC != nil {
+		lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			slog.Error("grpc listen failed", "port", cfg.GRPCPort, "error", err)
+			server.Close()
+			os.Exit(1)
+		}
+		go func() {
+			slog.Info("grpc server listening", "port", cfg.GRPCPort)
+			listenErr <- server.GRPC.Serve(lis)
+		}()
+	}
+
+	// SIGINT/SIGTERM 수신 시 새 연결을 받지 않고, 처리 중인 요청을 SHUTDOWN_TIMEOUT_SECONDS 까지 기다린 뒤
+	// outbox 디스패처와 DB 연결을 정리한다
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	select {
+	case s := <-sig:
+		slog.Info("shutting down", "signal", s.String(), "timeout", cfg.ShutdownTimeout)
+	case err := <-listenErr:
+		slog.Error("server stopped", "error", err)
+		server.Close()
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout)*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Error("server shutdown failed", "error", err)
 	}
+	slog.Info("server stopped")
 }
@@ -3,25 +3,110 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 
-	"github.com/gofiber/fiber/v2/log"
 	"github.com/joho/godotenv"
 )
 
 // Config 환경 변수 구조체
 // Config holds all environment variables for the application.
 type Config struct {
+	AppEnv       string // "dev" 이면 개발용 API(/dev/...) 활성화
 	Port         string
+	GRPCPort     string // gRPC API 포트, 비어 있으면 gRPC 서버를 띄우지 않음 (기본값)
 	JwtSecret    string
 	SMTPServer   string
 	SMTPPort     string
 	SMTPID       string
 	SMTPPassword string
+	SMTPSecurity string // "starttls", "tls" or "none"
+	SMTPAuth     string // "plain", "login", "cram-md5" or "none"
 	DatabaseURL  string
 	DBType       string // "postgres" or "sqlite"
 	SqlitePath   string // sqlite 파일 경로
+
+	PasswordHashAlgorithm string // "argon2id" or "bcrypt"
+	BcryptCost            int
+	Argon2Memory          int // KiB
+	Argon2Iterations      int
+	Argon2Parallelism     int
+
+	PasswordMinLength      int
+	PasswordMaxLength      int
+	PasswordRequireUpper   bool
+	PasswordRequireLower   bool
+	PasswordRequireDigit   bool
+	PasswordRequireSymbol  bool
+	PasswordMinCharClasses int
+	PasswordMinStrength    int // 0~4
+	PasswordHistorySize    int
+
+	BreachCheck     string // "off", "local" or "api"
+	BreachCorpusDir string // HIBP 코퍼스 파티션 디렉토리 (local)
+	BreachAPIURL    string // Pwned Passwords range API 주소 (api)
+
+	MailProvider string // "smtp", "api" or "file"
+	MailFrom     string // 발신 주소, 기본값 SMTP_ID
+	MailFromName string
+	MailAPIURL   string // SendGrid v3 호환 API 주소 (api)
+	MailAPIKey   string
+	MailFileDir  string // .eml 파일을 저장할 디렉토리 (file)
+
+	MailTemplateDir   string // 내장 메일 템플릿을 덮어쓸 디렉토리, 비어 있으면 내장 템플릿만 사용
+	MailDefaultLocale string // 프로필과 Accept-Language 모두 맞지 않을 때의 메일 언어
+	BrandName         string
+	BrandURL          string
+	BrandLogoURL      string
+	BrandSupportEmail string // 기본값 MAIL_FROM
+	BrandColor        string
+
+	PublicBaseURL     string            // 메일 링크의 프론트엔드 주소
+	LinkPaths         map[string]string // 흐름별 경로 템플릿, 예: "/auth/reset/{token}"
+	RedirectAllowlist []string          // 클라이언트가 지정할 수 있는 redirect 주소(origin 또는 prefix)
+	LinkSigningSecret string            // 링크 파라미터 서명 키, 비어 있으면 JWT_SECRET 에서 유도
+
+	OutboxEnabled     bool // false 이면 메일을 요청 처리 중에 바로 발송
+	OutboxInterval    int  // 초
+	OutboxMaxAttempts int
+
+	AdminAPIKey string // 비어 있으면 /admin API 비활성화
+
+	JWTSigningAlgorithm  string // "HS256" (JWT_SECRET) or "ES256" (DB 서명 키, JWKS 공개)
+	JWTKeyReloadInterval int    // 초, 다른 인스턴스가 교체한 서명 키를 다시 읽는 주기
+	JWTKeyRetention      int    // 초, 교체된 서명 키를 JWKS 에 남겨 두는 기간
+
+	WebhookInterval    int // 초
+	WebhookMaxAttempts int
+	WebhookTimeout     int // 초, 요청 한 번의 제한 시간
+
+	SMSProvider string // "log" or "file"
+	SMSFilePath string // file 공급자가 메시지를 기록할 경로
+
+	PhoneDefaultRegion string // 국가 코드 없이 입력한 전화번호의 지역 (ISO 3166-1 alpha-2)
+
+	MetricsEnabled bool // true 이면 /metrics 에서 Prometheus 지표 제공
+
+	GRPCReflection bool // true 이면 gRPC 서버 리플렉션 등록 (grpcurl 등 개발 도구용)
+
+	AuditRetentionDays int // 감사 로그 보존 기간(일), 0 이면 삭제하지 않음
+
+	TokenPurgeInterval  int // 초, 만료된 토큰 삭제 주기, 0 이면 서버에서 실행하지 않음
+	TokenPurgeBatchSize int // 삭제 문 하나가 지우는 최대 행 수
+
+	LogLevel  string // "debug", "info", "warn" or "error"
+	LogFormat string // "json" or "text"
+
+	TracingExporter    string  // "none", "stdout" or "otlp"
+	TracingEndpoint    string  // OTLP/HTTP 주소, 예: "http://otel-collector:4318"
+	TracingServiceName string  // 스팬의 service.name
+	TracingSampleRatio float64 // 새로 시작하는 trace 중 기록할 비율, 0~1
+
+	ShutdownTimeout  int // 초, 종료 신호 후 처리 중인 요청을 기다리는 최대 시간
+	ReadinessTimeout int // 초, /readyz 의 점검 항목별 제한 시간
 }
 
 var (
@@ -35,7 +120,7 @@ func LoadConfig(filenames ...string) Config {
 	once.Do(func() {
 		err := godotenv.Load(filenames...)
 		if err != nil {
-			log.Warn("Error loading .env file", "error", err)
+			slog.Warn("Error loading .env file", "error", err)
 		}
 
 		dbType := getEnv("DB_TYPE", "postgres")
@@ -55,21 +140,129 @@ func LoadConfig(filenames ...string) Config {
 		}
 
 		config = Config{
+			AppEnv:       getEnv("APP_ENV", "prod"),
 			Port:         getEnv("PORT", "3000"),
+			GRPCPort:     getEnv("GRPC_PORT", ""),
 			JwtSecret:    getEnv("JWT_SECRET", ""),
 			SMTPServer:   getEnv("SMTP_SERVER", ""),
 			SMTPPort:     getEnv("SMTP_PORT", ""),
 			SMTPID:       getEnv("SMTP_ID", ""),
 			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			SMTPSecurity: getEnv("SMTP_SECURITY", "starttls"),
+			SMTPAuth:     getEnv("SMTP_AUTH", "plain"),
 			DatabaseURL:  databaseURL,
 			DBType:       dbType,
 			SqlitePath:   sqlitePath,
+
+			PasswordHashAlgorithm: getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			BcryptCost:            getEnvInt("BCRYPT_COST", 12),
+			Argon2Memory:          getEnvInt("ARGON2_MEMORY", 19*1024),
+			Argon2Iterations:      getEnvInt("ARGON2_ITERATIONS", 2),
+			Argon2Parallelism:     getEnvInt("ARGON2_PARALLELISM", 1),
+
+			PasswordMinLength:      getEnvInt("PASSWORD_MIN_LENGTH", 8),
+			PasswordMaxLength:      getEnvInt("PASSWORD_MAX_LENGTH", 128),
+			PasswordRequireUpper:   getEnvBool("PASSWORD_REQUIRE_UPPER", false),
+			PasswordRequireLower:   getEnvBool("PASSWORD_REQUIRE_LOWER", false),
+			PasswordRequireDigit:   getEnvBool("PASSWORD_REQUIRE_DIGIT", false),
+			PasswordRequireSymbol:  getEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+			PasswordMinCharClasses: getEnvInt("PASSWORD_MIN_CHAR_CLASSES", 2),
+			PasswordMinStrength:    getEnvInt("PASSWORD_MIN_STRENGTH", 2),
+			PasswordHistorySize:    getEnvInt("PASSWORD_HISTORY_SIZE", 5),
+
+			BreachCheck:     getEnv("BREACH_CHECK", "off"),
+			BreachCorpusDir: getEnv("BREACH_CORPUS_DIR", "./data/pwned"),
+			BreachAPIURL:    getEnv("BREACH_API_URL", "https://api.pwnedpasswords.com"),
+
+			MailProvider: getEnv("MAIL_PROVIDER", "smtp"),
+			MailFrom:     getEnv("MAIL_FROM", getEnv("SMTP_ID", "")),
+			MailFromName: getEnv("MAIL_FROM_NAME", ""),
+			MailAPIURL:   getEnv("MAIL_API_URL", "https://api.sendgrid.com/v3/mail/send"),
+			MailAPIKey:   getEnv("MAIL_API_KEY", ""),
+			MailFileDir:  getEnv("MAIL_FILE_DIR", "./data/mail"),
+
+			MailTemplateDir:   getEnv("MAIL_TEMPLATE_DIR", ""),
+			MailDefaultLocale: getEnv("MAIL_DEFAULT_LOCALE", "ko"),
+			BrandName:         getEnv("BRAND_NAME", "YourApp"),
+			BrandURL:          getEnv("BRAND_URL", ""),
+			BrandLogoURL:      getEnv("BRAND_LOGO_URL", ""),
+			BrandSupportEmail: getEnv("BRAND_SUPPORT_EMAIL", getEnv("MAIL_FROM", getEnv("SMTP_ID", ""))),
+			BrandColor:        getEnv("BRAND_COLOR", "#1a73e8"),
+
+			PublicBaseURL: getEnv("PUBLIC_BASE_URL", "http://127.0.0.1:3000"),
+			LinkPaths: map[string]string{
+				"password_reset": getEnv("LINK_PASSWORD_RESET_PATH", ""),
+				"magic_link":     getEnv("LINK_MAGIC_LINK_PATH", ""),
+			},
+			RedirectAllowlist: getEnvList("REDIRECT_ALLOWLIST"),
+			LinkSigningSecret: getEnv("LINK_SIGNING_SECRET", ""),
+
+			OutboxEnabled:     getEnvBool("OUTBOX_ENABLED", true),
+			OutboxInterval:    getEnvInt("OUTBOX_INTERVAL_SECONDS", 5),
+			OutboxMaxAttempts: getEnvInt("OUTBOX_MAX_ATTEMPTS", 8),
+
+			AdminAPIKey: getEnv("ADMIN_API_KEY", ""),
+
+			JWTSigningAlgorithm:  getEnv("JWT_SIGNING_ALGORITHM", "HS256"),
+			JWTKeyReloadInterval: getEnvInt("JWT_KEY_RELOAD_INTERVAL_SECONDS", 60),
+			JWTKeyRetention:      getEnvInt("JWT_KEY_RETENTION_SECONDS", 3600),
+
+			WebhookInterval:    getEnvInt("WEBHOOK_INTERVAL_SECONDS", 5),
+			WebhookMaxAttempts: getEnvInt("WEBHOOK_MAX_ATTEMPTS", 10),
+			WebhookTimeout:     getEnvInt("WEBHOOK_TIMEOUT_SECONDS", 10),
+
+			SMSProvider: getEnv("SMS_PROVIDER", "log"),
+			SMSFilePath: getEnv("SMS_FILE_PATH", "./data/sms.log"),
+
+			PhoneDefaultRegion: getEnv("PHONE_DEFAULT_REGION", "KR"),
+
+			MetricsEnabled: getEnvBool("METRICS_ENABLED", true),
+
+			GRPCReflection: getEnvBool("GRPC_REFLECTION", false),
+
+			AuditRetentionDays: getEnvInt("AUDIT_RETENTION_DAYS", 365),
+
+			TokenPurgeInterval:  getEnvInt("TOKEN_PURGE_INTERVAL_SECONDS", 3600),
+			TokenPurgeBatchSize: getEnvInt("TOKEN_PURGE_BATCH_SIZE", 1000),
+
+			LogLevel:  getEnv("LOG_LEVEL", "info"),
+			LogFormat: getEnv("LOG_FORMAT", "json"),
+
+			TracingExporter:    getEnv("TRACING_EXPORTER", "none"),
+			TracingEndpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			TracingServiceName: getEnv("OTEL_SERVICE_NAME", "auth"),
+			TracingSampleRatio: getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+
+			ShutdownTimeout:  getEnvInt("SHUTDOWN_TIMEOUT_SECONDS", 20),
+			ReadinessTimeout: getEnvInt("READINESS_TIMEOUT_SECONDS", 2),
 		}
-		log.Info("Configuration loaded successfully", config)
 	})
 	return config
 }
 
+// LogValue implements slog.LogValuer. It logs only settings that are safe to show, never
+// secrets such as JwtSecret, SMTPPassword, MailAPIKey or the password in DatabaseURL.
+func (c Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("appEnv", c.AppEnv),
+		slog.String("port", c.Port),
+		slog.String("grpcPort", c.GRPCPort),
+		slog.Bool("grpcReflection", c.GRPCReflection),
+		slog.String("dbType", c.DBType),
+		slog.String("jwtSigningAlgorithm", c.JWTSigningAlgorithm),
+		slog.String("hashAlgorithm", c.PasswordHashAlgorithm),
+		slog.String("breachCheck", c.BreachCheck),
+		slog.String("mailProvider", c.MailProvider),
+		slog.String("smsProvider", c.SMSProvider),
+		slog.Bool("outboxEnabled", c.OutboxEnabled),
+		slog.Bool("adminAPIEnabled", c.AdminAPIKey != ""),
+		slog.Bool("metricsEnabled", c.MetricsEnabled),
+		slog.String("tracingExporter", c.TracingExporter),
+		slog.String("logLevel", c.LogLevel),
+		slog.String("logFormat", c.LogFormat),
+	)
+}
+
 // getEnv 환경 변수 가져오기
 // getEnv returns the value of the environment variable or a default value if not set.
 func getEnv(key, defaultValue string) string {
@@ -78,3 +271,63 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvList returns the comma separated values of the environment variable, or nil if it is not set.
+func getEnvList(key string) []string {
+	value := getEnv(key, "")
+	if value == "" {
+		return nil
+	}
+	var list []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// getEnvInt returns the integer value of the environment variable or a default value
+// if it is not set or not a valid integer.
+func getEnvInt(key string, defaultValue int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Warn("Invalid integer environment variable, using default", "key", key, "error", err)
+		return defaultValue
+	}
+	return n
+}
+
+// getEnvFloat returns the float value of the environment variable or a default value
+// if it is not set or not a valid number.
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		slog.Warn("Invalid float environment variable, using default", "key", key, "error", err)
+		return defaultValue
+	}
+	return f
+}
+
+// getEnvBool returns the boolean value of the environment variable or a default value
+// if it is not set or not a valid boolean.
+func getEnvBool(key string, defaultValue bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		slog.Warn("Invalid boolean environment variable, using default", "key", key, "error", err)
+		return defaultValue
+	}
+	return b
+}
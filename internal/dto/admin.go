@@ -0,0 +1,58 @@
+// Package dto provides data transfer objects for admin API responses.
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxMessageResponse describes an outbox message for admins.
+// The payload is left out because it may contain tokens, e.g. password reset links.
+type OutboxMessageResponse struct {
+	ID            int64      `json:"id"`
+	Kind          string     `json:"kind"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	LastError     string     `json:"lastError"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	SentAt        *time.Time `json:"sentAt,omitempty"`
+}
+
+// WebhookEndpointRequest creates or replaces a webhook endpoint.
+type WebhookEndpointRequest struct {
+	URL          string   `json:"url" validate:"required,http_url,max=2048"`
+	Events       []string `json:"events" validate:"required,min=1,dive,required"` // 예: ["user.registered"], ["*"] 이면 전체
+	Description  string   `json:"description" validate:"max=256"`
+	Active       *bool    `json:"active"`       // 생략하면 true
+	RotateSecret bool     `json:"rotateSecret"` // 수정 시 새 서명 키 발급
+}
+
+// WebhookEndpointResponse describes a webhook endpoint. Secret is only returned when it is
+// created, i.e. by POST and by PUT with rotateSecret.
+type WebhookEndpointResponse struct {
+	ID          int64     `json:"id"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// WebhookDeliveryResponse describes a webhook delivery and the result of its last attempt.
+type WebhookDeliveryResponse struct {
+	ID             int64           `json:"id"`
+	EndpointID     int64           `json:"endpointId"`
+	EventID        string          `json:"eventId"`
+	Event          string          `json:"event"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"responseStatus,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+}
@@ -0,0 +1,20 @@
+// Package dto provides data transfer objects for audit log responses.
+package dto
+
+import "time"
+
+// AuditEventResponse describes a security-relevant event in the audit log.
+type AuditEventResponse struct {
+	ID        int64             `json:"id"`
+	UserID    *int64            `json:"userId,omitempty"` // 관리자 조회에서만
+	Event     string            `json:"event"`
+	Outcome   string            `json:"outcome"`
+	Actor     string            `json:"actor"`
+	IP        string            `json:"ip"`
+	UserAgent string            `json:"userAgent"`
+	Details   map[string]string `json:"details,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+	// PrevHash and Hash are the event's links in the hash chain, only in admin queries.
+	PrevHash string `json:"prevHash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+}
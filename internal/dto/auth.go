@@ -32,6 +32,8 @@ type LoginResponse struct {
 	Email        string `json:"email"`
 	AccessToken  string `json:"accessToken"`
 	RefreshToken string `json:"refreshToken"`
+	// Redirect is the verified redirect URL of the magic link, if it had one.
+	Redirect string `json:"redirect,omitempty"`
 }
 
 // RefreshTokenRequest represents a refresh token request.
@@ -41,12 +43,20 @@ type RefreshTokenRequest struct {
 
 // RefreshTokenResponse represents a refresh token response.
 type RefreshTokenResponse struct {
-	AccessToken string `json:"accessToken"`
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"` // 재발급 시 기존 리프레시 토큰은 폐기된다
 }
 
 // FindEmailRequest represents a request to find an email by phone number.
 type FindEmailRequest struct {
 	PhoneNumber string `json:"phoneNumber" validate:"required,phonekr"`
+	// Code is the SMS code sent by /auth/phone/code.
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// PhoneCodeRequest represents a request to send an SMS code for email recovery.
+type PhoneCodeRequest struct {
+	PhoneNumber string `json:"phoneNumber" validate:"required,phonekr"`
 }
 
 // FindEmailResponse represents a response containing an email.
@@ -57,15 +67,35 @@ type FindEmailResponse struct {
 // ForgotPasswordRequest represents a request to send a password reset email.
 type ForgotPasswordRequest struct {
 	Email string `json:"email" validate:"required,email"`
+	// RedirectURL is where the frontend goes after the reset; it must be in the redirect allowlist.
+	RedirectURL string `json:"redirectUrl" validate:"omitempty,url"`
 }
 
 // ResetPasswordRequest represents a request to reset a password.
 type ResetPasswordRequest struct {
 	Token       string `json:"token" validate:"required"`
 	NewPassword string `json:"newPassword" validate:"required,min=8,max=128"`
+	// Redirect and Signature are the redirect and sig parameters of the reset link, if it had a redirect.
+	Redirect  string `json:"redirect"`
+	Signature string `json:"sig"`
 }
 
 // LogoutRequest represents a logout request.
 type LogoutRequest struct {
 	RefreshToken string `json:"refreshToken" validate:"required"`
 }
+
+// MagicLinkRequest represents a request to email a passwordless sign-in link.
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	// RedirectURL is where the frontend goes after signing in; it must be in the redirect allowlist.
+	RedirectURL string `json:"redirectUrl" validate:"omitempty,url"`
+}
+
+// MagicLinkVerifyRequest represents a request to exchange a magic link token for tokens.
+type MagicLinkVerifyRequest struct {
+	Token string `json:"token" validate:"required"`
+	// Redirect and Signature are the redirect and sig parameters of the magic link, if it had a redirect.
+	Redirect  string `json:"redirect"`
+	Signature string `json:"sig"`
+}
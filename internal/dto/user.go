@@ -7,7 +7,13 @@ type ProfileResponse struct {
 	Name        string `json:"name"`
 	BirthDate   string `json:"birthDate"`
 	GenderCode  string `json:"genderCode"`
-	PhoneNumber string `json:"phoneNumber"`
+	PhoneNumber string `json:"phoneNumber"` // E.164, 예: +821012345678
+	// PhoneNumberDisplay is PhoneNumber formatted for display, e.g. "010-1234-5678".
+	PhoneNumberDisplay string `json:"phoneNumberDisplay"`
+	// PhoneVerified reports whether the phone number was verified by SMS.
+	PhoneVerified bool `json:"phoneVerified"`
+	// Locale is the preferred language of emails (BCP 47), empty if not set.
+	Locale string `json:"locale"`
 }
 
 // UpdateProfileRequest represents a request to update a user profile.
@@ -18,6 +24,21 @@ type UpdateProfileRequest struct {
 	BirthDate   string `json:"birthDate" validate:"required,len=10"`
 	GenderCode  string `json:"genderCode" validate:"required,oneof=M F O N U"`
 	PhoneNumber string `json:"phoneNumber" validate:"required,phonekr"`
+	// PhoneVerificationCode is the SMS code sent to PhoneNumber, required when the number changes.
+	PhoneVerificationCode string `json:"phoneVerificationCode" validate:"omitempty,len=6,numeric"`
+	// Locale is the preferred language of emails (e.g. "ko", "en"); empty keeps the current value.
+	Locale string `json:"locale" validate:"omitempty,bcp47_language_tag"`
+}
+
+// SendPhoneCodeRequest represents a request to send an SMS verification code.
+// PhoneNumber may be omitted to verify the signed-in user's current number.
+type SendPhoneCodeRequest struct {
+	PhoneNumber string `json:"phoneNumber" validate:"omitempty,phonekr"`
+}
+
+// VerifyPhoneRequest represents a request to verify the current phone number.
+type VerifyPhoneRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
 }
 
 // ChangePasswordRequest represents a request to change a user's password.
@@ -30,3 +51,20 @@ type ChangePasswordRequest struct {
 type DeleteProfileRequest struct {
 	CurrentPassword string `json:"currentPassword" validate:"required"`
 }
+
+// NotificationPreferencesResponse lists which security notification emails the user receives.
+type NotificationPreferencesResponse struct {
+	NewDeviceLogin  bool `json:"newDeviceLogin"`
+	PasswordChanged bool `json:"passwordChanged"` // 변경 및 재설정
+	PhoneChanged    bool `json:"phoneChanged"`
+	AccountDeleted  bool `json:"accountDeleted"`
+}
+
+// UpdateNotificationPreferencesRequest represents a request to change notification preferences.
+// Omitted fields keep their current value.
+type UpdateNotificationPreferencesRequest struct {
+	NewDeviceLogin  *bool `json:"newDeviceLogin"`
+	PasswordChanged *bool `json:"passwordChanged"`
+	PhoneChanged    *bool `json:"phoneChanged"`
+	AccountDeleted  *bool `json:"accountDeleted"`
+}
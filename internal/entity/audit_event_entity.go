@@ -0,0 +1,54 @@
+// Package entity provides database entity definitions for the authentication service.
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// AuditEventEntity is a security-relevant event, e.g. a login attempt or a password change.
+// Rows form a hash chain: Hash covers the row's fields and PrevHash, the Hash of the row
+// before it, so changing or deleting a row breaks every later Hash.
+type AuditEventEntity struct {
+	ID        int64     `db:"id" json:"id"`
+	UserID    *int64    `db:"user_id" json:"userID"` // 알 수 없는 이메일로 로그인 시도한 경우 등은 nil
+	Actor     string    `db:"actor" json:"actor"`    // "user", "admin" or "system"
+	Event     string    `db:"event" json:"event"`    // 예: "auth.login"
+	Outcome   string    `db:"outcome" json:"outcome"`
+	IP        string    `db:"ip" json:"ip"`
+	UserAgent string    `db:"user_agent" json:"userAgent"`
+	Details   string    `db:"details" json:"details"` // JSON object
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+	PrevHash  string    `db:"prev_hash" json:"prevHash"`
+	Hash      string    `db:"hash" json:"hash"`
+}
+
+// ComputeHash returns the SHA-256 of PrevHash and the event's fields, hex encoded.
+// CreatedAt is hashed at second precision, which both databases store exactly.
+func (e *AuditEventEntity) ComputeHash() string {
+	userID := ""
+	if e.UserID != nil {
+		userID = strconv.FormatInt(*e.UserID, 10)
+	}
+	fields := []string{
+		e.PrevHash,
+		userID,
+		e.Actor,
+		e.Event,
+		e.Outcome,
+		e.IP,
+		e.UserAgent,
+		e.Details,
+		e.CreatedAt.UTC().Truncate(time.Second).Format(time.RFC3339),
+	}
+	h := sha256.New()
+	for _, f := range fields {
+		// 길이를 앞에 붙여 필드 경계를 옮겨도 같은 해시가 나오지 않게 한다
+		h.Write([]byte(strconv.Itoa(len(f))))
+		h.Write([]byte{':'})
+		h.Write([]byte(f))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
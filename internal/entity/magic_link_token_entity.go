@@ -0,0 +1,16 @@
+// Package entity provides database entity definitions for the authentication service.
+package entity
+
+import "time"
+
+// MagicLinkTokenEntity represents a single-use passwordless sign-in token.
+// Only the SHA-256 hash of the token is stored, bound to the device that requested it.
+type MagicLinkTokenEntity struct {
+	ID         int64     `db:"id" json:"id"`
+	UserID     int64     `db:"user_id" json:"userID"`
+	TokenHash  string    `db:"token_hash" json:"-"`
+	DeviceInfo string    `db:"device_info" json:"deviceInfo"`
+	ExpiredAt  time.Time `db:"expired_at" json:"expiredAt"`
+	Used       bool      `db:"used" json:"used"`
+	CreatedAt  time.Time `db:"created_at" json:"createdAt"`
+}
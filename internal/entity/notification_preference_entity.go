@@ -0,0 +1,23 @@
+// Package entity provides database entity definitions for the authentication service.
+package entity
+
+// NotificationPreferenceEntity holds which security notification emails a user receives.
+// Users without a stored row receive all of them.
+type NotificationPreferenceEntity struct {
+	UserID          int64 `db:"user_id" json:"userID"`
+	NewDeviceLogin  bool  `db:"new_device_login" json:"newDeviceLogin"`
+	PasswordChanged bool  `db:"password_changed" json:"passwordChanged"` // 변경 및 재설정
+	PhoneChanged    bool  `db:"phone_changed" json:"phoneChanged"`
+	AccountDeleted  bool  `db:"account_deleted" json:"accountDeleted"`
+}
+
+// DefaultNotificationPreference returns the preferences of a user who has not changed them.
+func DefaultNotificationPreference(userID int64) *NotificationPreferenceEntity {
+	return &NotificationPreferenceEntity{
+		UserID:          userID,
+		NewDeviceLogin:  true,
+		PasswordChanged: true,
+		PhoneChanged:    true,
+		AccountDeleted:  true,
+	}
+}
@@ -0,0 +1,25 @@
+// Package entity provides database entity definitions for the authentication service.
+package entity
+
+import "time"
+
+// Outbox message statuses.
+const (
+	OutboxStatusPending = "pending" // 발송 대기 또는 재시도 대기
+	OutboxStatusSent    = "sent"
+	OutboxStatusDead    = "dead" // 최대 시도 횟수 초과
+)
+
+// OutboxMessageEntity is a side effect (e.g. an email) recorded in the same transaction
+// as the change that caused it and delivered later by the outbox dispatcher.
+type OutboxMessageEntity struct {
+	ID            int64      `db:"id" json:"id"`
+	Kind          string     `db:"kind" json:"kind"`       // 예: "email"
+	Payload       string     `db:"payload" json:"payload"` // kind 별 JSON
+	Status        string     `db:"status" json:"status"`
+	Attempts      int        `db:"attempts" json:"attempts"`
+	NextAttemptAt time.Time  `db:"next_attempt_at" json:"nextAttemptAt"`
+	LastError     string     `db:"last_error" json:"lastError"`
+	CreatedAt     time.Time  `db:"created_at" json:"createdAt"`
+	SentAt        *time.Time `db:"sent_at" json:"sentAt"`
+}
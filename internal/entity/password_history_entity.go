@@ -0,0 +1,12 @@
+// Package entity provides database entity definitions for the authentication service.
+package entity
+
+import "time"
+
+// PasswordHistoryEntity represents a previously used password hash of a user.
+type PasswordHistoryEntity struct {
+	ID           int64     `db:"id" json:"id"`
+	UserID       int64     `db:"user_id" json:"userID"`
+	PasswordHash string    `db:"password_hash" json:"-"`
+	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+}
@@ -0,0 +1,17 @@
+// Package entity provides database entity definitions for the authentication service.
+package entity
+
+import "time"
+
+// PhoneVerificationEntity represents a one-time code sent by SMS to verify a phone number.
+// Only the hash of the code is stored; Attempts counts failed verification attempts.
+type PhoneVerificationEntity struct {
+	ID          int64     `db:"id" json:"id"`
+	PhoneNumber string    `db:"phone_number" json:"phoneNumber"`
+	Purpose     string    `db:"purpose" json:"purpose"`
+	CodeHash    string    `db:"code_hash" json:"-"`
+	Attempts    int       `db:"attempts" json:"attempts"`
+	ExpiredAt   time.Time `db:"expired_at" json:"expiredAt"`
+	Used        bool      `db:"used" json:"used"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+}
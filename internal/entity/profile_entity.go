@@ -5,14 +5,16 @@ import "time"
 
 // ProfileEntity represents a user profile record in the database.
 type ProfileEntity struct {
-	ID          int64      `db:"id" json:"id"`
-	UserID      int64      `db:"user_id" json:"userID"`
-	Name        string     `db:"name" json:"name"`
-	BirthDate   time.Time  `db:"birth_date" json:"birthDate"`     // YYYY-MM-DD(ISO 8601)
-	GenderCode  GenderCode `db:"gender_code" json:"genderCode"`   // 'M','F','O','N','U'
-	PhoneNumber string     `db:"phone_number" json:"phoneNumber"` // E.164 국제표준
-	CreatedAt   time.Time  `db:"created_at" json:"createdAt"`
-	UpdatedAt   time.Time  `db:"updated_at" json:"updatedAt"`
+	ID              int64      `db:"id" json:"id"`
+	UserID          int64      `db:"user_id" json:"userID"`
+	Name            string     `db:"name" json:"name"`
+	BirthDate       time.Time  `db:"birth_date" json:"birthDate"`              // YYYY-MM-DD(ISO 8601)
+	GenderCode      GenderCode `db:"gender_code" json:"genderCode"`            // 'M','F','O','N','U'
+	PhoneNumber     string     `db:"phone_number" json:"phoneNumber"`          // E.164 국제표준
+	PhoneVerifiedAt *time.Time `db:"phone_verified_at" json:"phoneVerifiedAt"` // SMS 인증 시각, 미인증이면 nil
+	Locale          string     `db:"locale" json:"locale"`                     // 메일 언어(BCP 47), 비어 있으면 Accept-Language 사용
+	CreatedAt       time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updatedAt"`
 }
 
 // GenderCode represents the gender code for a profile.
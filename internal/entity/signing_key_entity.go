@@ -0,0 +1,15 @@
+// Package entity provides database entity definitions for the authentication service.
+package entity
+
+import "time"
+
+// SigningKeyEntity is a key pair that signs access tokens. The public half is published in the
+// JWKS so other services can verify tokens without calling this service.
+type SigningKeyEntity struct {
+	ID         int64      `db:"id"`
+	KID        string     `db:"kid"`       // JWT 헤더의 kid
+	Algorithm  string     `db:"algorithm"` // 예: "ES256"
+	PrivateKey string     `db:"private_key"`
+	CreatedAt  time.Time  `db:"created_at"`
+	RetiredAt  *time.Time `db:"retired_at"` // 교체되어 더 이상 서명하지 않으면 설정
+}
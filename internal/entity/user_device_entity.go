@@ -0,0 +1,15 @@
+// Package entity provides database entity definitions for the authentication service.
+package entity
+
+import "time"
+
+// UserDeviceEntity is a device (User-Agent) a user has signed in from.
+// It is used to notify the user about sign-ins from devices not seen before.
+type UserDeviceEntity struct {
+	ID          int64     `db:"id" json:"id"`
+	UserID      int64     `db:"user_id" json:"userID"`
+	DeviceHash  string    `db:"device_hash" json:"-"` // device_info 의 SHA-256
+	DeviceInfo  string    `db:"device_info" json:"deviceInfo"`
+	FirstSeenAt time.Time `db:"first_seen_at" json:"firstSeenAt"`
+	LastSeenAt  time.Time `db:"last_seen_at" json:"lastSeenAt"`
+}
@@ -13,4 +13,5 @@ type UserEntity struct {
 	CreatedAt    time.Time  `db:"created_at" json:"createdAt"`
 	UpdatedAt    time.Time  `db:"updated_at" json:"updatedAt"`
 	DeletedAt    *time.Time `db:"deleted_at" json:"deletedAt,omitempty"`
+	DisabledAt   *time.Time `db:"disabled_at" json:"disabledAt,omitempty"` // 관리자가 정지한 계정, 로그인 불가
 }
@@ -0,0 +1,61 @@
+// Package entity provides database entity definitions for the authentication service.
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// Webhook delivery statuses.
+const (
+	WebhookStatusPending   = "pending" // 발송 대기 또는 재시도 대기
+	WebhookStatusDelivered = "delivered"
+	WebhookStatusDead      = "dead" // 최대 시도 횟수 초과
+)
+
+// WebhookEndpointEntity is a URL subscribed to user lifecycle events.
+type WebhookEndpointEntity struct {
+	ID          int64     `db:"id" json:"id"`
+	URL         string    `db:"url" json:"url"`
+	Secret      string    `db:"secret" json:"-"`      // 페이로드 서명 키
+	Events      string    `db:"events" json:"events"` // 쉼표로 구분한 이벤트 목록, "*" 이면 전체
+	Description string    `db:"description" json:"description"`
+	Active      bool      `db:"active" json:"active"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// EventList returns the subscribed events.
+func (e *WebhookEndpointEntity) EventList() []string {
+	if e.Events == "" {
+		return nil
+	}
+	return strings.Split(e.Events, ",")
+}
+
+// Subscribes reports whether the endpoint receives the event.
+func (e *WebhookEndpointEntity) Subscribes(event string) bool {
+	for _, ev := range e.EventList() {
+		if ev == "*" || ev == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryEntity is one event to send to one endpoint, together with the result of
+// its last attempt.
+type WebhookDeliveryEntity struct {
+	ID             int64      `db:"id" json:"id"`
+	EndpointID     int64      `db:"endpoint_id" json:"endpointId"`
+	EventID        string     `db:"event_id" json:"eventId"` // 재전송해도 바뀌지 않아 수신 측 중복 제거에 쓴다
+	Event          string     `db:"event" json:"event"`
+	Payload        string     `db:"payload" json:"payload"`
+	Status         string     `db:"status" json:"status"`
+	Attempts       int        `db:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at" json:"nextAttemptAt"`
+	ResponseStatus int        `db:"response_status" json:"responseStatus"` // 마지막 시도의 HTTP 상태, 응답이 없으면 0
+	LastError      string     `db:"last_error" json:"lastError"`
+	CreatedAt      time.Time  `db:"created_at" json:"createdAt"`
+	DeliveredAt    *time.Time `db:"delivered_at" json:"deliveredAt"`
+}
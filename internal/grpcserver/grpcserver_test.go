@@ -0,0 +1,170 @@
+package grpcserver_test
+
+import (
+	"auth/internal/grpcserver"
+	"auth/internal/repository"
+	"auth/internal/service"
+	"auth/internal/service/email"
+	"auth/pkg/api/authv1"
+	"context"
+	"net"
+	"net/mail"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"zombiezen.com/go/sqlite"
+)
+
+type nopMailer struct{}
+
+func (nopMailer) Send(context.Context, *email.Message) error { return nil }
+
+func newClient(t *testing.T) (authv1.AuthServiceClient, *grpc.ClientConn) {
+	conn, err := sqlite.OpenConn(":memory:", 0)
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	authService := service.NewAuthService(nil,
+		repository.NewUserRepositoryAuto("sqlite", nil, conn),
+		repository.NewProfileRepositoryAuto("sqlite", nil, conn),
+		service.NewJwtService("secret"),
+		email.NewEmailServiceWithMailer(nopMailer{}, mail.Address{Address: "noreply@example.com"}))
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpcserver.New(authService)
+	reflection.Register(srv)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+	cc, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = cc.Close()
+	})
+	return authv1.NewAuthServiceClient(cc), cc
+}
+
+var registerRequest = &authv1.RegisterRequest{
+	Email:       "kim@example.com",
+	Password:    "Str0ng!Passw0rd#x",
+	Name:        "Kim",
+	BirthDate:   "1990-01-01",
+	GenderCode:  "M",
+	PhoneNumber: "010-1234-5678",
+}
+
+func errorReason(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestAuthServer_가입로그인프로필(t *testing.T) {
+	client, _ := newClient(t)
+	ctx := context.Background()
+
+	reg, err := client.Register(ctx, registerRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, "+821012345678", reg.PhoneNumber)
+
+	_, err = client.Register(ctx, registerRequest)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	assert.Equal(t, "conflict", errorReason(err))
+
+	_, err = client.Login(ctx, &authv1.LoginRequest{Email: registerRequest.Email, Password: "Wrong!Passw0rd#x"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	login, err := client.Login(ctx, &authv1.LoginRequest{Email: registerRequest.Email, Password: registerRequest.Password, DeviceInfo: "test"})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, login.AccessToken)
+
+	verified, err := client.VerifyAccessToken(ctx, &authv1.VerifyAccessTokenRequest{AccessToken: login.AccessToken})
+	assert.Nil(t, err)
+	assert.Equal(t, login.UserId, verified.UserId)
+	_, err = client.VerifyAccessToken(ctx, &authv1.VerifyAccessTokenRequest{AccessToken: "garbage"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// 프로필은 access token 이 있어야 한다
+	_, err = client.GetProfile(ctx, &authv1.GetProfileRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	authed := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+login.AccessToken)
+	profile, err := client.GetProfile(authed, &authv1.GetProfileRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, registerRequest.Email, profile.Email)
+	assert.Equal(t, "010-1234-5678", profile.PhoneNumberDisplay)
+
+	updated, err := client.UpdateProfile(authed, &authv1.UpdateProfileRequest{
+		Name:        "Lee",
+		BirthDate:   "1991-02-03",
+		GenderCode:  "F",
+		PhoneNumber: "010-1234-5678",
+		Locale:      "en",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Lee", updated.Name)
+	assert.Equal(t, "en", updated.Locale)
+	assert.Equal(t, registerRequest.Email, updated.Email)
+
+	refreshed, err := client.RefreshToken(ctx, &authv1.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	assert.Nil(t, err)
+	_, err = client.Logout(ctx, &authv1.LogoutRequest{RefreshToken: refreshed.RefreshToken})
+	assert.Nil(t, err)
+	_, err = client.RefreshToken(ctx, &authv1.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuthServer_검증오류(t *testing.T) {
+	client, _ := newClient(t)
+	req := proto.Clone(registerRequest).(*authv1.RegisterRequest)
+	req.BirthDate = "1990"
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "en")
+
+	var header metadata.MD
+	_, err := client.Register(ctx, req, grpc.Header(&header))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "validationError", errorReason(err))
+	assert.NotEmpty(t, header.Get("x-request-id"))
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, d := range status.Convert(err).Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			violations = br.FieldViolations
+		}
+	}
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "birth_date", violations[0].Field)
+	assert.Equal(t, "len", violations[0].Reason)
+	assert.Contains(t, violations[0].Description, "must be")
+}
+
+func TestNew_리플렉션(t *testing.T) {
+	_, cc := newClient(t)
+	stream, err := grpc_reflection_v1.NewServerReflectionClient(cc).ServerReflectionInfo(context.Background())
+	assert.Nil(t, err)
+	assert.Nil(t, stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := stream.Recv()
+	assert.Nil(t, err)
+	var names []string
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		names = append(names, s.Name)
+	}
+	assert.Contains(t, names, "auth.v1.AuthService")
+}
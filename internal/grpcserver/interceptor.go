@@ -0,0 +1,204 @@
+package grpcserver
+
+import (
+	"auth/internal/logging"
+	"auth/internal/service"
+	"auth/internal/service/audit"
+	"auth/internal/tracing"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey is the metadata key of the request ID, in the request and the response header.
+const requestIDKey = "x-request-id"
+
+// serverErrorCodes are the codes reported as errors in spans and access logs.
+var serverErrorCodes = map[codes.Code]bool{
+	codes.Unknown:          true,
+	codes.Internal:         true,
+	codes.Unavailable:      true,
+	codes.DataLoss:         true,
+	codes.DeadlineExceeded: true,
+	codes.Unimplemented:    true,
+}
+
+// TracingInterceptor starts a server span for every call, continuing the trace of the incoming
+// traceparent metadata if any. The span is named after the full method, e.g.
+// "/auth.v1.AuthService/Login".
+func TracingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+		service, method := splitMethod(info.FullMethod)
+		ctx, span := otel.Tracer(tracing.ScopeName).Start(ctx, info.FullMethod, trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.RPCSystemGRPC, semconv.RPCService(service), semconv.RPCMethod(method)))
+		defer span.End()
+		resp, err := next(ctx, req)
+		code := status.Code(err)
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+		if serverErrorCodes[code] {
+			span.SetStatus(otelcodes.Error, err.Error())
+		}
+		return resp, err
+	}
+}
+
+// LoggingInterceptor assigns every call an ID, taken from a well-formed x-request-id metadata
+// value or generated, returns it in the response header and stores it in the context so logs
+// carry it. Errors returned by later interceptors and the RPCs are converted with Status here,
+// so the logged code is the one sent to the client. Every call writes one access log record.
+func LoggingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		md, _ := metadata.FromIncomingContext(ctx)
+		var id string
+		if v := md.Get(requestIDKey); len(v) > 0 {
+			id = v[0]
+		}
+		id = logging.NewRequestID(id)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+		ctx = logging.WithRequestID(ctx, id)
+
+		resp, err := next(ctx, req)
+		code := codes.OK
+		if err != nil {
+			st := Status(ctx, err)
+			if serverErrorCodes[st.Code()] {
+				slog.ErrorContext(ctx, "rpc failed", "method", info.FullMethod, "error", err)
+			}
+			code = st.Code()
+			err = st.Err()
+		}
+		level := slog.LevelInfo
+		if serverErrorCodes[code] {
+			level = slog.LevelError
+		}
+		slog.Log(ctx, level, "rpc",
+			"method", info.FullMethod,
+			"code", code.String(),
+			"latency_ms", time.Since(start).Milliseconds(),
+			"ip", peerIP(ctx),
+		)
+		return resp, err
+	}
+}
+
+// RecoveryInterceptor turns a panic in a later interceptor or an RPC into an Internal error,
+// so one bad call does not bring the server down.
+func RecoveryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				slog.ErrorContext(ctx, "rpc panicked", "method", info.FullMethod, "panic", fmt.Sprint(p), "stack", string(debug.Stack()))
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return next(ctx, req)
+	}
+}
+
+// ClientInfoInterceptor stores the client IP and user agent in the context, where the audit log
+// reads them, like middleware.ClientInfoMiddleware does for HTTP.
+func ClientInfoInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		var userAgent string
+		if v := md.Get("user-agent"); len(v) > 0 {
+			userAgent = v[0]
+		}
+		return next(audit.WithClient(ctx, audit.Client{IP: peerIP(ctx), UserAgent: userAgent}), req)
+	}
+}
+
+type userIDKey struct{}
+
+// UserID returns the ID of the user authenticated by AuthInterceptor.
+func UserID(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(userIDKey{}).(int64)
+	return id, ok
+}
+
+// AuthInterceptor requires an access token in the "authorization" metadata, as
+// "Bearer <token>", for the full method names in methods, and stores its user for UserID.
+// Other methods are passed through.
+func AuthInterceptor(jwtSvc *service.JwtService, methods ...string) grpc.UnaryServerInterceptor {
+	protected := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		protected[m] = true
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+		if !protected[info.FullMethod] {
+			return next(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "missing token")
+		}
+		// "Bearer <token>"
+		scheme, token, ok := strings.Cut(values[0], " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return nil, status.Error(codes.Unauthenticated, "invalid token format")
+		}
+		userID, err := jwtSvc.ValidateAccessToken(token)
+		if err != nil {
+			return nil, err
+		}
+		return next(context.WithValue(ctx, userIDKey{}, userID), req)
+	}
+}
+
+// peerIP returns the IP address of the client, or "" if unknown.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+// splitMethod splits "/auth.v1.AuthService/Login" into the service and the method.
+func splitMethod(fullMethod string) (string, string) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return service, method
+}
+
+// metadataCarrier reads propagation headers from the incoming metadata.
+type metadataCarrier metadata.MD
+
+func (m metadataCarrier) Get(key string) string {
+	if v := metadata.MD(m).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (m metadataCarrier) Set(key, value string) {
+	metadata.MD(m).Set(key, value)
+}
+
+func (m metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,192 @@
+// Package grpcserver serves the gRPC API of pkg/api/authv1 with the same AuthService as the
+// REST handlers.
+package grpcserver
+
+import (
+	"auth/internal/dto"
+	"auth/internal/handler"
+	"auth/internal/service"
+	"auth/internal/service/audit"
+	"auth/pkg/api/authv1"
+	"context"
+	"log/slog"
+
+	"google.golang.org/grpc"
+)
+
+// authenticatedMethods are the RPCs that need an access token.
+var authenticatedMethods = []string{
+	authv1.AuthService_GetProfile_FullMethodName,
+	authv1.AuthService_UpdateProfile_FullMethodName,
+}
+
+// New returns a gRPC server with the AuthService API. Every call passes
+// through the interceptors for tracing, request IDs and access logs, error mapping, panics,
+// client info and, for authenticatedMethods, authentication.
+func New(authService *service.AuthService, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(
+		TracingInterceptor(),
+		LoggingInterceptor(),
+		RecoveryInterceptor(),
+		ClientInfoInterceptor(),
+		AuthInterceptor(authService.JwtSvc(), authenticatedMethods...),
+	))
+	srv := grpc.NewServer(opts...)
+	authv1.RegisterAuthServiceServer(srv, NewAuthServer(authService))
+	return srv
+}
+
+// AuthServer implements authv1.AuthServiceServer. Like the REST handlers, it validates requests
+// with handler.Validate and returns domain errors as they are; LoggingInterceptor converts them
+// to status errors.
+type AuthServer struct {
+	authv1.UnimplementedAuthServiceServer
+	authService *service.AuthService
+}
+
+// NewAuthServer creates a new AuthServer.
+func NewAuthServer(authSvc *service.AuthService) *AuthServer {
+	return &AuthServer{authService: authSvc}
+}
+
+// Register creates an account.
+func (s *AuthServer) Register(ctx context.Context, req *authv1.RegisterRequest) (*authv1.RegisterResponse, error) {
+	cmd := &dto.RegisterRequest{
+		Email:       req.GetEmail(),
+		Password:    req.GetPassword(),
+		Name:        req.GetName(),
+		BirthDate:   req.GetBirthDate(),
+		GenderCode:  req.GetGenderCode(),
+		PhoneNumber: req.GetPhoneNumber(),
+	}
+	if err := handler.Validate.Struct(cmd); err != nil {
+		slog.WarnContext(ctx, "Register: validation failed", "error", err)
+		return nil, err
+	}
+	result, err := s.authService.RegisterUser(ctx, cmd)
+	if err != nil {
+		slog.WarnContext(ctx, "Register failed", "email", cmd.Email, "error", err)
+		return nil, err
+	}
+	return &authv1.RegisterResponse{
+		Email:       result.Email,
+		Name:        result.Name,
+		BirthDate:   result.BirthDate,
+		GenderCode:  result.GenderCode,
+		PhoneNumber: result.PhoneNumber,
+	}, nil
+}
+
+// Login checks the email and password and issues tokens bound to the device.
+func (s *AuthServer) Login(ctx context.Context, req *authv1.LoginRequest) (*authv1.LoginResponse, error) {
+	cmd := &dto.LoginRequest{Email: req.GetEmail(), Password: req.GetPassword()}
+	if err := handler.Validate.Struct(cmd); err != nil {
+		slog.WarnContext(ctx, "Login: validation failed", "error", err)
+		return nil, err
+	}
+	deviceInfo := req.GetDeviceInfo()
+	if deviceInfo == "" {
+		deviceInfo = audit.ClientFrom(ctx).UserAgent
+	}
+	result, err := s.authService.Login(ctx, cmd, deviceInfo)
+	if err != nil {
+		slog.WarnContext(ctx, "Login failed", "email", cmd.Email, "error", err)
+		return nil, err
+	}
+	return &authv1.LoginResponse{
+		UserId:       result.UserID,
+		Email:        result.Email,
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.RefreshToken,
+	}, nil
+}
+
+// RefreshToken exchanges a refresh token for new access and refresh tokens.
+func (s *AuthServer) RefreshToken(ctx context.Context, req *authv1.RefreshTokenRequest) (*authv1.RefreshTokenResponse, error) {
+	cmd := &dto.RefreshTokenRequest{RefreshToken: req.GetRefreshToken()}
+	if err := handler.Validate.Struct(cmd); err != nil {
+		return nil, err
+	}
+	accessToken, refreshToken, err := s.authService.RefreshToken(ctx, cmd.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	return &authv1.RefreshTokenResponse{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// Logout revokes a refresh token. Like the REST endpoint it succeeds for invalid tokens, so
+// clients can always discard their tokens.
+func (s *AuthServer) Logout(ctx context.Context, req *authv1.LogoutRequest) (*authv1.LogoutResponse, error) {
+	cmd := &dto.LogoutRequest{RefreshToken: req.GetRefreshToken()}
+	if err := handler.Validate.Struct(cmd); err != nil {
+		return nil, err
+	}
+	userID, deviceInfo, err := s.authService.JwtSvc().ValidateRefreshToken(cmd.RefreshToken)
+	if err == nil {
+		_ = s.authService.Logout(ctx, userID, cmd.RefreshToken, deviceInfo)
+		slog.InfoContext(ctx, "Logout success", "userID", userID)
+	} else {
+		slog.WarnContext(ctx, "Logout: invalid refresh token", "error", err)
+	}
+	return &authv1.LogoutResponse{}, nil
+}
+
+// GetProfile returns the profile of the signed-in user.
+func (s *AuthServer) GetProfile(ctx context.Context, _ *authv1.GetProfileRequest) (*authv1.Profile, error) {
+	userID, _ := UserID(ctx)
+	profile, err := s.authService.GetProfile(ctx, userID)
+	if err != nil {
+		slog.WarnContext(ctx, "GetProfile failed", "userID", userID, "error", err)
+		return nil, err
+	}
+	return toProfile(profile), nil
+}
+
+// UpdateProfile changes the profile of the signed-in user and returns it.
+func (s *AuthServer) UpdateProfile(ctx context.Context, req *authv1.UpdateProfileRequest) (*authv1.Profile, error) {
+	userID, _ := UserID(ctx)
+	cmd := &dto.UpdateProfileRequest{
+		Name:                  req.GetName(),
+		BirthDate:             req.GetBirthDate(),
+		GenderCode:            req.GetGenderCode(),
+		PhoneNumber:           req.GetPhoneNumber(),
+		PhoneVerificationCode: req.GetPhoneVerificationCode(),
+		Locale:                req.GetLocale(),
+	}
+	if err := handler.Validate.Struct(cmd); err != nil {
+		slog.WarnContext(ctx, "UpdateProfile: validation failed", "error", err)
+		return nil, err
+	}
+	if _, err := s.authService.UpdateProfile(ctx, userID, cmd); err != nil {
+		slog.WarnContext(ctx, "UpdateProfile failed", "userID", userID, "error", err)
+		return nil, err
+	}
+	// 수정 결과에는 이메일이 없으므로 다시 조회한다
+	profile, err := s.authService.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toProfile(profile), nil
+}
+
+// VerifyAccessToken checks an access token and returns its user.
+func (s *AuthServer) VerifyAccessToken(_ context.Context, req *authv1.VerifyAccessTokenRequest) (*authv1.VerifyAccessTokenResponse, error) {
+	userID, err := s.authService.JwtSvc().ValidateAccessToken(req.GetAccessToken())
+	if err != nil {
+		return nil, err
+	}
+	return &authv1.VerifyAccessTokenResponse{UserId: userID}, nil
+}
+
+func toProfile(p *dto.ProfileResponse) *authv1.Profile {
+	return &authv1.Profile{
+		Email:              p.Email,
+		Name:               p.Name,
+		BirthDate:          p.BirthDate,
+		GenderCode:         p.GenderCode,
+		PhoneNumber:        p.PhoneNumber,
+		PhoneNumberDisplay: p.PhoneNumberDisplay,
+		PhoneVerified:      p.PhoneVerified,
+		Locale:             p.Locale,
+	}
+}
@@ -0,0 +1,102 @@
+package grpcserver
+
+import (
+	"auth/internal/handler"
+	"auth/internal/service/password"
+	"context"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the google.rpc.ErrorInfo details of failed calls.
+const ErrorDomain = "auth"
+
+// httpCodes maps the HTTP statuses of handler.MapError to gRPC codes.
+var httpCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.AlreadyExists,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// Status converts an error returned by an RPC into a status, classified like the REST API by
+// handler.MapError so both transports agree. The status carries an ErrorInfo detail whose
+// reason is the REST error code, and for validation and password policy errors a BadRequest
+// detail with the fields, translated for the accept-language metadata. Status errors are
+// returned as they are.
+func Status(ctx context.Context, err error) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	var acceptLanguage string
+	if v := md.Get("accept-language"); len(v) > 0 {
+		acceptLanguage = v[0]
+	}
+	httpStatus, code, details := handler.MapErrorLocale(err, acceptLanguage)
+	c, ok := httpCodes[httpStatus]
+	if !ok {
+		c = codes.Unknown
+	}
+	msg, _ := details.(string)
+	if msg == "" {
+		msg = code
+	}
+	st := status.New(c, msg)
+	info := &errdetails.ErrorInfo{Reason: code, Domain: ErrorDomain}
+	var withDetails *status.Status
+	switch d := details.(type) {
+	case []handler.FieldError:
+		br := &errdetails.BadRequest{}
+		for _, fe := range d {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       snakeCase(fe.Field),
+				Description: fe.Message,
+				Reason:      fe.Rule,
+			})
+		}
+		withDetails, err = st.WithDetails(info, br)
+	case []password.Violation:
+		br := &errdetails.BadRequest{}
+		for _, v := range d {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       "password",
+				Description: v.Message,
+				Reason:      v.Rule,
+			})
+		}
+		withDetails, err = st.WithDetails(info, br)
+	default:
+		withDetails, err = st.WithDetails(info)
+	}
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+// snakeCase converts the JSON field names of validation errors, e.g. "birthDate", to the
+// proto field names, e.g. "birth_date".
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
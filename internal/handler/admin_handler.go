@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"auth/internal/dto"
+	"auth/internal/entity"
+	"auth/internal/repository"
+	"auth/internal/service/audit"
+	"auth/internal/service/outbox"
+	"auth/internal/service/webhook"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminHandler handles operator endpoints protected by the admin API key.
+type AdminHandler struct {
+	outbox   *outbox.Dispatcher
+	auditLog *audit.Service
+	webhooks *webhook.Dispatcher
+}
+
+// NewAdminHandler creates a new AdminHandler.
+func NewAdminHandler(dispatcher *outbox.Dispatcher, auditLog *audit.Service, webhooks *webhook.Dispatcher) *AdminHandler {
+	return &AdminHandler{outbox: dispatcher, auditLog: auditLog, webhooks: webhooks}
+}
+
+// ListOutbox godoc
+// @Summary outbox 메시지 조회
+// @Description 발송 대기(pending) 또는 발송 실패(dead) 메시지를 오래된 순으로 조회한다. 본문(payload)은 포함하지 않는다.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "관리자 API 키"
+// @Param status query string false "pending 또는 dead (기본값 dead)"
+// @Param limit query int false "최대 개수 (기본값 50, 최대 500)"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"outbox messages\",\"data\":[{\"id\":1,\"kind\":\"email\",\"status\":\"dead\",\"attempts\":8}]}"
+// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"badRequest\",\"data\":\"status must be pending or dead\"}"
+// @Failure 401 {object} APIResponse "예시: {\"error\":\"invalid admin key\"}"
+// @Router /admin/outbox [get]
+func (h *AdminHandler) ListOutbox(c *fiber.Ctx) error {
+	status := c.Query("status", entity.OutboxStatusDead)
+	if status != entity.OutboxStatusPending && status != entity.OutboxStatusDead {
+		return NewError(fiber.StatusBadRequest, BadRequest, "status must be pending or dead")
+	}
+	limit := c.QueryInt("limit", 50)
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	messages, err := h.outbox.List(c.UserContext(), status, limit)
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "ListOutbox failed", "error", err)
+		return NewError(fiber.StatusInternalServerError, InternalError, "failed to list outbox")
+	}
+	result := make([]dto.OutboxMessageResponse, 0, len(messages))
+	for _, m := range messages {
+		result = append(result, dto.OutboxMessageResponse{
+			ID:            m.ID,
+			Kind:          m.Kind,
+			Status:        m.Status,
+			Attempts:      m.Attempts,
+			NextAttemptAt: m.NextAttemptAt,
+			LastError:     m.LastError,
+			CreatedAt:     m.CreatedAt,
+			SentAt:        m.SentAt,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(result, fiber.StatusOK, "outbox messages"))
+}
+
+// RetryOutbox godoc
+// @Summary outbox 메시지 재발송
+// @Description 발송되지 않은 메시지를 시도 횟수를 초기화하여 즉시 재발송 대기열에 넣는다.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "관리자 API 키"
+// @Param id path int true "메시지 ID"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"message requeued\",\"data\":null}"
+// @Failure 404 {object} APIResponse "예시: {\"success\":false,\"code\":404,\"message\":\"notFound\",\"data\":\"message not found or already sent\"}"
+// @Router /admin/outbox/{id}/retry [post]
+func (h *AdminHandler) RetryOutbox(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid id")
+	}
+	ok, err := h.outbox.Retry(c.UserContext(), int64(id))
+	event := audit.Event{
+		Type:    audit.EventAdminOutboxRetry,
+		Actor:   audit.ActorAdmin,
+		Outcome: audit.OutcomeSuccess,
+		Details: map[string]string{"messageId": strconv.Itoa(id)},
+	}
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "RetryOutbox failed", "id", id, "error", err)
+		event.Outcome = audit.OutcomeError
+		h.auditLog.Record(c.UserContext(), event)
+		return NewError(fiber.StatusInternalServerError, InternalError, "failed to requeue message")
+	}
+	if !ok {
+		event.Outcome = audit.OutcomeFailure
+		h.auditLog.Record(c.UserContext(), event)
+		return NewError(fiber.StatusNotFound, NotFound, "message not found or already sent")
+	}
+	h.auditLog.Record(c.UserContext(), event)
+	slog.InfoContext(c.UserContext(), "RetryOutbox success", "id", id)
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(nil, fiber.StatusOK, "message requeued"))
+}
+
+// ListAudit godoc
+// @Summary 감사 로그 조회
+// @Description 보안 감사 이벤트를 최신 순으로 조회한다. 다음 페이지는 마지막 이벤트의 id 를 before 로 넘긴다.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "관리자 API 키"
+// @Param userId query int false "사용자 ID"
+// @Param event query string false "이벤트, 예: auth.login"
+// @Param outcome query string false "success, failure 또는 error"
+// @Param since query string false "이 시각 이후 (RFC 3339)"
+// @Param until query string false "이 시각 이전 (RFC 3339)"
+// @Param before query int false "이 id 이전 이벤트만"
+// @Param limit query int false "최대 개수 (기본값 50, 최대 500)"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"audit events\",\"data\":[{\"id\":12,\"userId\":1,\"event\":\"auth.login\",\"outcome\":\"failure\",\"actor\":\"user\",\"ip\":\"203.0.113.7\"}]}"
+// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"badRequest\",\"data\":\"since must be an RFC 3339 time\"}"
+// @Router /admin/audit [get]
+func (h *AdminHandler) ListAudit(c *fiber.Ctx) error {
+	f := repository.AuditFilter{
+		Event:    c.Query("event"),
+		Outcome:  c.Query("outcome"),
+		BeforeID: int64(c.QueryInt("before")),
+		Limit:    c.QueryInt("limit", 50),
+	}
+	if f.Limit <= 0 || f.Limit > 500 {
+		f.Limit = 50
+	}
+	if v := c.Query("userId"); v != "" {
+		userID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return NewError(fiber.StatusBadRequest, BadRequest, "userId must be a number")
+		}
+		f.UserID = &userID
+	}
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{{"since", &f.Since}, {"until", &f.Until}} {
+		v := c.Query(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return NewError(fiber.StatusBadRequest, BadRequest, p.name+" must be an RFC 3339 time")
+		}
+		*p.dst = &t
+	}
+	events, err := h.auditLog.List(c.UserContext(), f)
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "ListAudit failed", "error", err)
+		return NewError(fiber.StatusInternalServerError, InternalError, "failed to list audit events")
+	}
+	result := make([]dto.AuditEventResponse, 0, len(events))
+	for _, e := range events {
+		r := dto.AuditEventResponse{
+			ID:        e.ID,
+			UserID:    e.UserID,
+			Event:     e.Event,
+			Outcome:   e.Outcome,
+			Actor:     e.Actor,
+			IP:        e.IP,
+			UserAgent: e.UserAgent,
+			CreatedAt: e.CreatedAt,
+			PrevHash:  e.PrevHash,
+			Hash:      e.Hash,
+		}
+		_ = json.Unmarshal([]byte(e.Details), &r.Details)
+		result = append(result, r)
+	}
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(result, fiber.StatusOK, "audit events"))
+}
+
+// VerifyAudit godoc
+// @Summary 감사 로그 해시 체인 검증
+// @Description 모든 감사 이벤트의 해시를 다시 계산하여 수정되거나 삭제된 이벤트가 없는지 확인한다. 보존 기간이 지나 삭제된 이벤트 이후부터 검증한다.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "관리자 API 키"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"audit chain verified\",\"data\":{\"valid\":false,\"checked\":42,\"firstId\":1,\"lastId\":42,\"brokenId\":42}}"
+// @Router /admin/audit/verify [get]
+func (h *AdminHandler) VerifyAudit(c *fiber.Ctx) error {
+	result, err := h.auditLog.Verify(c.UserContext())
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "VerifyAudit failed", "error", err)
+		return NewError(fiber.StatusInternalServerError, InternalError, "failed to verify audit events")
+	}
+	if !result.Valid {
+		slog.ErrorContext(c.UserContext(), "VerifyAudit: hash chain broken", "id", result.BrokenID)
+	}
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(result, fiber.StatusOK, "audit chain verified"))
+}
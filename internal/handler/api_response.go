@@ -7,6 +7,8 @@ type APIResponse struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 	Data    any    `json:"data,omitempty"`
+	// TraceID identifies the request's trace on error responses, to be quoted when reporting a problem.
+	TraceID string `json:"traceId,omitempty"`
 }
 
 // NewAPISuccess returns a successful APIResponse with optional custom message.
@@ -4,26 +4,14 @@ package handler
 import (
 	"auth/internal/dto"
 	"auth/internal/service"
+	"auth/internal/service/link"
+	"auth/pkg/utils"
+	"errors"
 	"log/slog"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-var (
-	// BadRequest is the error code for bad request responses.
-	BadRequest = "badRequest"
-	// ValidationError is the error code for validation errors.
-	ValidationError = "validationError"
-	// Conflict is the error code for conflict responses, e.g., duplicate email.
-	Conflict = "conflict"
-	// InternalError is the error code for internal server errors.
-	InternalError = "internalError"
-	// Unauthorized is the error code for unauthorized access.
-	Unauthorized = "unauthorized"
-	// NotFound is the error code for not found responses.
-	NotFound = "notNound"
-)
-
 // AuthHandler handles HTTP requests for authentication and user management.
 type AuthHandler struct {
 	authService *service.AuthService
@@ -42,28 +30,24 @@ func NewAuthHandler(authSvc *service.AuthService) *AuthHandler {
 // @Param data body dto.RegisterRequest true "회원가입 정보"
 // @Success 201 {object} APIResponse "예시: {\"success\":true,\"code\":201,\"message\":\"회원가입이 완료되었습니다.\",\"data\":{\"id\":1,\"email\":\"user@example.com\"}}"
 // @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"필수 입력값이 누락되었습니다.\",\"data\":null}"
-// @Failure 409 {object} APIResponse "예시: {\"success\":false,\"code\":409,\"message\":\"email already exists\",\"data\":null}"
+// @Failure 409 {object} APIResponse "예시: {\"success\":false,\"code\":409,\"message\":\"conflict\",\"data\":\"email already exists\"}"
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	req := new(dto.RegisterRequest)
 	if err := c.BodyParser(&req); err != nil {
-		slog.Warn("Register: invalid request body", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, "invalid request body"))
+		slog.WarnContext(c.UserContext(), "Register: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request body")
 	}
 	if err := Validate.Struct(req); err != nil {
-		slog.Warn("Register: validation failed", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, ValidationError, err.Error()))
+		slog.WarnContext(c.UserContext(), "Register: validation failed", "error", err)
+		return err
 	}
-	result, err := h.authService.RegisterUser(c.Context(), req)
+	result, err := h.authService.RegisterUser(c.UserContext(), req)
 	if err != nil {
-		if err.Error() == "email already exists" {
-			slog.Warn("Register: email exists", "email", req.Email)
-			return c.Status(fiber.StatusConflict).JSON(NewAPIError(fiber.StatusConflict, Conflict, "email already exists"))
-		}
-		slog.Error("Register: internal error", "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(NewAPIError(fiber.StatusInternalServerError, InternalError, "internal error"))
-	}
-	slog.Info("User registered", "email", req.Email)
+		slog.WarnContext(c.UserContext(), "Register failed", "email", req.Email, "error", err)
+		return err
+	}
+	slog.InfoContext(c.UserContext(), "User registered", "email", req.Email)
 	return c.Status(fiber.StatusCreated).JSON(NewAPISuccess(result, fiber.StatusCreated, "회원가입이 완료되었습니다."))
 }
 
@@ -75,29 +59,25 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 // @Param data body dto.LoginRequest true "로그인 정보"
 // @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"로그인 성공\",\"data\":{\"accessToken\":\"...\",\"refreshToken\":\"...\"}}"
 // @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"invalid request\",\"data\":null}"
-// @Failure 401 {object} APIResponse "예시: {\"success\":false,\"code\":401,\"message\":\"invalid credentials\",\"data\":null}"
+// @Failure 401 {object} APIResponse "예시: {\"success\":false,\"code\":401,\"message\":\"unauthorized\",\"data\":\"invalid credentials\"}"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	req := new(dto.LoginRequest)
 	if err := c.BodyParser(&req); err != nil {
-		slog.Warn("User login invalid request body", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, "invalid request"))
+		slog.WarnContext(c.UserContext(), "User login invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
 	}
 	if err := Validate.Struct(req); err != nil {
-		slog.Warn("Login: validation failed", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, ValidationError, err.Error()))
+		slog.WarnContext(c.UserContext(), "Login: validation failed", "error", err)
+		return err
 	}
 	deviceInfo := c.Get("User-Agent")
-	result, err := h.authService.Login(c.Context(), req, deviceInfo)
+	result, err := h.authService.Login(c.UserContext(), req, deviceInfo)
 	if err != nil {
-		if err.Error() == "user not found" || err.Error() == "invalid password" {
-			slog.Warn("Login failed", "email", req.Email, "error", err)
-			return c.Status(fiber.StatusUnauthorized).JSON(NewAPIError(fiber.StatusUnauthorized, Unauthorized, "invalid credentials"))
-		}
-		slog.Error("Login: internal error", "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(NewAPIError(fiber.StatusInternalServerError, InternalError, "internal error"))
-	}
-	slog.Info("User login success", "email", req.Email)
+		slog.WarnContext(c.UserContext(), "Login failed", "email", req.Email, "error", err)
+		return err
+	}
+	slog.InfoContext(c.UserContext(), "User login success", "email", req.Email)
 	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(result, fiber.StatusOK, "로그인 성공"))
 }
 
@@ -114,42 +94,135 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	var req dto.RefreshTokenRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, "invalid payload"))
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid payload")
 	}
-	accessToken, refreshToken, err := h.authService.RefreshToken(c.Context(), req.RefreshToken)
+	accessToken, refreshToken, err := h.authService.RefreshToken(c.UserContext(), req.RefreshToken)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(NewAPIError(fiber.StatusUnauthorized, Unauthorized, err.Error()))
+		return err
 	}
-	resp := NewAPISuccess(fiber.Map{"accessToken": accessToken, "refreshToken": refreshToken}, fiber.StatusOK, "토큰 재발급 성공")
+	resp := NewAPISuccess(dto.RefreshTokenResponse{AccessToken: accessToken, RefreshToken: refreshToken}, fiber.StatusOK, "토큰 재발급 성공")
 	return c.JSON(resp)
 }
 
+// RequestMagicLink godoc
+// @Summary 매직 링크 로그인 메일 발송
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param data body dto.MagicLinkRequest true "이메일"
+// @Param Accept-Language header string false "메일 언어 (프로필에 언어가 설정되어 있으면 무시)"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"magic link sent\",\"data\":null}"
+// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"invalidRedirect\",\"data\":\"redirect url not allowed\"}"
+// @Router /auth/magic-link [post]
+func (h *AuthHandler) RequestMagicLink(c *fiber.Ctx) error {
+	req := new(dto.MagicLinkRequest)
+	if err := c.BodyParser(&req); err != nil {
+		slog.WarnContext(c.UserContext(), "RequestMagicLink: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
+	}
+	if err := Validate.Struct(req); err != nil {
+		slog.WarnContext(c.UserContext(), "RequestMagicLink: validation failed", "error", err)
+		return err
+	}
+	// 계정 존재 여부를 노출하지 않도록 허용되지 않은 redirect 외에는 결과와 관계없이 성공 응답
+	err := h.authService.RequestMagicLink(c.UserContext(), req.Email, req.RedirectURL, c.Get("User-Agent"), c.Get(fiber.HeaderAcceptLanguage))
+	if errors.Is(err, link.ErrRedirectNotAllowed) {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(nil, fiber.StatusOK, "magic link sent"))
+}
+
+// VerifyMagicLink godoc
+// @Summary 매직 링크 로그인
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param data body dto.MagicLinkVerifyRequest true "매직 링크 토큰"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"로그인 성공\",\"data\":{\"accessToken\":\"...\",\"refreshToken\":\"...\"}}"
+// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"invalid request\",\"data\":null}"
+// @Failure 401 {object} APIResponse "예시: {\"success\":false,\"code\":401,\"message\":\"unauthorized\",\"data\":\"invalid, expired, or already used magic link\"}"
+// @Description 링크에 redirect 가 있으면 redirect 와 sig 를 함께 보내야 하며, 검증된 redirect 가 응답에 포함된다.
+// @Router /auth/magic-link/verify [post]
+func (h *AuthHandler) VerifyMagicLink(c *fiber.Ctx) error {
+	req := new(dto.MagicLinkVerifyRequest)
+	if err := c.BodyParser(&req); err != nil {
+		slog.WarnContext(c.UserContext(), "VerifyMagicLink: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
+	}
+	if err := Validate.Struct(req); err != nil {
+		slog.WarnContext(c.UserContext(), "VerifyMagicLink: validation failed", "error", err)
+		return err
+	}
+	if err := h.authService.VerifyLinkRedirect(link.FlowMagicLink, req.Token, req.Redirect, req.Signature); err != nil {
+		slog.WarnContext(c.UserContext(), "VerifyMagicLink: invalid redirect", "error", err)
+		return err
+	}
+	result, err := h.authService.VerifyMagicLink(c.UserContext(), req.Token, c.Get("User-Agent"))
+	if err != nil {
+		slog.WarnContext(c.UserContext(), "VerifyMagicLink failed", "error", err)
+		return err
+	}
+	result.Redirect = req.Redirect
+	slog.InfoContext(c.UserContext(), "User magic link login success", "userID", result.UserID)
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(result, fiber.StatusOK, "로그인 성공"))
+}
+
+// SendFindEmailCode godoc
+// @Summary 이메일 찾기 인증번호 발송
+// @Description 가입된 번호일 때만 SMS 가 발송되며, 응답은 가입 여부와 관계없이 같다.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param data body dto.PhoneCodeRequest true "휴대폰 번호"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"verification code sent\",\"data\":null}"
+// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"invalid request\",\"data\":null}"
+// @Failure 429 {object} APIResponse "예시: {\"success\":false,\"code\":429,\"message\":\"tooManyRequests\",\"data\":\"verification code recently sent\"}"
+// @Router /auth/phone/code [post]
+func (h *AuthHandler) SendFindEmailCode(c *fiber.Ctx) error {
+	req := new(dto.PhoneCodeRequest)
+	if err := c.BodyParser(&req); err != nil {
+		slog.WarnContext(c.UserContext(), "SendFindEmailCode: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
+	}
+	if err := Validate.Struct(req); err != nil {
+		slog.WarnContext(c.UserContext(), "SendFindEmailCode: validation failed", "error", err)
+		return err
+	}
+	if err := h.authService.SendPhoneCode(c.UserContext(), 0, req.PhoneNumber, service.PhonePurposeFindEmail); err != nil {
+		slog.WarnContext(c.UserContext(), "SendFindEmailCode failed", "error", err)
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(nil, fiber.StatusOK, "verification code sent"))
+}
+
 // FindEmail godoc
 // @Summary 이메일(아이디) 찾기
+// @Description /auth/phone/code 로 받은 SMS 인증번호가 필요하다.
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Param data body dto.FindEmailRequest true "휴대폰 번호"
+// @Param data body dto.FindEmailRequest true "휴대폰 번호와 인증번호"
 // @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"이메일 찾기 성공\",\"data\":{\"email\":\"user@example.com\"}}"
-// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"invalid request\",\"data\":null}"
-// @Failure 404 {object} APIResponse "예시: {\"success\":false,\"code\":404,\"message\":\"not found\",\"data\":null}"
+// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"verificationFailed\",\"data\":\"invalid verification code\"}"
+// @Failure 429 {object} APIResponse "예시: {\"success\":false,\"code\":429,\"message\":\"tooManyRequests\",\"data\":\"too many verification attempts\"}"
+// @Failure 404 {object} APIResponse "예시: {\"success\":false,\"code\":404,\"message\":\"notFound\",\"data\":\"user not found\"}"
 // @Router /auth/email/recover [post]
 func (h *AuthHandler) FindEmail(c *fiber.Ctx) error {
 	req := new(dto.FindEmailRequest)
 	if err := c.BodyParser(&req); err != nil {
-		slog.Warn("FindEmail: invalid request body", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, "invalid request"))
+		slog.WarnContext(c.UserContext(), "FindEmail: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
 	}
 	if err := Validate.Struct(req); err != nil {
-		slog.Warn("FindEmail: validation failed", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, ValidationError, err.Error()))
+		slog.WarnContext(c.UserContext(), "FindEmail: validation failed", "error", err)
+		return err
 	}
-	result, err := h.authService.FindEmail(c.Context(), req)
+	result, err := h.authService.FindEmail(c.UserContext(), req)
 	if err != nil {
-		slog.Warn("FindEmail failed", "error", err)
-		return c.Status(fiber.StatusNotFound).JSON(NewAPIError(fiber.StatusNotFound, NotFound, "not found"))
+		slog.WarnContext(c.UserContext(), "FindEmail failed", "error", err)
+		return err
 	}
-	slog.Info("FindEmail success", "phone", req.PhoneNumber)
+	slog.InfoContext(c.UserContext(), "FindEmail success", "phone", utils.MaskPhone(req.PhoneNumber))
 	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(result, fiber.StatusOK, "이메일 찾기 성공"))
 }
 
@@ -159,20 +232,24 @@ func (h *AuthHandler) FindEmail(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param data body dto.ForgotPasswordRequest true "이메일"
+// @Param Accept-Language header string false "메일 언어 (프로필에 언어가 설정되어 있으면 무시)"
 // @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"password reset email sent\",\"data\":null}"
-// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"invalid request\",\"data\":null}"
+// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"invalidRedirect\",\"data\":\"redirect url not allowed\"}"
 // @Router /auth/password/forgot [post]
 func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
 	req := new(dto.ForgotPasswordRequest)
 	if err := c.BodyParser(&req); err != nil {
-		slog.Warn("ForgotPassword: invalid request body", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, "invalid request"))
+		slog.WarnContext(c.UserContext(), "ForgotPassword: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
 	}
 	if err := Validate.Struct(req); err != nil {
-		slog.Warn("ForgotPassword: validation failed", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, ValidationError, err.Error()))
+		slog.WarnContext(c.UserContext(), "ForgotPassword: validation failed", "error", err)
+		return err
+	}
+	err := h.authService.ForgotPassword(c.UserContext(), req.Email, req.RedirectURL, c.Get(fiber.HeaderAcceptLanguage))
+	if errors.Is(err, link.ErrRedirectNotAllowed) {
+		return err
 	}
-	_ = h.authService.ForgotPassword(c.Context(), req.Email)
 	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(nil, fiber.StatusOK, "password reset email sent"))
 }
 
@@ -182,26 +259,34 @@ func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param data body dto.ResetPasswordRequest true "비밀번호 재설정 정보"
-// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"password reset successful\",\"data\":null}"
-// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"invalid or expired token\",\"data\":null}"
+// @Description 링크에 redirect 가 있으면 redirect 와 sig 를 함께 보내야 하며, 검증된 redirect 가 응답에 포함된다.
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"password reset successful\",\"data\":{\"redirect\":\"https://app.example.com/login\"}}"
+// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"invalidToken\",\"data\":\"invalid, expired, or already used token\"}"
 // @Router /auth/password/reset [post]
 func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
 	req := new(dto.ResetPasswordRequest)
 	if err := c.BodyParser(&req); err != nil {
-		slog.Warn("ResetPassword: invalid request body", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, "invalid request"))
+		slog.WarnContext(c.UserContext(), "ResetPassword: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
 	}
 	if err := Validate.Struct(req); err != nil {
-		slog.Warn("ResetPassword: validation failed", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, ValidationError, err.Error()))
+		slog.WarnContext(c.UserContext(), "ResetPassword: validation failed", "error", err)
+		return err
 	}
-	err := h.authService.ResetPassword(c.Context(), req.Token, req.NewPassword)
-	if err != nil {
-		slog.Warn("ResetPassword failed", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, "invalid or expired token"))
+	if err := h.authService.VerifyLinkRedirect(link.FlowPasswordReset, req.Token, req.Redirect, req.Signature); err != nil {
+		slog.WarnContext(c.UserContext(), "ResetPassword: invalid redirect", "error", err)
+		return err
+	}
+	if err := h.authService.ResetPassword(c.UserContext(), req.Token, req.NewPassword); err != nil {
+		slog.WarnContext(c.UserContext(), "ResetPassword failed", "error", err)
+		return err
+	}
+	slog.InfoContext(c.UserContext(), "ResetPassword success")
+	var data interface{}
+	if req.Redirect != "" {
+		data = fiber.Map{"redirect": req.Redirect}
 	}
-	slog.Info("ResetPassword success")
-	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(nil, fiber.StatusOK, "password reset successful"))
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(data, fiber.StatusOK, "password reset successful"))
 }
 
 // Logout godoc
@@ -216,19 +301,19 @@ func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 	req := new(dto.LogoutRequest)
 	if err := c.BodyParser(&req); err != nil {
-		slog.Warn("Logout: invalid request body", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, "invalid request"))
+		slog.WarnContext(c.UserContext(), "Logout: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
 	}
 	if req.RefreshToken == "" {
-		slog.Warn("Logout: missing refresh token")
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, "refresh token required"))
+		slog.WarnContext(c.UserContext(), "Logout: missing refresh token")
+		return NewError(fiber.StatusBadRequest, BadRequest, "refresh token required")
 	}
 	userID, deviceInfo, err := h.authService.JwtSvc().ValidateRefreshToken(req.RefreshToken)
 	if err == nil {
-		_ = h.authService.Logout(c.Context(), userID, req.RefreshToken, deviceInfo)
-		slog.Info("Logout success", "userID", userID)
+		_ = h.authService.Logout(c.UserContext(), userID, req.RefreshToken, deviceInfo)
+		slog.InfoContext(c.UserContext(), "Logout success", "userID", userID)
 	} else {
-		slog.Warn("Logout: invalid refresh token", "error", err)
+		slog.WarnContext(c.UserContext(), "Logout: invalid refresh token", "error", err)
 	}
 	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(nil, fiber.StatusOK, "logout successful"))
 }
@@ -240,20 +325,20 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 // @Produce json
 // @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"프로필 조회 성공\",\"data\":{\"id\":1,\"email\":\"user@example.com\"}}"
 // @Failure 401 {object} APIResponse "예시: {\"success\":false,\"code\":401,\"message\":\"unauthorized\",\"data\":null}"
-// @Failure 404 {object} APIResponse "예시: {\"success\":false,\"code\":404,\"message\":\"profile not found\",\"data\":null}"
+// @Failure 404 {object} APIResponse "예시: {\"success\":false,\"code\":404,\"message\":\"notFound\",\"data\":\"profile not found\"}"
 // @Router /users/me [get]
 func (h *AuthHandler) GetProfile(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(int64)
 	if !ok {
-		slog.Warn("GetProfile: unauthorized access")
-		return c.Status(fiber.StatusUnauthorized).JSON(NewAPIError(fiber.StatusUnauthorized, Unauthorized, "unauthorized"))
+		slog.WarnContext(c.UserContext(), "GetProfile: unauthorized access")
+		return NewError(fiber.StatusUnauthorized, Unauthorized, "unauthorized")
 	}
-	profile, err := h.authService.GetProfile(c.Context(), userID)
+	profile, err := h.authService.GetProfile(c.UserContext(), userID)
 	if err != nil {
-		slog.Warn("GetProfile failed", "userID", userID, "error", err)
-		return c.Status(fiber.StatusNotFound).JSON(NewAPIError(fiber.StatusNotFound, NotFound, "profile not found"))
+		slog.WarnContext(c.UserContext(), "GetProfile failed", "userID", userID, "error", err)
+		return err
 	}
-	slog.Info("GetProfile success", "userID", userID)
+	slog.InfoContext(c.UserContext(), "GetProfile success", "userID", userID)
 	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(profile, fiber.StatusOK, "프로필 조회 성공"))
 }
 
@@ -263,6 +348,8 @@ func (h *AuthHandler) GetProfile(c *fiber.Ctx) error {
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
+// @Description 전화번호를 바꿀 때는 /users/me/phone/code 로 새 번호에 받은 인증번호(phoneVerificationCode)가 필요하다.
+// @Description locale 은 메일 언어(예: ko, en)이며 비어 있으면 기존 값을 유지한다.
 // @Param data body dto.UpdateProfileRequest true "프로필 정보"
 // @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"profile updated successfully\",\"data\":null}"
 // @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"invalid request\",\"data\":null}"
@@ -271,27 +358,95 @@ func (h *AuthHandler) GetProfile(c *fiber.Ctx) error {
 func (h *AuthHandler) UpdateProfile(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(int64)
 	if !ok {
-		slog.Warn("UpdateProfile: unauthorized access")
-		return c.Status(fiber.StatusUnauthorized).JSON(NewAPIError(fiber.StatusUnauthorized, Unauthorized, "unauthorized"))
+		slog.WarnContext(c.UserContext(), "UpdateProfile: unauthorized access")
+		return NewError(fiber.StatusUnauthorized, Unauthorized, "unauthorized")
 	}
 	req := new(dto.UpdateProfileRequest)
 	if err := c.BodyParser(&req); err != nil {
-		slog.Warn("UpdateProfile: invalid request body", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, "invalid request"))
+		slog.WarnContext(c.UserContext(), "UpdateProfile: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
 	}
 	if err := Validate.Struct(req); err != nil {
-		slog.Warn("UpdateProfile: validation failed", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, ValidationError, err.Error()))
+		slog.WarnContext(c.UserContext(), "UpdateProfile: validation failed", "error", err)
+		return err
 	}
-	_, err := h.authService.UpdateProfile(c.Context(), userID, req)
+	_, err := h.authService.UpdateProfile(c.UserContext(), userID, req)
 	if err != nil {
-		slog.Warn("UpdateProfile failed", "userID", userID, "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, err.Error()))
+		slog.WarnContext(c.UserContext(), "UpdateProfile failed", "userID", userID, "error", err)
+		return err
 	}
-	slog.Info("UpdateProfile success", "userID", userID)
+	slog.InfoContext(c.UserContext(), "UpdateProfile success", "userID", userID)
 	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(nil, fiber.StatusOK, "profile updated successfully"))
 }
 
+// SendPhoneCode godoc
+// @Summary 전화번호 인증번호 발송
+// @Description phoneNumber 를 생략하면 현재 등록된 번호로 발송한다.
+// @Tags User
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body dto.SendPhoneCodeRequest true "휴대폰 번호"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"verification code sent\",\"data\":null}"
+// @Failure 409 {object} APIResponse "예시: {\"success\":false,\"code\":409,\"message\":\"conflict\",\"data\":\"phone number already in use\"}"
+// @Failure 429 {object} APIResponse "예시: {\"success\":false,\"code\":429,\"message\":\"tooManyRequests\",\"data\":\"verification code recently sent\"}"
+// @Router /users/me/phone/code [post]
+func (h *AuthHandler) SendPhoneCode(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		slog.WarnContext(c.UserContext(), "SendPhoneCode: unauthorized access")
+		return NewError(fiber.StatusUnauthorized, Unauthorized, "unauthorized")
+	}
+	req := new(dto.SendPhoneCodeRequest)
+	if err := c.BodyParser(&req); err != nil {
+		slog.WarnContext(c.UserContext(), "SendPhoneCode: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
+	}
+	if err := Validate.Struct(req); err != nil {
+		slog.WarnContext(c.UserContext(), "SendPhoneCode: validation failed", "error", err)
+		return err
+	}
+	if err := h.authService.SendPhoneCode(c.UserContext(), userID, req.PhoneNumber, service.PhonePurposeVerifyPhone); err != nil {
+		slog.WarnContext(c.UserContext(), "SendPhoneCode failed", "userID", userID, "error", err)
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(nil, fiber.StatusOK, "verification code sent"))
+}
+
+// VerifyPhone godoc
+// @Summary 현재 전화번호 인증
+// @Tags User
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body dto.VerifyPhoneRequest true "인증번호"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"phone number verified\",\"data\":null}"
+// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"verificationFailed\",\"data\":\"invalid verification code\"}"
+// @Failure 429 {object} APIResponse "예시: {\"success\":false,\"code\":429,\"message\":\"tooManyRequests\",\"data\":\"too many verification attempts\"}"
+// @Router /users/me/phone/verify [post]
+func (h *AuthHandler) VerifyPhone(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		slog.WarnContext(c.UserContext(), "VerifyPhone: unauthorized access")
+		return NewError(fiber.StatusUnauthorized, Unauthorized, "unauthorized")
+	}
+	req := new(dto.VerifyPhoneRequest)
+	if err := c.BodyParser(&req); err != nil {
+		slog.WarnContext(c.UserContext(), "VerifyPhone: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
+	}
+	if err := Validate.Struct(req); err != nil {
+		slog.WarnContext(c.UserContext(), "VerifyPhone: validation failed", "error", err)
+		return err
+	}
+	if err := h.authService.VerifyPhone(c.UserContext(), userID, req.Code); err != nil {
+		slog.WarnContext(c.UserContext(), "VerifyPhone failed", "userID", userID, "error", err)
+		return err
+	}
+	slog.InfoContext(c.UserContext(), "VerifyPhone success", "userID", userID)
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(nil, fiber.StatusOK, "phone number verified"))
+}
+
 // DeleteProfile godoc
 // @Summary 회원 탈퇴(소프트 삭제)
 // @Tags User
@@ -306,28 +461,28 @@ func (h *AuthHandler) UpdateProfile(c *fiber.Ctx) error {
 func (h *AuthHandler) DeleteProfile(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(int64)
 	if !ok {
-		slog.Warn("DeleteProfile: unauthorized access")
-		return c.Status(fiber.StatusUnauthorized).JSON(NewAPIError(fiber.StatusUnauthorized, Unauthorized, "unauthorized"))
+		slog.WarnContext(c.UserContext(), "DeleteProfile: unauthorized access")
+		return NewError(fiber.StatusUnauthorized, Unauthorized, "unauthorized")
 	}
 	req := new(dto.DeleteProfileRequest)
 	if err := c.BodyParser(&req); err != nil {
-		slog.Warn("DeleteProfile: invalid request body", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, "invalid request"))
+		slog.WarnContext(c.UserContext(), "DeleteProfile: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
 	}
 	if err := Validate.Struct(req); err != nil {
-		slog.Warn("DeleteProfile: validation failed", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, ValidationError, err.Error()))
+		slog.WarnContext(c.UserContext(), "DeleteProfile: validation failed", "error", err)
+		return err
 	}
-	err := h.authService.CheckPassword(c.Context(), userID, req.CurrentPassword)
+	err := h.authService.CheckPassword(c.UserContext(), userID, req.CurrentPassword)
 	if err != nil {
-		slog.Warn("DeleteProfile: password check failed", "userID", userID, "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, err.Error()))
+		slog.WarnContext(c.UserContext(), "DeleteProfile: password check failed", "userID", userID, "error", err)
+		return err
 	}
-	if err := h.authService.DeleteProfile(c.Context(), userID); err != nil {
-		slog.Error("DeleteProfile failed", "userID", userID, "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(NewAPIError(fiber.StatusInternalServerError, InternalError, "internal error"))
+	if err := h.authService.DeleteProfile(c.UserContext(), userID); err != nil {
+		slog.ErrorContext(c.UserContext(), "DeleteProfile failed", "userID", userID, "error", err)
+		return err
 	}
-	slog.Info("DeleteProfile success", "userID", userID)
+	slog.InfoContext(c.UserContext(), "DeleteProfile success", "userID", userID)
 	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(nil, fiber.StatusOK, "account deleted (soft delete)"))
 }
 
@@ -345,23 +500,105 @@ func (h *AuthHandler) DeleteProfile(c *fiber.Ctx) error {
 func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(int64)
 	if !ok {
-		slog.Warn("ChangePassword: unauthorized access")
-		return c.Status(fiber.StatusUnauthorized).JSON(NewAPIError(fiber.StatusUnauthorized, Unauthorized, "unauthorized"))
+		slog.WarnContext(c.UserContext(), "ChangePassword: unauthorized access")
+		return NewError(fiber.StatusUnauthorized, Unauthorized, "unauthorized")
 	}
 	req := new(dto.ChangePasswordRequest)
 	if err := c.BodyParser(&req); err != nil {
-		slog.Warn("ChangePassword: invalid request body", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, "invalid request"))
+		slog.WarnContext(c.UserContext(), "ChangePassword: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
 	}
 	if err := Validate.Struct(req); err != nil {
-		slog.Warn("ChangePassword: validation failed", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, ValidationError, err.Error()))
+		slog.WarnContext(c.UserContext(), "ChangePassword: validation failed", "error", err)
+		return err
 	}
-	err := h.authService.ChangePassword(c.Context(), userID, req.OldPassword, req.NewPassword)
-	if err != nil {
-		slog.Warn("ChangePassword failed", "userID", userID, "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(NewAPIError(fiber.StatusBadRequest, BadRequest, err.Error()))
+	if err := h.authService.ChangePassword(c.UserContext(), userID, req.OldPassword, req.NewPassword); err != nil {
+		slog.WarnContext(c.UserContext(), "ChangePassword failed", "userID", userID, "error", err)
+		return err
 	}
-	slog.Info("ChangePassword success", "userID", userID)
+	slog.InfoContext(c.UserContext(), "ChangePassword success", "userID", userID)
 	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(nil, fiber.StatusOK, "password changed successfully"))
 }
+
+// GetNotificationPreferences godoc
+// @Summary 내 보안 알림 설정 조회
+// @Tags User
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"알림 설정 조회 성공\",\"data\":{\"newDeviceLogin\":true,\"passwordChanged\":true}}"
+// @Failure 401 {object} APIResponse "예시: {\"success\":false,\"code\":401,\"message\":\"unauthorized\",\"data\":null}"
+// @Router /users/me/notifications [get]
+func (h *AuthHandler) GetNotificationPreferences(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		slog.WarnContext(c.UserContext(), "GetNotificationPreferences: unauthorized access")
+		return NewError(fiber.StatusUnauthorized, Unauthorized, "unauthorized")
+	}
+	prefs, err := h.authService.GetNotificationPreferences(c.UserContext(), userID)
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "GetNotificationPreferences failed", "userID", userID, "error", err)
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(prefs, fiber.StatusOK, "알림 설정 조회 성공"))
+}
+
+// GetActivity godoc
+// @Summary 내 계정 활동 조회
+// @Description 로그인, 비밀번호 변경 등 내 계정의 보안 이벤트를 최신 순으로 조회한다. 다음 페이지는 마지막 이벤트의 id 를 before 로 넘긴다.
+// @Tags User
+// @Security ApiKeyAuth
+// @Produce json
+// @Param before query int false "이 id 이전 이벤트만"
+// @Param limit query int false "최대 개수 (기본값 20, 최대 100)"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"활동 조회 성공\",\"data\":[{\"id\":12,\"event\":\"auth.login\",\"outcome\":\"success\",\"actor\":\"user\",\"ip\":\"203.0.113.7\",\"userAgent\":\"Mozilla/5.0\",\"details\":{\"method\":\"password\"},\"createdAt\":\"2025-01-01T00:00:00Z\"}]}"
+// @Failure 401 {object} APIResponse "예시: {\"success\":false,\"code\":401,\"message\":\"unauthorized\",\"data\":null}"
+// @Router /users/me/activity [get]
+func (h *AuthHandler) GetActivity(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		slog.WarnContext(c.UserContext(), "GetActivity: unauthorized access")
+		return NewError(fiber.StatusUnauthorized, Unauthorized, "unauthorized")
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	events, err := h.authService.ListActivity(c.UserContext(), userID, int64(c.QueryInt("before")), limit)
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "GetActivity failed", "userID", userID, "error", err)
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(events, fiber.StatusOK, "활동 조회 성공"))
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary 내 보안 알림 설정 변경
+// @Description 보낸 항목만 변경되고 나머지는 유지됩니다.
+// @Tags User
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param data body dto.UpdateNotificationPreferencesRequest true "알림 설정"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"알림 설정 변경 성공\",\"data\":{\"newDeviceLogin\":false,\"passwordChanged\":true}}"
+// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"invalid request\",\"data\":null}"
+// @Failure 401 {object} APIResponse "예시: {\"success\":false,\"code\":401,\"message\":\"unauthorized\",\"data\":null}"
+// @Router /users/me/notifications [put]
+func (h *AuthHandler) UpdateNotificationPreferences(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int64)
+	if !ok {
+		slog.WarnContext(c.UserContext(), "UpdateNotificationPreferences: unauthorized access")
+		return NewError(fiber.StatusUnauthorized, Unauthorized, "unauthorized")
+	}
+	req := new(dto.UpdateNotificationPreferencesRequest)
+	if err := c.BodyParser(req); err != nil {
+		slog.WarnContext(c.UserContext(), "UpdateNotificationPreferences: invalid request body", "error", err)
+		return NewError(fiber.StatusBadRequest, BadRequest, "invalid request")
+	}
+	prefs, err := h.authService.UpdateNotificationPreferences(c.UserContext(), userID, req)
+	if err != nil {
+		slog.ErrorContext(c.UserContext(), "UpdateNotificationPreferences failed", "userID", userID, "error", err)
+		return err
+	}
+	slog.InfoContext(c.UserContext(), "UpdateNotificationPreferences success", "userID", userID)
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(prefs, fiber.StatusOK, "알림 설정 변경 성공"))
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"auth/internal/service/email"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DevHandler handles development-only endpoints. Routes are registered only when APP_ENV=dev.
+type DevHandler struct {
+	email *email.Service
+}
+
+// NewDevHandler creates a new DevHandler.
+func NewDevHandler(emailService *email.Service) *DevHandler {
+	return &DevHandler{email: emailService}
+}
+
+// PreviewEmail godoc
+// @Summary 메일 템플릿 미리보기 (개발 환경 전용)
+// @Description 예시 데이터로 메일 템플릿을 렌더링한다. format=text 이면 제목과 텍스트 본문, 그 외에는 HTML 본문을 반환한다.
+// @Tags Dev
+// @Produce html
+// @Param template path string true "템플릿 이름 (예: password_reset, magic_link)"
+// @Param locale query string false "언어 (기본값 Accept-Language)"
+// @Param format query string false "html 또는 text (기본값 html)"
+// @Success 200 {string} string "렌더링된 메일"
+// @Failure 404 {object} APIResponse "예시: {\"success\":false,\"code\":404,\"message\":\"notFound\",\"data\":\"unknown email template\"}"
+// @Router /dev/emails/{template} [get]
+func (h *DevHandler) PreviewEmail(c *fiber.Ctx) error {
+	locale := h.email.Locale(c.Query("locale"), c.Get(fiber.HeaderAcceptLanguage))
+	rendered, err := h.email.Preview(c.Params("template"), locale)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentLanguage, locale)
+	if c.Query("format") == "text" {
+		c.Type("txt", "utf-8")
+		return c.SendString("Subject: " + rendered.Subject + "\n\n" + rendered.Text)
+	}
+	c.Type("html", "utf-8")
+	return c.SendString(rendered.HTML)
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"auth/internal/service"
+	"auth/internal/service/email"
+	"auth/internal/service/link"
+	"auth/internal/service/password"
+	"auth/internal/service/phone"
+	"auth/internal/tracing"
+	"errors"
+	"log/slog"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Error codes returned in APIResponse.Message of failed requests. Clients may rely on them;
+// the human readable reason, if any, is in APIResponse.Data.
+const (
+	// BadRequest is the error code for bad request responses.
+	BadRequest = "badRequest"
+	// ValidationError is the error code for validation errors.
+	ValidationError = "validationError"
+	// Conflict is the error code for conflict responses, e.g., duplicate email.
+	Conflict = "conflict"
+	// InternalError is the error code for internal server errors.
+	InternalError = "internalError"
+	// ServiceUnavailable is the error code for features that are not configured on this server.
+	ServiceUnavailable = "serviceUnavailable"
+	// Unauthorized is the error code for unauthorized access.
+	Unauthorized = "unauthorized"
+	// AccountDisabled is the error code for signing in to an account an administrator disabled.
+	AccountDisabled = "accountDisabled"
+	// NotFound is the error code for not found responses.
+	NotFound = "notFound"
+	// PasswordPolicy is the error code for passwords rejected by the password policy.
+	PasswordPolicy = "passwordPolicy"
+	// IncorrectPassword is the error code for a wrong current password when confirming a change.
+	IncorrectPassword = "incorrectPassword"
+	// InvalidToken is the error code for unknown, expired or already used email link tokens.
+	InvalidToken = "invalidToken"
+	// VerificationFailed is the error code for missing, wrong or expired SMS verification codes.
+	VerificationFailed = "verificationFailed"
+	// TooManyRequests is the error code for rate limited requests.
+	TooManyRequests = "tooManyRequests"
+	// InvalidRedirect is the error code for redirect URLs not in the allowlist or with a wrong link signature.
+	InvalidRedirect = "invalidRedirect"
+)
+
+// Error is a failure with an explicit status and error code, for handler level errors such as
+// malformed request bodies. Handlers return it and the ErrorHandler writes the response.
+type Error struct {
+	Status  int
+	Code    string
+	Details any
+}
+
+// NewError returns an *Error with the status, error code and optional details.
+func NewError(status int, code string, details any) *Error {
+	return &Error{Status: status, Code: code, Details: details}
+}
+
+func (e *Error) Error() string {
+	if s, ok := e.Details.(string); ok && s != "" {
+		return e.Code + ": " + s
+	}
+	return e.Code
+}
+
+// errorMappings maps domain errors to their HTTP status and error code, in match order.
+var errorMappings = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{service.ErrEmailExists, fiber.StatusConflict, Conflict},
+	{service.ErrPhoneNumberInUse, fiber.StatusConflict, Conflict},
+	{service.ErrInvalidCredentials, fiber.StatusUnauthorized, Unauthorized},
+	{service.ErrAccountDisabled, fiber.StatusForbidden, AccountDisabled},
+	{service.ErrInvalidToken, fiber.StatusUnauthorized, Unauthorized},
+	{service.ErrTokenExpired, fiber.StatusUnauthorized, Unauthorized},
+	{service.ErrInvalidMagicLink, fiber.StatusUnauthorized, Unauthorized},
+	{service.ErrMagicLinkDeviceMismatch, fiber.StatusUnauthorized, Unauthorized},
+	{service.ErrIncorrectPassword, fiber.StatusBadRequest, IncorrectPassword},
+	{service.ErrInvalidResetToken, fiber.StatusBadRequest, InvalidToken},
+	{service.ErrInvalidBirthDate, fiber.StatusBadRequest, ValidationError},
+	{service.ErrUserNotFound, fiber.StatusNotFound, NotFound},
+	{service.ErrProfileNotFound, fiber.StatusNotFound, NotFound},
+	{service.ErrVerificationCodeRequired, fiber.StatusBadRequest, VerificationFailed},
+	{service.ErrInvalidVerificationCode, fiber.StatusBadRequest, VerificationFailed},
+	{service.ErrVerificationCodeExpired, fiber.StatusBadRequest, VerificationFailed},
+	{service.ErrTooManyVerificationAttempts, fiber.StatusTooManyRequests, TooManyRequests},
+	{service.ErrVerificationCodeRecentlySent, fiber.StatusTooManyRequests, TooManyRequests},
+	{service.ErrTooManyVerificationCodes, fiber.StatusTooManyRequests, TooManyRequests},
+	{service.ErrPhoneVerificationUnavailable, fiber.StatusServiceUnavailable, ServiceUnavailable},
+	{phone.ErrInvalidNumber, fiber.StatusBadRequest, ValidationError},
+	{phone.ErrNotMobile, fiber.StatusBadRequest, ValidationError},
+	{link.ErrRedirectNotAllowed, fiber.StatusBadRequest, InvalidRedirect},
+	{link.ErrInvalidSignature, fiber.StatusBadRequest, InvalidRedirect},
+	{email.ErrUnknownTemplate, fiber.StatusNotFound, NotFound},
+}
+
+// MapError returns the HTTP status, error code and details for an error returned by a handler.
+// Domain errors are reported with their own message, validation errors with a FieldError per
+// field, password policy violations with the list of violations, and *Error and *fiber.Error
+// with their own status. Any other error is an internal error whose message is not exposed.
+//
+// Validation messages are in DefaultValidationLocale; the ErrorHandler uses the request's Accept-Language.
+func MapError(err error) (status int, code string, details any) {
+	return mapError(err, Translator(""))
+}
+
+// MapErrorLocale is MapError with validation messages in the language best matching an
+// Accept-Language value, for transports other than the Fiber app, e.g. gRPC.
+func MapErrorLocale(err error, acceptLanguage string) (status int, code string, details any) {
+	return mapError(err, Translator(acceptLanguage))
+}
+
+func mapError(err error, trans ut.Translator) (status int, code string, details any) {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Status, apiErr.Code, apiErr.Details
+	}
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return fiber.StatusBadRequest, ValidationError, fieldErrors(validationErrs, trans)
+	}
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		return fiber.StatusBadRequest, PasswordPolicy, policyErr.Violations
+	}
+	for _, m := range errorMappings {
+		if errors.Is(err, m.err) {
+			return m.status, m.code, m.err.Error()
+		}
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code, statusCode(fiberErr.Code), fiberErr.Message
+	}
+	return fiber.StatusInternalServerError, InternalError, "internal error"
+}
+
+// statusCode returns the error code for a bare HTTP status.
+func statusCode(status int) string {
+	switch status {
+	case fiber.StatusBadRequest, fiber.StatusRequestEntityTooLarge, fiber.StatusUnsupportedMediaType:
+		return BadRequest
+	case fiber.StatusUnauthorized, fiber.StatusForbidden:
+		return Unauthorized
+	case fiber.StatusNotFound, fiber.StatusMethodNotAllowed:
+		return NotFound
+	case fiber.StatusConflict:
+		return Conflict
+	case fiber.StatusTooManyRequests:
+		return TooManyRequests
+	case fiber.StatusServiceUnavailable:
+		return ServiceUnavailable
+	}
+	return InternalError
+}
+
+// ErrorHandler is the Fiber error handler. It writes the response for errors returned by
+// handlers and middleware, as mapped by MapError: an APIResponse, or Problem Details when the
+// client prefers application/problem+json.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	status, code, details := mapError(err, Translator(c.Get(fiber.HeaderAcceptLanguage)))
+	if status >= fiber.StatusInternalServerError {
+		slog.ErrorContext(c.UserContext(), "request failed", "method", c.Method(), "path", c.Path(), "error", err)
+	}
+	traceID := tracing.TraceID(c.UserContext())
+	if wantsProblem(c) {
+		p := NewProblem(status, code, details, c.Path())
+		p.TraceID = traceID
+		return c.Status(status).JSON(p, MIMEApplicationProblemJSON)
+	}
+	resp := NewAPIError(status, code, details)
+	resp.TraceID = traceID
+	return c.Status(status).JSON(resp)
+}
@@ -0,0 +1,107 @@
+package handler_test
+
+import (
+	"auth/internal/handler"
+	"auth/internal/service"
+	"auth/internal/service/email"
+	"auth/internal/service/link"
+	"auth/internal/service/password"
+	"auth/internal/service/phone"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapError_도메인에러(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{service.ErrEmailExists, fiber.StatusConflict, handler.Conflict},
+		{service.ErrPhoneNumberInUse, fiber.StatusConflict, handler.Conflict},
+		{service.ErrInvalidCredentials, fiber.StatusUnauthorized, handler.Unauthorized},
+		{service.ErrInvalidToken, fiber.StatusUnauthorized, handler.Unauthorized},
+		{service.ErrTokenExpired, fiber.StatusUnauthorized, handler.Unauthorized},
+		{service.ErrInvalidMagicLink, fiber.StatusUnauthorized, handler.Unauthorized},
+		{service.ErrMagicLinkDeviceMismatch, fiber.StatusUnauthorized, handler.Unauthorized},
+		{service.ErrIncorrectPassword, fiber.StatusBadRequest, handler.IncorrectPassword},
+		{service.ErrInvalidResetToken, fiber.StatusBadRequest, handler.InvalidToken},
+		{service.ErrInvalidBirthDate, fiber.StatusBadRequest, handler.ValidationError},
+		{service.ErrUserNotFound, fiber.StatusNotFound, handler.NotFound},
+		{service.ErrProfileNotFound, fiber.StatusNotFound, handler.NotFound},
+		{service.ErrVerificationCodeRequired, fiber.StatusBadRequest, handler.VerificationFailed},
+		{service.ErrInvalidVerificationCode, fiber.StatusBadRequest, handler.VerificationFailed},
+		{service.ErrVerificationCodeExpired, fiber.StatusBadRequest, handler.VerificationFailed},
+		{service.ErrTooManyVerificationAttempts, fiber.StatusTooManyRequests, handler.TooManyRequests},
+		{service.ErrVerificationCodeRecentlySent, fiber.StatusTooManyRequests, handler.TooManyRequests},
+		{service.ErrTooManyVerificationCodes, fiber.StatusTooManyRequests, handler.TooManyRequests},
+		{service.ErrPhoneVerificationUnavailable, fiber.StatusServiceUnavailable, handler.ServiceUnavailable},
+		{phone.ErrInvalidNumber, fiber.StatusBadRequest, handler.ValidationError},
+		{phone.ErrNotMobile, fiber.StatusBadRequest, handler.ValidationError},
+		{link.ErrRedirectNotAllowed, fiber.StatusBadRequest, handler.InvalidRedirect},
+		{link.ErrInvalidSignature, fiber.StatusBadRequest, handler.InvalidRedirect},
+		{email.ErrUnknownTemplate, fiber.StatusNotFound, handler.NotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			status, code, details := handler.MapError(tc.err)
+			assert.Equal(t, tc.status, status)
+			assert.Equal(t, tc.code, code)
+			assert.Equal(t, tc.err.Error(), details)
+
+			// 감싼 에러도 같은 응답, 감싼 메시지는 노출하지 않음
+			status, code, details = handler.MapError(fmt.Errorf("%w: wrapped detail", tc.err))
+			assert.Equal(t, tc.status, status)
+			assert.Equal(t, tc.code, code)
+			assert.Equal(t, tc.err.Error(), details)
+		})
+	}
+}
+
+func TestMapError_비밀번호정책(t *testing.T) {
+	violations := []password.Violation{{Rule: password.RuleMinLength, Message: "too short"}}
+	status, code, details := handler.MapError(fmt.Errorf("register: %w", &password.PolicyError{Violations: violations}))
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, handler.PasswordPolicy, code)
+	assert.Equal(t, violations, details)
+}
+
+func TestMapError_기타에러(t *testing.T) {
+	status, code, details := handler.MapError(errors.New("pq: connection refused"))
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Equal(t, handler.InternalError, code)
+	assert.Equal(t, "internal error", details)
+
+	status, code, details = handler.MapError(fiber.ErrNotFound)
+	assert.Equal(t, fiber.StatusNotFound, status)
+	assert.Equal(t, handler.NotFound, code)
+	assert.Equal(t, "Not Found", details)
+}
+
+func TestErrorHandler_응답(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	app.Get("/conflict", func(_ *fiber.Ctx) error { return service.ErrEmailExists })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/conflict", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	var body handler.APIResponse
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.False(t, body.Success)
+	assert.Equal(t, fiber.StatusConflict, body.Code)
+	assert.Equal(t, "conflict", body.Message)
+	assert.Equal(t, "email already exists", body.Data)
+
+	// 없는 경로
+	resp, err = app.Test(httptest.NewRequest("GET", "/missing", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "notFound", body.Message)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Readiness check results reported per check by Ready.
+const (
+	CheckOK     = "ok"
+	CheckFailed = "failed"
+)
+
+// HealthCheck is a named dependency check run by the readiness probe, e.g. a database ping.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthHandler serves the liveness and readiness probes.
+type HealthHandler struct {
+	// Timeout bounds each check. Checks run one after another because the sqlite connection
+	// cannot be used concurrently.
+	Timeout time.Duration
+
+	checks   []HealthCheck
+	draining atomic.Bool
+}
+
+// NewHealthHandler creates a HealthHandler whose readiness probe runs checks in order.
+func NewHealthHandler(checks ...HealthCheck) *HealthHandler {
+	return &HealthHandler{Timeout: 2 * time.Second, checks: checks}
+}
+
+// Drain makes the readiness probe fail from now on, so the load balancer stops sending new
+// requests while the server shuts down.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// Live is the liveness probe (GET /healthz). It only reports that the process serves requests
+// and never checks dependencies, so an outage of the database does not restart the server.
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return c.JSON(NewAPISuccess(nil, fiber.StatusOK, "ok"))
+}
+
+// Ready is the readiness probe (GET /readyz). It runs every check and responds 200 with the
+// result of each, or 503 when any check fails or the server is shutting down. Failure reasons
+// are logged rather than returned, as the endpoint is not authenticated.
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	results := make(map[string]string, len(h.checks))
+	ready := !h.draining.Load()
+	for _, check := range h.checks {
+		ctx, cancel := context.WithTimeout(c.UserContext(), h.Timeout)
+		err := check.Check(ctx)
+		cancel()
+		if err != nil {
+			slog.WarnContext(c.UserContext(), "readiness check failed", "check", check.Name, "error", err)
+			results[check.Name] = CheckFailed
+			ready = false
+			continue
+		}
+		results[check.Name] = CheckOK
+	}
+	if !ready {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(NewAPIError(fiber.StatusServiceUnavailable, ServiceUnavailable, results))
+	}
+	return c.JSON(NewAPISuccess(results, fiber.StatusOK, "ready"))
+}
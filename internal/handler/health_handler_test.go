@@ -0,0 +1,63 @@
+package handler_test
+
+import (
+	"auth/internal/handler"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func getHealth(t *testing.T, app *fiber.App, path string) (int, handler.APIResponse) {
+	resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+	assert.Nil(t, err)
+	var body handler.APIResponse
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func TestHealth_준비상태(t *testing.T) {
+	var dbErr error
+	h := handler.NewHealthHandler(
+		handler.HealthCheck{Name: "database", Check: func(context.Context) error { return dbErr }},
+		handler.HealthCheck{Name: "mailer", Check: func(context.Context) error { return nil }},
+	)
+	app := fiber.New()
+	app.Get("/healthz", h.Live)
+	app.Get("/readyz", h.Ready)
+
+	status, body := getHealth(t, app, "/readyz")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, map[string]any{"database": "ok", "mailer": "ok"}, body.Data)
+
+	// 실패 이유는 응답에 넣지 않는다
+	dbErr = errors.New("dial tcp 10.0.0.5:5432: connection refused")
+	status, body = getHealth(t, app, "/readyz")
+	assert.Equal(t, fiber.StatusServiceUnavailable, status)
+	assert.Equal(t, handler.ServiceUnavailable, body.Message)
+	assert.Equal(t, map[string]any{"database": "failed", "mailer": "ok"}, body.Data)
+
+	// liveness 는 의존성과 무관
+	status, _ = getHealth(t, app, "/healthz")
+	assert.Equal(t, fiber.StatusOK, status)
+}
+
+func TestHealth_종료중(t *testing.T) {
+	h := handler.NewHealthHandler()
+	app := fiber.New()
+	app.Get("/healthz", h.Live)
+	app.Get("/readyz", h.Ready)
+
+	status, _ := getHealth(t, app, "/readyz")
+	assert.Equal(t, fiber.StatusOK, status)
+
+	h.Drain()
+	status, _ = getHealth(t, app, "/readyz")
+	assert.Equal(t, fiber.StatusServiceUnavailable, status)
+	status, _ = getHealth(t, app, "/healthz")
+	assert.Equal(t, fiber.StatusOK, status)
+}
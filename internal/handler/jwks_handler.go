@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"auth/internal/service/jwks"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JWKSHandler publishes the public keys that verify access tokens.
+type JWKSHandler struct {
+	// MaxAge is how long verifiers may cache the key set. Keep it shorter than
+	// jwks.KeySet.RetainFor, so they see a new key before the old one is dropped.
+	MaxAge time.Duration
+
+	keys *jwks.KeySet
+}
+
+// NewJWKSHandler creates a JWKSHandler whose responses may be cached for five minutes.
+func NewJWKSHandler(keys *jwks.KeySet) *JWKSHandler {
+	return &JWKSHandler{MaxAge: 5 * time.Minute, keys: keys}
+}
+
+// JWKS serves the JSON Web Key Set (GET /.well-known/jwks.json). The response is the bare
+// RFC 7517 document rather than an APIResponse, as JWT libraries expect. Without
+// JWT_SIGNING_ALGORITHM=ES256 access tokens are signed with the shared secret and the set is empty.
+func (h *JWKSHandler) JWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(h.MaxAge.Seconds())))
+	return c.JSON(h.keys.Document())
+}
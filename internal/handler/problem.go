@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"auth/internal/service/password"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MIMEApplicationProblemJSON is the media type of Problem Details (RFC 9457).
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// ProblemTypePrefix prefixes the error code to form the problem type URI.
+const ProblemTypePrefix = "urn:auth:problem:"
+
+// Problem is an RFC 9457 Problem Details object, written instead of APIResponse when the
+// request prefers application/problem+json.
+type Problem struct {
+	Type     string `json:"type"` // ProblemTypePrefix + 오류 코드
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Code is the same error code as APIResponse.Message.
+	Code string `json:"code"`
+	// Errors lists the fields that failed validation.
+	Errors []FieldError `json:"errors,omitempty"`
+	// Violations lists the password policy rules the password broke.
+	Violations []password.Violation `json:"violations,omitempty"`
+	// TraceID is the same trace ID as APIResponse.TraceID.
+	TraceID string `json:"traceId,omitempty"`
+}
+
+// NewProblem builds the Problem for a status, error code and details as returned by MapError.
+func NewProblem(status int, code string, details any, instance string) Problem {
+	p := Problem{
+		Type:     ProblemTypePrefix + code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Instance: instance,
+		Code:     code,
+	}
+	switch d := details.(type) {
+	case string:
+		p.Detail = d
+	case []FieldError:
+		p.Detail = "request validation failed"
+		p.Errors = d
+	case []password.Violation:
+		p.Detail = "password does not satisfy the password policy"
+		p.Violations = d
+	}
+	return p
+}
+
+// wantsProblem reports whether the client prefers Problem Details over the APIResponse envelope.
+// Clients that accept any JSON keep getting APIResponse.
+func wantsProblem(c *fiber.Ctx) bool {
+	return c.Accepts(fiber.MIMEApplicationJSON, MIMEApplicationProblemJSON) == MIMEApplicationProblemJSON
+}
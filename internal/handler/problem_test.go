@@ -0,0 +1,89 @@
+package handler_test
+
+import (
+	"auth/internal/dto"
+	"auth/internal/handler"
+	"auth/internal/service"
+	"auth/internal/service/password"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newProblemApp() *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	app.Get("/conflict", func(_ *fiber.Ctx) error { return service.ErrEmailExists })
+	app.Get("/invalid", func(_ *fiber.Ctx) error {
+		return handler.Validate.Struct(&dto.LoginRequest{Email: "not-an-email"})
+	})
+	app.Get("/policy", func(_ *fiber.Ctx) error {
+		return fmt.Errorf("wrapped: %w", &password.PolicyError{Violations: []password.Violation{{Rule: password.RuleDigit, Message: "needs a digit"}}})
+	})
+	return app
+}
+
+func getProblem(t *testing.T, app *fiber.App, path, accept string) (int, string, map[string]any) {
+	req := httptest.NewRequest("GET", path, nil)
+	if accept != "" {
+		req.Header.Set(fiber.HeaderAccept, accept)
+	}
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	var body map[string]any
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, resp.Header.Get(fiber.HeaderContentType), body
+}
+
+func TestProblem_Accept협상(t *testing.T) {
+	app := newProblemApp()
+
+	status, ctype, body := getProblem(t, app, "/conflict", "application/problem+json")
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Equal(t, handler.MIMEApplicationProblemJSON, ctype)
+	assert.Equal(t, "urn:auth:problem:conflict", body["type"])
+	assert.Equal(t, "Conflict", body["title"])
+	assert.Equal(t, float64(fiber.StatusConflict), body["status"])
+	assert.Equal(t, "email already exists", body["detail"])
+	assert.Equal(t, "/conflict", body["instance"])
+	assert.Equal(t, "conflict", body["code"])
+
+	// problem+json 을 더 선호할 때만
+	_, ctype, _ = getProblem(t, app, "/conflict", "application/json, application/problem+json;q=0.5")
+	assert.Equal(t, fiber.MIMEApplicationJSON, ctype)
+	_, ctype, _ = getProblem(t, app, "/conflict", "application/problem+json, application/json;q=0.5")
+	assert.Equal(t, handler.MIMEApplicationProblemJSON, ctype)
+
+	// Accept 가 없거나 */* 이면 기존 APIResponse
+	for _, accept := range []string{"", "*/*"} {
+		_, ctype, body = getProblem(t, app, "/conflict", accept)
+		assert.Equal(t, fiber.MIMEApplicationJSON, ctype)
+		assert.Equal(t, false, body["success"])
+		assert.Equal(t, "conflict", body["message"])
+	}
+}
+
+func TestProblem_검증에러필드별(t *testing.T) {
+	app := newProblemApp()
+
+	status, _, body := getProblem(t, app, "/invalid", "application/problem+json")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, "urn:auth:problem:validationError", body["type"])
+	assert.Equal(t, []any{
+		map[string]any{"field": "email", "rule": "email", "message": "email은(는) 올바른 이메일 주소여야 합니다."},
+		map[string]any{"field": "password", "rule": "required", "message": "password은(는) 필수 필드입니다."},
+	}, body["errors"])
+
+	// APIResponse 에서는 data 에 같은 목록
+	_, _, body = getProblem(t, app, "/invalid", "")
+	assert.Equal(t, "validationError", body["message"])
+	assert.Len(t, body["data"], 2)
+
+	status, _, body = getProblem(t, app, "/policy", "application/problem+json")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, "urn:auth:problem:passwordPolicy", body["type"])
+	assert.Len(t, body["violations"], 1)
+}
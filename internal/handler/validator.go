@@ -2,31 +2,143 @@
 package handler
 
 import (
+	"auth/internal/service/phone"
+	"reflect"
 	"regexp"
+	"strings"
 
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ko"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	ko_translations "github.com/go-playground/validator/v10/translations/ko"
+	"golang.org/x/text/language"
 )
 
 var (
-	phoneRegexp = regexp.MustCompile(`^\+[1-9]\d{1,14}$`)
-	nameRegexp  = regexp.MustCompile(`^[가-힣a-zA-Z]+$`)
+	nameRegexp = regexp.MustCompile(`^[가-힣a-zA-Z]+$`)
+
+	phoneParser = phone.MustNewParser(phone.DefaultRegion)
 
 	// Validate is the global validator instance.
 	Validate *validator.Validate
+
+	translators *ut.UniversalTranslator
 )
 
+// DefaultValidationLocale is the language of validation messages when Accept-Language matches none.
+const DefaultValidationLocale = "ko"
+
+// customTranslations are the validation messages of the custom tags, per locale.
+var customTranslations = map[string]map[string]string{
+	"ko": {
+		"namekr":             "{0}은(는) 한글 또는 영문자만 사용할 수 있습니다.",
+		"phonekr":            "{0}은(는) 010-1234-5678 또는 +821012345678 과 같은 휴대폰 번호여야 합니다.",
+		"bcp47_language_tag": "{0}은(는) ko, en 과 같은 언어 태그여야 합니다.",
+		"http_url":           "{0}은(는) http 또는 https 로 시작하는 URL 이어야 합니다.",
+	},
+	"en": {
+		"namekr":             "{0} must contain only Korean or English letters",
+		"phonekr":            "{0} must be a mobile phone number such as 010-1234-5678 or +821012345678",
+		"bcp47_language_tag": "{0} must be a language tag such as ko or en",
+		"http_url":           "{0} must be an http or https URL",
+	},
+}
+
 func init() {
 	Validate = validator.New()
+	// 에러의 필드 이름을 요청 JSON 의 키로
+	Validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name, _, _ := strings.Cut(fld.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
 	_ = Validate.RegisterValidation("namekr", NameValidator)
 	_ = Validate.RegisterValidation("phonekr", PhoneValidator)
+
+	koLocale := ko.New()
+	translators = ut.New(koLocale, koLocale, en.New())
+	koTrans, _ := translators.GetTranslator("ko")
+	enTrans, _ := translators.GetTranslator("en")
+	if err := ko_translations.RegisterDefaultTranslations(Validate, koTrans); err != nil {
+		panic(err)
+	}
+	if err := en_translations.RegisterDefaultTranslations(Validate, enTrans); err != nil {
+		panic(err)
+	}
+	for _, trans := range []ut.Translator{koTrans, enTrans} {
+		for tag, text := range customTranslations[trans.Locale()] {
+			if err := Validate.RegisterTranslation(tag, trans, registerMessage(tag, text), translateMessage); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+func registerMessage(tag, text string) validator.RegisterTranslationsFunc {
+	return func(trans ut.Translator) error {
+		return trans.Add(tag, text, true)
+	}
+}
+
+func translateMessage(trans ut.Translator, fe validator.FieldError) string {
+	msg, err := trans.T(fe.Tag(), fe.Field())
+	if err != nil {
+		return fe.Error()
+	}
+	return msg
+}
+
+// Translator returns the validation message translator best matching an Accept-Language
+// header value, or the DefaultValidationLocale one.
+func Translator(acceptLanguage string) ut.Translator {
+	tags, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	for _, tag := range tags {
+		base, _ := tag.Base()
+		if trans, ok := translators.GetTranslator(base.String()); ok {
+			return trans
+		}
+	}
+	trans, _ := translators.GetTranslator(DefaultValidationLocale)
+	return trans
+}
+
+// SetPhoneParser sets the parser PhoneValidator uses, so numbers without a country code are read
+// in the configured region. The default parser uses phone.DefaultRegion.
+func SetPhoneParser(p *phone.Parser) {
+	phoneParser = p
 }
 
-// PhoneValidator validates phone numbers in E.164 format.
+// PhoneValidator validates mobile phone numbers in national format for the configured region
+// or in international format.
 func PhoneValidator(fl validator.FieldLevel) bool {
-	return phoneRegexp.MatchString(fl.Field().String())
+	return phoneParser.Valid(fl.Field().String())
 }
 
 // NameValidator validates Korean and English names.
 func NameValidator(fl validator.FieldLevel) bool {
 	return nameRegexp.MatchString(fl.Field().String())
 }
+
+// FieldError describes a request field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`   // 요청 JSON 의 키
+	Rule    string `json:"rule"`    // 실패한 validate 태그, 예: "required", "email"
+	Message string `json:"message"` // 요청 언어로 번역된 설명
+}
+
+// fieldErrors converts validator errors into one FieldError per failed field, translated with trans.
+func fieldErrors(errs validator.ValidationErrors, trans ut.Translator) []FieldError {
+	result := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		result = append(result, FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Message: fe.Translate(trans),
+		})
+	}
+	return result
+}
@@ -0,0 +1,71 @@
+package handler_test
+
+import (
+	"auth/internal/dto"
+	"auth/internal/handler"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func invalidRegisterRequest() *dto.RegisterRequest {
+	return &dto.RegisterRequest{
+		Email:       "user@example.com",
+		Password:    "password123",
+		Name:        "홍길동1",
+		BirthDate:   "1990-01-01",
+		GenderCode:  "M",
+		PhoneNumber: "02-312-3456",
+	}
+}
+
+func validationData(t *testing.T, acceptLanguage string) []handler.FieldError {
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	app.Post("/register", func(_ *fiber.Ctx) error {
+		return handler.Validate.Struct(invalidRegisterRequest())
+	})
+	req := httptest.NewRequest("POST", "/register", nil)
+	if acceptLanguage != "" {
+		req.Header.Set(fiber.HeaderAcceptLanguage, acceptLanguage)
+	}
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	var body struct {
+		Message string               `json:"message"`
+		Data    []handler.FieldError `json:"data"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, handler.ValidationError, body.Message)
+	return body.Data
+}
+
+func TestValidate_한국어메시지(t *testing.T) {
+	assert.Equal(t, []handler.FieldError{
+		{Field: "name", Rule: "namekr", Message: "name은(는) 한글 또는 영문자만 사용할 수 있습니다."},
+		{Field: "phoneNumber", Rule: "phonekr", Message: "phoneNumber은(는) 010-1234-5678 또는 +821012345678 과 같은 휴대폰 번호여야 합니다."},
+	}, validationData(t, ""))
+	assert.Equal(t, "name은(는) 한글 또는 영문자만 사용할 수 있습니다.", validationData(t, "ko-KR,ko;q=0.9")[0].Message)
+}
+
+func TestValidate_영어메시지(t *testing.T) {
+	assert.Equal(t, []handler.FieldError{
+		{Field: "name", Rule: "namekr", Message: "name must contain only Korean or English letters"},
+		{Field: "phoneNumber", Rule: "phonekr", Message: "phoneNumber must be a mobile phone number such as 010-1234-5678 or +821012345678"},
+	}, validationData(t, "en-US,en;q=0.9"))
+	// 지원하지 않는 언어는 다음 선호 언어로
+	assert.Equal(t, "name must contain only Korean or English letters", validationData(t, "fr-FR, en;q=0.5")[0].Message)
+}
+
+func TestValidate_기본태그번역(t *testing.T) {
+	err := handler.Validate.Struct(&dto.ChangePasswordRequest{OldPassword: "password123", NewPassword: "short"})
+	var errs validator.ValidationErrors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, "newPassword", errs[0].Field())
+	assert.Equal(t, "newPassword must be at least 8 characters in length", errs[0].Translate(handler.Translator("en")))
+	assert.Equal(t, "newPassword의 길이는 최소 8자여야 합니다.", errs[0].Translate(handler.Translator("ko")))
+}
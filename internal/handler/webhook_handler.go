@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"auth/internal/dto"
+	"auth/internal/entity"
+	"auth/internal/service/audit"
+	"auth/internal/service/webhook"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func webhookEndpointResponse(e *entity.WebhookEndpointEntity, withSecret bool) dto.WebhookEndpointResponse {
+	r := dto.WebhookEndpointResponse{
+		ID:          e.ID,
+		URL:         e.URL,
+		Events:      e.EventList(),
+		Description: e.Description,
+		Active:      e.Active,
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
+	}
+	if withSecret {
+		r.Secret = e.Secret
+	}
+	return r
+}
+
+// recordWebhookAudit records an admin change to webhooks with the outcome of err.
+func (h *AdminHandler) recordWebhookAudit(c *fiber.Ctx, event string, id int64, err error) {
+	outcome := audit.OutcomeSuccess
+	if errors.Is(err, webhook.ErrInvalidEndpoint) || errors.Is(err, errWebhookNotFound) || errors.Is(err, errDeliveryNotFound) {
+		outcome = audit.OutcomeFailure
+	} else if err != nil {
+		outcome = audit.OutcomeError
+	}
+	details := map[string]string{}
+	if id != 0 {
+		details["id"] = strconv.FormatInt(id, 10)
+	}
+	h.auditLog.Record(c.UserContext(), audit.Event{Type: event, Actor: audit.ActorAdmin, Outcome: outcome, Details: details})
+}
+
+var (
+	errWebhookNotFound  = errors.New("webhook not found")
+	errDeliveryNotFound = errors.New("delivery not found")
+)
+
+// webhookError converts an error of the webhook dispatcher to a response.
+func webhookError(c *fiber.Ctx, op string, err error) error {
+	switch {
+	case errors.Is(err, webhook.ErrInvalidEndpoint):
+		return NewError(fiber.StatusBadRequest, BadRequest, strings.TrimPrefix(err.Error(), "webhook: invalid endpoint: "))
+	case errors.Is(err, errWebhookNotFound), errors.Is(err, errDeliveryNotFound):
+		return NewError(fiber.StatusNotFound, NotFound, err.Error())
+	}
+	slog.ErrorContext(c.UserContext(), op+" failed", "error", err)
+	return NewError(fiber.StatusInternalServerError, InternalError, "webhook operation failed")
+}
+
+func webhookID(c *fiber.Ctx) (int64, error) {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return 0, NewError(fiber.StatusBadRequest, BadRequest, "invalid id")
+	}
+	return int64(id), nil
+}
+
+func parseWebhookRequest(c *fiber.Ctx) (*dto.WebhookEndpointRequest, error) {
+	req := new(dto.WebhookEndpointRequest)
+	if err := c.BodyParser(req); err != nil {
+		return nil, NewError(fiber.StatusBadRequest, BadRequest, "invalid request body")
+	}
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// ListWebhooks godoc
+// @Summary 웹훅 엔드포인트 목록
+// @Description 등록된 웹훅 엔드포인트를 등록 순으로 조회한다. 서명 키는 포함하지 않는다.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "관리자 API 키"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"webhooks\",\"data\":[{\"id\":1,\"url\":\"https://crm.example.com/hooks/auth\",\"events\":[\"user.registered\"],\"active\":true}]}"
+// @Router /admin/webhooks [get]
+func (h *AdminHandler) ListWebhooks(c *fiber.Ctx) error {
+	endpoints, err := h.webhooks.Endpoints(c.UserContext())
+	if err != nil {
+		return webhookError(c, "ListWebhooks", err)
+	}
+	result := make([]dto.WebhookEndpointResponse, 0, len(endpoints))
+	for _, e := range endpoints {
+		result = append(result, webhookEndpointResponse(e, false))
+	}
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(result, fiber.StatusOK, "webhooks"))
+}
+
+// CreateWebhook godoc
+// @Summary 웹훅 엔드포인트 등록
+// @Description 이벤트를 받을 엔드포인트를 등록한다. 응답의 secret 은 이때만 반환되며 X-Webhook-Signature 검증에 사용한다.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "관리자 API 키"
+// @Param body body dto.WebhookEndpointRequest true "엔드포인트"
+// @Success 201 {object} APIResponse "예시: {\"success\":true,\"code\":201,\"message\":\"webhook created\",\"data\":{\"id\":1,\"url\":\"https://crm.example.com/hooks/auth\",\"events\":[\"*\"],\"active\":true,\"secret\":\"whsec_...\"}}"
+// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"badRequest\",\"data\":\"unknown event \\\"user.login\\\"\"}"
+// @Router /admin/webhooks [post]
+func (h *AdminHandler) CreateWebhook(c *fiber.Ctx) error {
+	req, err := parseWebhookRequest(c)
+	if err != nil {
+		return err
+	}
+	e := &entity.WebhookEndpointEntity{
+		URL:         req.URL,
+		Events:      strings.Join(req.Events, ","),
+		Description: req.Description,
+		Active:      req.Active == nil || *req.Active,
+	}
+	err = h.webhooks.CreateEndpoint(c.UserContext(), e)
+	h.recordWebhookAudit(c, audit.EventAdminWebhookCreate, e.ID, err)
+	if err != nil {
+		return webhookError(c, "CreateWebhook", err)
+	}
+	slog.InfoContext(c.UserContext(), "CreateWebhook success", "id", e.ID, "url", e.URL)
+	return c.Status(fiber.StatusCreated).JSON(NewAPISuccess(webhookEndpointResponse(e, true), fiber.StatusCreated, "webhook created"))
+}
+
+// GetWebhook godoc
+// @Summary 웹훅 엔드포인트 조회
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "관리자 API 키"
+// @Param id path int true "엔드포인트 ID"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"webhook\",\"data\":{\"id\":1,\"url\":\"https://crm.example.com/hooks/auth\",\"events\":[\"*\"],\"active\":true}}"
+// @Failure 404 {object} APIResponse "예시: {\"success\":false,\"code\":404,\"message\":\"notFound\",\"data\":\"webhook not found\"}"
+// @Router /admin/webhooks/{id} [get]
+func (h *AdminHandler) GetWebhook(c *fiber.Ctx) error {
+	id, err := webhookID(c)
+	if err != nil {
+		return err
+	}
+	e, err := h.webhooks.Endpoint(c.UserContext(), id)
+	if err == nil && e == nil {
+		err = errWebhookNotFound
+	}
+	if err != nil {
+		return webhookError(c, "GetWebhook", err)
+	}
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(webhookEndpointResponse(e, false), fiber.StatusOK, "webhook"))
+}
+
+// UpdateWebhook godoc
+// @Summary 웹훅 엔드포인트 수정
+// @Description 엔드포인트의 URL, 이벤트, 설명, 활성 여부를 바꾼다. rotateSecret 이 true 이면 새 서명 키를 발급하여 응답에 포함한다.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "관리자 API 키"
+// @Param id path int true "엔드포인트 ID"
+// @Param body body dto.WebhookEndpointRequest true "엔드포인트"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"webhook updated\",\"data\":{\"id\":1,\"url\":\"https://crm.example.com/hooks/auth\",\"events\":[\"user.deleted\"],\"active\":false}}"
+// @Failure 404 {object} APIResponse "예시: {\"success\":false,\"code\":404,\"message\":\"notFound\",\"data\":\"webhook not found\"}"
+// @Router /admin/webhooks/{id} [put]
+func (h *AdminHandler) UpdateWebhook(c *fiber.Ctx) error {
+	id, err := webhookID(c)
+	if err != nil {
+		return err
+	}
+	req, err := parseWebhookRequest(c)
+	if err != nil {
+		return err
+	}
+	e := &entity.WebhookEndpointEntity{
+		ID:          id,
+		URL:         req.URL,
+		Events:      strings.Join(req.Events, ","),
+		Description: req.Description,
+		Active:      req.Active == nil || *req.Active,
+	}
+	ok, err := h.webhooks.UpdateEndpoint(c.UserContext(), e, req.RotateSecret)
+	if err == nil && !ok {
+		err = errWebhookNotFound
+	}
+	h.recordWebhookAudit(c, audit.EventAdminWebhookUpdate, id, err)
+	if err != nil {
+		return webhookError(c, "UpdateWebhook", err)
+	}
+	slog.InfoContext(c.UserContext(), "UpdateWebhook success", "id", id, "rotateSecret", req.RotateSecret)
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(webhookEndpointResponse(e, req.RotateSecret), fiber.StatusOK, "webhook updated"))
+}
+
+// DeleteWebhook godoc
+// @Summary 웹훅 엔드포인트 삭제
+// @Description 엔드포인트와 발송 기록을 삭제한다. 대기 중인 발송은 보내지 않는다.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "관리자 API 키"
+// @Param id path int true "엔드포인트 ID"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"webhook deleted\",\"data\":null}"
+// @Failure 404 {object} APIResponse "예시: {\"success\":false,\"code\":404,\"message\":\"notFound\",\"data\":\"webhook not found\"}"
+// @Router /admin/webhooks/{id} [delete]
+func (h *AdminHandler) DeleteWebhook(c *fiber.Ctx) error {
+	id, err := webhookID(c)
+	if err != nil {
+		return err
+	}
+	ok, err := h.webhooks.DeleteEndpoint(c.UserContext(), id)
+	if err == nil && !ok {
+		err = errWebhookNotFound
+	}
+	h.recordWebhookAudit(c, audit.EventAdminWebhookDelete, id, err)
+	if err != nil {
+		return webhookError(c, "DeleteWebhook", err)
+	}
+	slog.InfoContext(c.UserContext(), "DeleteWebhook success", "id", id)
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(nil, fiber.StatusOK, "webhook deleted"))
+}
+
+// ListWebhookDeliveries godoc
+// @Summary 웹훅 발송 기록 조회
+// @Description 엔드포인트의 발송 기록을 최신 순으로 조회한다. 각 항목에는 마지막 시도의 응답 상태와 오류가 포함된다.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "관리자 API 키"
+// @Param id path int true "엔드포인트 ID"
+// @Param status query string false "pending, delivered 또는 dead (기본값 전체)"
+// @Param limit query int false "최대 개수 (기본값 50, 최대 500)"
+// @Success 200 {object} APIResponse "예시: {\"success\":true,\"code\":200,\"message\":\"webhook deliveries\",\"data\":[{\"id\":7,\"endpointId\":1,\"event\":\"user.registered\",\"status\":\"dead\",\"attempts\":10,\"responseStatus\":500}]}"
+// @Failure 400 {object} APIResponse "예시: {\"success\":false,\"code\":400,\"message\":\"badRequest\",\"data\":\"status must be pending, delivered or dead\"}"
+// @Router /admin/webhooks/{id}/deliveries [get]
+func (h *AdminHandler) ListWebhookDeliveries(c *fiber.Ctx) error {
+	id, err := webhookID(c)
+	if err != nil {
+		return err
+	}
+	status := c.Query("status")
+	switch status {
+	case "", entity.WebhookStatusPending, entity.WebhookStatusDelivered, entity.WebhookStatusDead:
+	default:
+		return NewError(fiber.StatusBadRequest, BadRequest, "status must be pending, delivered or dead")
+	}
+	limit := c.QueryInt("limit", 50)
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	deliveries, err := h.webhooks.Deliveries(c.UserContext(), id, status, limit)
+	if err != nil {
+		return webhookError(c, "ListWebhookDeliveries", err)
+	}
+	result := make([]dto.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, w := range deliveries {
+		result = append(result, dto.WebhookDeliveryResponse{
+			ID:             w.ID,
+			EndpointID:     w.EndpointID,
+			EventID:        w.EventID,
+			Event:          w.Event,
+			Status:         w.Status,
+			Attempts:       w.Attempts,
+			ResponseStatus: w.ResponseStatus,
+			LastError:      w.LastError,
+			NextAttemptAt:  w.NextAttemptAt,
+			CreatedAt:      w.CreatedAt,
+			DeliveredAt:    w.DeliveredAt,
+			Payload:        json.RawMessage(w.Payload),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(NewAPISuccess(result, fiber.StatusOK, "webhook deliveries"))
+}
+
+// ReplayWebhookDelivery godoc
+// @Summary 웹훅 재전송
+// @Description 발송 기록의 이벤트를 같은 이벤트 ID 와 본문으로 다시 보낸다. 원래 기록은 그대로 두고 새 발송을 만든다.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "관리자 API 키"
+// @Param id path int true "발송 ID"
+// @Success 202 {object} APIResponse "예시: {\"success\":true,\"code\":202,\"message\":\"webhook replay queued\",\"data\":{\"id\":8}}"
+// @Failure 404 {object} APIResponse "예시: {\"success\":false,\"code\":404,\"message\":\"notFound\",\"data\":\"delivery not found\"}"
+// @Router /admin/webhooks/deliveries/{id}/replay [post]
+func (h *AdminHandler) ReplayWebhookDelivery(c *fiber.Ctx) error {
+	id, err := webhookID(c)
+	if err != nil {
+		return err
+	}
+	replay, err := h.webhooks.Replay(c.UserContext(), id)
+	if err == nil && replay == nil {
+		err = errDeliveryNotFound
+	}
+	h.recordWebhookAudit(c, audit.EventAdminWebhookReplay, id, err)
+	if err != nil {
+		return webhookError(c, "ReplayWebhookDelivery", err)
+	}
+	slog.InfoContext(c.UserContext(), "ReplayWebhookDelivery success", "id", id, "replayId", replay.ID)
+	return c.Status(fiber.StatusAccepted).JSON(NewAPISuccess(fiber.Map{"id": replay.ID}, fiber.StatusAccepted, "webhook replay queued"))
+}
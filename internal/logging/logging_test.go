@@ -0,0 +1,130 @@
+package logging_test
+
+import (
+	"auth/internal/logging"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLogger(t *testing.T, buf *bytes.Buffer) *slog.Logger {
+	h, err := logging.NewHandler(buf, "debug", logging.FormatJSON)
+	assert.Nil(t, err)
+	return slog.New(h)
+}
+
+func decode(t *testing.T, line []byte) map[string]any {
+	var record map[string]any
+	assert.Nil(t, json.Unmarshal(line, &record))
+	return record
+}
+
+func TestRedact(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLogger(t, &buf)
+
+	logger.Info("test",
+		"email", "testuser@gmail.com",
+		"phone", "+821012345678",
+		"refresh_token", "eyJhbGciOi",
+		"SMTPPassword", "hunter2",
+		"code", "123456",
+		"error", errors.New(`invalid sender "testuser@gmail.com"`),
+		"userId", 7,
+		slog.Group("user", "email", "ab@naver.com"),
+	)
+	record := decode(t, buf.Bytes())
+	assert.Equal(t, "te******@gmail.com", record["email"])
+	assert.Equal(t, "*********5678", record["phone"])
+	assert.Equal(t, logging.Redacted, record["refresh_token"])
+	assert.Equal(t, logging.Redacted, record["SMTPPassword"])
+	assert.Equal(t, logging.Redacted, record["code"])
+	assert.Equal(t, `invalid sender "te******@gmail.com"`, record["error"])
+	assert.Equal(t, float64(7), record["userId"])
+	assert.Equal(t, map[string]any{"email": "***@naver.com"}, record["user"])
+
+	// With 으로 붙인 속성도 가린다
+	buf.Reset()
+	logger.With("accessToken", "eyJhbGciOi").Info("test")
+	assert.Equal(t, logging.Redacted, decode(t, buf.Bytes())["accessToken"])
+}
+
+func TestNewHandler_설정오류(t *testing.T) {
+	_, err := logging.NewHandler(&bytes.Buffer{}, "verbose", logging.FormatJSON)
+	assert.NotNil(t, err)
+	_, err = logging.NewHandler(&bytes.Buffer{}, "info", "xml")
+	assert.NotNil(t, err)
+
+	var buf bytes.Buffer
+	h, err := logging.NewHandler(&buf, "warn", logging.FormatText)
+	assert.Nil(t, err)
+	logger := slog.New(h)
+	logger.Info("hidden")
+	logger.Warn("shown")
+	assert.NotContains(t, buf.String(), "hidden")
+	assert.Contains(t, buf.String(), "msg=shown")
+}
+
+func TestMiddleware_요청ID(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(newLogger(t, &buf))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	app := fiber.New()
+	app.Use(logging.Middleware("/healthz"))
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		slog.InfoContext(c.UserContext(), "handler", "requestId", logging.RequestID(c.UserContext()))
+		return c.SendString("ok")
+	})
+	app.Get("/healthz", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	// 클라이언트가 보낸 ID 를 그대로 쓴다
+	req := httptest.NewRequest("GET", "/users/1?token=secret", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "req-123")
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "req-123", resp.Header.Get(fiber.HeaderXRequestID))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	handlerLog, accessLog := decode(t, []byte(lines[0])), decode(t, []byte(lines[1]))
+	assert.Equal(t, "req-123", handlerLog["request_id"])
+	assert.Equal(t, "req-123", handlerLog["requestId"])
+	assert.Equal(t, "request", accessLog["msg"])
+	assert.Equal(t, "req-123", accessLog["request_id"])
+	assert.Equal(t, "/users/1", accessLog["path"])
+	assert.Equal(t, "/users/:id", accessLog["route"])
+	assert.Equal(t, float64(fiber.StatusOK), accessLog["status"])
+	assert.NotContains(t, buf.String(), "secret", "쿼리 문자열은 기록하지 않는다")
+
+	// 형식에 맞지 않는 ID 는 새로 만든다
+	buf.Reset()
+	req = httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "bad id\nlevel=ERROR")
+	resp, err = app.Test(req)
+	assert.Nil(t, err)
+	id := resp.Header.Get(fiber.HeaderXRequestID)
+	assert.Len(t, id, 36)
+	assert.Contains(t, buf.String(), id)
+
+	// 프로브는 접근 로그를 남기지 않는다
+	buf.Reset()
+	resp, err = app.Test(httptest.NewRequest("GET", "/healthz", nil))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, resp.Header.Get(fiber.HeaderXRequestID))
+	assert.Empty(t, buf.String())
+}
+
+func TestRequestID_없음(t *testing.T) {
+	assert.Equal(t, "", logging.RequestID(context.Background()))
+	assert.Equal(t, "abc", logging.RequestID(logging.WithRequestID(context.Background(), "abc")))
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// requestIDPattern limits the X-Request-ID values accepted from clients, so a header cannot
+// inject arbitrary text into the logs.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+// NewRequestID returns id if it is a well-formed request ID received from a client, or else a
+// new random ID.
+func NewRequestID(id string) string {
+	if requestIDPattern.MatchString(id) {
+		return id
+	}
+	return utils.UUIDv4()
+}
+
+// Middleware assigns every request an ID, taken from a well-formed X-Request-ID header or
+// generated, returns it in the X-Request-ID response header and stores it in the request's user
+// context so logs written with c.UserContext() carry it. When the request finishes it writes one
+// access log record, except for the paths in skipPaths, e.g. health probes.
+//
+// Only the path is logged, never the query string, which may hold tokens. Errors returned by
+// later handlers are passed to the app's ErrorHandler here, so the logged status is the one
+// sent to the client.
+func Middleware(skipPaths ...string) fiber.Handler {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, p := range skipPaths {
+		skip[p] = true
+	}
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		id := NewRequestID(utils.CopyString(c.Get(fiber.HeaderXRequestID)))
+		c.Set(fiber.HeaderXRequestID, id)
+		ctx := WithRequestID(c.UserContext(), id)
+		c.SetUserContext(ctx)
+
+		own := c.Route()
+		if err := c.Next(); err != nil {
+			if herr := c.App().ErrorHandler(c, err); herr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		if skip[c.Path()] {
+			return nil
+		}
+		status := c.Response().StatusCode()
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		attrs := []any{
+			"method", c.Method(),
+			"path", c.Path(),
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"ip", c.IP(),
+			"user_agent", c.Get(fiber.HeaderUserAgent),
+		}
+		if c.Route() != own {
+			attrs = append(attrs, "route", c.Route().Path)
+		}
+		slog.Log(ctx, level, "request", attrs...)
+		return nil
+	}
+}
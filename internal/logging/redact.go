@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"auth/pkg/utils"
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the value of secret attributes.
+const Redacted = "[REDACTED]"
+
+// secretKeys are substrings of attribute keys whose values are never logged.
+var secretKeys = []string{"password", "secret", "token", "apikey", "authorization", "cookie", "signature"}
+
+// emailPattern finds email addresses inside other values, e.g. error messages.
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// RedactHandler masks sensitive attributes before passing records on. Attributes are matched
+// by key, ignoring case, "_" and "-":
+//   - keys containing password, secret, token, apikey, authorization, cookie or signature, and
+//     the key "code", are replaced with Redacted;
+//   - keys containing email are masked with utils.MaskEmail, and keys containing phone with
+//     utils.MaskPhone.
+//
+// Email addresses inside any other string or error value are masked as well.
+type RedactHandler struct {
+	slog.Handler
+}
+
+// NewRedactHandler wraps h.
+func NewRedactHandler(h slog.Handler) *RedactHandler {
+	return &RedactHandler{Handler: h}
+}
+
+// Handle implements slog.Handler.
+func (h *RedactHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(redact(a))
+		return true
+	})
+	return h.Handler.Handle(ctx, out)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = redact(a)
+	}
+	return &RedactHandler{Handler: h.Handler.WithAttrs(redacted)}
+}
+
+// WithGroup implements slog.Handler.
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	return &RedactHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+func redact(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		group := v.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			redacted[i] = redact(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	}
+
+	key := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(a.Key))
+	if key == "code" {
+		return slog.String(a.Key, Redacted)
+	}
+	for _, s := range secretKeys {
+		if strings.Contains(key, s) {
+			return slog.String(a.Key, Redacted)
+		}
+	}
+	switch {
+	case strings.Contains(key, "email"):
+		return slog.String(a.Key, utils.MaskEmail(v.String()))
+	case strings.Contains(key, "phone"):
+		return slog.String(a.Key, utils.MaskPhone(v.String()))
+	}
+
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, maskEmails(v.String()))
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return slog.String(a.Key, maskEmails(err.Error()))
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+func maskEmails(s string) string {
+	if !strings.Contains(s, "@") {
+		return s
+	}
+	return emailPattern.ReplaceAllStringFunc(s, utils.MaskEmail)
+}
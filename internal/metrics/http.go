@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// RouteUnmatched is the route label of requests that matched no route, so scans of random paths
+// do not create a series per path.
+const RouteUnmatched = "unmatched"
+
+// Middleware records HTTPRequests and HTTPDuration for every request, labelled with the route
+// template, e.g. "/api/v1/admin/outbox/:id/retry", rather than the path.
+//
+// Errors returned by later handlers are passed to the app's ErrorHandler here, as the logger
+// middleware does, so the recorded status is the one sent to the client.
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		own := c.Route()
+		if err := c.Next(); err != nil {
+			if herr := c.App().ErrorHandler(c, err); herr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		route := c.Route().Path
+		if c.Route() == own {
+			route = RouteUnmatched
+		}
+		// Fiber 의 문자열은 요청 버퍼를 가리키므로 레이블로 보관하기 전에 복사한다
+		method := utils.CopyString(c.Method())
+		HTTPRequests.WithLabelValues(method, route, strconv.Itoa(c.Response().StatusCode())).Inc()
+		HTTPDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+
+// Handler serves the metrics in Registry in the Prometheus text format.
+func Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+}
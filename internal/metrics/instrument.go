@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"auth/internal/service/email"
+	"auth/pkg/utils"
+	"context"
+	"time"
+)
+
+// InstrumentHasher returns a PasswordHasher that records PasswordHashDuration for every Hash and
+// Verify call of h. Verify is labelled with the algorithm of the stored hash, which differs from
+// the configured one for hashes not yet upgraded.
+func InstrumentHasher(h utils.PasswordHasher) utils.PasswordHasher {
+	return &hasher{PasswordHasher: h}
+}
+
+type hasher struct {
+	utils.PasswordHasher
+}
+
+func (h *hasher) Hash(password string) (string, error) {
+	defer observeSince(h.Algorithm(), "hash", time.Now())
+	return h.PasswordHasher.Hash(password)
+}
+
+func (h *hasher) Verify(password, encoded string) (bool, error) {
+	algorithm := utils.HashAlgorithm(encoded)
+	if algorithm == "" {
+		algorithm = "unknown"
+	}
+	defer observeSince(algorithm, "verify", time.Now())
+	return h.PasswordHasher.Verify(password, encoded)
+}
+
+func observeSince(algorithm, operation string, start time.Time) {
+	PasswordHashDuration.WithLabelValues(algorithm, operation).Observe(time.Since(start).Seconds())
+}
+
+// InstrumentMailer returns a Mailer that records EmailSends and EmailSendDuration for every
+// message sent through m. It keeps m's Ping, if any, for the readiness probe.
+func InstrumentMailer(m email.Mailer) email.Mailer {
+	return &mailer{Mailer: m}
+}
+
+type mailer struct {
+	email.Mailer
+}
+
+func (m *mailer) Send(ctx context.Context, msg *email.Message) error {
+	start := time.Now()
+	err := m.Mailer.Send(ctx, msg)
+	EmailSendDuration.Observe(time.Since(start).Seconds())
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	}
+	EmailSends.WithLabelValues(outcome).Inc()
+	return err
+}
+
+func (m *mailer) Ping(ctx context.Context) error {
+	if p, ok := m.Mailer.(email.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Package metrics defines the Prometheus metrics of the service and the helpers that record them.
+package metrics
+
+import (
+	"auth/pkg/database"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Namespace prefixes every metric name.
+const Namespace = "auth"
+
+// Outcome label values of the business counters.
+const (
+	OutcomeSuccess = "success" // 요청이 처리됨
+	OutcomeFailure = "failure" // 사용자 입력이 거부됨, 예: 틀린 비밀번호
+	OutcomeError   = "error"   // 서버 쪽 오류
+)
+
+// Login method label values.
+const (
+	LoginPassword  = "password"
+	LoginMagicLink = "magic_link"
+)
+
+// Registry holds every metric of the service, together with the Go runtime and process collectors.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts handled requests by method, route template and status code.
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+	// HTTPDuration observes request latency by method and route template.
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// Logins counts sign-in attempts by method and outcome.
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "logins_total",
+		Help:      "Sign-in attempts by method (password, magic_link) and outcome.",
+	}, []string{"method", "outcome"})
+	// Registrations counts sign-ups by outcome.
+	Registrations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "registrations_total",
+		Help:      "Sign-ups by outcome.",
+	}, []string{"outcome"})
+	// TokenRefreshes counts refresh token exchanges by outcome.
+	TokenRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "token_refreshes_total",
+		Help:      "Refresh token exchanges by outcome.",
+	}, []string{"outcome"})
+	// PasswordResetRequests counts password reset emails requested, by outcome. Requests for
+	// unknown emails are failures.
+	PasswordResetRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "password_reset_requests_total",
+		Help:      "Password reset requests by outcome.",
+	}, []string{"outcome"})
+	// Lockouts counts verifications locked after too many wrong attempts, by what was locked.
+	Lockouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "lockouts_total",
+		Help:      "Verifications locked after too many wrong attempts, by reason.",
+	}, []string{"reason"})
+
+	// PasswordHashDuration observes password hashing and verification time by algorithm.
+	PasswordHashDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "password_hash_duration_seconds",
+		Help:      "Password hash and verify duration by algorithm and operation.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"algorithm", "operation"})
+
+	// EmailSends counts email deliveries by outcome.
+	EmailSends = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "email_sends_total",
+		Help:      "Email deliveries by outcome (success, error).",
+	}, []string{"outcome"})
+	// EmailSendDuration observes email delivery time.
+	EmailSendDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "email_send_duration_seconds",
+		Help:      "Email delivery duration.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// WebhookDeliveries counts webhook delivery attempts by event and outcome.
+	WebhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Webhook delivery attempts by event and outcome (success, error).",
+	}, []string{"event", "outcome"})
+
+	// TokensPurged counts expired tokens deleted by the janitor, by kind.
+	TokensPurged = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "tokens_purged_total",
+		Help:      "Expired tokens deleted by kind (refresh, password_reset, magic_link).",
+	}, []string{"kind"})
+	// TokenPurgeRuns counts janitor runs by outcome; skipped runs found another instance purging.
+	TokenPurgeRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "token_purge_runs_total",
+		Help:      "Token purge runs by outcome (success, error, skipped).",
+	}, []string{"outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests, HTTPDuration,
+		Logins, Registrations, TokenRefreshes, PasswordResetRequests, Lockouts,
+		PasswordHashDuration,
+		EmailSends, EmailSendDuration,
+		WebhookDeliveries,
+		TokensPurged, TokenPurgeRuns,
+		NewPoolCollector(database.GetPool),
+	)
+}
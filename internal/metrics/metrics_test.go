@@ -0,0 +1,98 @@
+package metrics_test
+
+import (
+	"auth/internal/metrics"
+	"auth/internal/service/email"
+	"auth/pkg/utils"
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_라우트템플릿(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	}})
+	app.Use(metrics.Middleware())
+	app.Get("/metrics", metrics.Handler())
+	app.Get("/users/:id", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	app.Post("/users", func(_ *fiber.Ctx) error { return errors.New("email already exists") })
+
+	requests := func(method, route, status string) float64 {
+		return testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(method, route, status))
+	}
+	before := requests("GET", "/users/:id", "200")
+	for _, id := range []string{"1", "2"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/users/"+id, nil))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	}
+	assert.Equal(t, before+2, requests("GET", "/users/:id", "200"))
+
+	// 핸들러 에러는 ErrorHandler 가 정한 상태 코드로
+	before = requests("POST", "/users", "409")
+	resp, err := app.Test(httptest.NewRequest("POST", "/users", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	assert.Equal(t, before+1, requests("POST", "/users", "409"))
+
+	// 없는 경로는 경로마다 시계열을 만들지 않는다
+	before = requests("GET", metrics.RouteUnmatched, "409")
+	_, _ = app.Test(httptest.NewRequest("GET", "/wp-admin/install.php", nil))
+	_, _ = app.Test(httptest.NewRequest("GET", "/.env", nil))
+	assert.Equal(t, before+2, requests("GET", metrics.RouteUnmatched, "409"))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/metrics", nil))
+	assert.Nil(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), `auth_http_requests_total{method="GET",route="/users/:id",status="200"}`)
+	assert.Contains(t, string(body), `auth_http_requests_total{method="POST",route="/users",status="409"}`)
+	assert.Contains(t, string(body), "go_goroutines")
+	assert.NotContains(t, string(body), "auth_db_pool_", "pool 이 없으면 내보내지 않는다")
+}
+
+func TestInstrumentHasher(t *testing.T) {
+	h := metrics.InstrumentHasher(utils.NewBcryptHasher(4))
+	assert.Equal(t, utils.AlgorithmBcrypt, h.Algorithm())
+
+	hash, err := h.Hash("password123")
+	assert.Nil(t, err)
+	ok, err := h.Verify("password123", hash)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	_, _ = h.Verify("password123", "plain")
+	// bcrypt hash, bcrypt verify, unknown verify
+	assert.Equal(t, 3, testutil.CollectAndCount(metrics.PasswordHashDuration))
+}
+
+type failingMailer struct{ err error }
+
+func (m failingMailer) Send(context.Context, *email.Message) error { return m.err }
+
+func TestInstrumentMailer(t *testing.T) {
+	sends := func(outcome string) float64 {
+		return testutil.ToFloat64(metrics.EmailSends.WithLabelValues(outcome))
+	}
+	success, failed := sends(metrics.OutcomeSuccess), sends(metrics.OutcomeError)
+	msg, _ := email.NewMessage(mail.Address{Address: "no-reply@example.com"}, "user@example.com", "subject", "body", "")
+
+	assert.Nil(t, metrics.InstrumentMailer(failingMailer{}).Send(context.Background(), msg))
+	assert.NotNil(t, metrics.InstrumentMailer(failingMailer{err: errors.New("smtp down")}).Send(context.Background(), msg))
+	assert.Equal(t, success+1, sends(metrics.OutcomeSuccess))
+	assert.Equal(t, failed+1, sends(metrics.OutcomeError))
+
+	// Ping 은 감싼 mailer 에 위임
+	dir := filepath.Join(t.TempDir(), "mail")
+	assert.Nil(t, metrics.InstrumentMailer(email.NewFileMailer(dir)).(email.Pinger).Ping(context.Background()))
+	_, err := os.Stat(dir)
+	assert.Nil(t, err)
+}
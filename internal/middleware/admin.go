@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminKeyMiddleware returns a Fiber middleware that only lets requests with
+// the admin API key in the X-Admin-Key header through.
+func AdminKeyMiddleware(apiKey string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get("X-Admin-Key")
+		if apiKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid admin key")
+		}
+		return c.Next()
+	}
+}
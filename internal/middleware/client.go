@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"auth/internal/service/audit"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// ClientInfoMiddleware stores the client IP and User-Agent in the request's user context,
+// where the audit log reads them.
+func ClientInfoMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(audit.WithClient(c.UserContext(), audit.Client{
+			IP:        utils.CopyString(c.IP()),
+			UserAgent: utils.CopyString(c.Get(fiber.HeaderUserAgent)),
+		}))
+		return c.Next()
+	}
+}
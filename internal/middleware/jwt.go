@@ -13,16 +13,16 @@ func JwtMiddleware(jwtSvc *service.JwtService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		auth := c.Get("Authorization")
 		if auth == "" {
-			return c.Status(401).JSON(fiber.Map{"error": "missing token"})
+			return fiber.NewError(fiber.StatusUnauthorized, "missing token")
 		}
 		// "Bearer <token>"
 		parts := strings.SplitN(auth, " ", 2)
 		if len(parts) != 2 {
-			return c.Status(401).JSON(fiber.Map{"error": "invalid token format"})
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid token format")
 		}
 		userID, err := jwtSvc.ValidateAccessToken(parts[1])
 		if err != nil {
-			return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+			return err
 		}
 		c.Locals("userID", userID)
 		return c.Next()
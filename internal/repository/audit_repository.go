@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"zombiezen.com/go/sqlite"
+)
+
+// AuditFilter selects audit events. Zero fields match everything.
+type AuditFilter struct {
+	UserID   *int64
+	Event    string
+	Outcome  string
+	Since    *time.Time
+	Until    *time.Time
+	BeforeID int64 // 0 이 아니면 이 ID 보다 이전 이벤트만 (페이지 커서)
+	Limit    int
+}
+
+// AuditRepository defines audit log database operations.
+type AuditRepository interface {
+	// Append stores the event at the end of the hash chain, setting its ID, CreatedAt
+	// (if zero), PrevHash and Hash.
+	Append(ctx context.Context, e *entity.AuditEventEntity) error
+	// List returns the events matching the filter, newest first.
+	List(ctx context.Context, f AuditFilter) ([]*entity.AuditEventEntity, error)
+	// ListAfter returns up to limit events with an ID greater than afterID, oldest first.
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]*entity.AuditEventEntity, error)
+	// DeleteBefore deletes events created before t and returns how many were deleted.
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+	CreateTable(ctx context.Context) error
+}
+
+type auditRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+// NewAuditRepository creates a new AuditRepository instance.
+func NewAuditRepository(dbPool *pgxpool.Pool) AuditRepository {
+	r := &auditRepository{dbPool: dbPool}
+	if err := r.CreateTable(context.Background()); err != nil {
+		slog.Warn("Error creating audit_events table", "error", err)
+	}
+	return r
+}
+
+// NewAuditRepositoryAuto returns an AuditRepository for the given DB type.
+func NewAuditRepositoryAuto(dbType string, pgxPool *pgxpool.Pool, sqliteConn interface{}) AuditRepository {
+	switch dbType {
+	case "sqlite":
+		if conn, ok := sqliteConn.(*sqlite.Conn); ok {
+			return NewAuditRepositorySqlite(conn)
+		}
+		panic("sqliteConn is not *sqlite.Conn")
+	case "postgres":
+		fallthrough
+	default:
+		return NewAuditRepository(pgxPool)
+	}
+}
+
+// CreateTable creates the audit_events table if it does not exist
+func (r *auditRepository) CreateTable(ctx context.Context) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS audit_events (
+		id         BIGSERIAL PRIMARY KEY,
+		user_id    BIGINT,
+		actor      VARCHAR(16) NOT NULL,
+		event      VARCHAR(64) NOT NULL,
+		outcome    VARCHAR(16) NOT NULL,
+		ip         VARCHAR(64) NOT NULL DEFAULT '',
+		user_agent TEXT NOT NULL DEFAULT '',
+		details    TEXT NOT NULL DEFAULT '{}',
+		created_at TIMESTAMPTZ NOT NULL,
+		prev_hash  CHAR(64) NOT NULL,
+		hash       CHAR(64) NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_audit_events_user ON audit_events (user_id, id);
+	CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events (created_at);
+	`
+	_, err := r.dbPool.Exec(ctx, query)
+	return err
+}
+
+const auditColumns = `id, user_id, actor, event, outcome, ip, user_agent, details, created_at, prev_hash, hash`
+
+func scanAudit(row pgx.Row) (*entity.AuditEventEntity, error) {
+	e := &entity.AuditEventEntity{}
+	err := row.Scan(&e.ID, &e.UserID, &e.Actor, &e.Event, &e.Outcome, &e.IP, &e.UserAgent, &e.Details, &e.CreatedAt, &e.PrevHash, &e.Hash)
+	return e, err
+}
+
+// auditChainLock is the advisory lock key serializing appends, so two concurrent appends
+// cannot both chain to the same previous row.
+const auditChainLock = 0x61756469
+
+// Append: 체인 끝의 해시를 잠금 후 읽어 이어 붙임
+func (r *auditRepository) Append(ctx context.Context, e *entity.AuditEventEntity) error {
+	tx, err := r.dbPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLock); err != nil {
+		return err
+	}
+	prev := ""
+	err = tx.QueryRow(ctx, `SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`).Scan(&prev)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+	prepareAuditEvent(e, prev)
+	err = tx.QueryRow(ctx, `INSERT INTO audit_events (user_id, actor, event, outcome, ip, user_agent, details, created_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id`,
+		e.UserID, e.Actor, e.Event, e.Outcome, e.IP, e.UserAgent, e.Details, e.CreatedAt, e.PrevHash, e.Hash,
+	).Scan(&e.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// prepareAuditEvent sets the fields Append fills in before the event is stored.
+func prepareAuditEvent(e *entity.AuditEventEntity, prevHash string) {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	// 해시와 저장 값이 같도록 두 DB 모두 정확히 저장하는 초 단위로 맞춘다
+	e.CreatedAt = e.CreatedAt.UTC().Truncate(time.Second)
+	if e.Details == "" {
+		e.Details = "{}"
+	}
+	e.PrevHash = prevHash
+	e.Hash = e.ComputeHash()
+}
+
+// auditWhere builds the WHERE clause for a filter, numbering parameters with placeholder.
+func auditWhere(f AuditFilter, placeholder func(n int) string) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, placeholder(len(args))))
+	}
+	if f.UserID != nil {
+		add("user_id = %s", *f.UserID)
+	}
+	if f.Event != "" {
+		add("event = %s", f.Event)
+	}
+	if f.Outcome != "" {
+		add("outcome = %s", f.Outcome)
+	}
+	if f.Since != nil {
+		add("created_at >= %s", f.Since.UTC())
+	}
+	if f.Until != nil {
+		add("created_at < %s", f.Until.UTC())
+	}
+	if f.BeforeID > 0 {
+		add("id < %s", f.BeforeID)
+	}
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// List: 조건에 맞는 이벤트를 최신 순으로 조회
+func (r *auditRepository) List(ctx context.Context, f AuditFilter) ([]*entity.AuditEventEntity, error) {
+	where, args := auditWhere(f, func(n int) string { return fmt.Sprintf("$%d", n) })
+	args = append(args, f.Limit)
+	rows, err := r.dbPool.Query(ctx, `SELECT `+auditColumns+` FROM audit_events`+where+
+		fmt.Sprintf(` ORDER BY id DESC LIMIT $%d`, len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	return collectAudit(rows)
+}
+
+// ListAfter: afterID 이후 이벤트를 오래된 순으로 조회 (체인 검증용)
+func (r *auditRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]*entity.AuditEventEntity, error) {
+	rows, err := r.dbPool.Query(ctx, `SELECT `+auditColumns+` FROM audit_events
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectAudit(rows)
+}
+
+func collectAudit(rows pgx.Rows) ([]*entity.AuditEventEntity, error) {
+	defer rows.Close()
+	var events []*entity.AuditEventEntity
+	for rows.Next() {
+		e, err := scanAudit(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// DeleteBefore: 보존 기간이 지난 이벤트 삭제
+func (r *auditRepository) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	cmd, err := r.dbPool.Exec(ctx, `DELETE FROM audit_events WHERE created_at < $1`, t)
+	if err != nil {
+		return 0, err
+	}
+	return cmd.RowsAffected(), nil
+}
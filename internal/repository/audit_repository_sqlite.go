@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"context"
+	"log/slog"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+)
+
+type auditRepositorySqlite struct {
+	db *sqlite.Conn
+}
+
+// NewAuditRepositorySqlite returns a new sqlite-based AuditRepository.
+func NewAuditRepositorySqlite(conn *sqlite.Conn) AuditRepository {
+	r := &auditRepositorySqlite{db: conn}
+	if err := r.CreateTable(context.Background()); err != nil {
+		slog.Warn("[sqlite] Error creating audit_events table", "error", err)
+	}
+	return r
+}
+
+// CreateTable creates the audit_events table if it does not exist.
+func (r *auditRepositorySqlite) CreateTable(_ context.Context) error {
+	return sqliteExec(r.db,
+		`CREATE TABLE IF NOT EXISTS audit_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER,
+			actor TEXT NOT NULL,
+			event TEXT NOT NULL,
+			outcome TEXT NOT NULL,
+			ip TEXT NOT NULL DEFAULT '',
+			user_agent TEXT NOT NULL DEFAULT '',
+			details TEXT NOT NULL DEFAULT '{}',
+			created_at DATETIME NOT NULL,
+			prev_hash TEXT NOT NULL,
+			hash TEXT NOT NULL
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_events_user ON audit_events (user_id, id);`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events (created_at);`,
+	)
+}
+
+// bindAuditArgs binds the arguments built by auditWhere, starting at parameter 1.
+func bindAuditArgs(stmt *sqlite.Stmt, args []interface{}) {
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case int64:
+			stmt.BindInt64(i+1, v)
+		case int:
+			stmt.BindInt64(i+1, int64(v))
+		case string:
+			stmt.BindText(i+1, v)
+		case time.Time:
+			sqliteBindTime(stmt, i+1, &v)
+		}
+	}
+}
+
+func (r *auditRepositorySqlite) query(q string, args []interface{}) ([]*entity.AuditEventEntity, error) {
+	stmt, err := r.db.Prepare(q)
+	if err != nil {
+		return nil, err
+	}
+	bindAuditArgs(stmt, args)
+	var events []*entity.AuditEventEntity
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			_ = stmt.Finalize()
+			return nil, err
+		}
+		if !hasRow {
+			break
+		}
+		e := &entity.AuditEventEntity{
+			ID:        stmt.ColumnInt64(0),
+			Actor:     stmt.ColumnText(2),
+			Event:     stmt.ColumnText(3),
+			Outcome:   stmt.ColumnText(4),
+			IP:        stmt.ColumnText(5),
+			UserAgent: stmt.ColumnText(6),
+			Details:   stmt.ColumnText(7),
+			PrevHash:  stmt.ColumnText(9),
+			Hash:      stmt.ColumnText(10),
+		}
+		if stmt.ColumnType(1) != sqlite.TypeNull {
+			userID := stmt.ColumnInt64(1)
+			e.UserID = &userID
+		}
+		if t := sqliteColumnTime(stmt, 8); t != nil {
+			e.CreatedAt = *t
+		}
+		events = append(events, e)
+	}
+	if err := stmt.Finalize(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Append stores the event after the last one. A single sqlite connection has no concurrent
+// writers, so reading the last hash and inserting need no lock.
+func (r *auditRepositorySqlite) Append(_ context.Context, e *entity.AuditEventEntity) error {
+	last, err := r.query("SELECT "+auditColumns+" FROM audit_events ORDER BY id DESC LIMIT 1", nil)
+	if err != nil {
+		return err
+	}
+	prev := ""
+	if len(last) > 0 {
+		prev = last[0].Hash
+	}
+	prepareAuditEvent(e, prev)
+	stmt, err := r.db.Prepare(`INSERT INTO audit_events (user_id, actor, event, outcome, ip, user_agent, details, created_at, prev_hash, hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	if e.UserID != nil {
+		stmt.BindInt64(1, *e.UserID)
+	} else {
+		stmt.BindNull(1)
+	}
+	stmt.BindText(2, e.Actor)
+	stmt.BindText(3, e.Event)
+	stmt.BindText(4, e.Outcome)
+	stmt.BindText(5, e.IP)
+	stmt.BindText(6, e.UserAgent)
+	stmt.BindText(7, e.Details)
+	sqliteBindTime(stmt, 8, &e.CreatedAt)
+	stmt.BindText(9, e.PrevHash)
+	stmt.BindText(10, e.Hash)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return err
+	}
+	if err2 != nil {
+		return err2
+	}
+	e.ID = r.db.LastInsertRowID()
+	return nil
+}
+
+// List returns the events matching the filter, newest first.
+func (r *auditRepositorySqlite) List(_ context.Context, f AuditFilter) ([]*entity.AuditEventEntity, error) {
+	where, args := auditWhere(f, func(int) string { return "?" })
+	args = append(args, f.Limit)
+	return r.query("SELECT "+auditColumns+" FROM audit_events"+where+" ORDER BY id DESC LIMIT ?", args)
+}
+
+// ListAfter returns events after afterID, oldest first.
+func (r *auditRepositorySqlite) ListAfter(_ context.Context, afterID int64, limit int) ([]*entity.AuditEventEntity, error) {
+	return r.query("SELECT "+auditColumns+" FROM audit_events WHERE id > ? ORDER BY id LIMIT ?", []interface{}{afterID, limit})
+}
+
+// DeleteBefore deletes events created before t.
+func (r *auditRepositorySqlite) DeleteBefore(_ context.Context, t time.Time) (int64, error) {
+	stmt, err := r.db.Prepare("DELETE FROM audit_events WHERE created_at < ?")
+	if err != nil {
+		return 0, err
+	}
+	sqliteBindTime(stmt, 1, &t)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return 0, err
+	}
+	if err2 != nil {
+		return 0, err2
+	}
+	return int64(r.db.Changes()), nil
+}
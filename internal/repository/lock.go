@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"zombiezen.com/go/sqlite"
+)
+
+// Locker runs periodic jobs on one server instance at a time.
+type Locker interface {
+	// TryLock runs fn while holding the lock with the key and reports whether it ran. It returns
+	// false without running fn if another instance holds the lock.
+	TryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error)
+}
+
+// pgLocker 는 세션 advisory lock 을 사용한다. 잠근 연결을 fn 이 끝날 때까지 붙잡아 둔다.
+type pgLocker struct {
+	dbPool *pgxpool.Pool
+}
+
+// NewLocker creates a Locker shared by every instance connected to the Postgres database.
+func NewLocker(pool *pgxpool.Pool) Locker {
+	return &pgLocker{dbPool: pool}
+}
+
+// NewLockerAuto creates the Locker for the database type.
+func NewLockerAuto(dbType string, pgxPool *pgxpool.Pool, sqliteConn interface{}) Locker {
+	switch dbType {
+	case "sqlite":
+		if _, ok := sqliteConn.(*sqlite.Conn); ok {
+			return NewLockerSqlite()
+		}
+		panic("sqliteConn is not *sqlite.Conn")
+	case "postgres":
+		fallthrough
+	default:
+		return NewLocker(pgxPool)
+	}
+}
+
+// TryLock: pg_try_advisory_lock 으로 잠금을 시도하고 fn 후 해제
+func (l *pgLocker) TryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error) {
+	conn, err := l.dbPool.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Release()
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&locked); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	defer func() {
+		// ctx 가 끝났어도 해제한다. 실패하면 연결을 닫아 세션과 함께 잠금을 푼다
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, key); err != nil {
+			slog.Warn("advisory unlock failed, closing connection", "key", key, "error", err)
+			_ = conn.Conn().Close(context.Background())
+		}
+	}()
+	return true, fn(ctx)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"sync"
+)
+
+// sqliteLocker 는 프로세스 안에서만 잠근다. 같은 파일을 여는 다른 프로세스(authctl 등)와는
+// 겹칠 수 있으므로, 여러 번 실행해도 결과가 같은 작업에만 사용한다.
+type sqliteLocker struct {
+	mu sync.Mutex
+}
+
+// NewLockerSqlite creates a Locker for sqlite. It only keeps jobs in the same process from
+// overlapping, not jobs of other processes using the same database file.
+func NewLockerSqlite() Locker {
+	return &sqliteLocker{}
+}
+
+// TryLock: 이미 실행 중이면 건너뜀
+func (l *sqliteLocker) TryLock(ctx context.Context, _ int64, fn func(ctx context.Context) error) (bool, error) {
+	if !l.mu.TryLock() {
+		return false, nil
+	}
+	defer l.mu.Unlock()
+	return true, fn(ctx)
+}
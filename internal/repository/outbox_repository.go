@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"zombiezen.com/go/sqlite"
+)
+
+// OutboxRepository defines transactional outbox database operations.
+type OutboxRepository interface {
+	// EnqueueTx stores a pending message within the caller's transaction.
+	// A nil tx enqueues outside of any transaction.
+	EnqueueTx(ctx context.Context, tx interface{}, m *entity.OutboxMessageEntity) error
+	// ClaimDue returns up to limit pending messages due at now and leases them until now+lease,
+	// so concurrent dispatchers do not pick up the same message.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.OutboxMessageEntity, error)
+	MarkSent(ctx context.Context, id int64) error
+	// MarkFailed records a failed attempt; status is pending (retry at nextAttemptAt) or dead.
+	MarkFailed(ctx context.Context, id int64, status string, nextAttemptAt time.Time, lastError string) error
+	// List returns messages with the status, oldest first.
+	List(ctx context.Context, status string, limit int) ([]*entity.OutboxMessageEntity, error)
+	// Requeue resets an unsent message to pending with no attempts, returning false if there is none with the id.
+	Requeue(ctx context.Context, id int64) (bool, error)
+	CreateTable(ctx context.Context) error
+}
+
+type outboxRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+// NewOutboxRepository creates a new OutboxRepository instance.
+func NewOutboxRepository(dbPool *pgxpool.Pool) OutboxRepository {
+	r := &outboxRepository{dbPool: dbPool}
+	if err := r.CreateTable(context.Background()); err != nil {
+		slog.Warn("Error creating outbox table", "error", err)
+	}
+	return r
+}
+
+// NewOutboxRepositoryAuto returns an OutboxRepository for the given DB type.
+func NewOutboxRepositoryAuto(dbType string, pgxPool *pgxpool.Pool, sqliteConn interface{}) OutboxRepository {
+	switch dbType {
+	case "sqlite":
+		if conn, ok := sqliteConn.(*sqlite.Conn); ok {
+			return NewOutboxRepositorySqlite(conn)
+		}
+		panic("sqliteConn is not *sqlite.Conn")
+	case "postgres":
+		fallthrough
+	default:
+		return NewOutboxRepository(pgxPool)
+	}
+}
+
+// CreateTable creates the outbox table if it does not exist
+func (r *outboxRepository) CreateTable(ctx context.Context) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS outbox (
+		id              BIGSERIAL PRIMARY KEY,
+		kind            VARCHAR(32) NOT NULL,
+		payload         TEXT NOT NULL,
+		status          VARCHAR(16) NOT NULL DEFAULT 'pending',
+		attempts        INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		last_error      TEXT NOT NULL DEFAULT '',
+		created_at      TIMESTAMPTZ DEFAULT NOW(),
+		sent_at         TIMESTAMPTZ
+	);
+	CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox (next_attempt_at) WHERE status = 'pending';
+	`
+	_, err := r.dbPool.Exec(ctx, query)
+	return err
+}
+
+const outboxColumns = `id, kind, payload, status, attempts, next_attempt_at, last_error, created_at, sent_at`
+
+func scanOutbox(row pgx.Row) (*entity.OutboxMessageEntity, error) {
+	m := &entity.OutboxMessageEntity{}
+	err := row.Scan(&m.ID, &m.Kind, &m.Payload, &m.Status, &m.Attempts, &m.NextAttemptAt, &m.LastError, &m.CreatedAt, &m.SentAt)
+	return m, err
+}
+
+// EnqueueTx: 트랜잭션 내에서 outbox 메시지 저장
+func (r *outboxRepository) EnqueueTx(ctx context.Context, tx interface{}, m *entity.OutboxMessageEntity) error {
+	var q interface {
+		QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	} = r.dbPool
+	if tx != nil {
+		pgxTx, ok := tx.(pgx.Tx)
+		if !ok {
+			return errors.New("tx is not pgx.Tx")
+		}
+		q = pgxTx
+	}
+	m.Status = entity.OutboxStatusPending
+	return q.QueryRow(ctx, `INSERT INTO outbox (kind, payload, status, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, 'pending', 0, NOW(), NOW())
+		RETURNING id, next_attempt_at, created_at`,
+		m.Kind, m.Payload,
+	).Scan(&m.ID, &m.NextAttemptAt, &m.CreatedAt)
+}
+
+// ClaimDue: 발송 시각이 된 메시지를 잠금(SKIP LOCKED) 후 lease 만큼 미뤄 두고 반환
+func (r *outboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.OutboxMessageEntity, error) {
+	rows, err := r.dbPool.Query(ctx, `UPDATE outbox SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+outboxColumns,
+		now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var messages []*entity.OutboxMessageEntity
+	for rows.Next() {
+		m, err := scanOutbox(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// MarkSent: 발송 완료 처리
+func (r *outboxRepository) MarkSent(ctx context.Context, id int64) error {
+	_, err := r.dbPool.Exec(ctx, `UPDATE outbox SET status = 'sent', attempts = attempts + 1, last_error = '', sent_at = NOW()
+		WHERE id = $1`, id)
+	return err
+}
+
+// MarkFailed: 실패한 시도 기록 (재시도 예약 또는 dead)
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64, status string, nextAttemptAt time.Time, lastError string) error {
+	_, err := r.dbPool.Exec(ctx, `UPDATE outbox SET status = $2, attempts = attempts + 1, next_attempt_at = $3, last_error = $4
+		WHERE id = $1`, id, status, nextAttemptAt, lastError)
+	return err
+}
+
+// List: 상태별 메시지 조회 (오래된 순)
+func (r *outboxRepository) List(ctx context.Context, status string, limit int) ([]*entity.OutboxMessageEntity, error) {
+	rows, err := r.dbPool.Query(ctx, `SELECT `+outboxColumns+` FROM outbox
+		WHERE status = $1
+		ORDER BY id
+		LIMIT $2`, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var messages []*entity.OutboxMessageEntity
+	for rows.Next() {
+		m, err := scanOutbox(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// Requeue: 메시지를 즉시 재발송 대기 상태로 되돌림
+func (r *outboxRepository) Requeue(ctx context.Context, id int64) (bool, error) {
+	cmd, err := r.dbPool.Exec(ctx, `UPDATE outbox SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1 AND status <> 'sent'`, id)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"context"
+	"log/slog"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+)
+
+type outboxRepositorySqlite struct {
+	db *sqlite.Conn
+}
+
+// NewOutboxRepositorySqlite returns a new sqlite-based OutboxRepository.
+func NewOutboxRepositorySqlite(conn *sqlite.Conn) OutboxRepository {
+	r := &outboxRepositorySqlite{db: conn}
+	if err := r.CreateTable(context.Background()); err != nil {
+		slog.Warn("[sqlite] Error creating outbox table", "error", err)
+	}
+	return r
+}
+
+// CreateTable creates the outbox table if it does not exist.
+func (r *outboxRepositorySqlite) CreateTable(_ context.Context) error {
+	return sqliteExec(r.db,
+		`CREATE TABLE IF NOT EXISTS outbox (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			kind TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			sent_at DATETIME
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox (status, next_attempt_at);`,
+	)
+}
+
+func (r *outboxRepositorySqlite) query(q string, bind func(*sqlite.Stmt)) ([]*entity.OutboxMessageEntity, error) {
+	stmt, err := r.db.Prepare(q)
+	if err != nil {
+		return nil, err
+	}
+	bind(stmt)
+	var messages []*entity.OutboxMessageEntity
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			_ = stmt.Finalize()
+			return nil, err
+		}
+		if !hasRow {
+			break
+		}
+		m := &entity.OutboxMessageEntity{
+			ID:        stmt.ColumnInt64(0),
+			Kind:      stmt.ColumnText(1),
+			Payload:   stmt.ColumnText(2),
+			Status:    stmt.ColumnText(3),
+			Attempts:  stmt.ColumnInt(4),
+			LastError: stmt.ColumnText(6),
+			SentAt:    sqliteColumnTime(stmt, 8),
+		}
+		if t := sqliteColumnTime(stmt, 5); t != nil {
+			m.NextAttemptAt = *t
+		}
+		if t := sqliteColumnTime(stmt, 7); t != nil {
+			m.CreatedAt = *t
+		}
+		messages = append(messages, m)
+	}
+	if err := stmt.Finalize(); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *outboxRepositorySqlite) exec(q string, bind func(*sqlite.Stmt)) (int, error) {
+	stmt, err := r.db.Prepare(q)
+	if err != nil {
+		return 0, err
+	}
+	bind(stmt)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return 0, err
+	}
+	if err2 != nil {
+		return 0, err2
+	}
+	return r.db.Changes(), nil
+}
+
+// EnqueueTx stores a pending message (no real tx used).
+func (r *outboxRepositorySqlite) EnqueueTx(_ context.Context, _ interface{}, m *entity.OutboxMessageEntity) error {
+	now := time.Now()
+	_, err := r.exec("INSERT INTO outbox (kind, payload, status, attempts, next_attempt_at, created_at) VALUES (?, ?, 'pending', 0, ?, ?)", func(stmt *sqlite.Stmt) {
+		stmt.BindText(1, m.Kind)
+		stmt.BindText(2, m.Payload)
+		sqliteBindTime(stmt, 3, &now)
+		sqliteBindTime(stmt, 4, &now)
+	})
+	if err != nil {
+		return err
+	}
+	m.ID = r.db.LastInsertRowID()
+	m.Status = entity.OutboxStatusPending
+	m.NextAttemptAt = now
+	m.CreatedAt = now
+	return nil
+}
+
+// ClaimDue returns due pending messages and leases them. A single sqlite connection
+// has no concurrent dispatchers, so select-then-update is sufficient.
+func (r *outboxRepositorySqlite) ClaimDue(_ context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.OutboxMessageEntity, error) {
+	messages, err := r.query("SELECT "+outboxColumns+" FROM outbox WHERE status = 'pending' AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?", func(stmt *sqlite.Stmt) {
+		sqliteBindTime(stmt, 1, &now)
+		stmt.BindInt64(2, int64(limit))
+	})
+	if err != nil {
+		return nil, err
+	}
+	leaseUntil := now.Add(lease)
+	for _, m := range messages {
+		_, err := r.exec("UPDATE outbox SET next_attempt_at = ? WHERE id = ?", func(stmt *sqlite.Stmt) {
+			sqliteBindTime(stmt, 1, &leaseUntil)
+			stmt.BindInt64(2, m.ID)
+		})
+		if err != nil {
+			return nil, err
+		}
+		m.NextAttemptAt = leaseUntil
+	}
+	return messages, nil
+}
+
+// MarkSent marks a message as sent.
+func (r *outboxRepositorySqlite) MarkSent(_ context.Context, id int64) error {
+	now := time.Now()
+	_, err := r.exec("UPDATE outbox SET status = 'sent', attempts = attempts + 1, last_error = '', sent_at = ? WHERE id = ?", func(stmt *sqlite.Stmt) {
+		sqliteBindTime(stmt, 1, &now)
+		stmt.BindInt64(2, id)
+	})
+	return err
+}
+
+// MarkFailed records a failed attempt.
+func (r *outboxRepositorySqlite) MarkFailed(_ context.Context, id int64, status string, nextAttemptAt time.Time, lastError string) error {
+	_, err := r.exec("UPDATE outbox SET status = ?, attempts = attempts + 1, next_attempt_at = ?, last_error = ? WHERE id = ?", func(stmt *sqlite.Stmt) {
+		stmt.BindText(1, status)
+		sqliteBindTime(stmt, 2, &nextAttemptAt)
+		stmt.BindText(3, lastError)
+		stmt.BindInt64(4, id)
+	})
+	return err
+}
+
+// List returns messages with the status, oldest first.
+func (r *outboxRepositorySqlite) List(_ context.Context, status string, limit int) ([]*entity.OutboxMessageEntity, error) {
+	return r.query("SELECT "+outboxColumns+" FROM outbox WHERE status = ? ORDER BY id LIMIT ?", func(stmt *sqlite.Stmt) {
+		stmt.BindText(1, status)
+		stmt.BindInt64(2, int64(limit))
+	})
+}
+
+// Requeue resets an unsent message to pending.
+func (r *outboxRepositorySqlite) Requeue(_ context.Context, id int64) (bool, error) {
+	now := time.Now()
+	n, err := r.exec("UPDATE outbox SET status = 'pending', attempts = 0, next_attempt_at = ? WHERE id = ? AND status <> 'sent'", func(stmt *sqlite.Stmt) {
+		sqliteBindTime(stmt, 1, &now)
+		stmt.BindInt64(2, id)
+	})
+	return n > 0, err
+}
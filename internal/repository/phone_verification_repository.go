@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"zombiezen.com/go/sqlite"
+)
+
+// PhoneVerificationRepository defines SMS verification code database operations.
+type PhoneVerificationRepository interface {
+	// Create stores a new code and invalidates earlier unused codes for the same number and purpose.
+	Create(ctx context.Context, v *entity.PhoneVerificationEntity) error
+	// FindLatest returns the most recent code for the number and purpose, used or not.
+	FindLatest(ctx context.Context, phoneNumber, purpose string) (*entity.PhoneVerificationEntity, error)
+	// IncrementAttempts counts an attempt if fewer than max were made and returns the new count.
+	// ok is false, without counting, once max attempts were made.
+	IncrementAttempts(ctx context.Context, id int64, max int) (attempts int, ok bool, err error)
+	// MarkUsed marks the code used, reporting false if it already was.
+	MarkUsed(ctx context.Context, id int64) (bool, error)
+	// CountSince returns how many codes were created for the number and purpose since the time
+	// and the total attempts made against them.
+	CountSince(ctx context.Context, phoneNumber, purpose string, since time.Time) (sent int, attempts int, err error)
+	CreateTable(ctx context.Context) error
+}
+
+type phoneVerificationRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+// NewPhoneVerificationRepository creates a new PhoneVerificationRepository instance.
+func NewPhoneVerificationRepository(dbPool *pgxpool.Pool) PhoneVerificationRepository {
+	r := &phoneVerificationRepository{dbPool: dbPool}
+	if err := r.CreateTable(context.Background()); err != nil {
+		slog.Warn("Error creating phone_verifications table", "error", err)
+	}
+	return r
+}
+
+// NewPhoneVerificationRepositoryAuto returns a PhoneVerificationRepository for the given DB type.
+func NewPhoneVerificationRepositoryAuto(dbType string, pgxPool *pgxpool.Pool, sqliteConn interface{}) PhoneVerificationRepository {
+	switch dbType {
+	case "sqlite":
+		if conn, ok := sqliteConn.(*sqlite.Conn); ok {
+			return NewPhoneVerificationRepositorySqlite(conn)
+		}
+		panic("sqliteConn is not *sqlite.Conn")
+	case "postgres":
+		fallthrough
+	default:
+		return NewPhoneVerificationRepository(pgxPool)
+	}
+}
+
+// CreateTable creates the phone_verifications table if it does not exist
+func (r *phoneVerificationRepository) CreateTable(ctx context.Context) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS phone_verifications (
+		id           SERIAL PRIMARY KEY,
+		phone_number VARCHAR(16) NOT NULL,
+		purpose      VARCHAR(32) NOT NULL,
+		code_hash    VARCHAR(64) NOT NULL,
+		attempts     INTEGER NOT NULL DEFAULT 0,
+		expired_at   TIMESTAMPTZ NOT NULL,
+		used         BOOLEAN DEFAULT false,
+		created_at   TIMESTAMPTZ DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_phone_verifications_phone ON phone_verifications (phone_number, purpose, id DESC);
+	`
+	_, err := r.dbPool.Exec(ctx, query)
+	return err
+}
+
+// Create: 이전 미사용 코드를 무효화하고 새 인증 코드(해시) 저장
+func (r *phoneVerificationRepository) Create(ctx context.Context, v *entity.PhoneVerificationEntity) error {
+	_, err := r.dbPool.Exec(ctx, `UPDATE phone_verifications SET used = true
+		WHERE phone_number = $1 AND purpose = $2 AND used = false`, v.PhoneNumber, v.Purpose)
+	if err != nil {
+		return err
+	}
+	return r.dbPool.QueryRow(ctx, `INSERT INTO phone_verifications (phone_number, purpose, code_hash, attempts, expired_at, used, created_at)
+		VALUES ($1, $2, $3, 0, $4, false, NOW())
+		RETURNING id, created_at`,
+		v.PhoneNumber, v.Purpose, v.CodeHash, v.ExpiredAt,
+	).Scan(&v.ID, &v.CreatedAt)
+}
+
+// FindLatest: 전화번호와 용도로 가장 최근 인증 코드 조회
+func (r *phoneVerificationRepository) FindLatest(ctx context.Context, phoneNumber, purpose string) (*entity.PhoneVerificationEntity, error) {
+	v := &entity.PhoneVerificationEntity{}
+	err := r.dbPool.QueryRow(ctx, `SELECT id, phone_number, purpose, code_hash, attempts, expired_at, used, created_at
+		FROM phone_verifications
+		WHERE phone_number = $1 AND purpose = $2
+		ORDER BY id DESC LIMIT 1`, phoneNumber, purpose,
+	).Scan(&v.ID, &v.PhoneNumber, &v.Purpose, &v.CodeHash, &v.Attempts, &v.ExpiredAt, &v.Used, &v.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// IncrementAttempts: 시도 횟수가 max 미만일 때만 원자적으로 증가
+func (r *phoneVerificationRepository) IncrementAttempts(ctx context.Context, id int64, max int) (int, bool, error) {
+	var attempts int
+	err := r.dbPool.QueryRow(ctx, `UPDATE phone_verifications SET attempts = attempts + 1
+		WHERE id = $1 AND attempts < $2
+		RETURNING attempts`, id, max,
+	).Scan(&attempts)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return attempts, true, nil
+}
+
+// MarkUsed: 인증 코드 사용 처리 (used=true), 이미 사용됐으면 false
+func (r *phoneVerificationRepository) MarkUsed(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.dbPool.Exec(ctx, `UPDATE phone_verifications SET used = true WHERE id = $1 AND used = false`, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// CountSince: 기간 안에 보낸 인증 코드 수와 시도 횟수 합계 조회
+func (r *phoneVerificationRepository) CountSince(ctx context.Context, phoneNumber, purpose string, since time.Time) (int, int, error) {
+	var sent, attempts int
+	err := r.dbPool.QueryRow(ctx, `SELECT COUNT(*), COALESCE(SUM(attempts), 0)
+		FROM phone_verifications
+		WHERE phone_number = $1 AND purpose = $2 AND created_at >= $3`, phoneNumber, purpose, since,
+	).Scan(&sent, &attempts)
+	if err != nil {
+		return 0, 0, err
+	}
+	return sent, attempts, nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"context"
+	"log/slog"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+)
+
+type phoneVerificationRepositorySqlite struct {
+	db *sqlite.Conn
+}
+
+// NewPhoneVerificationRepositorySqlite returns a new sqlite-based PhoneVerificationRepository.
+func NewPhoneVerificationRepositorySqlite(conn *sqlite.Conn) PhoneVerificationRepository {
+	r := &phoneVerificationRepositorySqlite{db: conn}
+	if err := r.CreateTable(context.Background()); err != nil {
+		slog.Warn("[sqlite] Error creating phone_verifications table", "error", err)
+	}
+	return r
+}
+
+// CreateTable creates the phone_verifications table if it does not exist.
+func (r *phoneVerificationRepositorySqlite) CreateTable(_ context.Context) error {
+	return sqliteExec(r.db,
+		`CREATE TABLE IF NOT EXISTS phone_verifications (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			phone_number TEXT NOT NULL,
+			purpose TEXT NOT NULL,
+			code_hash TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			expired_at DATETIME NOT NULL,
+			used BOOLEAN DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_phone_verifications_phone ON phone_verifications (phone_number, purpose, id);`,
+	)
+}
+
+// Create stores a new code and invalidates earlier unused codes.
+func (r *phoneVerificationRepositorySqlite) Create(_ context.Context, v *entity.PhoneVerificationEntity) error {
+	stmt, err := r.db.Prepare("UPDATE phone_verifications SET used = 1 WHERE phone_number = ? AND purpose = ? AND used = 0")
+	if err != nil {
+		return err
+	}
+	stmt.BindText(1, v.PhoneNumber)
+	stmt.BindText(2, v.Purpose)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return err
+	}
+	if err2 != nil {
+		return err2
+	}
+
+	v.CreatedAt = time.Now()
+	stmt, err = r.db.Prepare("INSERT INTO phone_verifications (phone_number, purpose, code_hash, attempts, expired_at, used, created_at) VALUES (?, ?, ?, 0, ?, 0, ?)")
+	if err != nil {
+		return err
+	}
+	stmt.BindText(1, v.PhoneNumber)
+	stmt.BindText(2, v.Purpose)
+	stmt.BindText(3, v.CodeHash)
+	sqliteBindTime(stmt, 4, &v.ExpiredAt)
+	sqliteBindTime(stmt, 5, &v.CreatedAt)
+	_, err = stmt.Step()
+	err2 = stmt.Finalize()
+	if err != nil {
+		return err
+	}
+	if err2 != nil {
+		return err2
+	}
+	v.ID = r.db.LastInsertRowID()
+	return nil
+}
+
+// FindLatest returns the most recent code for the number and purpose.
+func (r *phoneVerificationRepositorySqlite) FindLatest(_ context.Context, phoneNumber, purpose string) (*entity.PhoneVerificationEntity, error) {
+	stmt, err := r.db.Prepare("SELECT id, phone_number, purpose, code_hash, attempts, expired_at, used, created_at FROM phone_verifications WHERE phone_number = ? AND purpose = ? ORDER BY id DESC LIMIT 1")
+	if err != nil {
+		return nil, err
+	}
+	stmt.BindText(1, phoneNumber)
+	stmt.BindText(2, purpose)
+	hasRow, err := stmt.Step()
+	if err != nil {
+		_ = stmt.Finalize()
+		return nil, err
+	}
+	if !hasRow {
+		_ = stmt.Finalize()
+		return nil, nil
+	}
+	var v entity.PhoneVerificationEntity
+	v.ID = stmt.ColumnInt64(0)
+	v.PhoneNumber = stmt.ColumnText(1)
+	v.Purpose = stmt.ColumnText(2)
+	v.CodeHash = stmt.ColumnText(3)
+	v.Attempts = stmt.ColumnInt(4)
+	if t := sqliteColumnTime(stmt, 5); t != nil {
+		v.ExpiredAt = *t
+	}
+	v.Used = stmt.ColumnInt64(6) != 0
+	if t := sqliteColumnTime(stmt, 7); t != nil {
+		v.CreatedAt = *t
+	}
+	if err := stmt.Finalize(); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// IncrementAttempts counts an attempt if fewer than max were made.
+func (r *phoneVerificationRepositorySqlite) IncrementAttempts(_ context.Context, id int64, max int) (int, bool, error) {
+	stmt, err := r.db.Prepare("UPDATE phone_verifications SET attempts = attempts + 1 WHERE id = ? AND attempts < ? RETURNING attempts")
+	if err != nil {
+		return 0, false, err
+	}
+	stmt.BindInt64(1, id)
+	stmt.BindInt64(2, int64(max))
+	hasRow, err := stmt.Step()
+	var attempts int
+	if err == nil && hasRow {
+		attempts = stmt.ColumnInt(0)
+		// RETURNING 문은 끝까지 실행해야 변경이 확정된다
+		_, err = stmt.Step()
+	}
+	err2 := stmt.Finalize()
+	if err != nil {
+		return 0, false, err
+	}
+	if err2 != nil {
+		return 0, false, err2
+	}
+	return attempts, hasRow, nil
+}
+
+// MarkUsed marks a code as used, reporting false if it already was.
+func (r *phoneVerificationRepositorySqlite) MarkUsed(_ context.Context, id int64) (bool, error) {
+	stmt, err := r.db.Prepare("UPDATE phone_verifications SET used = 1 WHERE id = ? AND used = 0")
+	if err != nil {
+		return false, err
+	}
+	stmt.BindInt64(1, id)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return false, err
+	}
+	if err2 != nil {
+		return false, err2
+	}
+	return r.db.Changes() == 1, nil
+}
+
+// CountSince returns the number of codes created since the time and their total attempts.
+func (r *phoneVerificationRepositorySqlite) CountSince(_ context.Context, phoneNumber, purpose string, since time.Time) (int, int, error) {
+	stmt, err := r.db.Prepare("SELECT COUNT(*), COALESCE(SUM(attempts), 0) FROM phone_verifications WHERE phone_number = ? AND purpose = ? AND created_at >= ?")
+	if err != nil {
+		return 0, 0, err
+	}
+	stmt.BindText(1, phoneNumber)
+	stmt.BindText(2, purpose)
+	sqliteBindTime(stmt, 3, &since)
+	var sent, attempts int
+	hasRow, err := stmt.Step()
+	if err == nil && hasRow {
+		sent = stmt.ColumnInt(0)
+		attempts = stmt.ColumnInt(1)
+	}
+	err2 := stmt.Finalize()
+	if err != nil {
+		return 0, 0, err
+	}
+	if err2 != nil {
+		return 0, 0, err2
+	}
+	return sent, attempts, nil
+}
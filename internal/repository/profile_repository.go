@@ -20,6 +20,9 @@ type ProfileRepository interface {
 	FindByUserID(ctx context.Context, userID int64) (*entity.ProfileEntity, error)
 	FindByPhoneNumber(ctx context.Context, phoneNumber string) (*entity.ProfileEntity, error)
 	Update(ctx context.Context, p *entity.ProfileEntity) error
+	// FindNotificationPreference returns the user's notification preferences, or the defaults if none are stored.
+	FindNotificationPreference(ctx context.Context, userID int64) (*entity.NotificationPreferenceEntity, error)
+	SaveNotificationPreference(ctx context.Context, p *entity.NotificationPreferenceEntity) error
 	CreateTable(ctx context.Context) error
 }
 
@@ -81,6 +84,18 @@ func (r *profileRepository) CreateTable(ctx context.Context) error {
 		created_at   TIMESTAMPTZ DEFAULT NOW(),
 		updated_at   TIMESTAMPTZ DEFAULT NOW()
 	);
+
+	ALTER TABLE profiles ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMPTZ;
+	ALTER TABLE profiles ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT '';
+
+	CREATE TABLE IF NOT EXISTS notification_preferences (
+		user_id          INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+		new_device_login BOOLEAN NOT NULL DEFAULT true,
+		password_changed BOOLEAN NOT NULL DEFAULT true,
+		phone_changed    BOOLEAN NOT NULL DEFAULT true,
+		account_deleted  BOOLEAN NOT NULL DEFAULT true,
+		updated_at       TIMESTAMPTZ DEFAULT NOW()
+	);
 	`
 	_, err := r.dbPool.Exec(ctx, query)
 	return err
@@ -92,11 +107,11 @@ func (r *profileRepository) CreateTx(ctx context.Context, tx interface{}, p *ent
 	if !ok {
 		return errors.New("tx is not pgx.Tx")
 	}
-	query := `INSERT INTO profiles (user_id, name, birth_date, gender_code, phone_number, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	query := `INSERT INTO profiles (user_id, name, birth_date, gender_code, phone_number, locale, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id`
 	return pgxTx.QueryRow(ctx, query,
-		p.UserID, p.Name, p.BirthDate, p.GenderCode, p.PhoneNumber, p.CreatedAt, p.UpdatedAt,
+		p.UserID, p.Name, p.BirthDate, p.GenderCode, p.PhoneNumber, p.Locale, p.CreatedAt, p.UpdatedAt,
 	).Scan(&p.ID)
 }
 
@@ -121,13 +136,15 @@ func (r *profileRepository) FindByUserID(ctx context.Context, userID int64) (*en
         birth_date,         -- time.Time
         gender_code,        -- string
         phone_number,       -- string
+        phone_verified_at,  -- *time.Time
+        locale,             -- string
         created_at,         -- time.Time
         updated_at          -- time.Time
     FROM profiles
     WHERE user_id = $1`
 	p := &entity.ProfileEntity{}
 	err := r.dbPool.QueryRow(ctx, query, userID).Scan(
-		&p.ID, &p.UserID, &p.Name, &p.BirthDate, &p.GenderCode, &p.PhoneNumber, &p.CreatedAt, &p.UpdatedAt,
+		&p.ID, &p.UserID, &p.Name, &p.BirthDate, &p.GenderCode, &p.PhoneNumber, &p.PhoneVerifiedAt, &p.Locale, &p.CreatedAt, &p.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -148,6 +165,8 @@ func (r *profileRepository) FindByPhoneNumber(ctx context.Context, phoneNumber s
             birth_date,         -- time.Time
             gender_code,        -- string
             phone_number,       -- string
+            phone_verified_at,  -- *time.Time
+            locale,             -- string
             created_at,         -- time.Time
             updated_at          -- time.Time
         FROM profiles
@@ -155,7 +174,7 @@ func (r *profileRepository) FindByPhoneNumber(ctx context.Context, phoneNumber s
     `
 	p := &entity.ProfileEntity{}
 	err := r.dbPool.QueryRow(ctx, query, phoneNumber).Scan(
-		&p.ID, &p.UserID, &p.Name, &p.BirthDate, &p.GenderCode, &p.PhoneNumber, &p.CreatedAt, &p.UpdatedAt,
+		&p.ID, &p.UserID, &p.Name, &p.BirthDate, &p.GenderCode, &p.PhoneNumber, &p.PhoneVerifiedAt, &p.Locale, &p.CreatedAt, &p.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -169,10 +188,10 @@ func (r *profileRepository) FindByPhoneNumber(ctx context.Context, phoneNumber s
 // Update modifies an existing profile
 func (r *profileRepository) Update(ctx context.Context, p *entity.ProfileEntity) error {
 	query := `UPDATE profiles
-        SET name = $1, birth_date = $2, gender_code = $3, phone_number = $4, updated_at = $5
-        WHERE user_id = $6`
+        SET name = $1, birth_date = $2, gender_code = $3, phone_number = $4, phone_verified_at = $5, locale = $6, updated_at = $7
+        WHERE user_id = $8`
 	cmd, err := r.dbPool.Exec(ctx, query,
-		p.Name, p.BirthDate, p.GenderCode, p.PhoneNumber, p.UpdatedAt, p.UserID,
+		p.Name, p.BirthDate, p.GenderCode, p.PhoneNumber, p.PhoneVerifiedAt, p.Locale, p.UpdatedAt, p.UserID,
 	)
 	if err != nil {
 		return err
@@ -186,10 +205,10 @@ func (r *profileRepository) Update(ctx context.Context, p *entity.ProfileEntity)
 // UpdateTx modifies an existing profile within a transaction
 func (r *profileRepository) UpdateTx(ctx context.Context, tx pgx.Tx, p *entity.ProfileEntity) error {
 	query := `UPDATE profiles
-        SET name = $1, birth_date = $2, gender_code = $3, phone_number = $4, updated_at = $5
-        WHERE user_id = $6`
+        SET name = $1, birth_date = $2, gender_code = $3, phone_number = $4, phone_verified_at = $5, locale = $6, updated_at = $7
+        WHERE user_id = $8`
 	cmd, err := tx.Exec(ctx, query,
-		p.Name, p.BirthDate, p.GenderCode, p.PhoneNumber, p.UpdatedAt, p.UserID,
+		p.Name, p.BirthDate, p.GenderCode, p.PhoneNumber, p.PhoneVerifiedAt, p.Locale, p.UpdatedAt, p.UserID,
 	)
 	if err != nil {
 		return err
@@ -199,3 +218,34 @@ func (r *profileRepository) UpdateTx(ctx context.Context, tx pgx.Tx, p *entity.P
 	}
 	return nil
 }
+
+// FindNotificationPreference retrieves notification preferences, defaulting to all enabled
+func (r *profileRepository) FindNotificationPreference(ctx context.Context, userID int64) (*entity.NotificationPreferenceEntity, error) {
+	p := &entity.NotificationPreferenceEntity{UserID: userID}
+	err := r.dbPool.QueryRow(ctx, `SELECT new_device_login, password_changed, phone_changed, account_deleted
+		FROM notification_preferences WHERE user_id = $1`, userID,
+	).Scan(&p.NewDeviceLogin, &p.PasswordChanged, &p.PhoneChanged, &p.AccountDeleted)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.DefaultNotificationPreference(userID), nil
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+// SaveNotificationPreference inserts or updates notification preferences
+func (r *profileRepository) SaveNotificationPreference(ctx context.Context, p *entity.NotificationPreferenceEntity) error {
+	_, err := r.dbPool.Exec(ctx, `INSERT INTO notification_preferences
+		(user_id, new_device_login, password_changed, phone_changed, account_deleted, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			new_device_login = EXCLUDED.new_device_login,
+			password_changed = EXCLUDED.password_changed,
+			phone_changed = EXCLUDED.phone_changed,
+			account_deleted = EXCLUDED.account_deleted,
+			updated_at = NOW()`,
+		p.UserID, p.NewDeviceLogin, p.PasswordChanged, p.PhoneChanged, p.AccountDeleted,
+	)
+	return err
+}
@@ -23,26 +23,40 @@ func NewProfileRepositorySqlite(conn *sqlite.Conn) ProfileRepository {
 }
 
 func (r *profileRepositorySqlite) createTable(_ context.Context) error {
-	stmt, err := r.db.Prepare(`CREATE TABLE IF NOT EXISTS profiles (
+	err := sqliteExec(r.db, `CREATE TABLE IF NOT EXISTS profiles (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		user_id INTEGER NOT NULL,
 		name TEXT NOT NULL,
 		birth_date TEXT,
 		gender_code TEXT,
 		phone_number TEXT UNIQUE,
+		phone_verified_at DATETIME,
+		locale TEXT NOT NULL DEFAULT '',
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);`)
 	if err != nil {
 		return err
 	}
-	err = stmt.Finalize()
-	return err
+	if err := sqliteAddColumn(r.db, "profiles", "phone_verified_at", "DATETIME"); err != nil {
+		return err
+	}
+	if err := sqliteAddColumn(r.db, "profiles", "locale", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	return sqliteExec(r.db, `CREATE TABLE IF NOT EXISTS notification_preferences (
+		user_id INTEGER PRIMARY KEY,
+		new_device_login BOOLEAN NOT NULL DEFAULT 1,
+		password_changed BOOLEAN NOT NULL DEFAULT 1,
+		phone_changed BOOLEAN NOT NULL DEFAULT 1,
+		account_deleted BOOLEAN NOT NULL DEFAULT 1,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`)
 }
 
 // CreateTx creates a profile in sqlite (no real tx used)
 func (r *profileRepositorySqlite) CreateTx(_ context.Context, _ interface{}, p *entity.ProfileEntity) error {
-	stmt, err := r.db.Prepare("INSERT INTO profiles (user_id, name, birth_date, gender_code, phone_number, created_at, updated_at) VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)")
+	stmt, err := r.db.Prepare("INSERT INTO profiles (user_id, name, birth_date, gender_code, phone_number, locale, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)")
 	if err != nil {
 		return err
 	}
@@ -51,6 +65,7 @@ func (r *profileRepositorySqlite) CreateTx(_ context.Context, _ interface{}, p *
 	stmt.BindText(3, p.BirthDate.Format("2006-01-02"))
 	stmt.BindText(4, string(p.GenderCode))
 	stmt.BindText(5, p.PhoneNumber)
+	stmt.BindText(6, p.Locale)
 	_, err = stmt.Step()
 	err2 := stmt.Finalize()
 	if err != nil {
@@ -61,7 +76,7 @@ func (r *profileRepositorySqlite) CreateTx(_ context.Context, _ interface{}, p *
 
 // FindByUserID returns a profile by user ID.
 func (r *profileRepositorySqlite) FindByUserID(_ context.Context, userID int64) (*entity.ProfileEntity, error) {
-	stmt, err := r.db.Prepare("SELECT id, user_id, name, birth_date, gender_code, phone_number, created_at, updated_at FROM profiles WHERE user_id = ?")
+	stmt, err := r.db.Prepare("SELECT id, user_id, name, birth_date, gender_code, phone_number, phone_verified_at, locale, created_at, updated_at FROM profiles WHERE user_id = ?")
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +103,8 @@ func (r *profileRepositorySqlite) FindByUserID(_ context.Context, userID int64)
 	}
 	p.GenderCode = entity.GenderCode(stmt.ColumnText(4))
 	p.PhoneNumber = stmt.ColumnText(5)
+	p.PhoneVerifiedAt = sqliteColumnTime(stmt, 6)
+	p.Locale = stmt.ColumnText(7)
 	// created_at, updated_at 생략 가능
 	_ = stmt.Finalize()
 	return &p, nil
@@ -95,7 +112,7 @@ func (r *profileRepositorySqlite) FindByUserID(_ context.Context, userID int64)
 
 // FindByPhoneNumber returns a profile by phone number.
 func (r *profileRepositorySqlite) FindByPhoneNumber(_ context.Context, phoneNumber string) (*entity.ProfileEntity, error) {
-	stmt, err := r.db.Prepare("SELECT id, user_id, name, birth_date, gender_code, phone_number, created_at, updated_at FROM profiles WHERE phone_number = ?")
+	stmt, err := r.db.Prepare("SELECT id, user_id, name, birth_date, gender_code, phone_number, phone_verified_at, locale, created_at, updated_at FROM profiles WHERE phone_number = ?")
 	if err != nil {
 		return nil, err
 	}
@@ -122,6 +139,8 @@ func (r *profileRepositorySqlite) FindByPhoneNumber(_ context.Context, phoneNumb
 	}
 	p.GenderCode = entity.GenderCode(stmt.ColumnText(4))
 	p.PhoneNumber = stmt.ColumnText(5)
+	p.PhoneVerifiedAt = sqliteColumnTime(stmt, 6)
+	p.Locale = stmt.ColumnText(7)
 	// created_at, updated_at 생략 가능
 	_ = stmt.Finalize()
 	return &p, nil
@@ -129,7 +148,7 @@ func (r *profileRepositorySqlite) FindByPhoneNumber(_ context.Context, phoneNumb
 
 // Update updates a profile in sqlite.
 func (r *profileRepositorySqlite) Update(_ context.Context, p *entity.ProfileEntity) error {
-	stmt, err := r.db.Prepare("UPDATE profiles SET name = ?, birth_date = ?, gender_code = ?, phone_number = ?, updated_at = CURRENT_TIMESTAMP WHERE user_id = ?")
+	stmt, err := r.db.Prepare("UPDATE profiles SET name = ?, birth_date = ?, gender_code = ?, phone_number = ?, phone_verified_at = ?, locale = ?, updated_at = CURRENT_TIMESTAMP WHERE user_id = ?")
 	if err != nil {
 		return err
 	}
@@ -137,7 +156,62 @@ func (r *profileRepositorySqlite) Update(_ context.Context, p *entity.ProfileEnt
 	stmt.BindText(2, p.BirthDate.Format("2006-01-02"))
 	stmt.BindText(3, string(p.GenderCode))
 	stmt.BindText(4, p.PhoneNumber)
-	stmt.BindInt64(5, p.UserID)
+	sqliteBindTime(stmt, 5, p.PhoneVerifiedAt)
+	stmt.BindText(6, p.Locale)
+	stmt.BindInt64(7, p.UserID)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return err
+	}
+	return err2
+}
+
+// FindNotificationPreference returns the user's notification preferences, or the defaults if none are stored.
+func (r *profileRepositorySqlite) FindNotificationPreference(_ context.Context, userID int64) (*entity.NotificationPreferenceEntity, error) {
+	stmt, err := r.db.Prepare("SELECT new_device_login, password_changed, phone_changed, account_deleted FROM notification_preferences WHERE user_id = ?")
+	if err != nil {
+		return nil, err
+	}
+	stmt.BindInt64(1, userID)
+	hasRow, err := stmt.Step()
+	if err != nil {
+		_ = stmt.Finalize()
+		return nil, err
+	}
+	if !hasRow {
+		_ = stmt.Finalize()
+		return entity.DefaultNotificationPreference(userID), nil
+	}
+	p := &entity.NotificationPreferenceEntity{
+		UserID:          userID,
+		NewDeviceLogin:  stmt.ColumnBool(0),
+		PasswordChanged: stmt.ColumnBool(1),
+		PhoneChanged:    stmt.ColumnBool(2),
+		AccountDeleted:  stmt.ColumnBool(3),
+	}
+	return p, stmt.Finalize()
+}
+
+// SaveNotificationPreference inserts or updates the user's notification preferences.
+func (r *profileRepositorySqlite) SaveNotificationPreference(_ context.Context, p *entity.NotificationPreferenceEntity) error {
+	stmt, err := r.db.Prepare(`INSERT INTO notification_preferences
+		(user_id, new_device_login, password_changed, phone_changed, account_deleted, updated_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id) DO UPDATE SET
+			new_device_login = excluded.new_device_login,
+			password_changed = excluded.password_changed,
+			phone_changed = excluded.phone_changed,
+			account_deleted = excluded.account_deleted,
+			updated_at = CURRENT_TIMESTAMP`)
+	if err != nil {
+		return err
+	}
+	stmt.BindInt64(1, p.UserID)
+	stmt.BindBool(2, p.NewDeviceLogin)
+	stmt.BindBool(3, p.PasswordChanged)
+	stmt.BindBool(4, p.PhoneChanged)
+	stmt.BindBool(5, p.AccountDeleted)
 	_, err = stmt.Step()
 	err2 := stmt.Finalize()
 	if err != nil {
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"auth/internal/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tracedProfileRepository records a span for every ProfileRepository call.
+type tracedProfileRepository struct {
+	next   ProfileRepository
+	system attribute.KeyValue
+}
+
+// NewTracedProfileRepository wraps repo so every call is recorded as a span named "ProfileRepository.<Method>".
+func NewTracedProfileRepository(repo ProfileRepository, dbType string) ProfileRepository {
+	return &tracedProfileRepository{next: repo, system: dbSystem(dbType)}
+}
+
+func (r *tracedProfileRepository) CreateTx(ctx context.Context, tx interface{}, p *entity.ProfileEntity) error {
+	ctx, span := startSpan(ctx, r.system, "ProfileRepository", "CreateTx")
+	err := r.next.CreateTx(ctx, tx, p)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedProfileRepository) FindByUserID(ctx context.Context, userID int64) (*entity.ProfileEntity, error) {
+	ctx, span := startSpan(ctx, r.system, "ProfileRepository", "FindByUserID")
+	result, err := r.next.FindByUserID(ctx, userID)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *tracedProfileRepository) FindByPhoneNumber(ctx context.Context, phoneNumber string) (*entity.ProfileEntity, error) {
+	ctx, span := startSpan(ctx, r.system, "ProfileRepository", "FindByPhoneNumber")
+	result, err := r.next.FindByPhoneNumber(ctx, phoneNumber)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *tracedProfileRepository) Update(ctx context.Context, p *entity.ProfileEntity) error {
+	ctx, span := startSpan(ctx, r.system, "ProfileRepository", "Update")
+	err := r.next.Update(ctx, p)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedProfileRepository) FindNotificationPreference(ctx context.Context, userID int64) (*entity.NotificationPreferenceEntity, error) {
+	ctx, span := startSpan(ctx, r.system, "ProfileRepository", "FindNotificationPreference")
+	result, err := r.next.FindNotificationPreference(ctx, userID)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *tracedProfileRepository) SaveNotificationPreference(ctx context.Context, p *entity.NotificationPreferenceEntity) error {
+	ctx, span := startSpan(ctx, r.system, "ProfileRepository", "SaveNotificationPreference")
+	err := r.next.SaveNotificationPreference(ctx, p)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedProfileRepository) CreateTable(ctx context.Context) error {
+	ctx, span := startSpan(ctx, r.system, "ProfileRepository", "CreateTable")
+	err := r.next.CreateTable(ctx)
+	tracing.End(span, err)
+	return err
+}
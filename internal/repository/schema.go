@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"zombiezen.com/go/sqlite"
+)
+
+// Tables are the tables the repositories create when they are constructed.
+var Tables = []string{
+	"users",
+	"profiles",
+	"refresh_tokens",
+	"password_reset_tokens",
+	"password_history",
+	"magic_link_tokens",
+	"user_devices",
+	"user_roles",
+	"notification_preferences",
+	"phone_verifications",
+	"outbox",
+	"audit_events",
+	"webhook_endpoints",
+	"webhook_deliveries",
+	"signing_keys",
+}
+
+// MissingTables returns the Tables that do not exist in the database, e.g. because creating them
+// failed on startup; the constructors only log that error.
+func MissingTables(ctx context.Context, dbType string, pgxPool *pgxpool.Pool, sqliteConn interface{}) ([]string, error) {
+	existing := map[string]bool{}
+	switch dbType {
+	case "sqlite":
+		conn, ok := sqliteConn.(*sqlite.Conn)
+		if !ok || conn == nil {
+			return nil, errors.New("sqliteConn is not *sqlite.Conn")
+		}
+		stmt, err := conn.Prepare(`SELECT name FROM sqlite_master WHERE type = 'table'`)
+		if err != nil {
+			return nil, err
+		}
+		for {
+			hasRow, err := stmt.Step()
+			if err != nil {
+				_ = stmt.Finalize()
+				return nil, err
+			}
+			if !hasRow {
+				break
+			}
+			existing[stmt.ColumnText(0)] = true
+		}
+		if err := stmt.Finalize(); err != nil {
+			return nil, err
+		}
+	default:
+		if pgxPool == nil {
+			return nil, errors.New("database not connected")
+		}
+		rows, err := pgxPool.Query(ctx, `SELECT tablename FROM pg_tables WHERE schemaname = current_schema()`)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return nil, err
+			}
+			existing[name] = true
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	var missing []string
+	for _, t := range Tables {
+		if !existing[t] {
+			missing = append(missing, t)
+		}
+	}
+	return missing, nil
+}
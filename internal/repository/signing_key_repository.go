@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"zombiezen.com/go/sqlite"
+)
+
+// SigningKeyRepository defines access token signing key database operations.
+type SigningKeyRepository interface {
+	// Create stores a new active key, setting its ID and CreatedAt.
+	Create(ctx context.Context, k *entity.SigningKeyEntity) error
+	// List returns all keys, newest first.
+	List(ctx context.Context) ([]*entity.SigningKeyEntity, error)
+	// RetireOthers marks every active key except kid as retired at t and returns how many were retired.
+	RetireOthers(ctx context.Context, kid string, t time.Time) (int64, error)
+	CreateTable(ctx context.Context) error
+}
+
+type signingKeyRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+// NewSigningKeyRepository creates a new SigningKeyRepository instance.
+func NewSigningKeyRepository(dbPool *pgxpool.Pool) SigningKeyRepository {
+	r := &signingKeyRepository{dbPool: dbPool}
+	if err := r.CreateTable(context.Background()); err != nil {
+		slog.Warn("Error creating signing_keys table", "error", err)
+	}
+	return r
+}
+
+// NewSigningKeyRepositoryAuto returns a SigningKeyRepository for the given DB type.
+func NewSigningKeyRepositoryAuto(dbType string, pgxPool *pgxpool.Pool, sqliteConn interface{}) SigningKeyRepository {
+	switch dbType {
+	case "sqlite":
+		if conn, ok := sqliteConn.(*sqlite.Conn); ok {
+			return NewSigningKeyRepositorySqlite(conn)
+		}
+		panic("sqliteConn is not *sqlite.Conn")
+	case "postgres":
+		fallthrough
+	default:
+		return NewSigningKeyRepository(pgxPool)
+	}
+}
+
+// CreateTable creates the signing_keys table if it does not exist
+func (r *signingKeyRepository) CreateTable(ctx context.Context) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS signing_keys (
+		id          BIGSERIAL PRIMARY KEY,
+		kid         VARCHAR(64) NOT NULL UNIQUE,
+		algorithm   VARCHAR(16) NOT NULL,
+		private_key TEXT NOT NULL,
+		created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		retired_at  TIMESTAMPTZ
+	);
+	`
+	_, err := r.dbPool.Exec(ctx, query)
+	return err
+}
+
+// Create: 새 서명 키 저장
+func (r *signingKeyRepository) Create(ctx context.Context, k *entity.SigningKeyEntity) error {
+	return r.dbPool.QueryRow(ctx, `INSERT INTO signing_keys (kid, algorithm, private_key, created_at)
+		VALUES ($1, $2, $3, NOW())
+		RETURNING id, created_at`,
+		k.KID, k.Algorithm, k.PrivateKey,
+	).Scan(&k.ID, &k.CreatedAt)
+}
+
+// List: 전체 키 조회 (최신순)
+func (r *signingKeyRepository) List(ctx context.Context) ([]*entity.SigningKeyEntity, error) {
+	rows, err := r.dbPool.Query(ctx, `SELECT id, kid, algorithm, private_key, created_at, retired_at
+		FROM signing_keys ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []*entity.SigningKeyEntity
+	for rows.Next() {
+		k := &entity.SigningKeyEntity{}
+		if err := rows.Scan(&k.ID, &k.KID, &k.Algorithm, &k.PrivateKey, &k.CreatedAt, &k.RetiredAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// RetireOthers: kid 를 제외한 활성 키를 폐기
+func (r *signingKeyRepository) RetireOthers(ctx context.Context, kid string, t time.Time) (int64, error) {
+	tag, err := r.dbPool.Exec(ctx, `UPDATE signing_keys SET retired_at = $2 WHERE kid <> $1 AND retired_at IS NULL`, kid, t)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
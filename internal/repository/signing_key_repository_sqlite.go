@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"context"
+	"log/slog"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+)
+
+type signingKeyRepositorySqlite struct {
+	db *sqlite.Conn
+}
+
+// NewSigningKeyRepositorySqlite returns a new sqlite-based SigningKeyRepository.
+func NewSigningKeyRepositorySqlite(conn *sqlite.Conn) SigningKeyRepository {
+	r := &signingKeyRepositorySqlite{db: conn}
+	if err := r.CreateTable(context.Background()); err != nil {
+		slog.Warn("[sqlite] Error creating signing_keys table", "error", err)
+	}
+	return r
+}
+
+// CreateTable creates the signing_keys table if it does not exist.
+func (r *signingKeyRepositorySqlite) CreateTable(_ context.Context) error {
+	return sqliteExec(r.db,
+		`CREATE TABLE IF NOT EXISTS signing_keys (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			kid TEXT NOT NULL UNIQUE,
+			algorithm TEXT NOT NULL,
+			private_key TEXT NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			retired_at DATETIME
+		);`,
+	)
+}
+
+// Create stores a new active key.
+func (r *signingKeyRepositorySqlite) Create(_ context.Context, k *entity.SigningKeyEntity) error {
+	now := time.Now()
+	stmt, err := r.db.Prepare("INSERT INTO signing_keys (kid, algorithm, private_key, created_at) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	stmt.BindText(1, k.KID)
+	stmt.BindText(2, k.Algorithm)
+	stmt.BindText(3, k.PrivateKey)
+	sqliteBindTime(stmt, 4, &now)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return err
+	}
+	if err2 != nil {
+		return err2
+	}
+	k.ID = r.db.LastInsertRowID()
+	k.CreatedAt = now.UTC().Truncate(time.Second)
+	return nil
+}
+
+// List returns all keys, newest first.
+func (r *signingKeyRepositorySqlite) List(_ context.Context) ([]*entity.SigningKeyEntity, error) {
+	stmt, err := r.db.Prepare("SELECT id, kid, algorithm, private_key, created_at, retired_at FROM signing_keys ORDER BY created_at DESC, id DESC")
+	if err != nil {
+		return nil, err
+	}
+	var keys []*entity.SigningKeyEntity
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			_ = stmt.Finalize()
+			return nil, err
+		}
+		if !hasRow {
+			break
+		}
+		k := &entity.SigningKeyEntity{
+			ID:         stmt.ColumnInt64(0),
+			KID:        stmt.ColumnText(1),
+			Algorithm:  stmt.ColumnText(2),
+			PrivateKey: stmt.ColumnText(3),
+			RetiredAt:  sqliteColumnTime(stmt, 5),
+		}
+		if t := sqliteColumnTime(stmt, 4); t != nil {
+			k.CreatedAt = *t
+		}
+		keys = append(keys, k)
+	}
+	if err := stmt.Finalize(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// RetireOthers marks every active key except kid as retired.
+func (r *signingKeyRepositorySqlite) RetireOthers(_ context.Context, kid string, t time.Time) (int64, error) {
+	stmt, err := r.db.Prepare("UPDATE signing_keys SET retired_at = ? WHERE kid <> ? AND retired_at IS NULL")
+	if err != nil {
+		return 0, err
+	}
+	sqliteBindTime(stmt, 1, &t)
+	stmt.BindText(2, kid)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return 0, err
+	}
+	if err2 != nil {
+		return 0, err2
+	}
+	return int64(r.db.Changes()), nil
+}
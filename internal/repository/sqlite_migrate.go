@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+)
+
+// sqliteExec runs each statement in order.
+func sqliteExec(conn *sqlite.Conn, stmts ...string) error {
+	for _, q := range stmts {
+		stmt, err := conn.Prepare(q)
+		if err != nil {
+			return err
+		}
+		_, err = stmt.Step()
+		err2 := stmt.Finalize()
+		if err != nil {
+			return err
+		}
+		if err2 != nil {
+			return err2
+		}
+	}
+	return nil
+}
+
+// sqliteAddColumn adds a column to an existing table unless it is already there.
+// sqlite has no ADD COLUMN IF NOT EXISTS, so the table schema is checked first.
+func sqliteAddColumn(conn *sqlite.Conn, table, column, definition string) error {
+	stmt, err := conn.Prepare(fmt.Sprintf("SELECT 1 FROM pragma_table_info('%s') WHERE name = ?", table))
+	if err != nil {
+		return err
+	}
+	stmt.BindText(1, column)
+	hasRow, err := stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return err
+	}
+	if err2 != nil {
+		return err2
+	}
+	if hasRow {
+		return nil
+	}
+	return sqliteExec(conn, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+}
+
+// sqliteTimeLayout is the layout of CURRENT_TIMESTAMP, used for all DATETIME columns (UTC).
+const sqliteTimeLayout = "2006-01-02 15:04:05"
+
+// sqliteBindTime binds t as UTC text, or NULL when t is nil.
+func sqliteBindTime(stmt *sqlite.Stmt, param int, t *time.Time) {
+	if t == nil {
+		stmt.BindNull(param)
+		return
+	}
+	stmt.BindText(param, t.UTC().Format(sqliteTimeLayout))
+}
+
+// sqliteColumnTime reads a nullable DATETIME column; NULL or unparsable values are nil.
+func sqliteColumnTime(stmt *sqlite.Stmt, col int) *time.Time {
+	if stmt.ColumnType(col) == sqlite.TypeNull {
+		return nil
+	}
+	t, err := time.Parse(sqliteTimeLayout, stmt.ColumnText(col))
+	if err != nil {
+		return nil
+	}
+	return &t
+}
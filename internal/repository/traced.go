@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"auth/internal/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// dbSystem returns the db.system attribute for the database type used by the *Auto constructors.
+func dbSystem(dbType string) attribute.KeyValue {
+	if dbType == "sqlite" {
+		return semconv.DBSystemSqlite
+	}
+	return semconv.DBSystemPostgreSQL
+}
+
+// startSpan starts a client span for a repository call, e.g. "UserRepository.FindByEmail".
+func startSpan(ctx context.Context, system attribute.KeyValue, repo, op string) (context.Context, trace.Span) {
+	return otel.Tracer(tracing.ScopeName).Start(ctx, repo+"."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(system, semconv.DBOperationName(op)))
+}
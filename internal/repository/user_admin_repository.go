@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"zombiezen.com/go/sqlite"
+)
+
+// UserAdminRepository defines the user operations of administrators. Unlike UserRepository it
+// also finds deleted users. The tables are created by the UserRepository.
+type UserAdminRepository interface {
+	// FindByID returns the user with the ID, deleted or not.
+	FindByID(ctx context.Context, id int64) (*entity.UserEntity, error)
+	// FindByEmail returns the user with the email, deleted or not.
+	FindByEmail(ctx context.Context, email string) (*entity.UserEntity, error)
+	// List returns up to limit users with an ID greater than afterID, in ID order.
+	List(ctx context.Context, afterID int64, limit int) ([]*entity.UserEntity, error)
+	// CreateTx stores the user with all of its fields, including the password hash as it is.
+	CreateTx(ctx context.Context, tx interface{}, u *entity.UserEntity) (int64, error)
+	// SetDisabled disables the user at t, or enables it if t is nil. It reports whether the user exists.
+	SetDisabled(ctx context.Context, id int64, t *time.Time) (bool, error)
+	// Restore undeletes and enables the user. It reports whether the user exists.
+	Restore(ctx context.Context, id int64) (bool, error)
+	// FindRoles returns the user's roles in name order.
+	FindRoles(ctx context.Context, userID int64) ([]string, error)
+	// AddRole grants the role, reporting false if the user already had it.
+	AddRole(ctx context.Context, userID int64, role string) (bool, error)
+	// RemoveRole revokes the role, reporting false if the user did not have it.
+	RemoveRole(ctx context.Context, userID int64, role string) (bool, error)
+	// PurgeRefreshTokens deletes up to limit refresh tokens that expired before t.
+	PurgeRefreshTokens(ctx context.Context, t time.Time, limit int) (int64, error)
+	// PurgePasswordResetTokens deletes up to limit password reset tokens that expired before t or were used.
+	PurgePasswordResetTokens(ctx context.Context, t time.Time, limit int) (int64, error)
+	// PurgeMagicLinkTokens deletes up to limit magic link tokens that expired before t or were used.
+	PurgeMagicLinkTokens(ctx context.Context, t time.Time, limit int) (int64, error)
+}
+
+type userAdminRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+// NewUserAdminRepository creates a new UserAdminRepository instance.
+func NewUserAdminRepository(dbPool *pgxpool.Pool) UserAdminRepository {
+	return &userAdminRepository{dbPool: dbPool}
+}
+
+// NewUserAdminRepositoryAuto returns a UserAdminRepository for the given DB type.
+func NewUserAdminRepositoryAuto(dbType string, pgxPool *pgxpool.Pool, sqliteConn interface{}) UserAdminRepository {
+	switch dbType {
+	case "sqlite":
+		if conn, ok := sqliteConn.(*sqlite.Conn); ok {
+			return NewUserAdminRepositorySqlite(conn)
+		}
+		panic("sqliteConn is not *sqlite.Conn")
+	case "postgres":
+		fallthrough
+	default:
+		return NewUserAdminRepository(pgxPool)
+	}
+}
+
+const userAdminColumns = `id, email, password_hash, provider, provider_id, created_at, updated_at, deleted_at, disabled_at`
+
+func scanUserAdmin(row pgx.Row) (*entity.UserEntity, error) {
+	u := &entity.UserEntity{}
+	var provider *string
+	err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &provider, &u.ProviderID,
+		&u.CreatedAt, &u.UpdatedAt, &u.DeletedAt, &u.DisabledAt)
+	if err != nil {
+		return nil, err
+	}
+	if provider != nil {
+		u.Provider = *provider
+	}
+	return u, nil
+}
+
+func (r *userAdminRepository) findOne(ctx context.Context, where string, arg interface{}) (*entity.UserEntity, error) {
+	u, err := scanUserAdmin(r.dbPool.QueryRow(ctx, `SELECT `+userAdminColumns+` FROM users WHERE `+where, arg))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+// FindByID: 탈퇴 사용자 포함 ID 조회
+func (r *userAdminRepository) FindByID(ctx context.Context, id int64) (*entity.UserEntity, error) {
+	return r.findOne(ctx, `id = $1`, id)
+}
+
+// FindByEmail: 탈퇴 사용자 포함 이메일 조회
+func (r *userAdminRepository) FindByEmail(ctx context.Context, email string) (*entity.UserEntity, error) {
+	return r.findOne(ctx, `email = $1`, email)
+}
+
+// List: afterID 다음부터 ID 순 조회
+func (r *userAdminRepository) List(ctx context.Context, afterID int64, limit int) ([]*entity.UserEntity, error) {
+	rows, err := r.dbPool.Query(ctx, `SELECT `+userAdminColumns+` FROM users WHERE id > $1 ORDER BY id LIMIT $2`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var users []*entity.UserEntity
+	for rows.Next() {
+		u, err := scanUserAdmin(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// CreateTx: 모든 컬럼을 지정해 사용자 생성 (가져오기용)
+func (r *userAdminRepository) CreateTx(ctx context.Context, tx interface{}, u *entity.UserEntity) (int64, error) {
+	pgxTx, ok := tx.(pgx.Tx)
+	if !ok {
+		return 0, errors.New("tx is not pgx.Tx")
+	}
+	var id int64
+	err := pgxTx.QueryRow(ctx, `INSERT INTO users (email, password_hash, provider, provider_id, created_at, updated_at, deleted_at, disabled_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`,
+		u.Email, u.PasswordHash, u.Provider, u.ProviderID, u.CreatedAt, u.UpdatedAt, u.DeletedAt, u.DisabledAt,
+	).Scan(&id)
+	return id, err
+}
+
+// SetDisabled: 계정 정지 또는 해제 (t 가 nil)
+func (r *userAdminRepository) SetDisabled(ctx context.Context, id int64, t *time.Time) (bool, error) {
+	tag, err := r.dbPool.Exec(ctx, `UPDATE users SET disabled_at = $2, updated_at = NOW() WHERE id = $1`, id, t)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Restore: 탈퇴·정지 해제
+func (r *userAdminRepository) Restore(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.dbPool.Exec(ctx, `UPDATE users SET deleted_at = NULL, disabled_at = NULL, updated_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// FindRoles: 사용자 역할 조회 (이름순)
+func (r *userAdminRepository) FindRoles(ctx context.Context, userID int64) ([]string, error) {
+	rows, err := r.dbPool.Query(ctx, `SELECT role FROM user_roles WHERE user_id = $1 ORDER BY role`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var roles []string
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// AddRole: 역할 부여
+func (r *userAdminRepository) AddRole(ctx context.Context, userID int64, role string) (bool, error) {
+	tag, err := r.dbPool.Exec(ctx, `INSERT INTO user_roles (user_id, role, created_at) VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, role) DO NOTHING`, userID, role)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RemoveRole: 역할 회수
+func (r *userAdminRepository) RemoveRole(ctx context.Context, userID int64, role string) (bool, error) {
+	tag, err := r.dbPool.Exec(ctx, `DELETE FROM user_roles WHERE user_id = $1 AND role = $2`, userID, role)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// PurgeRefreshTokens: 만료된 리프레시 토큰을 limit 개까지 삭제
+func (r *userAdminRepository) PurgeRefreshTokens(ctx context.Context, t time.Time, limit int) (int64, error) {
+	return r.purge(ctx, `DELETE FROM refresh_tokens WHERE id IN (
+		SELECT id FROM refresh_tokens WHERE expired_at < $1 LIMIT $2)`, t, limit)
+}
+
+// PurgePasswordResetTokens: 만료·사용된 비밀번호 재설정 토큰을 limit 개까지 삭제
+func (r *userAdminRepository) PurgePasswordResetTokens(ctx context.Context, t time.Time, limit int) (int64, error) {
+	return r.purge(ctx, `DELETE FROM password_reset_tokens WHERE id IN (
+		SELECT id FROM password_reset_tokens WHERE expired_at < $1 OR used LIMIT $2)`, t, limit)
+}
+
+// PurgeMagicLinkTokens: 만료·사용된 매직 링크 토큰을 limit 개까지 삭제
+func (r *userAdminRepository) PurgeMagicLinkTokens(ctx context.Context, t time.Time, limit int) (int64, error) {
+	return r.purge(ctx, `DELETE FROM magic_link_tokens WHERE id IN (
+		SELECT id FROM magic_link_tokens WHERE expired_at < $1 OR used LIMIT $2)`, t, limit)
+}
+
+func (r *userAdminRepository) purge(ctx context.Context, query string, t time.Time, limit int) (int64, error) {
+	tag, err := r.dbPool.Exec(ctx, query, t, limit)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
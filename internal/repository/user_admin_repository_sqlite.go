@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"context"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+)
+
+type userAdminRepositorySqlite struct {
+	db *sqlite.Conn
+}
+
+// NewUserAdminRepositorySqlite returns a new sqlite-based UserAdminRepository.
+func NewUserAdminRepositorySqlite(conn *sqlite.Conn) UserAdminRepository {
+	return &userAdminRepositorySqlite{db: conn}
+}
+
+func (r *userAdminRepositorySqlite) query(q string, bind func(*sqlite.Stmt)) ([]*entity.UserEntity, error) {
+	stmt, err := r.db.Prepare("SELECT " + userAdminColumns + " FROM users WHERE " + q)
+	if err != nil {
+		return nil, err
+	}
+	bind(stmt)
+	var users []*entity.UserEntity
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			_ = stmt.Finalize()
+			return nil, err
+		}
+		if !hasRow {
+			break
+		}
+		u := &entity.UserEntity{
+			ID:           stmt.ColumnInt64(0),
+			Email:        stmt.ColumnText(1),
+			PasswordHash: stmt.ColumnText(2),
+			Provider:     stmt.ColumnText(3),
+			DeletedAt:    sqliteColumnTime(stmt, 7),
+			DisabledAt:   sqliteColumnTime(stmt, 8),
+		}
+		if providerID := stmt.ColumnText(4); providerID != "" {
+			u.ProviderID = &providerID
+		}
+		if t := sqliteColumnTime(stmt, 5); t != nil {
+			u.CreatedAt = *t
+		}
+		if t := sqliteColumnTime(stmt, 6); t != nil {
+			u.UpdatedAt = *t
+		}
+		users = append(users, u)
+	}
+	if err := stmt.Finalize(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *userAdminRepositorySqlite) findOne(q string, bind func(*sqlite.Stmt)) (*entity.UserEntity, error) {
+	users, err := r.query(q, bind)
+	if err != nil || len(users) == 0 {
+		return nil, err
+	}
+	return users[0], nil
+}
+
+// FindByID returns a user by ID, deleted or not.
+func (r *userAdminRepositorySqlite) FindByID(_ context.Context, id int64) (*entity.UserEntity, error) {
+	return r.findOne("id = ?", func(stmt *sqlite.Stmt) {
+		stmt.BindInt64(1, id)
+	})
+}
+
+// FindByEmail returns a user by email, deleted or not.
+func (r *userAdminRepositorySqlite) FindByEmail(_ context.Context, email string) (*entity.UserEntity, error) {
+	return r.findOne("email = ?", func(stmt *sqlite.Stmt) {
+		stmt.BindText(1, email)
+	})
+}
+
+// List returns users after afterID in ID order.
+func (r *userAdminRepositorySqlite) List(_ context.Context, afterID int64, limit int) ([]*entity.UserEntity, error) {
+	return r.query("id > ? ORDER BY id LIMIT ?", func(stmt *sqlite.Stmt) {
+		stmt.BindInt64(1, afterID)
+		stmt.BindInt64(2, int64(limit))
+	})
+}
+
+// CreateTx creates a user with all of its fields (no real tx used).
+func (r *userAdminRepositorySqlite) CreateTx(_ context.Context, _ interface{}, u *entity.UserEntity) (int64, error) {
+	stmt, err := r.db.Prepare("INSERT INTO users (email, password_hash, provider, provider_id, created_at, updated_at, deleted_at, disabled_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return 0, err
+	}
+	stmt.BindText(1, u.Email)
+	stmt.BindText(2, u.PasswordHash)
+	stmt.BindText(3, u.Provider)
+	if u.ProviderID != nil {
+		stmt.BindText(4, *u.ProviderID)
+	} else {
+		stmt.BindNull(4)
+	}
+	sqliteBindTime(stmt, 5, &u.CreatedAt)
+	sqliteBindTime(stmt, 6, &u.UpdatedAt)
+	sqliteBindTime(stmt, 7, u.DeletedAt)
+	sqliteBindTime(stmt, 8, u.DisabledAt)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return 0, err
+	}
+	if err2 != nil {
+		return 0, err2
+	}
+	return r.db.LastInsertRowID(), nil
+}
+
+// exec runs a statement and returns the number of changed rows.
+func (r *userAdminRepositorySqlite) exec(q string, bind func(*sqlite.Stmt)) (int64, error) {
+	stmt, err := r.db.Prepare(q)
+	if err != nil {
+		return 0, err
+	}
+	bind(stmt)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return 0, err
+	}
+	if err2 != nil {
+		return 0, err2
+	}
+	return int64(r.db.Changes()), nil
+}
+
+// SetDisabled disables the user at t, or enables it if t is nil.
+func (r *userAdminRepositorySqlite) SetDisabled(_ context.Context, id int64, t *time.Time) (bool, error) {
+	n, err := r.exec("UPDATE users SET disabled_at = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", func(stmt *sqlite.Stmt) {
+		sqliteBindTime(stmt, 1, t)
+		stmt.BindInt64(2, id)
+	})
+	return n > 0, err
+}
+
+// Restore undeletes and enables the user.
+func (r *userAdminRepositorySqlite) Restore(_ context.Context, id int64) (bool, error) {
+	n, err := r.exec("UPDATE users SET deleted_at = NULL, disabled_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = ?", func(stmt *sqlite.Stmt) {
+		stmt.BindInt64(1, id)
+	})
+	return n > 0, err
+}
+
+// FindRoles returns the user's roles in name order.
+func (r *userAdminRepositorySqlite) FindRoles(_ context.Context, userID int64) ([]string, error) {
+	stmt, err := r.db.Prepare("SELECT role FROM user_roles WHERE user_id = ? ORDER BY role")
+	if err != nil {
+		return nil, err
+	}
+	stmt.BindInt64(1, userID)
+	var roles []string
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			_ = stmt.Finalize()
+			return nil, err
+		}
+		if !hasRow {
+			break
+		}
+		roles = append(roles, stmt.ColumnText(0))
+	}
+	if err := stmt.Finalize(); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// AddRole grants the role unless the user already has it.
+func (r *userAdminRepositorySqlite) AddRole(_ context.Context, userID int64, role string) (bool, error) {
+	n, err := r.exec("INSERT OR IGNORE INTO user_roles (user_id, role, created_at) VALUES (?, ?, CURRENT_TIMESTAMP)", func(stmt *sqlite.Stmt) {
+		stmt.BindInt64(1, userID)
+		stmt.BindText(2, role)
+	})
+	return n > 0, err
+}
+
+// RemoveRole revokes the role.
+func (r *userAdminRepositorySqlite) RemoveRole(_ context.Context, userID int64, role string) (bool, error) {
+	n, err := r.exec("DELETE FROM user_roles WHERE user_id = ? AND role = ?", func(stmt *sqlite.Stmt) {
+		stmt.BindInt64(1, userID)
+		stmt.BindText(2, role)
+	})
+	return n > 0, err
+}
+
+// PurgeRefreshTokens deletes up to limit refresh tokens that expired before t.
+func (r *userAdminRepositorySqlite) PurgeRefreshTokens(_ context.Context, t time.Time, limit int) (int64, error) {
+	return r.purge("DELETE FROM refresh_tokens WHERE id IN (SELECT id FROM refresh_tokens WHERE expired_at < ? LIMIT ?)", t, limit)
+}
+
+// PurgePasswordResetTokens deletes up to limit expired or used password reset tokens.
+func (r *userAdminRepositorySqlite) PurgePasswordResetTokens(_ context.Context, t time.Time, limit int) (int64, error) {
+	return r.purge("DELETE FROM password_reset_tokens WHERE id IN (SELECT id FROM password_reset_tokens WHERE expired_at < ? OR used LIMIT ?)", t, limit)
+}
+
+// PurgeMagicLinkTokens deletes up to limit expired or used magic link tokens.
+func (r *userAdminRepositorySqlite) PurgeMagicLinkTokens(_ context.Context, t time.Time, limit int) (int64, error) {
+	return r.purge("DELETE FROM magic_link_tokens WHERE id IN (SELECT id FROM magic_link_tokens WHERE expired_at < ? OR used LIMIT ?)", t, limit)
+}
+
+func (r *userAdminRepositorySqlite) purge(q string, t time.Time, limit int) (int64, error) {
+	return r.exec(q, func(stmt *sqlite.Stmt) {
+		sqliteBindTime(stmt, 1, &t)
+		stmt.BindInt64(2, int64(limit))
+	})
+}
@@ -3,11 +3,13 @@ package repository
 
 import (
 	"auth/internal/entity"
+	"auth/pkg/utils"
 	"context"
 	"errors"
 	"log/slog"
 	"time"
 
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"zombiezen.com/go/sqlite"
@@ -20,6 +22,7 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*entity.UserEntity, error)
 	FindByEmailTx(ctx context.Context, tx interface{}, email string) (*entity.UserEntity, error)
 	UpdatePassword(ctx context.Context, id int64, passwordHash string) error
+	UpdatePasswordTx(ctx context.Context, tx interface{}, id int64, passwordHash string) error
 	Delete(ctx context.Context, id int64) error
 	InsertRefreshToken(ctx context.Context, rt *entity.RefreshTokenEntity) error
 	DeleteByUserIDAndDevice(ctx context.Context, userID int64, deviceInfo string) error
@@ -28,8 +31,19 @@ type UserRepository interface {
 	DeleteRefreshToken(ctx context.Context, userID int64, token string) error
 	DeleteAllRefreshTokens(ctx context.Context, userID int64) error
 	SavePasswordResetToken(ctx context.Context, userID int64, token string, expiredAt time.Time) error
+	SavePasswordResetTokenTx(ctx context.Context, tx interface{}, userID int64, token string, expiredAt time.Time) error
 	FindByPasswordResetToken(ctx context.Context, token string) (*entity.PasswordResetTokenEntity, error)
 	ExpirePasswordResetToken(ctx context.Context, token string) error
+	InsertPasswordHistory(ctx context.Context, userID int64, passwordHash string, keep int) error
+	InsertPasswordHistoryTx(ctx context.Context, tx interface{}, userID int64, passwordHash string, keep int) error
+	FindPasswordHistory(ctx context.Context, userID int64, limit int) ([]*entity.PasswordHistoryEntity, error)
+	SaveMagicLinkToken(ctx context.Context, t *entity.MagicLinkTokenEntity) error
+	FindByMagicLinkToken(ctx context.Context, tokenHash string) (*entity.MagicLinkTokenEntity, error)
+	// ConsumeMagicLinkToken marks the token used if it is unused and unexpired, reporting whether it was.
+	ConsumeMagicLinkToken(ctx context.Context, tokenHash string) (bool, error)
+	// RecordDevice records a sign-in from the device, returning true if the user never used it
+	// before but has used other devices, so the first device of an account is not reported.
+	RecordDevice(ctx context.Context, userID int64, deviceInfo string) (bool, error)
 }
 
 // NewUserRepository creates a new UserRepository instance.
@@ -88,6 +102,38 @@ func (r *userRepository) createTable(ctx context.Context) error {
 		expired_at TIMESTAMPTZ,
 		used BOOLEAN DEFAULT false,
 		UNIQUE (user_id)
+	);
+	CREATE TABLE IF NOT EXISTS password_history (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		password_hash VARCHAR(255) NOT NULL,
+		created_at TIMESTAMPTZ DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_password_history_user_id ON password_history (user_id, created_at DESC);
+	CREATE TABLE IF NOT EXISTS magic_link_tokens (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		token_hash VARCHAR(64) NOT NULL UNIQUE,
+		device_info VARCHAR(255),
+		expired_at TIMESTAMPTZ,
+		used BOOLEAN DEFAULT false,
+		created_at TIMESTAMPTZ DEFAULT NOW()
+	);
+	CREATE TABLE IF NOT EXISTS user_devices (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		device_hash VARCHAR(64) NOT NULL,
+		device_info VARCHAR(255),
+		first_seen_at TIMESTAMPTZ DEFAULT NOW(),
+		last_seen_at TIMESTAMPTZ DEFAULT NOW(),
+		UNIQUE (user_id, device_hash)
+	);
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
+	CREATE TABLE IF NOT EXISTS user_roles (
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		role VARCHAR(64) NOT NULL,
+		created_at TIMESTAMPTZ DEFAULT NOW(),
+		PRIMARY KEY (user_id, role)
 	);`
 	_, err := r.dbPool.Exec(ctx, query)
 	return err
@@ -127,12 +173,12 @@ func (r *userRepository) CreateTx(ctx context.Context, tx interface{}, user *ent
 
 // FindById: ID로 사용자 조회
 func (r *userRepository) FindByID(ctx context.Context, id int64) (*entity.UserEntity, error) {
-	query := `SELECT id, email, password_hash, created_at, updated_at, deleted_at
+	query := `SELECT id, email, password_hash, created_at, updated_at, deleted_at, disabled_at
         FROM users
         WHERE id = $1 AND deleted_at IS NULL`
 	u := &entity.UserEntity{}
 	err := r.dbPool.QueryRow(ctx, query, id).Scan(
-		&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt,
+		&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt, &u.DisabledAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -145,13 +191,13 @@ func (r *userRepository) FindByID(ctx context.Context, id int64) (*entity.UserEn
 
 // FindByEmail: 이메일로 사용자 조회
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity.UserEntity, error) {
-	query := `SELECT id, email, password_hash, provider, provider_id, created_at, updated_at, deleted_at
+	query := `SELECT id, email, password_hash, provider, provider_id, created_at, updated_at, deleted_at, disabled_at
         FROM users
         WHERE email = $1 AND deleted_at IS NULL`
 	u := &entity.UserEntity{}
 	err := r.dbPool.QueryRow(ctx, query, email).Scan(
 		&u.ID, &u.Email, &u.PasswordHash, &u.Provider, &u.ProviderID,
-		&u.CreatedAt, &u.UpdatedAt, &u.DeletedAt,
+		&u.CreatedAt, &u.UpdatedAt, &u.DeletedAt, &u.DisabledAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -187,10 +233,23 @@ func (r *userRepository) FindByEmailTx(ctx context.Context, tx interface{}, emai
 
 // UpdatePassword: 비밀번호(hash, 해시) 변경
 func (r *userRepository) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	return updatePassword(ctx, r.dbPool, id, passwordHash)
+}
+
+// UpdatePasswordTx: 트랜잭션 내에서 비밀번호 변경
+func (r *userRepository) UpdatePasswordTx(ctx context.Context, tx interface{}, id int64, passwordHash string) error {
+	pgxTx, ok := tx.(pgx.Tx)
+	if !ok {
+		return errors.New("tx is not pgx.Tx")
+	}
+	return updatePassword(ctx, pgxTx, id, passwordHash)
+}
+
+func updatePassword(ctx context.Context, db pgExecer, id int64, passwordHash string) error {
 	query := `UPDATE users
         SET password_hash = $1, updated_at = NOW()
         WHERE id = $2`
-	_, err := r.dbPool.Exec(ctx, query, passwordHash, id)
+	_, err := db.Exec(ctx, query, passwordHash, id)
 	return err
 }
 
@@ -270,6 +329,19 @@ func (r *userRepository) SavePasswordResetToken(ctx context.Context, userID int6
 	return err
 }
 
+// SavePasswordResetTokenTx: 트랜잭션 내에서 비밀번호 재설정 토큰 저장
+func (r *userRepository) SavePasswordResetTokenTx(ctx context.Context, tx interface{}, userID int64, token string, expiredAt time.Time) error {
+	pgxTx, ok := tx.(pgx.Tx)
+	if !ok {
+		return errors.New("tx is not pgx.Tx")
+	}
+	_, err := pgxTx.Exec(ctx, `INSERT INTO password_reset_tokens (user_id, token, expired_at, used)
+         VALUES ($1, $2, $3, false)
+         ON CONFLICT (user_id) DO UPDATE SET token = $2, expired_at = $3, used = false`,
+		userID, token, expiredAt)
+	return err
+}
+
 func (r *userRepository) FindByPasswordResetToken(ctx context.Context, token string) (*entity.PasswordResetTokenEntity, error) {
 	row := r.dbPool.QueryRow(ctx, `SELECT user_id, token, expired_at, used FROM password_reset_tokens WHERE token=$1 AND used=false`, token)
 	var info entity.PasswordResetTokenEntity
@@ -284,3 +356,104 @@ func (r *userRepository) ExpirePasswordResetToken(ctx context.Context, token str
 	_, err := r.dbPool.Exec(ctx, `UPDATE password_reset_tokens SET used=true WHERE token=$1`, token)
 	return err
 }
+
+// InsertPasswordHistory: 비밀번호 이력 추가 후 최근 keep 개만 유지
+func (r *userRepository) InsertPasswordHistory(ctx context.Context, userID int64, passwordHash string, keep int) error {
+	return insertPasswordHistory(ctx, r.dbPool, userID, passwordHash, keep)
+}
+
+// InsertPasswordHistoryTx: 트랜잭션 내에서 비밀번호 이력 추가
+func (r *userRepository) InsertPasswordHistoryTx(ctx context.Context, tx interface{}, userID int64, passwordHash string, keep int) error {
+	pgxTx, ok := tx.(pgx.Tx)
+	if !ok {
+		return errors.New("tx is not pgx.Tx")
+	}
+	return insertPasswordHistory(ctx, pgxTx, userID, passwordHash, keep)
+}
+
+// pgExecer is satisfied by both *pgxpool.Pool and pgx.Tx.
+type pgExecer interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+func insertPasswordHistory(ctx context.Context, db pgExecer, userID int64, passwordHash string, keep int) error {
+	_, err := db.Exec(ctx, `INSERT INTO password_history (user_id, password_hash, created_at) VALUES ($1, $2, NOW())`,
+		userID, passwordHash)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(ctx, `DELETE FROM password_history
+		WHERE user_id = $1 AND id NOT IN (
+			SELECT id FROM password_history WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2
+		)`, userID, keep)
+	return err
+}
+
+// FindPasswordHistory: 최근 비밀번호 이력 조회 (최신순)
+func (r *userRepository) FindPasswordHistory(ctx context.Context, userID int64, limit int) ([]*entity.PasswordHistoryEntity, error) {
+	rows, err := r.dbPool.Query(ctx, `SELECT id, user_id, password_hash, created_at
+		FROM password_history
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var history []*entity.PasswordHistoryEntity
+	for rows.Next() {
+		h := &entity.PasswordHistoryEntity{}
+		if err := rows.Scan(&h.ID, &h.UserID, &h.PasswordHash, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, h)
+	}
+	return history, rows.Err()
+}
+
+// SaveMagicLinkToken: 매직 링크 토큰(해시) 저장
+func (r *userRepository) SaveMagicLinkToken(ctx context.Context, t *entity.MagicLinkTokenEntity) error {
+	return r.dbPool.QueryRow(ctx, `INSERT INTO magic_link_tokens (user_id, token_hash, device_info, expired_at, used, created_at)
+		VALUES ($1, $2, $3, $4, false, NOW())
+		RETURNING id`,
+		t.UserID, t.TokenHash, t.DeviceInfo, t.ExpiredAt,
+	).Scan(&t.ID)
+}
+
+// FindByMagicLinkToken: 토큰 해시로 매직 링크 조회
+func (r *userRepository) FindByMagicLinkToken(ctx context.Context, tokenHash string) (*entity.MagicLinkTokenEntity, error) {
+	t := &entity.MagicLinkTokenEntity{}
+	err := r.dbPool.QueryRow(ctx, `SELECT id, user_id, token_hash, device_info, expired_at, used, created_at
+		FROM magic_link_tokens WHERE token_hash = $1`, tokenHash,
+	).Scan(&t.ID, &t.UserID, &t.TokenHash, &t.DeviceInfo, &t.ExpiredAt, &t.Used, &t.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// ConsumeMagicLinkToken: 사용하지 않은 유효한 매직 링크만 사용 처리, 처리했으면 true
+func (r *userRepository) ConsumeMagicLinkToken(ctx context.Context, tokenHash string) (bool, error) {
+	tag, err := r.dbPool.Exec(ctx, `UPDATE magic_link_tokens SET used = true
+		WHERE token_hash = $1 AND used = false AND expired_at > NOW()`, tokenHash)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// RecordDevice: 로그인 기기 기록, 다른 기기를 쓰던 사용자가 처음 보는 기기면 true
+func (r *userRepository) RecordDevice(ctx context.Context, userID int64, deviceInfo string) (bool, error) {
+	var newDevice bool
+	// xmax = 0 이면 UPDATE 가 아닌 INSERT 된 행. 기록과 다른 기기 확인을 한 문장으로 처리한다
+	err := r.dbPool.QueryRow(ctx, `INSERT INTO user_devices (user_id, device_hash, device_info, first_seen_at, last_seen_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (user_id, device_hash) DO UPDATE SET last_seen_at = NOW()
+		RETURNING (xmax = 0) AND EXISTS (SELECT 1 FROM user_devices d WHERE d.user_id = $1 AND d.device_hash <> $2)`,
+		userID, utils.HashToken(deviceInfo), deviceInfo,
+	).Scan(&newDevice)
+	return newDevice, err
+}
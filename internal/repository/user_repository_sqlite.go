@@ -2,8 +2,9 @@ package repository
 
 import (
 	"auth/internal/entity"
+	"auth/pkg/utils"
 	"context"
-	"log"
+	"log/slog"
 	"time"
 
 	"zombiezen.com/go/sqlite"
@@ -17,7 +18,7 @@ type userRepositorySqlite struct {
 func NewUserRepositorySqlite(conn *sqlite.Conn) UserRepository {
 	repo := &userRepositorySqlite{db: conn}
 	if err := repo.createTable(context.Background()); err != nil {
-		log.Printf("[sqlite] Error creating tables: %v", err)
+		slog.Error("[sqlite] Error creating tables", "error", err)
 	}
 	return repo
 }
@@ -41,6 +42,8 @@ func (r *userRepositorySqlite) createTable(_ context.Context) error {
 			birth_date TEXT,
 			gender_code TEXT,
 			phone_number TEXT UNIQUE,
+			phone_verified_at DATETIME,
+			locale TEXT NOT NULL DEFAULT '',
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		);`,
@@ -60,6 +63,36 @@ func (r *userRepositorySqlite) createTable(_ context.Context) error {
 			used BOOLEAN DEFAULT 0,
 			UNIQUE (user_id)
 		);`,
+		`CREATE TABLE IF NOT EXISTS password_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			password_hash TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS magic_link_tokens (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			device_info TEXT,
+			expired_at DATETIME,
+			used BOOLEAN DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS user_devices (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			device_hash TEXT NOT NULL,
+			device_info TEXT,
+			first_seen_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			last_seen_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (user_id, device_hash)
+		);`,
+		`CREATE TABLE IF NOT EXISTS user_roles (
+			user_id INTEGER NOT NULL,
+			role TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_id, role)
+		);`,
 	}
 	for _, q := range stmts {
 		stmt, err := r.db.Prepare(q)
@@ -75,7 +108,7 @@ func (r *userRepositorySqlite) createTable(_ context.Context) error {
 			return err2
 		}
 	}
-	return nil
+	return sqliteAddColumn(r.db, "users", "disabled_at", "DATETIME")
 }
 
 // CreateTx creates a user in sqlite (no real tx used)
@@ -101,7 +134,7 @@ func (r *userRepositorySqlite) CreateTx(_ context.Context, _ interface{}, user *
 
 // FindByID returns a user by ID.
 func (r *userRepositorySqlite) FindByID(_ context.Context, id int64) (*entity.UserEntity, error) {
-	stmt, err := r.db.Prepare("SELECT id, email, password_hash, provider, provider_id, created_at, updated_at, deleted_at FROM users WHERE id = ? AND deleted_at IS NULL")
+	stmt, err := r.db.Prepare("SELECT id, email, password_hash, provider, provider_id, created_at, updated_at, deleted_at, disabled_at FROM users WHERE id = ? AND deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -124,13 +157,14 @@ func (r *userRepositorySqlite) FindByID(_ context.Context, id int64) (*entity.Us
 	if providerID != "" {
 		u.ProviderID = &providerID
 	}
+	u.DisabledAt = sqliteColumnTime(stmt, 8)
 	_ = stmt.Finalize()
 	return &u, nil
 }
 
 // FindByEmail returns a user by email.
 func (r *userRepositorySqlite) FindByEmail(_ context.Context, email string) (*entity.UserEntity, error) {
-	stmt, err := r.db.Prepare("SELECT id, email, password_hash, provider, provider_id, created_at, updated_at, deleted_at FROM users WHERE email = ? AND deleted_at IS NULL")
+	stmt, err := r.db.Prepare("SELECT id, email, password_hash, provider, provider_id, created_at, updated_at, deleted_at, disabled_at FROM users WHERE email = ? AND deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
@@ -153,6 +187,7 @@ func (r *userRepositorySqlite) FindByEmail(_ context.Context, email string) (*en
 	if providerID != "" {
 		u.ProviderID = &providerID
 	}
+	u.DisabledAt = sqliteColumnTime(stmt, 8)
 	_ = stmt.Finalize()
 	return &u, nil
 }
@@ -178,6 +213,11 @@ func (r *userRepositorySqlite) UpdatePassword(_ context.Context, id int64, passw
 	return err2
 }
 
+// UpdatePasswordTx updates a user's password hash (no real tx used).
+func (r *userRepositorySqlite) UpdatePasswordTx(ctx context.Context, _ interface{}, id int64, passwordHash string) error {
+	return r.UpdatePassword(ctx, id, passwordHash)
+}
+
 // Delete soft-deletes a user.
 func (r *userRepositorySqlite) Delete(_ context.Context, id int64) error {
 	stmt, err := r.db.Prepare("UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?")
@@ -205,7 +245,7 @@ func (r *userRepositorySqlite) InsertRefreshToken(_ context.Context, rt *entity.
 	if rt.ExpiredAt.IsZero() {
 		stmt.BindNull(4)
 	} else {
-		stmt.BindText(4, rt.ExpiredAt.Format("2006-01-02 15:04:05"))
+		stmt.BindText(4, rt.ExpiredAt.UTC().Format("2006-01-02 15:04:05"))
 	}
 	_, err = stmt.Step()
 	err2 := stmt.Finalize()
@@ -262,7 +302,7 @@ func (r *userRepositorySqlite) FindRefreshToken(_ context.Context, token string)
 	}
 	err2 := stmt.Finalize()
 	if err2 != nil {
-		log.Printf("stmt.Finalize error: %v", err2)
+		slog.Error("stmt.Finalize error", "error", err2)
 	}
 	return &rt, nil
 }
@@ -297,7 +337,7 @@ func (r *userRepositorySqlite) FindByUserDeviceAndToken(_ context.Context, userI
 		}
 	}
 	if err2 := stmt.Finalize(); err2 != nil {
-		log.Printf("stmt.Finalize error: %v", err2)
+		slog.Error("stmt.Finalize error", "error", err2)
 	}
 	return &rt, nil
 }
@@ -338,7 +378,7 @@ func (r *userRepositorySqlite) SavePasswordResetToken(_ context.Context, userID
 	}
 	stmt.BindInt64(1, userID)
 	stmt.BindText(2, token)
-	stmt.BindText(3, expiredAt.Format("2006-01-02 15:04:05"))
+	stmt.BindText(3, expiredAt.UTC().Format("2006-01-02 15:04:05"))
 	_, err = stmt.Step()
 	err2 := stmt.Finalize()
 	if err != nil {
@@ -347,6 +387,11 @@ func (r *userRepositorySqlite) SavePasswordResetToken(_ context.Context, userID
 	return err2
 }
 
+// SavePasswordResetTokenTx saves a password reset token (no real tx used).
+func (r *userRepositorySqlite) SavePasswordResetTokenTx(ctx context.Context, _ interface{}, userID int64, token string, expiredAt time.Time) error {
+	return r.SavePasswordResetToken(ctx, userID, token, expiredAt)
+}
+
 // FindByPasswordResetToken finds a password reset token entity by token string (SQLite).
 func (r *userRepositorySqlite) FindByPasswordResetToken(_ context.Context, token string) (*entity.PasswordResetTokenEntity, error) {
 	stmt, err := r.db.Prepare("SELECT id, user_id, token, expired_at, used FROM password_reset_tokens WHERE token = ?")
@@ -376,7 +421,7 @@ func (r *userRepositorySqlite) FindByPasswordResetToken(_ context.Context, token
 	prt.ExpiredAt = t
 	prt.Used = stmt.ColumnInt64(4) != 0
 	if err2 := stmt.Finalize(); err2 != nil {
-		log.Printf("stmt.Finalize error: %v", err2)
+		slog.Error("stmt.Finalize error", "error", err2)
 	}
 	return &prt, nil
 }
@@ -394,3 +439,191 @@ func (r *userRepositorySqlite) ExpirePasswordResetToken(_ context.Context, token
 	}
 	return err2
 }
+
+// InsertPasswordHistory inserts a password hash into the history and keeps only the latest entries.
+func (r *userRepositorySqlite) InsertPasswordHistory(_ context.Context, userID int64, passwordHash string, keep int) error {
+	stmt, err := r.db.Prepare("INSERT INTO password_history (user_id, password_hash, created_at) VALUES (?, ?, CURRENT_TIMESTAMP)")
+	if err != nil {
+		return err
+	}
+	stmt.BindInt64(1, userID)
+	stmt.BindText(2, passwordHash)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return err
+	}
+	if err2 != nil {
+		return err2
+	}
+	stmt, err = r.db.Prepare("DELETE FROM password_history WHERE user_id = ? AND id NOT IN (SELECT id FROM password_history WHERE user_id = ? ORDER BY id DESC LIMIT ?)")
+	if err != nil {
+		return err
+	}
+	stmt.BindInt64(1, userID)
+	stmt.BindInt64(2, userID)
+	stmt.BindInt64(3, int64(keep))
+	_, err = stmt.Step()
+	err2 = stmt.Finalize()
+	if err != nil {
+		return err
+	}
+	return err2
+}
+
+// InsertPasswordHistoryTx inserts a password hash into the history (no real tx used).
+func (r *userRepositorySqlite) InsertPasswordHistoryTx(ctx context.Context, _ interface{}, userID int64, passwordHash string, keep int) error {
+	return r.InsertPasswordHistory(ctx, userID, passwordHash, keep)
+}
+
+// FindPasswordHistory returns the latest password hashes of a user, newest first.
+func (r *userRepositorySqlite) FindPasswordHistory(_ context.Context, userID int64, limit int) ([]*entity.PasswordHistoryEntity, error) {
+	stmt, err := r.db.Prepare("SELECT id, user_id, password_hash FROM password_history WHERE user_id = ? ORDER BY id DESC LIMIT ?")
+	if err != nil {
+		return nil, err
+	}
+	stmt.BindInt64(1, userID)
+	stmt.BindInt64(2, int64(limit))
+	var history []*entity.PasswordHistoryEntity
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			_ = stmt.Finalize()
+			return nil, err
+		}
+		if !hasRow {
+			break
+		}
+		history = append(history, &entity.PasswordHistoryEntity{
+			ID:           stmt.ColumnInt64(0),
+			UserID:       stmt.ColumnInt64(1),
+			PasswordHash: stmt.ColumnText(2),
+		})
+	}
+	if err := stmt.Finalize(); err != nil {
+		slog.Error("stmt.Finalize error", "error", err)
+	}
+	return history, nil
+}
+
+// SaveMagicLinkToken stores a magic link token hash.
+func (r *userRepositorySqlite) SaveMagicLinkToken(_ context.Context, t *entity.MagicLinkTokenEntity) error {
+	stmt, err := r.db.Prepare("INSERT INTO magic_link_tokens (user_id, token_hash, device_info, expired_at, used, created_at) VALUES (?, ?, ?, ?, 0, CURRENT_TIMESTAMP)")
+	if err != nil {
+		return err
+	}
+	stmt.BindInt64(1, t.UserID)
+	stmt.BindText(2, t.TokenHash)
+	stmt.BindText(3, t.DeviceInfo)
+	stmt.BindText(4, t.ExpiredAt.UTC().Format("2006-01-02 15:04:05"))
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return err
+	}
+	if err2 != nil {
+		return err2
+	}
+	t.ID = r.db.LastInsertRowID()
+	return nil
+}
+
+// FindByMagicLinkToken finds a magic link token by its hash.
+func (r *userRepositorySqlite) FindByMagicLinkToken(_ context.Context, tokenHash string) (*entity.MagicLinkTokenEntity, error) {
+	stmt, err := r.db.Prepare("SELECT id, user_id, token_hash, device_info, expired_at, used FROM magic_link_tokens WHERE token_hash = ?")
+	if err != nil {
+		return nil, err
+	}
+	stmt.BindText(1, tokenHash)
+	hasRow, err := stmt.Step()
+	if err != nil {
+		_ = stmt.Finalize()
+		return nil, err
+	}
+	if !hasRow {
+		_ = stmt.Finalize()
+		return nil, nil
+	}
+	var t entity.MagicLinkTokenEntity
+	t.ID = stmt.ColumnInt64(0)
+	t.UserID = stmt.ColumnInt64(1)
+	t.TokenHash = stmt.ColumnText(2)
+	t.DeviceInfo = stmt.ColumnText(3)
+	expiredAt, parseErr := time.Parse("2006-01-02 15:04:05", stmt.ColumnText(4))
+	if parseErr != nil {
+		_ = stmt.Finalize()
+		return nil, parseErr
+	}
+	t.ExpiredAt = expiredAt
+	t.Used = stmt.ColumnInt64(5) != 0
+	if err2 := stmt.Finalize(); err2 != nil {
+		slog.Error("stmt.Finalize error", "error", err2)
+	}
+	return &t, nil
+}
+
+// ConsumeMagicLinkToken marks a magic link token used if it is unused and unexpired.
+func (r *userRepositorySqlite) ConsumeMagicLinkToken(_ context.Context, tokenHash string) (bool, error) {
+	stmt, err := r.db.Prepare("UPDATE magic_link_tokens SET used = 1 WHERE token_hash = ? AND used = 0 AND expired_at > CURRENT_TIMESTAMP")
+	if err != nil {
+		return false, err
+	}
+	stmt.BindText(1, tokenHash)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return false, err
+	}
+	if err2 != nil {
+		return false, err2
+	}
+	return r.db.Changes() == 1, nil
+}
+
+// RecordDevice records a sign-in from the device, returning true if it is new for a user who
+// has used other devices.
+func (r *userRepositorySqlite) RecordDevice(_ context.Context, userID int64, deviceInfo string) (bool, error) {
+	stmt, err := r.db.Prepare("UPDATE user_devices SET last_seen_at = CURRENT_TIMESTAMP WHERE user_id = ? AND device_hash = ?")
+	if err != nil {
+		return false, err
+	}
+	hash := utils.HashToken(deviceInfo)
+	stmt.BindInt64(1, userID)
+	stmt.BindText(2, hash)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return false, err
+	}
+	if err2 != nil {
+		return false, err2
+	}
+	if r.db.Changes() > 0 {
+		return false, nil
+	}
+	stmt, err = r.db.Prepare(`INSERT INTO user_devices (user_id, device_hash, device_info, first_seen_at, last_seen_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING EXISTS (SELECT 1 FROM user_devices d WHERE d.user_id = ? AND d.device_hash <> ?)`)
+	if err != nil {
+		return false, err
+	}
+	stmt.BindInt64(1, userID)
+	stmt.BindText(2, hash)
+	stmt.BindText(3, deviceInfo)
+	stmt.BindInt64(4, userID)
+	stmt.BindText(5, hash)
+	hasRow, err := stmt.Step()
+	if err != nil {
+		_ = stmt.Finalize()
+		return false, err
+	}
+	newDevice := hasRow && stmt.ColumnBool(0)
+	// RETURNING 행을 읽은 뒤 끝까지 실행해야 INSERT 가 완료된다
+	if hasRow {
+		if _, err := stmt.Step(); err != nil {
+			_ = stmt.Finalize()
+			return false, err
+		}
+	}
+	return newDevice, stmt.Finalize()
+}
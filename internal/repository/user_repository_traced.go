@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"auth/internal/tracing"
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tracedUserRepository records a span for every UserRepository call.
+type tracedUserRepository struct {
+	next   UserRepository
+	system attribute.KeyValue
+}
+
+// NewTracedUserRepository wraps repo so every call is recorded as a span named "UserRepository.<Method>".
+func NewTracedUserRepository(repo UserRepository, dbType string) UserRepository {
+	return &tracedUserRepository{next: repo, system: dbSystem(dbType)}
+}
+
+func (r *tracedUserRepository) CreateTx(ctx context.Context, tx interface{}, user *entity.UserEntity) (int64, error) {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "CreateTx")
+	result, err := r.next.CreateTx(ctx, tx, user)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) FindByID(ctx context.Context, id int64) (*entity.UserEntity, error) {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "FindByID")
+	result, err := r.next.FindByID(ctx, id)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) FindByEmail(ctx context.Context, email string) (*entity.UserEntity, error) {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "FindByEmail")
+	result, err := r.next.FindByEmail(ctx, email)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) FindByEmailTx(ctx context.Context, tx interface{}, email string) (*entity.UserEntity, error) {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "FindByEmailTx")
+	result, err := r.next.FindByEmailTx(ctx, tx, email)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "UpdatePassword")
+	err := r.next.UpdatePassword(ctx, id, passwordHash)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) UpdatePasswordTx(ctx context.Context, tx interface{}, id int64, passwordHash string) error {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "UpdatePasswordTx")
+	err := r.next.UpdatePasswordTx(ctx, tx, id, passwordHash)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) Delete(ctx context.Context, id int64) error {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "Delete")
+	err := r.next.Delete(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) InsertRefreshToken(ctx context.Context, rt *entity.RefreshTokenEntity) error {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "InsertRefreshToken")
+	err := r.next.InsertRefreshToken(ctx, rt)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) DeleteByUserIDAndDevice(ctx context.Context, userID int64, deviceInfo string) error {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "DeleteByUserIDAndDevice")
+	err := r.next.DeleteByUserIDAndDevice(ctx, userID, deviceInfo)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) FindRefreshToken(ctx context.Context, token string) (*entity.RefreshTokenEntity, error) {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "FindRefreshToken")
+	result, err := r.next.FindRefreshToken(ctx, token)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) FindByUserDeviceAndToken(ctx context.Context, userID int64, deviceInfo, token string) (*entity.RefreshTokenEntity, error) {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "FindByUserDeviceAndToken")
+	result, err := r.next.FindByUserDeviceAndToken(ctx, userID, deviceInfo, token)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) DeleteRefreshToken(ctx context.Context, userID int64, token string) error {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "DeleteRefreshToken")
+	err := r.next.DeleteRefreshToken(ctx, userID, token)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) DeleteAllRefreshTokens(ctx context.Context, userID int64) error {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "DeleteAllRefreshTokens")
+	err := r.next.DeleteAllRefreshTokens(ctx, userID)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) SavePasswordResetToken(ctx context.Context, userID int64, token string, expiredAt time.Time) error {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "SavePasswordResetToken")
+	err := r.next.SavePasswordResetToken(ctx, userID, token, expiredAt)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) SavePasswordResetTokenTx(ctx context.Context, tx interface{}, userID int64, token string, expiredAt time.Time) error {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "SavePasswordResetTokenTx")
+	err := r.next.SavePasswordResetTokenTx(ctx, tx, userID, token, expiredAt)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) FindByPasswordResetToken(ctx context.Context, token string) (*entity.PasswordResetTokenEntity, error) {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "FindByPasswordResetToken")
+	result, err := r.next.FindByPasswordResetToken(ctx, token)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) ExpirePasswordResetToken(ctx context.Context, token string) error {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "ExpirePasswordResetToken")
+	err := r.next.ExpirePasswordResetToken(ctx, token)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) InsertPasswordHistory(ctx context.Context, userID int64, passwordHash string, keep int) error {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "InsertPasswordHistory")
+	err := r.next.InsertPasswordHistory(ctx, userID, passwordHash, keep)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) InsertPasswordHistoryTx(ctx context.Context, tx interface{}, userID int64, passwordHash string, keep int) error {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "InsertPasswordHistoryTx")
+	err := r.next.InsertPasswordHistoryTx(ctx, tx, userID, passwordHash, keep)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) FindPasswordHistory(ctx context.Context, userID int64, limit int) ([]*entity.PasswordHistoryEntity, error) {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "FindPasswordHistory")
+	result, err := r.next.FindPasswordHistory(ctx, userID, limit)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) SaveMagicLinkToken(ctx context.Context, t *entity.MagicLinkTokenEntity) error {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "SaveMagicLinkToken")
+	err := r.next.SaveMagicLinkToken(ctx, t)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) FindByMagicLinkToken(ctx context.Context, tokenHash string) (*entity.MagicLinkTokenEntity, error) {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "FindByMagicLinkToken")
+	result, err := r.next.FindByMagicLinkToken(ctx, tokenHash)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) ConsumeMagicLinkToken(ctx context.Context, tokenHash string) (bool, error) {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "ConsumeMagicLinkToken")
+	result, err := r.next.ConsumeMagicLinkToken(ctx, tokenHash)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) RecordDevice(ctx context.Context, userID int64, deviceInfo string) (bool, error) {
+	ctx, span := startSpan(ctx, r.system, "UserRepository", "RecordDevice")
+	result, err := r.next.RecordDevice(ctx, userID, deviceInfo)
+	tracing.End(span, err)
+	return result, err
+}
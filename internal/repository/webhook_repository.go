@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"zombiezen.com/go/sqlite"
+)
+
+// WebhookRepository defines webhook endpoint and delivery database operations.
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, e *entity.WebhookEndpointEntity) error
+	// UpdateEndpoint saves the URL, secret, events, description and active flag, returning
+	// false if there is no endpoint with the ID.
+	UpdateEndpoint(ctx context.Context, e *entity.WebhookEndpointEntity) (bool, error)
+	// DeleteEndpoint deletes the endpoint and its deliveries, returning false if there is none with the id.
+	DeleteEndpoint(ctx context.Context, id int64) (bool, error)
+	// FindEndpoint returns the endpoint, or nil if there is none with the id.
+	FindEndpoint(ctx context.Context, id int64) (*entity.WebhookEndpointEntity, error)
+	// ListEndpoints returns all endpoints, oldest first.
+	ListEndpoints(ctx context.Context) ([]*entity.WebhookEndpointEntity, error)
+
+	// Enqueue stores a pending delivery due now.
+	Enqueue(ctx context.Context, d *entity.WebhookDeliveryEntity) error
+	// ClaimDue returns up to limit pending deliveries due at now and leases them until now+lease,
+	// so concurrent dispatchers do not pick up the same delivery.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDeliveryEntity, error)
+	MarkDelivered(ctx context.Context, id int64, responseStatus int) error
+	// MarkFailed records a failed attempt; status is pending (retry at nextAttemptAt) or dead.
+	MarkFailed(ctx context.Context, id int64, status string, nextAttemptAt time.Time, responseStatus int, lastError string) error
+	// ListDeliveries returns the endpoint's deliveries, newest first. An empty status matches all.
+	ListDeliveries(ctx context.Context, endpointID int64, status string, limit int) ([]*entity.WebhookDeliveryEntity, error)
+	// FindDelivery returns the delivery, or nil if there is none with the id.
+	FindDelivery(ctx context.Context, id int64) (*entity.WebhookDeliveryEntity, error)
+	CreateTable(ctx context.Context) error
+}
+
+type webhookRepository struct {
+	dbPool *pgxpool.Pool
+}
+
+// NewWebhookRepository creates a new WebhookRepository instance.
+func NewWebhookRepository(dbPool *pgxpool.Pool) WebhookRepository {
+	r := &webhookRepository{dbPool: dbPool}
+	if err := r.CreateTable(context.Background()); err != nil {
+		slog.Warn("Error creating webhook tables", "error", err)
+	}
+	return r
+}
+
+// NewWebhookRepositoryAuto returns a WebhookRepository for the given DB type.
+func NewWebhookRepositoryAuto(dbType string, pgxPool *pgxpool.Pool, sqliteConn interface{}) WebhookRepository {
+	switch dbType {
+	case "sqlite":
+		if conn, ok := sqliteConn.(*sqlite.Conn); ok {
+			return NewWebhookRepositorySqlite(conn)
+		}
+		panic("sqliteConn is not *sqlite.Conn")
+	case "postgres":
+		fallthrough
+	default:
+		return NewWebhookRepository(pgxPool)
+	}
+}
+
+// CreateTable creates the webhook_endpoints and webhook_deliveries tables if they do not exist
+func (r *webhookRepository) CreateTable(ctx context.Context) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS webhook_endpoints (
+		id          BIGSERIAL PRIMARY KEY,
+		url         TEXT NOT NULL,
+		secret      TEXT NOT NULL,
+		events      TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		active      BOOLEAN NOT NULL DEFAULT TRUE,
+		created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id              BIGSERIAL PRIMARY KEY,
+		endpoint_id     BIGINT NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+		event_id        VARCHAR(64) NOT NULL,
+		event           VARCHAR(64) NOT NULL,
+		payload         TEXT NOT NULL,
+		status          VARCHAR(16) NOT NULL DEFAULT 'pending',
+		attempts        INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		response_status INTEGER NOT NULL DEFAULT 0,
+		last_error      TEXT NOT NULL DEFAULT '',
+		created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		delivered_at    TIMESTAMPTZ
+	);
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint ON webhook_deliveries (endpoint_id, id);
+	`
+	_, err := r.dbPool.Exec(ctx, query)
+	return err
+}
+
+const webhookEndpointColumns = `id, url, secret, events, description, active, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, endpoint_id, event_id, event, payload, status, attempts, next_attempt_at, response_status, last_error, created_at, delivered_at`
+
+func scanWebhookEndpoint(row pgx.Row) (*entity.WebhookEndpointEntity, error) {
+	e := &entity.WebhookEndpointEntity{}
+	err := row.Scan(&e.ID, &e.URL, &e.Secret, &e.Events, &e.Description, &e.Active, &e.CreatedAt, &e.UpdatedAt)
+	return e, err
+}
+
+func scanWebhookDelivery(row pgx.Row) (*entity.WebhookDeliveryEntity, error) {
+	d := &entity.WebhookDeliveryEntity{}
+	err := row.Scan(&d.ID, &d.EndpointID, &d.EventID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt)
+	return d, err
+}
+
+func collectWebhookDeliveries(rows pgx.Rows) ([]*entity.WebhookDeliveryEntity, error) {
+	defer rows.Close()
+	var deliveries []*entity.WebhookDeliveryEntity
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// CreateEndpoint: 웹훅 엔드포인트 등록
+func (r *webhookRepository) CreateEndpoint(ctx context.Context, e *entity.WebhookEndpointEntity) error {
+	return r.dbPool.QueryRow(ctx, `INSERT INTO webhook_endpoints (url, secret, events, description, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING id, created_at, updated_at`,
+		e.URL, e.Secret, e.Events, e.Description, e.Active,
+	).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
+}
+
+// UpdateEndpoint: 웹훅 엔드포인트 수정
+func (r *webhookRepository) UpdateEndpoint(ctx context.Context, e *entity.WebhookEndpointEntity) (bool, error) {
+	err := r.dbPool.QueryRow(ctx, `UPDATE webhook_endpoints SET url = $2, secret = $3, events = $4, description = $5, active = $6, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`,
+		e.ID, e.URL, e.Secret, e.Events, e.Description, e.Active,
+	).Scan(&e.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DeleteEndpoint: 웹훅 엔드포인트 삭제 (발송 기록은 ON DELETE CASCADE 로 함께 삭제)
+func (r *webhookRepository) DeleteEndpoint(ctx context.Context, id int64) (bool, error) {
+	cmd, err := r.dbPool.Exec(ctx, `DELETE FROM webhook_endpoints WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// FindEndpoint: ID 로 웹훅 엔드포인트 조회
+func (r *webhookRepository) FindEndpoint(ctx context.Context, id int64) (*entity.WebhookEndpointEntity, error) {
+	e, err := scanWebhookEndpoint(r.dbPool.QueryRow(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// ListEndpoints: 모든 웹훅 엔드포인트 조회 (오래된 순)
+func (r *webhookRepository) ListEndpoints(ctx context.Context) ([]*entity.WebhookEndpointEntity, error) {
+	rows, err := r.dbPool.Query(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var endpoints []*entity.WebhookEndpointEntity
+	for rows.Next() {
+		e, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, e)
+	}
+	return endpoints, rows.Err()
+}
+
+// Enqueue: 발송 대기 저장
+func (r *webhookRepository) Enqueue(ctx context.Context, d *entity.WebhookDeliveryEntity) error {
+	d.Status = entity.WebhookStatusPending
+	return r.dbPool.QueryRow(ctx, `INSERT INTO webhook_deliveries (endpoint_id, event_id, event, payload, status, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, 'pending', 0, NOW(), NOW())
+		RETURNING id, next_attempt_at, created_at`,
+		d.EndpointID, d.EventID, d.Event, d.Payload,
+	).Scan(&d.ID, &d.NextAttemptAt, &d.CreatedAt)
+}
+
+// ClaimDue: 발송 시각이 된 항목을 잠금(SKIP LOCKED) 후 lease 만큼 미뤄 두고 반환
+func (r *webhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDeliveryEntity, error) {
+	rows, err := r.dbPool.Query(ctx, `UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+webhookDeliveryColumns,
+		now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectWebhookDeliveries(rows)
+}
+
+// MarkDelivered: 발송 완료 처리
+func (r *webhookRepository) MarkDelivered(ctx context.Context, id int64, responseStatus int) error {
+	_, err := r.dbPool.Exec(ctx, `UPDATE webhook_deliveries SET status = 'delivered', attempts = attempts + 1, response_status = $2, last_error = '', delivered_at = NOW()
+		WHERE id = $1`, id, responseStatus)
+	return err
+}
+
+// MarkFailed: 실패한 시도 기록 (재시도 예약 또는 dead)
+func (r *webhookRepository) MarkFailed(ctx context.Context, id int64, status string, nextAttemptAt time.Time, responseStatus int, lastError string) error {
+	_, err := r.dbPool.Exec(ctx, `UPDATE webhook_deliveries SET status = $2, attempts = attempts + 1, next_attempt_at = $3, response_status = $4, last_error = $5
+		WHERE id = $1`, id, status, nextAttemptAt, responseStatus, lastError)
+	return err
+}
+
+// ListDeliveries: 엔드포인트의 발송 기록 조회 (최신 순)
+func (r *webhookRepository) ListDeliveries(ctx context.Context, endpointID int64, status string, limit int) ([]*entity.WebhookDeliveryEntity, error) {
+	rows, err := r.dbPool.Query(ctx, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries
+		WHERE endpoint_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY id DESC
+		LIMIT $3`, endpointID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectWebhookDeliveries(rows)
+}
+
+// FindDelivery: ID 로 발송 기록 조회
+func (r *webhookRepository) FindDelivery(ctx context.Context, id int64) (*entity.WebhookDeliveryEntity, error) {
+	d, err := scanWebhookDelivery(r.dbPool.QueryRow(ctx, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"auth/internal/entity"
+	"context"
+	"log/slog"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+)
+
+type webhookRepositorySqlite struct {
+	db *sqlite.Conn
+}
+
+// NewWebhookRepositorySqlite returns a new sqlite-based WebhookRepository.
+func NewWebhookRepositorySqlite(conn *sqlite.Conn) WebhookRepository {
+	r := &webhookRepositorySqlite{db: conn}
+	if err := r.CreateTable(context.Background()); err != nil {
+		slog.Warn("[sqlite] Error creating webhook tables", "error", err)
+	}
+	return r
+}
+
+// CreateTable creates the webhook_endpoints and webhook_deliveries tables if they do not exist.
+func (r *webhookRepositorySqlite) CreateTable(_ context.Context) error {
+	return sqliteExec(r.db,
+		`CREATE TABLE IF NOT EXISTS webhook_endpoints (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL,
+			events TEXT NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			active INTEGER NOT NULL DEFAULT 1,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			endpoint_id INTEGER NOT NULL,
+			event_id TEXT NOT NULL,
+			event TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			response_status INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			delivered_at DATETIME
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_endpoint ON webhook_deliveries (endpoint_id, id);`,
+	)
+}
+
+func (r *webhookRepositorySqlite) exec(q string, bind func(*sqlite.Stmt)) (int, error) {
+	stmt, err := r.db.Prepare(q)
+	if err != nil {
+		return 0, err
+	}
+	bind(stmt)
+	_, err = stmt.Step()
+	err2 := stmt.Finalize()
+	if err != nil {
+		return 0, err
+	}
+	if err2 != nil {
+		return 0, err2
+	}
+	return r.db.Changes(), nil
+}
+
+func (r *webhookRepositorySqlite) queryEndpoints(q string, bind func(*sqlite.Stmt)) ([]*entity.WebhookEndpointEntity, error) {
+	stmt, err := r.db.Prepare(q)
+	if err != nil {
+		return nil, err
+	}
+	bind(stmt)
+	var endpoints []*entity.WebhookEndpointEntity
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			_ = stmt.Finalize()
+			return nil, err
+		}
+		if !hasRow {
+			break
+		}
+		e := &entity.WebhookEndpointEntity{
+			ID:          stmt.ColumnInt64(0),
+			URL:         stmt.ColumnText(1),
+			Secret:      stmt.ColumnText(2),
+			Events:      stmt.ColumnText(3),
+			Description: stmt.ColumnText(4),
+			Active:      stmt.ColumnInt(5) != 0,
+		}
+		if t := sqliteColumnTime(stmt, 6); t != nil {
+			e.CreatedAt = *t
+		}
+		if t := sqliteColumnTime(stmt, 7); t != nil {
+			e.UpdatedAt = *t
+		}
+		endpoints = append(endpoints, e)
+	}
+	if err := stmt.Finalize(); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func (r *webhookRepositorySqlite) queryDeliveries(q string, bind func(*sqlite.Stmt)) ([]*entity.WebhookDeliveryEntity, error) {
+	stmt, err := r.db.Prepare(q)
+	if err != nil {
+		return nil, err
+	}
+	bind(stmt)
+	var deliveries []*entity.WebhookDeliveryEntity
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			_ = stmt.Finalize()
+			return nil, err
+		}
+		if !hasRow {
+			break
+		}
+		d := &entity.WebhookDeliveryEntity{
+			ID:             stmt.ColumnInt64(0),
+			EndpointID:     stmt.ColumnInt64(1),
+			EventID:        stmt.ColumnText(2),
+			Event:          stmt.ColumnText(3),
+			Payload:        stmt.ColumnText(4),
+			Status:         stmt.ColumnText(5),
+			Attempts:       stmt.ColumnInt(6),
+			ResponseStatus: stmt.ColumnInt(8),
+			LastError:      stmt.ColumnText(9),
+			DeliveredAt:    sqliteColumnTime(stmt, 11),
+		}
+		if t := sqliteColumnTime(stmt, 7); t != nil {
+			d.NextAttemptAt = *t
+		}
+		if t := sqliteColumnTime(stmt, 10); t != nil {
+			d.CreatedAt = *t
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := stmt.Finalize(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// CreateEndpoint stores a new endpoint.
+func (r *webhookRepositorySqlite) CreateEndpoint(_ context.Context, e *entity.WebhookEndpointEntity) error {
+	now := time.Now()
+	_, err := r.exec("INSERT INTO webhook_endpoints (url, secret, events, description, active, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)", func(stmt *sqlite.Stmt) {
+		stmt.BindText(1, e.URL)
+		stmt.BindText(2, e.Secret)
+		stmt.BindText(3, e.Events)
+		stmt.BindText(4, e.Description)
+		stmt.BindBool(5, e.Active)
+		sqliteBindTime(stmt, 6, &now)
+		sqliteBindTime(stmt, 7, &now)
+	})
+	if err != nil {
+		return err
+	}
+	e.ID = r.db.LastInsertRowID()
+	e.CreatedAt = now
+	e.UpdatedAt = now
+	return nil
+}
+
+// UpdateEndpoint saves the endpoint.
+func (r *webhookRepositorySqlite) UpdateEndpoint(_ context.Context, e *entity.WebhookEndpointEntity) (bool, error) {
+	now := time.Now()
+	n, err := r.exec("UPDATE webhook_endpoints SET url = ?, secret = ?, events = ?, description = ?, active = ?, updated_at = ? WHERE id = ?", func(stmt *sqlite.Stmt) {
+		stmt.BindText(1, e.URL)
+		stmt.BindText(2, e.Secret)
+		stmt.BindText(3, e.Events)
+		stmt.BindText(4, e.Description)
+		stmt.BindBool(5, e.Active)
+		sqliteBindTime(stmt, 6, &now)
+		stmt.BindInt64(7, e.ID)
+	})
+	if err != nil || n == 0 {
+		return false, err
+	}
+	e.UpdatedAt = now
+	return true, nil
+}
+
+// DeleteEndpoint deletes the endpoint and its deliveries. Foreign keys are not enforced
+// on the sqlite connection, so the deliveries are deleted explicitly.
+func (r *webhookRepositorySqlite) DeleteEndpoint(_ context.Context, id int64) (bool, error) {
+	bind := func(stmt *sqlite.Stmt) {
+		stmt.BindInt64(1, id)
+	}
+	if _, err := r.exec("DELETE FROM webhook_deliveries WHERE endpoint_id = ?", bind); err != nil {
+		return false, err
+	}
+	n, err := r.exec("DELETE FROM webhook_endpoints WHERE id = ?", bind)
+	return n > 0, err
+}
+
+// FindEndpoint returns the endpoint with the id, or nil.
+func (r *webhookRepositorySqlite) FindEndpoint(_ context.Context, id int64) (*entity.WebhookEndpointEntity, error) {
+	endpoints, err := r.queryEndpoints("SELECT "+webhookEndpointColumns+" FROM webhook_endpoints WHERE id = ?", func(stmt *sqlite.Stmt) {
+		stmt.BindInt64(1, id)
+	})
+	if err != nil || len(endpoints) == 0 {
+		return nil, err
+	}
+	return endpoints[0], nil
+}
+
+// ListEndpoints returns all endpoints, oldest first.
+func (r *webhookRepositorySqlite) ListEndpoints(_ context.Context) ([]*entity.WebhookEndpointEntity, error) {
+	return r.queryEndpoints("SELECT "+webhookEndpointColumns+" FROM webhook_endpoints ORDER BY id", func(*sqlite.Stmt) {})
+}
+
+// Enqueue stores a pending delivery.
+func (r *webhookRepositorySqlite) Enqueue(_ context.Context, d *entity.WebhookDeliveryEntity) error {
+	now := time.Now()
+	_, err := r.exec("INSERT INTO webhook_deliveries (endpoint_id, event_id, event, payload, status, attempts, next_attempt_at, created_at) VALUES (?, ?, ?, ?, 'pending', 0, ?, ?)", func(stmt *sqlite.Stmt) {
+		stmt.BindInt64(1, d.EndpointID)
+		stmt.BindText(2, d.EventID)
+		stmt.BindText(3, d.Event)
+		stmt.BindText(4, d.Payload)
+		sqliteBindTime(stmt, 5, &now)
+		sqliteBindTime(stmt, 6, &now)
+	})
+	if err != nil {
+		return err
+	}
+	d.ID = r.db.LastInsertRowID()
+	d.Status = entity.WebhookStatusPending
+	d.NextAttemptAt = now
+	d.CreatedAt = now
+	return nil
+}
+
+// ClaimDue returns due pending deliveries and leases them. A single sqlite connection
+// has no concurrent dispatchers, so select-then-update is sufficient.
+func (r *webhookRepositorySqlite) ClaimDue(_ context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDeliveryEntity, error) {
+	deliveries, err := r.queryDeliveries("SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE status = 'pending' AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?", func(stmt *sqlite.Stmt) {
+		sqliteBindTime(stmt, 1, &now)
+		stmt.BindInt64(2, int64(limit))
+	})
+	if err != nil {
+		return nil, err
+	}
+	leaseUntil := now.Add(lease)
+	for _, d := range deliveries {
+		_, err := r.exec("UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ?", func(stmt *sqlite.Stmt) {
+			sqliteBindTime(stmt, 1, &leaseUntil)
+			stmt.BindInt64(2, d.ID)
+		})
+		if err != nil {
+			return nil, err
+		}
+		d.NextAttemptAt = leaseUntil
+	}
+	return deliveries, nil
+}
+
+// MarkDelivered marks a delivery as delivered.
+func (r *webhookRepositorySqlite) MarkDelivered(_ context.Context, id int64, responseStatus int) error {
+	now := time.Now()
+	_, err := r.exec("UPDATE webhook_deliveries SET status = 'delivered', attempts = attempts + 1, response_status = ?, last_error = '', delivered_at = ? WHERE id = ?", func(stmt *sqlite.Stmt) {
+		stmt.BindInt64(1, int64(responseStatus))
+		sqliteBindTime(stmt, 2, &now)
+		stmt.BindInt64(3, id)
+	})
+	return err
+}
+
+// MarkFailed records a failed attempt.
+func (r *webhookRepositorySqlite) MarkFailed(_ context.Context, id int64, status string, nextAttemptAt time.Time, responseStatus int, lastError string) error {
+	_, err := r.exec("UPDATE webhook_deliveries SET status = ?, attempts = attempts + 1, next_attempt_at = ?, response_status = ?, last_error = ? WHERE id = ?", func(stmt *sqlite.Stmt) {
+		stmt.BindText(1, status)
+		sqliteBindTime(stmt, 2, &nextAttemptAt)
+		stmt.BindInt64(3, int64(responseStatus))
+		stmt.BindText(4, lastError)
+		stmt.BindInt64(5, id)
+	})
+	return err
+}
+
+// ListDeliveries returns the endpoint's deliveries, newest first.
+func (r *webhookRepositorySqlite) ListDeliveries(_ context.Context, endpointID int64, status string, limit int) ([]*entity.WebhookDeliveryEntity, error) {
+	return r.queryDeliveries("SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE endpoint_id = ? AND (? = '' OR status = ?) ORDER BY id DESC LIMIT ?", func(stmt *sqlite.Stmt) {
+		stmt.BindInt64(1, endpointID)
+		stmt.BindText(2, status)
+		stmt.BindText(3, status)
+		stmt.BindInt64(4, int64(limit))
+	})
+}
+
+// FindDelivery returns the delivery with the id, or nil.
+func (r *webhookRepositorySqlite) FindDelivery(_ context.Context, id int64) (*entity.WebhookDeliveryEntity, error) {
+	deliveries, err := r.queryDeliveries("SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE id = ?", func(stmt *sqlite.Stmt) {
+		stmt.BindInt64(1, id)
+	})
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+	return deliveries[0], nil
+}
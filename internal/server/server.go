@@ -12,12 +12,33 @@ package server
 
 import (
 	"auth/internal/config"
+	"auth/internal/grpcserver"
 	"auth/internal/handler"
+	"auth/internal/logging"
+	"auth/internal/metrics"
 	"auth/internal/middleware"
 	"auth/internal/repository"
 	"auth/internal/service"
+	"auth/internal/service/audit"
 	"auth/internal/service/email"
+	"auth/internal/service/event"
+	"auth/internal/service/janitor"
+	"auth/internal/service/jwks"
+	"auth/internal/service/link"
+	"auth/internal/service/outbox"
+	"auth/internal/service/password"
+	"auth/internal/service/phone"
+	"auth/internal/service/sms"
+	"auth/internal/service/webhook"
+	"auth/internal/tracing"
 	"auth/pkg/database"
+	"auth/pkg/utils"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"strings"
+	"time"
 
 	// docs 패키지는 Swagger 문서 생성을 위해 필요합니다. 실제 코드에서는 사용되지 않습니다.
 	_ "auth/docs"
@@ -25,9 +46,10 @@ import (
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/swagger"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 )
 
 // APIPrefix is the base path for all API routes.
@@ -37,26 +59,53 @@ const (
 	APIVersion = "/v1"
 )
 
-// Server wraps the Fiber app and database pool.
+// Server wraps the Fiber app, the gRPC server and database pool.
 type Server struct {
-	App       *fiber.App
-	DbPool    *pgxpool.Pool
+	App        *fiber.App
+	GRPC       *grpc.Server // GRPCPort 가 비어 있으면 nil
+	DbPool     *pgxpool.Pool
 	SqliteConn interface{} // *sqlite.Conn 타입이지만, 임시로 interface{}로 둠
+
+	dispatcher     *outbox.Dispatcher
+	auditLog       *audit.Service
+	webhooks       *webhook.Dispatcher
+	events         *event.Bus
+	keys           *jwks.KeySet
+	janitor        *janitor.Janitor
+	health         *handler.HealthHandler
+	tracerShutdown func(context.Context) error
 }
 
 // NewServer creates and configures a new HTTP server for the authentication service.
 func NewServer(cfg config.Config) *Server {
+	tracerShutdown, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.TracingExporter,
+		Endpoint:    cfg.TracingEndpoint,
+		ServiceName: cfg.TracingServiceName,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		panic(err)
+	}
+
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
-		JSONEncoder: sonic.Marshal,
-		JSONDecoder: sonic.Unmarshal,
-		ErrorHandler: func(ctx *fiber.Ctx, err error) error {
-			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
-		},
+		JSONEncoder:  sonic.Marshal,
+		JSONDecoder:  sonic.Unmarshal,
+		ErrorHandler: handler.ErrorHandler,
+		// 시작 배너 대신 main 에서 구조화된 로그를 남긴다
+		DisableStartupMessage: true,
 	})
 
-	app.Use(logger.New())
+	// 로그와 오류 응답에 trace ID 를 넣을 수 있도록 가장 먼저 스팬을 시작한다
+	app.Use(tracing.Middleware())
+	// 프로브 요청은 주기적으로 들어오므로 접근 로그에서 뺀다
+	app.Use(logging.Middleware("/healthz", "/readyz", "/metrics"))
+	if cfg.MetricsEnabled {
+		app.Use(metrics.Middleware())
+	}
 	app.Use(cors.New())
+	app.Use(middleware.ClientInfoMiddleware())
 
 	var dbPool *pgxpool.Pool
 	var sqliteConn interface{}
@@ -79,24 +128,207 @@ func NewServer(cfg config.Config) *Server {
 
 	var userRepo repository.UserRepository
 	var profileRepo repository.ProfileRepository
+	var phoneRepo repository.PhoneVerificationRepository
+	var outboxRepo repository.OutboxRepository
+	var auditRepo repository.AuditRepository
+	var webhookRepo repository.WebhookRepository
+	var signingKeyRepo repository.SigningKeyRepository
 	if cfg.DBType == "sqlite" {
 		userRepo = repository.NewUserRepositoryAuto(cfg.DBType, nil, sqliteConn)
 		profileRepo = repository.NewProfileRepositoryAuto(cfg.DBType, nil, sqliteConn)
+		phoneRepo = repository.NewPhoneVerificationRepositoryAuto(cfg.DBType, nil, sqliteConn)
+		outboxRepo = repository.NewOutboxRepositoryAuto(cfg.DBType, nil, sqliteConn)
+		auditRepo = repository.NewAuditRepositoryAuto(cfg.DBType, nil, sqliteConn)
+		webhookRepo = repository.NewWebhookRepositoryAuto(cfg.DBType, nil, sqliteConn)
+		signingKeyRepo = repository.NewSigningKeyRepositoryAuto(cfg.DBType, nil, sqliteConn)
 	} else {
 		userRepo = repository.NewUserRepositoryAuto(cfg.DBType, dbPool, nil)
 		profileRepo = repository.NewProfileRepositoryAuto(cfg.DBType, dbPool, nil)
+		phoneRepo = repository.NewPhoneVerificationRepositoryAuto(cfg.DBType, dbPool, nil)
+		outboxRepo = repository.NewOutboxRepositoryAuto(cfg.DBType, dbPool, nil)
+		auditRepo = repository.NewAuditRepositoryAuto(cfg.DBType, dbPool, nil)
+		webhookRepo = repository.NewWebhookRepositoryAuto(cfg.DBType, dbPool, nil)
+		signingKeyRepo = repository.NewSigningKeyRepositoryAuto(cfg.DBType, dbPool, nil)
+	}
+	userRepo = repository.NewTracedUserRepository(userRepo, cfg.DBType)
+	profileRepo = repository.NewTracedProfileRepository(profileRepo, cfg.DBType)
+
+	// sqlite 연결 하나를 요청 처리와 백그라운드 작업이 함께 쓰면 안전하지 않으므로,
+	// sqlite 에서는 주기 작업을 시작하지 않는다. 토큰 정리는 authctl tokens purge 로 실행한다.
+	background := cfg.DBType != "sqlite"
+	if !background {
+		slog.Warn("sqlite: outbox, signing key reload, audit purge, webhook delivery and token purge loops are disabled; mail is sent inline")
 	}
 
-	jwtService := service.NewJwtService(cfg.JwtSecret)
-	emailService := email.NewEmailService(cfg.SMTPServer, cfg.SMTPPort, cfg.SMTPID, cfg.SMTPPassword)
-	authService := service.NewAuthService(dbPool, userRepo, profileRepo, jwtService, emailService)
+	keySet := jwks.NewKeySet(signingKeyRepo)
+	keySet.Interval = time.Duration(cfg.JWTKeyReloadInterval) * time.Second
+	keySet.RetainFor = time.Duration(cfg.JWTKeyRetention) * time.Second
+	var jwtOpts []service.JwtOption
+	switch cfg.JWTSigningAlgorithm {
+	case "ES256":
+		// 첫 기동이면 서명 키를 만든다
+		if err := keySet.Ensure(context.Background()); err != nil {
+			panic(err)
+		}
+		// sqlite 에서는 주기적으로 다시 읽지 않고, 모르는 키 ID 를 만났을 때만 다시 읽는다
+		if background {
+			keySet.Start()
+		}
+		jwtOpts = append(jwtOpts, service.WithSigningKeys(keySet))
+	case "HS256", "":
+	default:
+		panic("지원하지 않는 JWT_SIGNING_ALGORITHM: " + cfg.JWTSigningAlgorithm)
+	}
+	jwtService := service.NewJwtService(cfg.JwtSecret, jwtOpts...)
+	var mailer email.Mailer
+	switch cfg.MailProvider {
+	case "smtp", "":
+		mailer = &email.SMTPMailer{
+			Host:     cfg.SMTPServer,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPID,
+			Password: cfg.SMTPPassword,
+			Security: cfg.SMTPSecurity,
+			Auth:     cfg.SMTPAuth,
+		}
+	case "api":
+		mailer = email.NewAPIMailer(cfg.MailAPIURL, cfg.MailAPIKey)
+	case "file":
+		mailer = email.NewFileMailer(cfg.MailFileDir)
+	default:
+		panic("지원하지 않는 MAIL_PROVIDER: " + cfg.MailProvider)
+	}
+	templates, err := email.NewTemplates(cfg.MailTemplateDir, cfg.MailDefaultLocale, email.Brand{
+		Name:         cfg.BrandName,
+		URL:          cfg.BrandURL,
+		LogoURL:      cfg.BrandLogoURL,
+		SupportEmail: cfg.BrandSupportEmail,
+		Color:        cfg.BrandColor,
+	})
+	if err != nil {
+		panic(err)
+	}
+	if cfg.MetricsEnabled {
+		mailer = metrics.InstrumentMailer(mailer)
+	}
+	emailService := email.NewEmailServiceWithMailer(mailer, mail.Address{Name: cfg.MailFromName, Address: cfg.MailFrom}, email.WithTemplates(templates))
+	argon2Params, err := utils.NewArgon2Params(cfg.Argon2Memory, cfg.Argon2Iterations, cfg.Argon2Parallelism)
+	if err != nil {
+		panic(err)
+	}
+	hasher, err := utils.NewPasswordHasher(cfg.PasswordHashAlgorithm, cfg.BcryptCost, argon2Params)
+	if err != nil {
+		panic(err)
+	}
+	smsSender, err := sms.NewSender(cfg.SMSProvider, cfg.SMSFilePath)
+	if err != nil {
+		panic(err)
+	}
+	phones, err := phone.NewParser(cfg.PhoneDefaultRegion)
+	if err != nil {
+		panic(err)
+	}
+	handler.SetPhoneParser(phones)
+	if cfg.MetricsEnabled {
+		hasher = metrics.InstrumentHasher(hasher)
+	}
+	authOpts := []service.AuthServiceOption{
+		service.WithPasswordHasher(hasher),
+		service.WithPhoneVerification(phoneRepo, smsSender),
+		service.WithPhoneParser(phones),
+		service.WithPasswordPolicy(password.Policy{
+			MinLength:          cfg.PasswordMinLength,
+			MaxLength:          cfg.PasswordMaxLength,
+			RequireUpper:       cfg.PasswordRequireUpper,
+			RequireLower:       cfg.PasswordRequireLower,
+			RequireDigit:       cfg.PasswordRequireDigit,
+			RequireSymbol:      cfg.PasswordRequireSymbol,
+			MinCharClasses:     cfg.PasswordMinCharClasses,
+			MinStrength:        cfg.PasswordMinStrength,
+			RejectPersonalInfo: true,
+			HistorySize:        cfg.PasswordHistorySize,
+		}),
+	}
+	switch cfg.BreachCheck {
+	case "local":
+		authOpts = append(authOpts, service.WithBreachChecker(password.NewRangeChecker(password.NewLocalCorpus(cfg.BreachCorpusDir))))
+	case "api":
+		authOpts = append(authOpts, service.WithBreachChecker(password.NewRangeChecker(password.NewHTTPRangeClient(cfg.BreachAPIURL))))
+	case "off", "":
+	default:
+		panic("지원하지 않는 BREACH_CHECK: " + cfg.BreachCheck)
+	}
+	dispatcher := outbox.NewDispatcher(outboxRepo)
+	dispatcher.Interval = time.Duration(cfg.OutboxInterval) * time.Second
+	dispatcher.MaxAttempts = cfg.OutboxMaxAttempts
+	dispatcher.Handle(email.OutboxKind, emailService.Deliver)
+	// sqlite 에서는 OUTBOX_ENABLED 를 무시하고 요청 처리 중에 바로 발송한다
+	if cfg.OutboxEnabled && background {
+		authOpts = append(authOpts, service.WithOutbox(outboxRepo))
+		dispatcher.Start()
+	}
+	linkSecret := []byte(cfg.LinkSigningSecret)
+	if len(linkSecret) == 0 {
+		// JWT 서명 키를 그대로 쓰지 않고 용도별 키를 유도한다
+		if linkSecret, err = link.DeriveSecret([]byte(cfg.JwtSecret)); err != nil {
+			panic(err)
+		}
+	}
+	links, err := link.NewBuilder(cfg.PublicBaseURL, cfg.LinkPaths, cfg.RedirectAllowlist, linkSecret)
+	if err != nil {
+		panic(err)
+	}
+	authOpts = append(authOpts, service.WithLinkBuilder(links))
+	// 감사 로그와 웹훅은 서비스가 발행하는 도메인 이벤트를 구독한다
+	events := event.NewBus()
+	if cfg.DBType == "sqlite" {
+		// 비동기 구독자(보안 알림 메일)가 sqlite 연결을 다른 고루틴에서 쓰지 않도록 한다
+		events = event.NewSyncBus()
+	}
+	authOpts = append(authOpts, service.WithEventBus(events))
+	auditLog := audit.NewService(auditRepo)
+	auditLog.Retention = time.Duration(cfg.AuditRetentionDays) * 24 * time.Hour
+	if background {
+		auditLog.Start()
+	}
+	auditLog.Subscribe(events)
+	authOpts = append(authOpts, service.WithAuditLog(auditLog))
+	webhooks := webhook.NewDispatcher(webhookRepo)
+	webhooks.Interval = time.Duration(cfg.WebhookInterval) * time.Second
+	webhooks.MaxAttempts = cfg.WebhookMaxAttempts
+	webhooks.Timeout = time.Duration(cfg.WebhookTimeout) * time.Second
+	if background {
+		webhooks.Start()
+	}
+	webhooks.Subscribe(events)
+	// 만료 토큰 삭제는 Postgres advisory lock 으로 한 인스턴스만 실행한다
+	tokenJanitor := janitor.New(repository.NewUserAdminRepositoryAuto(cfg.DBType, dbPool, sqliteConn), repository.NewLockerAuto(cfg.DBType, dbPool, sqliteConn))
+	tokenJanitor.Interval = time.Duration(cfg.TokenPurgeInterval) * time.Second
+	tokenJanitor.BatchSize = cfg.TokenPurgeBatchSize
+	if background {
+		tokenJanitor.Start()
+	}
+	authService := service.NewAuthService(dbPool, userRepo, profileRepo, jwtService, emailService, authOpts...)
+	service.SubscribeMetrics(events)
+	authService.SubscribeNotifications(events)
 	authHandler := handler.NewAuthHandler(authService)
+	var grpcServer *grpc.Server
+	if cfg.GRPCPort != "" {
+		grpcServer = grpcserver.New(authService)
+		if cfg.GRPCReflection {
+			reflection.Register(grpcServer)
+		}
+	}
+	adminHandler := handler.NewAdminHandler(dispatcher, auditLog, webhooks)
 
 	api := app.Group(APIPrefix).Group(APIVersion)
 	auth := api.Group("/auth")
 	auth.Post("/register", authHandler.Register)
 	auth.Post("/login", authHandler.Login)
+	auth.Post("/magic-link", authHandler.RequestMagicLink)
+	auth.Post("/magic-link/verify", authHandler.VerifyMagicLink)
 	auth.Post("/refresh-token", authHandler.RefreshToken)
+	auth.Post("/phone/code", authHandler.SendFindEmailCode)
 	auth.Post("/email/recover", authHandler.FindEmail)
 	auth.Post("/password/forgot", authHandler.ForgotPassword)
 	auth.Post("/password/reset", authHandler.ResetPassword)
@@ -106,17 +338,124 @@ func NewServer(cfg config.Config) *Server {
 	users.Use(middleware.JwtMiddleware(jwtService))
 	users.Get("/me", authHandler.GetProfile)
 	users.Put("/me", authHandler.UpdateProfile)
+	users.Post("/me/phone/code", authHandler.SendPhoneCode)
+	users.Post("/me/phone/verify", authHandler.VerifyPhone)
 	users.Delete("/me", authHandler.DeleteProfile)
 	users.Put("/me/password", authHandler.ChangePassword)
+	users.Get("/me/notifications", authHandler.GetNotificationPreferences)
+	users.Put("/me/notifications", authHandler.UpdateNotificationPreferences)
+	users.Get("/me/activity", authHandler.GetActivity)
+
+	if cfg.AdminAPIKey != "" {
+		admin := api.Group("/admin", middleware.AdminKeyMiddleware(cfg.AdminAPIKey))
+		admin.Get("/outbox", adminHandler.ListOutbox)
+		admin.Post("/outbox/:id/retry", adminHandler.RetryOutbox)
+		admin.Get("/audit", adminHandler.ListAudit)
+		admin.Get("/audit/verify", adminHandler.VerifyAudit)
+		admin.Get("/webhooks", adminHandler.ListWebhooks)
+		admin.Post("/webhooks", adminHandler.CreateWebhook)
+		admin.Post("/webhooks/deliveries/:id/replay", adminHandler.ReplayWebhookDelivery)
+		admin.Get("/webhooks/:id", adminHandler.GetWebhook)
+		admin.Put("/webhooks/:id", adminHandler.UpdateWebhook)
+		admin.Delete("/webhooks/:id", adminHandler.DeleteWebhook)
+		admin.Get("/webhooks/:id/deliveries", adminHandler.ListWebhookDeliveries)
+	}
+
+	if cfg.AppEnv == "dev" {
+		devHandler := handler.NewDevHandler(emailService)
+		dev := api.Group("/dev")
+		dev.Get("/emails/:template", devHandler.PreviewEmail)
+	}
 
 	app.Get("/swagger/*", swagger.HandlerDefault)
+	app.Get("/.well-known/jwks.json", handler.NewJWKSHandler(keySet).JWKS)
+
+	health := handler.NewHealthHandler(
+		handler.HealthCheck{Name: "database", Check: func(ctx context.Context) error {
+			if cfg.DBType == "sqlite" {
+				return database.PingSqlite()
+			}
+			return database.Ping(ctx)
+		}},
+		handler.HealthCheck{Name: "migrations", Check: func(ctx context.Context) error {
+			missing, err := repository.MissingTables(ctx, cfg.DBType, dbPool, sqliteConn)
+			if err != nil {
+				return err
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("missing tables: %s", strings.Join(missing, ", "))
+			}
+			return nil
+		}},
+		handler.HealthCheck{Name: "mailer", Check: emailService.Ping},
+	)
+	health.Timeout = time.Duration(cfg.ReadinessTimeout) * time.Second
+	app.Get("/healthz", health.Live)
+	app.Get("/readyz", health.Ready)
+	if cfg.MetricsEnabled {
+		app.Get("/metrics", metrics.Handler())
+	}
 
-	return &Server{App: app, DbPool: dbPool, SqliteConn: sqliteConn}
+	return &Server{App: app, GRPC: grpcServer, DbPool: dbPool, SqliteConn: sqliteConn, dispatcher: dispatcher, auditLog: auditLog, webhooks: webhooks, events: events, keys: keySet, janitor: tokenJanitor, health: health, tracerShutdown: tracerShutdown}
 }
 
-// Close gracefully closes the database connection pool.
+// Shutdown fails the readiness probe, stops accepting connections and waits for in-flight
+// requests and calls until ctx is done, then releases the server's resources with Close.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Drain()
+	grpcStopped := make(chan struct{})
+	go func() {
+		defer close(grpcStopped)
+		if s.GRPC != nil {
+			s.GRPC.GracefulStop()
+		}
+	}()
+	err := s.App.ShutdownWithContext(ctx)
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		// 남은 호출을 끊는다
+		if s.GRPC != nil {
+			s.GRPC.Stop()
+		}
+		<-grpcStopped
+	}
+	s.Close()
+	return err
+}
+
+// Close waits for asynchronous event subscribers, stops the outbox and webhook dispatchers, audit and
+// token purges and signing key reloads, closes the database connections and flushes pending spans.
 func (s *Server) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.events.Wait(ctx); err != nil {
+		slog.Warn("event subscribers did not finish in time", "error", err)
+	}
+	if err := s.dispatcher.Stop(ctx); err != nil {
+		slog.Warn("outbox dispatcher did not stop in time", "error", err)
+	}
+	if err := s.webhooks.Stop(ctx); err != nil {
+		slog.Warn("webhook dispatcher did not stop in time", "error", err)
+	}
+	if err := s.auditLog.Stop(ctx); err != nil {
+		slog.Warn("audit purge did not stop in time", "error", err)
+	}
+	if err := s.janitor.Stop(ctx); err != nil {
+		slog.Warn("token janitor did not stop in time", "error", err)
+	}
+	if err := s.keys.Stop(ctx); err != nil {
+		slog.Warn("signing key reload did not stop in time", "error", err)
+	}
 	if s.DbPool != nil {
 		s.DbPool.Close()
 	}
+	if s.SqliteConn != nil {
+		if err := database.CloseSqlite(); err != nil {
+			slog.Warn("sqlite close failed", "error", err)
+		}
+	}
+	if err := s.tracerShutdown(ctx); err != nil {
+		slog.Warn("tracer shutdown failed", "error", err)
+	}
 }
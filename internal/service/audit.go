@@ -0,0 +1,44 @@
+package service
+
+import (
+	"auth/internal/dto"
+	"auth/internal/repository"
+	"auth/internal/service/audit"
+	"context"
+	"encoding/json"
+)
+
+// WithAuditLog sets the audit log ListActivity reads. Events are recorded by subscribing the
+// log to the event bus, see audit.Service.Subscribe.
+func WithAuditLog(log *audit.Service) AuthServiceOption {
+	return func(s *AuthService) {
+		s.auditLog = log
+	}
+}
+
+// ListActivity returns the user's audit events, newest first. beforeID, if not 0, continues
+// from the last event of the previous page.
+func (s *AuthService) ListActivity(ctx context.Context, userID, beforeID int64, limit int) ([]dto.AuditEventResponse, error) {
+	if s.auditLog == nil {
+		return []dto.AuditEventResponse{}, nil
+	}
+	events, err := s.auditLog.List(ctx, repository.AuditFilter{UserID: &userID, BeforeID: beforeID, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.AuditEventResponse, 0, len(events))
+	for _, e := range events {
+		r := dto.AuditEventResponse{
+			ID:        e.ID,
+			Event:     e.Event,
+			Outcome:   e.Outcome,
+			Actor:     e.Actor,
+			IP:        e.IP,
+			UserAgent: e.UserAgent,
+			CreatedAt: e.CreatedAt,
+		}
+		_ = json.Unmarshal([]byte(e.Details), &r.Details)
+		result = append(result, r)
+	}
+	return result, nil
+}
@@ -0,0 +1,240 @@
+// Package audit records security-relevant events in the hash-chained audit_events table.
+package audit
+
+import (
+	"auth/internal/entity"
+	"auth/internal/repository"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Events recorded by the service.
+const (
+	EventRegister             = "auth.register"
+	EventLogin                = "auth.login"
+	EventRefresh              = "auth.refresh"
+	EventLogout               = "auth.logout"
+	EventPasswordChange       = "password.change"
+	EventPasswordResetRequest = "password.reset_request"
+	EventPasswordReset        = "password.reset"
+	EventProfileUpdate        = "profile.update"
+	EventPhoneVerify          = "phone.verify"
+	EventAccountDelete        = "account.delete"
+	EventAdminOutboxRetry     = "admin.outbox_retry"
+	EventAdminWebhookCreate   = "admin.webhook_create"
+	EventAdminWebhookUpdate   = "admin.webhook_update"
+	EventAdminWebhookDelete   = "admin.webhook_delete"
+	EventAdminWebhookReplay   = "admin.webhook_replay"
+	EventAdminUserCreate      = "admin.user_create"
+	EventAdminUserDisable     = "admin.user_disable"
+	EventAdminUserRestore     = "admin.user_restore"
+	EventAdminUserImport      = "admin.user_import"
+	EventAdminPasswordReset   = "admin.password_reset"
+	EventAdminSessionsRevoke  = "admin.sessions_revoke"
+	EventAdminRoleAdd         = "admin.role_add"
+	EventAdminRoleRemove      = "admin.role_remove"
+	EventAdminKeyRotate       = "admin.key_rotate"
+)
+
+// Outcomes of an event, the same values as the metrics outcome label.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure" // 요청 때문에 거부됨, 예: 틀린 비밀번호
+	OutcomeError   = "error"   // 서버 쪽 오류
+)
+
+// Actors that cause events.
+const (
+	ActorUser   = "user"
+	ActorAdmin  = "admin"
+	ActorSystem = "system"
+)
+
+// Event describes an event to record.
+type Event struct {
+	Type    string
+	Actor   string // 비어 있으면 ActorUser
+	UserID  int64  // 0 이면 알 수 없음
+	Outcome string
+	Details map[string]string
+}
+
+// Client is the network client of a request, stored in its context by the HTTP layer.
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+type clientKey struct{}
+
+// WithClient returns a copy of ctx carrying the client.
+func WithClient(ctx context.Context, c Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, c)
+}
+
+// ClientFrom returns the client in ctx, or the zero Client.
+func ClientFrom(ctx context.Context) Client {
+	c, _ := ctx.Value(clientKey{}).(Client)
+	return c
+}
+
+// Service writes and queries the audit log and deletes events older than Retention.
+type Service struct {
+	repo repository.AuditRepository
+
+	Retention     time.Duration // 0 이면 삭제하지 않음
+	PurgeInterval time.Duration
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewService creates a Service that keeps events forever.
+func NewService(repo repository.AuditRepository) *Service {
+	return &Service{
+		repo:          repo,
+		PurgeInterval: 24 * time.Hour,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Record appends the event with the client in ctx. Failures are logged and do not affect
+// the caller, so an unavailable audit log does not lock users out.
+func (s *Service) Record(ctx context.Context, ev Event) {
+	client := ClientFrom(ctx)
+	e := &entity.AuditEventEntity{
+		Actor:     ev.Actor,
+		Event:     ev.Type,
+		Outcome:   ev.Outcome,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}
+	if e.Actor == "" {
+		e.Actor = ActorUser
+	}
+	if ev.UserID != 0 {
+		userID := ev.UserID
+		e.UserID = &userID
+	}
+	if len(ev.Details) > 0 {
+		details, _ := json.Marshal(ev.Details) // map[string]string 는 항상 인코딩된다
+		e.Details = string(details)
+	}
+	if err := s.repo.Append(ctx, e); err != nil {
+		slog.ErrorContext(ctx, "audit: append failed", "event", ev.Type, "userId", ev.UserID, "error", err)
+	}
+}
+
+// List returns the events matching the filter, newest first.
+func (s *Service) List(ctx context.Context, f repository.AuditFilter) ([]*entity.AuditEventEntity, error) {
+	return s.repo.List(ctx, f)
+}
+
+// VerifyResult is the result of Verify.
+type VerifyResult struct {
+	Valid   bool  `json:"valid"`
+	Checked int   `json:"checked"`
+	FirstID int64 `json:"firstId,omitempty"`
+	LastID  int64 `json:"lastId,omitempty"`
+	// BrokenID is the first event whose hash does not match its fields or the event before it.
+	BrokenID int64 `json:"brokenId,omitempty"`
+}
+
+// verifyBatch is how many events Verify reads at a time.
+const verifyBatch = 500
+
+// Verify recomputes the hash chain. The oldest remaining event's PrevHash is trusted, since
+// events before it may have been deleted by retention.
+func (s *Service) Verify(ctx context.Context) (*VerifyResult, error) {
+	result := &VerifyResult{Valid: true}
+	var afterID int64
+	prev := ""
+	for {
+		events, err := s.repo.ListAfter(ctx, afterID, verifyBatch)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			if result.Checked == 0 {
+				result.FirstID = e.ID
+				prev = e.PrevHash
+			}
+			result.Checked++
+			result.LastID = e.ID
+			if e.PrevHash != prev || e.ComputeHash() != e.Hash {
+				result.Valid = false
+				result.BrokenID = e.ID
+				return result, nil
+			}
+			prev = e.Hash
+			afterID = e.ID
+		}
+		if len(events) < verifyBatch {
+			return result, nil
+		}
+	}
+}
+
+// Purge deletes events older than Retention and returns how many were deleted.
+func (s *Service) Purge(ctx context.Context, now time.Time) (int64, error) {
+	if s.Retention <= 0 {
+		return 0, nil
+	}
+	return s.repo.DeleteBefore(ctx, now.Add(-s.Retention))
+}
+
+// Start purges expired events every PurgeInterval in a background goroutine until Stop is called.
+// It does nothing when Retention is not set.
+func (s *Service) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started || s.stopped || s.Retention <= 0 {
+		return
+	}
+	s.started = true
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.PurgeInterval)
+		defer ticker.Stop()
+		for {
+			if n, err := s.Purge(context.Background(), time.Now()); err != nil {
+				slog.Error("audit: purge failed", "error", err)
+			} else if n > 0 {
+				slog.Info("audit: purged expired events", "count", n)
+			}
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop signals the purge loop to exit and waits for it to finish or ctx to end.
+// It does nothing if the loop was never started.
+func (s *Service) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.stop)
+	}
+	started := s.started
+	s.mu.Unlock()
+	if !started {
+		return nil
+	}
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,152 @@
+package audit_test
+
+import (
+	"auth/internal/repository"
+	"auth/internal/service/audit"
+	"auth/internal/service/event"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+func newService(t *testing.T) (*audit.Service, *sqlite.Conn) {
+	conn, err := sqlite.OpenConn(":memory:", 0)
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return audit.NewService(repository.NewAuditRepositorySqlite(conn)), conn
+}
+
+func TestRecord_조회(t *testing.T) {
+	s, _ := newService(t)
+	ctx := audit.WithClient(context.Background(), audit.Client{IP: "203.0.113.7", UserAgent: "Mozilla/5.0"})
+
+	s.Record(ctx, audit.Event{Type: audit.EventLogin, UserID: 1, Outcome: audit.OutcomeSuccess, Details: map[string]string{"method": "password"}})
+	s.Record(ctx, audit.Event{Type: audit.EventLogin, Outcome: audit.OutcomeFailure})
+	s.Record(ctx, audit.Event{Type: audit.EventPasswordChange, UserID: 1, Outcome: audit.OutcomeSuccess})
+	s.Record(ctx, audit.Event{Type: audit.EventAdminOutboxRetry, Actor: audit.ActorAdmin, Outcome: audit.OutcomeSuccess})
+
+	userID := int64(1)
+	events, err := s.List(ctx, repository.AuditFilter{UserID: &userID, Limit: 10})
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, audit.EventPasswordChange, events[0].Event, "최신 순")
+	login := events[1]
+	assert.Equal(t, audit.ActorUser, login.Actor)
+	assert.Equal(t, "203.0.113.7", login.IP)
+	assert.Equal(t, "Mozilla/5.0", login.UserAgent)
+	assert.Equal(t, `{"method":"password"}`, login.Details)
+	assert.False(t, login.CreatedAt.IsZero())
+
+	events, err = s.List(ctx, repository.AuditFilter{Event: audit.EventLogin, Outcome: audit.OutcomeFailure, Limit: 10})
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Nil(t, events[0].UserID)
+
+	// 페이지 커서
+	events, err = s.List(ctx, repository.AuditFilter{Limit: 2})
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	next, err := s.List(ctx, repository.AuditFilter{BeforeID: events[1].ID, Limit: 10})
+	assert.Nil(t, err)
+	assert.Len(t, next, 2)
+	assert.Less(t, next[0].ID, events[1].ID)
+
+	since := time.Now().Add(time.Hour)
+	events, err = s.List(ctx, repository.AuditFilter{Since: &since, Limit: 10})
+	assert.Nil(t, err)
+	assert.Empty(t, events)
+}
+
+func TestVerify_변조감지(t *testing.T) {
+	s, conn := newService(t)
+	ctx := context.Background()
+
+	result, err := s.Verify(ctx)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, 0, result.Checked)
+
+	for i := int64(1); i <= 3; i++ {
+		s.Record(ctx, audit.Event{Type: audit.EventLogin, UserID: i, Outcome: audit.OutcomeSuccess})
+	}
+	result, err = s.Verify(ctx)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, 3, result.Checked)
+
+	// 행을 고치면 그 행의 해시가 맞지 않는다
+	assert.Nil(t, sqlitex.ExecuteTransient(conn, "UPDATE audit_events SET user_id = 9 WHERE id = 2", nil))
+	result, err = s.Verify(ctx)
+	assert.Nil(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(2), result.BrokenID)
+}
+
+func TestVerify_중간삭제감지(t *testing.T) {
+	s, conn := newService(t)
+	ctx := context.Background()
+	for i := int64(1); i <= 3; i++ {
+		s.Record(ctx, audit.Event{Type: audit.EventLogin, UserID: i, Outcome: audit.OutcomeSuccess})
+	}
+
+	assert.Nil(t, sqlitex.ExecuteTransient(conn, "DELETE FROM audit_events WHERE id = 2", nil))
+	result, err := s.Verify(ctx)
+	assert.Nil(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(3), result.BrokenID)
+
+	// 보존 기간으로 앞부분이 지워진 것은 변조가 아니다
+	assert.Nil(t, sqlitex.ExecuteTransient(conn, "DELETE FROM audit_events WHERE id <= 2", nil))
+	result, err = s.Verify(ctx)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, int64(3), result.FirstID)
+}
+
+func TestPurge(t *testing.T) {
+	s, _ := newService(t)
+	ctx := context.Background()
+	s.Record(ctx, audit.Event{Type: audit.EventLogin, UserID: 1, Outcome: audit.OutcomeSuccess})
+
+	n, err := s.Purge(ctx, time.Now().Add(48*time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), n, "보존 기간이 없으면 지우지 않는다")
+
+	s.Retention = 24 * time.Hour
+	n, err = s.Purge(ctx, time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), n)
+	n, err = s.Purge(ctx, time.Now().Add(48*time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestSubscribe_도메인이벤트기록(t *testing.T) {
+	s, _ := newService(t)
+	bus := event.NewBus()
+	s.Subscribe(bus)
+	ctx := context.Background()
+
+	bus.Publish(ctx, event.LoggedIn{UserID: 1, Method: event.MethodMagicLink})
+	bus.Publish(ctx, event.ProfileUpdated{UserID: 1, PhoneNumber: "+821012345678", PhoneChanged: true})
+	bus.Publish(ctx, event.Failed{Action: event.NamePasswordChanged, UserID: 1, Outcome: audit.OutcomeFailure, Err: errors.New("incorrect")})
+	// 가입 실패는 기록하지 않는다
+	bus.Publish(ctx, event.Failed{Action: event.NameUserRegistered, Outcome: audit.OutcomeFailure})
+
+	events, err := s.List(ctx, repository.AuditFilter{Limit: 10})
+	assert.Nil(t, err)
+	assert.Len(t, events, 3)
+	assert.Equal(t, audit.EventPasswordChange, events[0].Event)
+	assert.Equal(t, audit.OutcomeFailure, events[0].Outcome)
+	assert.Equal(t, audit.EventProfileUpdate, events[1].Event)
+	assert.NotContains(t, events[1].Details, "12345678")
+	assert.Equal(t, audit.EventLogin, events[2].Event)
+	assert.Equal(t, `{"method":"magic_link"}`, events[2].Details)
+}
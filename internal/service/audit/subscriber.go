@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"auth/internal/service/event"
+	"auth/pkg/utils"
+	"context"
+)
+
+// eventTypes maps the domain events to the audit events recording them.
+var eventTypes = map[string]string{
+	event.NameUserRegistered:         EventRegister,
+	event.NameLoggedIn:               EventLogin,
+	event.NameTokenRefreshed:         EventRefresh,
+	event.NameLoggedOut:              EventLogout,
+	event.NamePasswordChanged:        EventPasswordChange,
+	event.NamePasswordResetRequested: EventPasswordResetRequest,
+	event.NamePasswordReset:          EventPasswordReset,
+	event.NameProfileUpdated:         EventProfileUpdate,
+	event.NamePhoneVerified:          EventPhoneVerify,
+	event.NameAccountDeleted:         EventAccountDelete,
+}
+
+// Subscribe records the domain events published on bus, successful and failed, in the audit log.
+// Events are recorded synchronously so the request's client is still known.
+func (s *Service) Subscribe(bus *event.Bus) {
+	event.Subscribe(bus, func(ctx context.Context, e event.Event) error {
+		if ev, ok := fromDomain(e); ok {
+			s.Record(ctx, ev)
+		}
+		return nil
+	})
+}
+
+// fromDomain returns the audit event for a domain event, if it is audited.
+func fromDomain(e event.Event) (Event, bool) {
+	if f, ok := e.(event.Failed); ok {
+		// 가입 실패는 아직 사용자가 없어 기록하지 않는다
+		t, ok := eventTypes[f.Action]
+		if !ok || f.Action == event.NameUserRegistered {
+			return Event{}, false
+		}
+		return Event{Type: t, UserID: f.UserID, Outcome: f.Outcome, Details: f.Details}, true
+	}
+	t, ok := eventTypes[e.EventName()]
+	if !ok {
+		return Event{}, false
+	}
+	ev := Event{Type: t, Outcome: OutcomeSuccess}
+	switch e := e.(type) {
+	case event.UserRegistered:
+		ev.UserID = e.UserID
+	case event.LoggedIn:
+		ev.UserID = e.UserID
+		ev.Details = map[string]string{"method": e.Method}
+	case event.TokenRefreshed:
+		ev.UserID = e.UserID
+	case event.LoggedOut:
+		ev.UserID = e.UserID
+	case event.PasswordChanged:
+		ev.UserID = e.UserID
+	case event.PasswordResetRequested:
+		ev.UserID = e.UserID
+	case event.PasswordReset:
+		ev.UserID = e.UserID
+	case event.ProfileUpdated:
+		ev.UserID = e.UserID
+		if e.PhoneChanged {
+			ev.Details = map[string]string{"phone": utils.MaskPhone(e.PhoneNumber)}
+		}
+	case event.PhoneVerified:
+		ev.UserID = e.UserID
+	case event.AccountDeleted:
+		ev.UserID = e.UserID
+	}
+	return ev, true
+}
@@ -5,10 +5,17 @@ import (
 	"auth/internal/dto"
 	"auth/internal/entity"
 	"auth/internal/repository"
+	"auth/internal/service/audit"
 	"auth/internal/service/email"
+	"auth/internal/service/event"
+	"auth/internal/service/link"
+	"auth/internal/service/password"
+	"auth/internal/service/phone"
+	"auth/internal/service/sms"
+	"auth/internal/tracing"
 	"auth/pkg/utils"
 	"context"
-	"errors"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"time"
@@ -23,21 +30,227 @@ type AuthService struct {
 	profileRepo  repository.ProfileRepository
 	jwtService   *JwtService
 	emailService *email.Service
+	hasher       utils.PasswordHasher
+	policy       password.Policy
+	breaches     password.BreachChecker
+	phoneRepo    repository.PhoneVerificationRepository
+	smsSender    sms.Sender
+	outbox       repository.OutboxRepository
+	links        *link.Builder
+	phones       *phone.Parser
+	auditLog     *audit.Service
+	events       *event.Bus
+}
+
+// AuthServiceOption configures optional dependencies of AuthService.
+type AuthServiceOption func(*AuthService)
+
+// WithPasswordHasher sets the hasher used to hash and verify passwords.
+func WithPasswordHasher(hasher utils.PasswordHasher) AuthServiceOption {
+	return func(s *AuthService) {
+		s.hasher = hasher
+	}
+}
+
+// WithPasswordPolicy sets the policy new passwords must satisfy.
+func WithPasswordPolicy(policy password.Policy) AuthServiceOption {
+	return func(s *AuthService) {
+		s.policy = policy
+	}
+}
+
+// WithBreachChecker rejects new passwords found in a breached password corpus.
+func WithBreachChecker(checker password.BreachChecker) AuthServiceOption {
+	return func(s *AuthService) {
+		s.breaches = checker
+	}
+}
+
+// WithPhoneVerification enables SMS verification codes, required by FindEmail and phone number changes.
+func WithPhoneVerification(repo repository.PhoneVerificationRepository, sender sms.Sender) AuthServiceOption {
+	return func(s *AuthService) {
+		s.phoneRepo = repo
+		s.smsSender = sender
+	}
+}
+
+// WithOutbox queues emails in the transactional outbox instead of sending them during the request.
+func WithOutbox(repo repository.OutboxRepository) AuthServiceOption {
+	return func(s *AuthService) {
+		s.outbox = repo
+	}
+}
+
+// WithLinkBuilder sets the builder of the links sent in emails.
+// By default links point to http://127.0.0.1:3000 and are not signed.
+func WithLinkBuilder(b *link.Builder) AuthServiceOption {
+	return func(s *AuthService) {
+		s.links = b
+	}
+}
+
+// WithPhoneParser sets the parser used to normalize phone numbers before they are stored or looked up.
+// The default parser reads numbers without a country code in phone.DefaultRegion.
+func WithPhoneParser(p *phone.Parser) AuthServiceOption {
+	return func(s *AuthService) {
+		s.phones = p
+	}
 }
 
 // NewAuthService creates a new AuthService with its dependencies.
-func NewAuthService(dbPool *pgxpool.Pool, userRepo repository.UserRepository, profileRepo repository.ProfileRepository, jwtService *JwtService, emailService *email.Service) *AuthService {
-	return &AuthService{dbPool, userRepo, profileRepo, jwtService, emailService}
+func NewAuthService(dbPool *pgxpool.Pool, userRepo repository.UserRepository, profileRepo repository.ProfileRepository, jwtService *JwtService, emailService *email.Service, opts ...AuthServiceOption) *AuthService {
+	s := &AuthService{
+		dbPool:       dbPool,
+		userRepo:     userRepo,
+		profileRepo:  profileRepo,
+		jwtService:   jwtService,
+		emailService: emailService,
+		hasher:       utils.DefaultPasswordHasher,
+		policy:       password.DefaultPolicy,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.hasher.Algorithm() == utils.AlgorithmBcrypt {
+		// bcrypt 가 해시하지 못하는 긴 비밀번호는 정책 위반으로 거부한다
+		s.policy = s.policy.LimitBytes(utils.BcryptMaxPasswordBytes)
+	}
+	if s.links == nil {
+		s.links, _ = link.NewBuilder("http://127.0.0.1:3000", nil, nil, nil) // 고정 주소라 에러 없음
+	}
+	if s.phones == nil {
+		s.phones = phone.MustNewParser(phone.DefaultRegion)
+	}
+	if s.events == nil {
+		s.events = event.NewBus()
+		SubscribeMetrics(s.events)
+		s.SubscribeNotifications(s.events)
+	}
+	return s
+}
+
+// VerifyLinkRedirect checks that redirect came unaltered from a link built for flow with token
+// and is still allowed. An empty redirect is always valid.
+func (s *AuthService) VerifyLinkRedirect(flow, token, redirect, signature string) error {
+	if redirect == "" {
+		return nil
+	}
+	if err := s.links.ValidateRedirect(redirect); err != nil {
+		return err
+	}
+	return s.links.Verify(flow, map[string]string{"token": token, link.ParamRedirect: redirect}, signature)
+}
+
+// verifyPassword checks the password against the stored hash.
+func (s *AuthService) verifyPassword(password, hash string) bool {
+	ok, err := s.hasher.Verify(password, hash)
+	if err != nil {
+		slog.Warn("verifyPassword: verify failed", "error", err)
+		return false
+	}
+	return ok
+}
+
+// rehashIfNeeded upgrades an outdated password hash after a successful login.
+// Failures are logged and do not affect the login.
+func (s *AuthService) rehashIfNeeded(ctx context.Context, userID int64, password, hash string) {
+	if !s.hasher.NeedsRehash(hash) {
+		return
+	}
+	newHash, err := s.hasher.Hash(password)
+	if err != nil {
+		slog.WarnContext(ctx, "rehashIfNeeded: hash failed", "userID", userID, "error", err)
+		return
+	}
+	if err := s.userRepo.UpdatePassword(ctx, userID, newHash); err != nil {
+		slog.WarnContext(ctx, "rehashIfNeeded: update password failed", "userID", userID, "error", err)
+		return
+	}
+	slog.InfoContext(ctx, "rehashIfNeeded: password hash upgraded", "userID", userID, "algorithm", s.hasher.Algorithm())
+}
+
+// checkNewPassword validates a new password against the password policy, the breached password
+// corpus and, for existing users, the current hash and the last HistorySize hashes.
+// It returns a *password.PolicyError listing every violation.
+func (s *AuthService) checkNewPassword(ctx context.Context, userID int64, currentHash, newPassword string, personalInfo ...string) error {
+	violations := s.policy.Validate(newPassword, personalInfo...)
+	if s.breaches != nil {
+		count, err := s.breaches.Count(ctx, newPassword)
+		if err != nil {
+			// 유출 DB 조회 실패 시에는 가입/변경을 막지 않는다 (fail open)
+			slog.WarnContext(ctx, "checkNewPassword: breach check failed", "error", err)
+		} else if count > 0 {
+			violations = append(violations, password.Violation{
+				Rule:    password.RuleBreached,
+				Message: "password has appeared in a data breach; choose a different password",
+			})
+		}
+	}
+	if userID != 0 && s.policy.HistorySize > 0 {
+		hashes := []string{currentHash}
+		history, err := s.userRepo.FindPasswordHistory(ctx, userID, s.policy.HistorySize)
+		if err != nil {
+			return err
+		}
+		for _, h := range history {
+			hashes = append(hashes, h.PasswordHash)
+		}
+		for _, h := range hashes {
+			if h != "" && s.verifyPassword(newPassword, h) {
+				violations = append(violations, password.Violation{
+					Rule:    password.RuleReused,
+					Message: fmt.Sprintf("password must differ from the last %d passwords", s.policy.HistorySize),
+				})
+				break
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return &password.PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// sendEmail queues the email in the outbox within tx, or sends it right away when no outbox is configured.
+func (s *AuthService) sendEmail(ctx context.Context, tx interface{}, to string, msg *email.Rendered) error {
+	if s.outbox == nil {
+		return s.emailService.Send(ctx, to, msg)
+	}
+	payload, err := json.Marshal(email.Payload{To: to, Subject: msg.Subject, Text: msg.Text, HTML: msg.HTML})
+	if err != nil {
+		return err
+	}
+	return s.outbox.EnqueueTx(ctx, tx, &entity.OutboxMessageEntity{Kind: email.OutboxKind, Payload: string(payload)})
+}
+
+// emailLocale returns the email locale for the user: the profile locale if set, otherwise
+// the request's Accept-Language, otherwise the default locale.
+func (s *AuthService) emailLocale(ctx context.Context, userID int64, acceptLanguage string) string {
+	var preferred string
+	profile, err := s.profileRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		slog.WarnContext(ctx, "emailLocale: find profile failed", "userId", userID, "error", err)
+	} else if profile != nil {
+		preferred = profile.Locale
+	}
+	return s.emailService.Locale(preferred, acceptLanguage)
 }
 
 // RegisterUser registers a new user and returns the registration response.
-func (s *AuthService) RegisterUser(ctx context.Context, req *dto.RegisterRequest) (*dto.RegisterResponse, error) {
+func (s *AuthService) RegisterUser(ctx context.Context, req *dto.RegisterRequest) (_ *dto.RegisterResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.RegisterUser")
+	defer func() { tracing.End(span, err) }()
+	defer func() { s.publishFailed(ctx, event.NameUserRegistered, 0, err, nil) }()
+	phoneNumber, err := s.phones.Normalize(req.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
 	var tx interface{}
 	var commit, rollback func() error
 	if s.dbPool != nil {
 		pgxTx, err := s.dbPool.Begin(ctx)
 		if err != nil {
-			slog.Error("RegisterUser: begin tx failed", "error", err)
+			slog.ErrorContext(ctx, "RegisterUser: begin tx failed", "error", err)
 			return nil, err
 		}
 		tx = pgxTx
@@ -48,7 +261,6 @@ func (s *AuthService) RegisterUser(ctx context.Context, req *dto.RegisterRequest
 		commit = func() error { return nil }
 		rollback = func() error { return nil }
 	}
-	var err error
 	defer func() {
 		if p := recover(); p != nil {
 			_ = rollback()
@@ -61,18 +273,22 @@ func (s *AuthService) RegisterUser(ctx context.Context, req *dto.RegisterRequest
 	// 1. 이메일 중복 확인 - 트랜잭션 내에서 확인
 	existingUser, err := s.userRepo.FindByEmailTx(ctx, tx, req.Email)
 	if err != nil {
-		slog.Error("RegisterUser: find email failed", "error", err)
+		slog.ErrorContext(ctx, "RegisterUser: find email failed", "error", err)
 		return nil, err
 	}
 	if existingUser != nil {
-		slog.Warn("RegisterUser: email exists", "email", req.Email)
-		return nil, errors.New("email already exists")
+		slog.WarnContext(ctx, "RegisterUser: email exists", "email", req.Email)
+		return nil, ErrEmailExists
 	}
 
-	// 2. 비밀번호 해시
-	hashed, err := utils.HashPassword(req.Password)
+	// 2. 비밀번호 정책 확인 및 해시
+	if err = s.checkNewPassword(ctx, 0, "", req.Password, req.Email, req.Name); err != nil {
+		slog.WarnContext(ctx, "RegisterUser: password policy violation", "error", err)
+		return nil, err
+	}
+	hashed, err := s.hasher.Hash(req.Password)
 	if err != nil {
-		slog.Error("RegisterUser: hash password failed", "error", err)
+		slog.ErrorContext(ctx, "RegisterUser: hash password failed", "error", err)
 		return nil, err
 	}
 
@@ -90,7 +306,7 @@ func (s *AuthService) RegisterUser(ctx context.Context, req *dto.RegisterRequest
 	newUserID, err := s.userRepo.CreateTx(ctx, tx, userEntity)
 	if err != nil {
 		_ = rollback()
-		slog.Error("RegisterUser: create user failed, rollback", "error", err)
+		slog.ErrorContext(ctx, "RegisterUser: create user failed, rollback", "error", err)
 		return nil, err
 	}
 
@@ -100,23 +316,36 @@ func (s *AuthService) RegisterUser(ctx context.Context, req *dto.RegisterRequest
 		Name:        req.Name,
 		BirthDate:   parseDate(req.BirthDate),
 		GenderCode:  entity.GenderCode(req.GenderCode),
-		PhoneNumber: req.PhoneNumber,
+		PhoneNumber: phoneNumber,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 	err = s.profileRepo.CreateTx(ctx, tx, profileEntity)
 	if err != nil {
 		_ = rollback()
-		slog.Error("RegisterUser: create profile failed", "error", err)
+		slog.ErrorContext(ctx, "RegisterUser: create profile failed", "error", err)
 		return nil, err
 	}
+	if s.policy.HistorySize > 0 {
+		if err = s.userRepo.InsertPasswordHistoryTx(ctx, tx, newUserID, hashed, s.policy.HistorySize); err != nil {
+			_ = rollback()
+			slog.ErrorContext(ctx, "RegisterUser: insert password history failed", "error", err)
+			return nil, err
+		}
+	}
 
 	if err := commit(); err != nil {
-		slog.Error("RegisterUser: commit failed", "error", err)
+		slog.ErrorContext(ctx, "RegisterUser: commit failed", "error", err)
 		return nil, err
 	}
+	s.events.Publish(ctx, event.UserRegistered{
+		UserID:      newUserID,
+		Email:       userEntity.Email,
+		Name:        profileEntity.Name,
+		PhoneNumber: profileEntity.PhoneNumber,
+	})
 
-	slog.Info("RegisterUser: success", "userID", newUserID, "email", req.Email)
+	slog.InfoContext(ctx, "RegisterUser: success", "userID", newUserID, "email", req.Email)
 	result := &dto.RegisterResponse{
 		Email:       userEntity.Email,
 		Name:        profileEntity.Name,
@@ -128,6 +357,18 @@ func (s *AuthService) RegisterUser(ctx context.Context, req *dto.RegisterRequest
 	return result, nil
 }
 
+// personalInfo returns the user's email and profile name for the password policy.
+func (s *AuthService) personalInfo(ctx context.Context, user *entity.UserEntity) []string {
+	info := []string{user.Email}
+	profile, err := s.profileRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		slog.WarnContext(ctx, "personalInfo: find profile failed", "userId", user.ID, "error", err)
+	} else if profile != nil {
+		info = append(info, profile.Name)
+	}
+	return info
+}
+
 // 날짜 파싱 유틸
 func parseDate(dateStr string) time.Time {
 	if dateStr == "" {
@@ -138,41 +379,63 @@ func parseDate(dateStr string) time.Time {
 }
 
 // Login authenticates a user and returns login response with tokens.
-func (s *AuthService) Login(ctx context.Context, cmd *dto.LoginRequest, deviceInfo string) (*dto.LoginResponse, error) {
+func (s *AuthService) Login(ctx context.Context, cmd *dto.LoginRequest, deviceInfo string) (_ *dto.LoginResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer func() { tracing.End(span, err) }()
+	var userID int64
+	failDetails := map[string]string{"method": event.MethodPassword}
+	defer func() { s.publishFailed(ctx, event.NameLoggedIn, userID, err, failDetails) }()
 	// 1. 이메일로 사용자 찾기
 	u, err := s.userRepo.FindByEmail(ctx, cmd.Email)
 	if err != nil {
-		slog.Error("Login: find by email failed", "error", err)
+		slog.ErrorContext(ctx, "Login: find by email failed", "error", err)
 		return nil, err
 	}
 	if u == nil {
-		slog.Warn("Login: user not found", "email", cmd.Email)
-		return nil, errors.New("user not found")
+		slog.WarnContext(ctx, "Login: user not found", "email", cmd.Email)
+		// 없는 계정에 대한 시도도 추적할 수 있도록 가린 주소를 남긴다
+		failDetails["email"] = utils.MaskEmail(cmd.Email)
+		return nil, ErrInvalidCredentials
 	}
+	userID = u.ID
 
 	// 2. 비밀번호 검증
-	if !utils.CheckPasswordHash(cmd.Password, u.PasswordHash) {
-		slog.Warn("Login: invalid password", "email", cmd.Email)
-		return nil, errors.New("invalid password")
+	if !s.verifyPassword(cmd.Password, u.PasswordHash) {
+		slog.WarnContext(ctx, "Login: invalid password", "email", cmd.Email)
+		return nil, ErrInvalidCredentials
+	}
+	// 정지 여부는 비밀번호가 맞을 때만 알려준다
+	if u.DisabledAt != nil {
+		slog.WarnContext(ctx, "Login: account disabled", "userID", u.ID)
+		return nil, ErrAccountDisabled
 	}
+	// 오래된 알고리즘/파라미터의 해시는 로그인 성공 시 재해시
+	s.rehashIfNeeded(ctx, u.ID, cmd.Password, u.PasswordHash)
+
+	return s.issueTokens(ctx, u, deviceInfo, event.MethodPassword)
+}
 
-	// 3. 기존 device의 refresh token 삭제 (동일 디바이스 중복 로그인 방지)
-	err = s.userRepo.DeleteByUserIDAndDevice(ctx, u.ID, deviceInfo)
+// issueTokens issues an access token and a device-bound refresh token for the user,
+// replacing any refresh token previously issued to the same device, and publishes
+// event.LoggedIn for the login method.
+func (s *AuthService) issueTokens(ctx context.Context, u *entity.UserEntity, deviceInfo, method string) (*dto.LoginResponse, error) {
+	// 기존 device의 refresh token 삭제 (동일 디바이스 중복 로그인 방지)
+	err := s.userRepo.DeleteByUserIDAndDevice(ctx, u.ID, deviceInfo)
 	if err != nil {
-		slog.Error("Login: delete old refresh token failed", "userID", u.ID, "error", err)
+		slog.ErrorContext(ctx, "issueTokens: delete old refresh token failed", "userID", u.ID, "error", err)
 		return nil, err
 	}
 
-	// 4. JWT 토큰 생성
+	// JWT 토큰 생성
 	accessToken, err := s.jwtService.GenerateToken(u.ID)
 	if err != nil {
-		slog.Error("Login: generate access token failed", "userID", u.ID, "error", err)
+		slog.ErrorContext(ctx, "issueTokens: generate access token failed", "userID", u.ID, "error", err)
 		return nil, err
 	}
-	// 5. Refresh Token 생성 및 저장
+	// Refresh Token 생성 및 저장
 	refreshToken, err := s.jwtService.GenerateRefreshToken(u.ID, deviceInfo)
 	if err != nil {
-		slog.Error("Login: generate refresh token failed", "userID", u.ID, "error", err)
+		slog.ErrorContext(ctx, "issueTokens: generate refresh token failed", "userID", u.ID, "error", err)
 		return nil, err
 	}
 
@@ -185,9 +448,11 @@ func (s *AuthService) Login(ctx context.Context, cmd *dto.LoginRequest, deviceIn
 		ExpiredAt:  time.Now().Add(7 * 24 * time.Hour),
 	})
 	if err != nil {
-		slog.Error("Login: insert refresh token failed", "userID", u.ID, "error", err)
+		slog.ErrorContext(ctx, "issueTokens: insert refresh token failed", "userID", u.ID, "error", err)
 		return nil, err
 	}
+	newDevice := s.recordDevice(ctx, u, deviceInfo)
+	s.events.Publish(ctx, event.LoggedIn{UserID: u.ID, Email: u.Email, Method: method, DeviceInfo: deviceInfo, NewDevice: newDevice})
 
 	return &dto.LoginResponse{
 		UserID:       u.ID,
@@ -197,31 +462,149 @@ func (s *AuthService) Login(ctx context.Context, cmd *dto.LoginRequest, deviceIn
 	}, nil
 }
 
+// magicLinkExpireMinutes is how long a magic link stays valid.
+const magicLinkExpireMinutes = 15
+
+// RequestMagicLink emails a single-use sign-in link bound to the requesting device.
+// redirect, if set, must be in the redirect allowlist and is carried in the link.
+// acceptLanguage selects the email language when the user has no preferred locale.
+func (s *AuthService) RequestMagicLink(ctx context.Context, email, redirect, deviceInfo, acceptLanguage string) error {
+	ctx, span := tracing.Start(ctx, "AuthService.RequestMagicLink")
+	defer span.End()
+	if err := s.links.ValidateRedirect(redirect); err != nil {
+		slog.WarnContext(ctx, "RequestMagicLink: redirect not allowed", "redirect", redirect)
+		return err
+	}
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		slog.ErrorContext(ctx, "RequestMagicLink: find user failed", "error", err)
+		return err
+	}
+	if user == nil {
+		slog.WarnContext(ctx, "RequestMagicLink: user not found", "email", email)
+		return ErrUserNotFound
+	}
+
+	token := utils.GenerateRandomString(64)
+	err = s.userRepo.SaveMagicLinkToken(ctx, &entity.MagicLinkTokenEntity{
+		UserID:     user.ID,
+		TokenHash:  utils.HashToken(token),
+		DeviceInfo: deviceInfo,
+		ExpiredAt:  time.Now().Add(magicLinkExpireMinutes * time.Minute),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "RequestMagicLink: save token failed", "error", err)
+		return err
+	}
+
+	loginLink, err := s.links.Build(link.FlowMagicLink, map[string]string{"token": token}, redirect)
+	if err != nil {
+		slog.ErrorContext(ctx, "RequestMagicLink: build link failed", "error", err)
+		return err
+	}
+	msg, err := s.emailService.RenderMagicLink(s.emailLocale(ctx, user.ID, acceptLanguage), loginLink, magicLinkExpireMinutes)
+	if err != nil {
+		slog.ErrorContext(ctx, "RequestMagicLink: render email failed", "error", err)
+		return err
+	}
+	if err := s.sendEmail(ctx, nil, email, msg); err != nil {
+		slog.ErrorContext(ctx, "RequestMagicLink: send email failed", "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "RequestMagicLink: success", "userId", user.ID)
+	return nil
+}
+
+// VerifyMagicLink exchanges a magic link token for access and refresh tokens.
+// The token must be unused, unexpired and presented from the device that requested it.
+func (s *AuthService) VerifyMagicLink(ctx context.Context, token, deviceInfo string) (_ *dto.LoginResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.VerifyMagicLink")
+	defer func() { tracing.End(span, err) }()
+	var userID int64
+	defer func() {
+		s.publishFailed(ctx, event.NameLoggedIn, userID, err, map[string]string{"method": event.MethodMagicLink})
+	}()
+	tokenHash := utils.HashToken(token)
+	link, err := s.userRepo.FindByMagicLinkToken(ctx, tokenHash)
+	if err != nil {
+		slog.ErrorContext(ctx, "VerifyMagicLink: find token failed", "error", err)
+		return nil, err
+	}
+	if link == nil || link.Used || time.Now().After(link.ExpiredAt) {
+		slog.WarnContext(ctx, "VerifyMagicLink: invalid, expired, or used token")
+		return nil, ErrInvalidMagicLink
+	}
+	userID = link.UserID
+	if link.DeviceInfo != deviceInfo {
+		slog.WarnContext(ctx, "VerifyMagicLink: device mismatch", "userId", link.UserID)
+		return nil, ErrMagicLinkDeviceMismatch
+	}
+	// 조건부 UPDATE 로 사용 처리하여, 동시에 같은 링크로 요청해도 한 번만 통과한다
+	consumed, err := s.userRepo.ConsumeMagicLinkToken(ctx, tokenHash)
+	if err != nil {
+		slog.ErrorContext(ctx, "VerifyMagicLink: consume token failed", "error", err)
+		return nil, err
+	}
+	if !consumed {
+		slog.WarnContext(ctx, "VerifyMagicLink: token already used or expired", "userId", link.UserID)
+		return nil, ErrInvalidMagicLink
+	}
+
+	user, err := s.userRepo.FindByID(ctx, link.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "VerifyMagicLink: find user failed", "error", err)
+		return nil, err
+	}
+	if user == nil {
+		slog.WarnContext(ctx, "VerifyMagicLink: user not found", "userId", link.UserID)
+		return nil, ErrInvalidMagicLink
+	}
+	if user.DisabledAt != nil {
+		slog.WarnContext(ctx, "VerifyMagicLink: account disabled", "userId", user.ID)
+		return nil, ErrAccountDisabled
+	}
+	result, err := s.issueTokens(ctx, user, deviceInfo, event.MethodMagicLink)
+	if err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "VerifyMagicLink: success", "userId", user.ID)
+	return result, nil
+}
+
 // RefreshToken generates new access and refresh tokens using a valid refresh token.
-func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (string, string, error) {
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (_, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.RefreshToken")
+	defer func() { tracing.End(span, err) }()
+	var failedUserID int64
+	defer func() { s.publishFailed(ctx, event.NameTokenRefreshed, failedUserID, err, nil) }()
 	userID, deviceInfo, err := s.jwtService.ValidateRefreshToken(refreshToken)
 	if err != nil {
-		slog.Warn("RefreshToken: invalid refresh token", "error", err)
-		return "", "", fmt.Errorf("invalid refresh token: %w", err)
+		slog.WarnContext(ctx, "RefreshToken: invalid refresh token", "error", err)
+		return "", "", err
 	}
+	failedUserID = userID
 	rtRecord, err := s.userRepo.FindByUserDeviceAndToken(ctx, userID, deviceInfo, refreshToken)
-	if err != nil || rtRecord == nil {
-		slog.Warn("RefreshToken: token not found", "userID", userID)
-		return "", "", errors.New("refresh token not found")
+	if err != nil {
+		slog.ErrorContext(ctx, "RefreshToken: find token failed", "userID", userID, "error", err)
+		return "", "", err
+	}
+	if rtRecord == nil {
+		slog.WarnContext(ctx, "RefreshToken: token not found", "userID", userID)
+		return "", "", fmt.Errorf("%w: refresh token revoked", ErrInvalidToken)
 	}
 	if time.Now().After(rtRecord.ExpiredAt) {
 		if delErr := s.userRepo.DeleteRefreshToken(ctx, userID, refreshToken); delErr != nil {
-			slog.Warn("RefreshToken: token expired, delete failed", "userID", userID, "error", delErr)
+			slog.WarnContext(ctx, "RefreshToken: token expired, delete failed", "userID", userID, "error", delErr)
 		}
-		slog.Warn("RefreshToken: token expired", "userID", userID)
-		return "", "", errors.New("refresh token expired")
+		slog.WarnContext(ctx, "RefreshToken: token expired", "userID", userID)
+		return "", "", ErrTokenExpired
 	}
 	// 기존 refresh token 삭제(재발급 시)
 	_ = s.userRepo.DeleteRefreshToken(ctx, userID, refreshToken)
 	// 새 refresh token 발급 및 저장
 	newRefreshToken, err := s.jwtService.GenerateRefreshToken(userID, deviceInfo)
 	if err != nil {
-		slog.Error("RefreshToken: generate new refresh token failed", "userId", userID, "error", err)
+		slog.ErrorContext(ctx, "RefreshToken: generate new refresh token failed", "userId", userID, "error", err)
 		return "", "", err
 	}
 	rt := &entity.RefreshTokenEntity{
@@ -233,48 +616,78 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (st
 	}
 	err = s.userRepo.InsertRefreshToken(ctx, rt)
 	if err != nil {
-		slog.Error("RefreshToken: insert new refresh token failed", "userId", userID, "error", err)
+		slog.ErrorContext(ctx, "RefreshToken: insert new refresh token failed", "userId", userID, "error", err)
 		return "", "", err
 	}
 	accessToken, err := s.jwtService.GenerateToken(userID)
 	if err != nil {
-		slog.Error("RefreshToken: generate access token failed", "userId", userID, "error", err)
+		slog.ErrorContext(ctx, "RefreshToken: generate access token failed", "userId", userID, "error", err)
 		return "", "", err
 	}
-	slog.Info("RefreshToken: success", "userId", userID)
+	s.events.Publish(ctx, event.TokenRefreshed{UserID: userID})
+	slog.InfoContext(ctx, "RefreshToken: success", "userId", userID)
 	return accessToken, newRefreshToken, nil
 }
 
-// FindEmail finds a user's email by phone number.
+// FindEmail finds a user's email by phone number, after verifying the SMS code sent to it.
 func (s *AuthService) FindEmail(ctx context.Context, cmd *dto.FindEmailRequest) (*dto.FindEmailResponse, error) {
-	profile, err := s.profileRepo.FindByPhoneNumber(ctx, cmd.PhoneNumber)
-	if err != nil || profile == nil {
-		slog.Warn("FindEmail: profile not found", "phone", cmd.PhoneNumber)
-		return nil, errors.New("not found")
+	ctx, span := tracing.Start(ctx, "AuthService.FindEmail")
+	defer span.End()
+	phoneNumber, err := s.phones.Normalize(cmd.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyPhoneCode(ctx, phoneNumber, PhonePurposeFindEmail, cmd.Code); err != nil {
+		slog.WarnContext(ctx, "FindEmail: phone verification failed", "phone", utils.MaskPhone(phoneNumber), "error", err)
+		return nil, err
+	}
+	profile, err := s.profileRepo.FindByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		slog.ErrorContext(ctx, "FindEmail: find profile failed", "error", err)
+		return nil, err
+	}
+	if profile == nil {
+		slog.WarnContext(ctx, "FindEmail: profile not found", "phone", utils.MaskPhone(phoneNumber))
+		return nil, ErrUserNotFound
 	}
 
 	user, err := s.userRepo.FindByID(ctx, profile.UserID)
-	if err != nil || user == nil {
-		slog.Warn("FindEmail: user not found", "userId", profile.UserID)
-		return nil, errors.New("not found")
+	if err != nil {
+		slog.ErrorContext(ctx, "FindEmail: find user failed", "error", err)
+		return nil, err
+	}
+	if user == nil {
+		slog.WarnContext(ctx, "FindEmail: user not found", "userId", profile.UserID)
+		return nil, ErrUserNotFound
 	}
 
-	slog.Info("FindEmail: success", "userId", user.ID)
+	slog.InfoContext(ctx, "FindEmail: success", "userId", user.ID)
 	return &dto.FindEmailResponse{
 		Email: utils.MaskEmail(user.Email),
 	}, nil
 }
 
 // ForgotPassword sends a password reset email to the user and saves the reset token.
-func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
+// redirect, if set, must be in the redirect allowlist and is carried in the link.
+// acceptLanguage selects the email language when the user has no preferred locale.
+func (s *AuthService) ForgotPassword(ctx context.Context, email, redirect, acceptLanguage string) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.ForgotPassword")
+	defer func() { tracing.End(span, err) }()
+	var userID int64
+	defer func() { s.publishFailed(ctx, event.NamePasswordResetRequested, userID, err, nil) }()
+	if err := s.links.ValidateRedirect(redirect); err != nil {
+		slog.WarnContext(ctx, "ForgotPassword: redirect not allowed", "redirect", redirect)
+		return err
+	}
+	var tx interface{}
 	var commit, rollback func() error
-	var err error
 	if s.dbPool != nil {
 		pgxTx, err2 := s.dbPool.Begin(ctx)
 		if err2 != nil {
-			slog.Error("ForgotPassword: begin tx failed", "error", err2)
+			slog.ErrorContext(ctx, "ForgotPassword: begin tx failed", "error", err2)
 			return err2
 		}
+		tx = pgxTx
 		commit = func() error { return pgxTx.Commit(ctx) }
 		rollback = func() error { return pgxTx.Rollback(ctx) }
 	} else {
@@ -291,48 +704,65 @@ func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
 	}()
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
-		slog.Error("ForgotPassword: find user failed", "error", err)
+		slog.ErrorContext(ctx, "ForgotPassword: find user failed", "error", err)
 		return err
 	}
 	if user == nil {
-		slog.Warn("ForgotPassword: user not found", "email", email)
-		return errors.New("user not found")
+		slog.WarnContext(ctx, "ForgotPassword: user not found", "email", email)
+		return ErrUserNotFound
 	}
+	userID = user.ID
 	// 토큰 생성 (간단 예시, 실제로는 더 안전하게)
 	token := utils.GenerateRandomString(32)
 	expireMinutes := 30
 	expiredAt := time.Now().Add(time.Duration(expireMinutes) * time.Minute)
-	err = s.userRepo.SavePasswordResetToken(ctx, user.ID, token, expiredAt)
+	err = s.userRepo.SavePasswordResetTokenTx(ctx, tx, user.ID, token, expiredAt)
 	if err != nil {
-		slog.Error("ForgotPassword: save token failed", "error", err)
+		slog.ErrorContext(ctx, "ForgotPassword: save token failed", "error", err)
 		return err
 	}
 
-	// 이메일 전송
-	resetLink := fmt.Sprintf("http://127.0.0.1:3000/reset-password.html?token=%s", token)
-	err = s.emailService.SendPasswordReset(email, resetLink, expireMinutes)
+	// 이메일 전송 (outbox 사용 시 같은 트랜잭션에 기록되고 커밋 후 발송된다)
+	resetLink, err := s.links.Build(link.FlowPasswordReset, map[string]string{"token": token}, redirect)
 	if err != nil {
-		slog.Error("ForgotPassword: send email failed", "error", err)
+		slog.ErrorContext(ctx, "ForgotPassword: build link failed", "error", err)
+		return err
+	}
+	msg, err := s.emailService.RenderPasswordReset(s.emailLocale(ctx, user.ID, acceptLanguage), resetLink, expireMinutes)
+	if err != nil {
+		slog.ErrorContext(ctx, "ForgotPassword: render email failed", "error", err)
+		return err
+	}
+	err = s.sendEmail(ctx, tx, email, msg)
+	if err != nil {
+		slog.ErrorContext(ctx, "ForgotPassword: send email failed", "error", err)
 		return err
 	}
 
 	if err := commit(); err != nil {
-		slog.Error("ForgotPassword: commit failed", "error", err)
+		slog.ErrorContext(ctx, "ForgotPassword: commit failed", "error", err)
 		return err
 	}
-	slog.Info("ForgotPassword: success", "userId", user.ID)
+	s.events.Publish(ctx, event.PasswordResetRequested{UserID: user.ID, Email: user.Email})
+	slog.InfoContext(ctx, "ForgotPassword: success", "userId", user.ID)
 	return nil
 }
 
 // ResetPassword resets the user's password using the provided reset token.
-func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.ResetPassword")
+	defer func() { tracing.End(span, err) }()
+	var userID int64
+	defer func() { s.publishFailed(ctx, event.NamePasswordReset, userID, err, nil) }()
+	var tx interface{}
 	var commit, rollback func() error
 	if s.dbPool != nil {
 		pgxTx, err := s.dbPool.Begin(ctx)
 		if err != nil {
-			slog.Error("ResetPassword: begin tx failed", "error", err)
+			slog.ErrorContext(ctx, "ResetPassword: begin tx failed", "error", err)
 			return err
 		}
+		tx = pgxTx
 		commit = func() error { return pgxTx.Commit(ctx) }
 		rollback = func() error { return pgxTx.Rollback(ctx) }
 	} else {
@@ -340,7 +770,6 @@ func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword stri
 		commit = func() error { return nil }
 		rollback = func() error { return nil }
 	}
-	var err error
 	defer func() {
 		if p := recover(); p != nil {
 			_ = rollback()
@@ -352,64 +781,103 @@ func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword stri
 
 	resetInfo, err := s.userRepo.FindByPasswordResetToken(ctx, token)
 	if err != nil {
-		slog.Error("ResetPassword: find token failed", "error", err)
+		slog.ErrorContext(ctx, "ResetPassword: find token failed", "error", err)
 		return err
 	}
 	if resetInfo == nil || time.Now().After(resetInfo.ExpiredAt) || resetInfo.Used {
-		slog.Warn("ResetPassword: invalid, expired, or used token", "token", token)
-		return errors.New("invalid, expired, or already used token")
+		slog.WarnContext(ctx, "ResetPassword: invalid, expired, or used token")
+		return ErrInvalidResetToken
+	}
+	userID = resetInfo.UserID
+	user, err := s.userRepo.FindByID(ctx, resetInfo.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "ResetPassword: find user failed", "error", err)
+		return err
+	}
+	if user == nil {
+		slog.WarnContext(ctx, "ResetPassword: user not found", "userId", resetInfo.UserID)
+		return ErrInvalidResetToken
+	}
+	if err = s.checkNewPassword(ctx, user.ID, user.PasswordHash, newPassword, s.personalInfo(ctx, user)...); err != nil {
+		slog.WarnContext(ctx, "ResetPassword: password policy violation", "userId", user.ID, "error", err)
+		return err
 	}
-	hashed, err := utils.HashPassword(newPassword)
+	hashed, err := s.hasher.Hash(newPassword)
 	if err != nil {
-		slog.Error("ResetPassword: hash failed", "error", err)
+		slog.ErrorContext(ctx, "ResetPassword: hash failed", "error", err)
 		return err
 	}
-	if err := s.userRepo.UpdatePassword(ctx, resetInfo.UserID, hashed); err != nil {
-		slog.Error("ResetPassword: update password failed", "error", err)
+	if err := s.userRepo.UpdatePasswordTx(ctx, tx, resetInfo.UserID, hashed); err != nil {
+		slog.ErrorContext(ctx, "ResetPassword: update password failed", "error", err)
 		return err
 	}
+	// 이력 저장에 실패하면 같은 비밀번호를 다시 쓸 수 있게 되므로 변경 전체를 되돌린다
+	if s.policy.HistorySize > 0 {
+		if err := s.userRepo.InsertPasswordHistoryTx(ctx, tx, resetInfo.UserID, hashed, s.policy.HistorySize); err != nil {
+			slog.ErrorContext(ctx, "ResetPassword: insert password history failed", "error", err)
+			return err
+		}
+	}
 	// used=true로 업데이트
 	if err := s.userRepo.ExpirePasswordResetToken(ctx, token); err != nil {
-		slog.Error("ResetPassword: expire token failed", "error", err)
+		slog.ErrorContext(ctx, "ResetPassword: expire token failed", "error", err)
 		return err
 	}
 	if err := commit(); err != nil {
-		slog.Error("ResetPassword: commit failed", "error", err)
+		slog.ErrorContext(ctx, "ResetPassword: commit failed", "error", err)
 		return err
 	}
-	slog.Info("ResetPassword: success", "userId", resetInfo.UserID)
+	s.events.Publish(ctx, event.PasswordReset{UserID: user.ID, Email: user.Email})
+	slog.InfoContext(ctx, "ResetPassword: success", "userId", resetInfo.UserID)
 	return nil
 }
 
 // GetProfile retrieves the profile information for the given user ID.
 func (s *AuthService) GetProfile(ctx context.Context, userID int64) (*dto.ProfileResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.GetProfile")
+	defer span.End()
 	profile, err := s.profileRepo.FindByUserID(ctx, userID)
-	if err != nil || profile == nil {
-		return nil, errors.New("profile not found")
+	if err != nil {
+		slog.ErrorContext(ctx, "GetProfile: find profile failed", "error", err)
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrProfileNotFound
 	}
 
 	user, err := s.userRepo.FindByID(ctx, userID)
-	if err != nil || user == nil {
-		return nil, errors.New("user not found")
+	if err != nil {
+		slog.ErrorContext(ctx, "GetProfile: find user failed", "error", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
 	}
 
 	result := &dto.ProfileResponse{
-		Email:       user.Email,
-		Name:        profile.Name,
-		BirthDate:   profile.BirthDate.Format("2006-01-02"), // YYYY-MM-DD
-		GenderCode:  string(profile.GenderCode),
-		PhoneNumber: profile.PhoneNumber,
+		Email:              user.Email,
+		Name:               profile.Name,
+		BirthDate:          profile.BirthDate.Format("2006-01-02"), // YYYY-MM-DD
+		GenderCode:         string(profile.GenderCode),
+		PhoneNumber:        profile.PhoneNumber,
+		PhoneNumberDisplay: s.phones.Format(profile.PhoneNumber),
+		PhoneVerified:      profile.PhoneVerifiedAt != nil,
+		Locale:             profile.Locale,
 	}
 	return result, nil
 }
 
 // UpdateProfile updates the profile information for the given user ID.
-func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, cmd *dto.UpdateProfileRequest) (*dto.ProfileResponse, error) {
+func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, cmd *dto.UpdateProfileRequest) (_ *dto.ProfileResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.UpdateProfile")
+	defer func() { tracing.End(span, err) }()
+	var failDetails map[string]string
+	defer func() { s.publishFailed(ctx, event.NameProfileUpdated, userID, err, failDetails) }()
 	var commit, rollback func() error
 	if s.dbPool != nil {
 		pgxTx, err := s.dbPool.Begin(ctx)
 		if err != nil {
-			slog.Error("UpdateProfile: begin tx failed", "error", err)
+			slog.ErrorContext(ctx, "UpdateProfile: begin tx failed", "error", err)
 			return nil, err
 		}
 		commit = func() error { return pgxTx.Commit(ctx) }
@@ -419,7 +887,6 @@ func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, cmd *dto.
 		commit = func() error { return nil }
 		rollback = func() error { return nil }
 	}
-	var err error
 	defer func() {
 		if p := recover(); p != nil {
 			_ = rollback()
@@ -431,48 +898,79 @@ func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, cmd *dto.
 
 	profile, err := s.profileRepo.FindByUserID(ctx, userID)
 	if err != nil {
-		slog.Error("UpdateProfile: find profile failed", "error", err)
+		slog.ErrorContext(ctx, "UpdateProfile: find profile failed", "error", err)
 		return nil, err
 	}
 	if profile == nil {
-		slog.Warn("UpdateProfile: profile not found", "userId", userID)
-		return nil, errors.New("profile not found")
+		slog.WarnContext(ctx, "UpdateProfile: profile not found", "userId", userID)
+		err = ErrProfileNotFound
+		return nil, err
 	}
 
-	existing, err := s.profileRepo.FindByPhoneNumber(ctx, cmd.PhoneNumber)
+	phoneNumber, err := s.phones.Normalize(cmd.PhoneNumber)
 	if err != nil {
-		slog.Error("UpdateProfile: find by phone failed", "error", err)
+		return nil, err
+	}
+	existing, err := s.profileRepo.FindByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateProfile: find by phone failed", "error", err)
 		return nil, err
 	}
 	if existing != nil && existing.UserID != userID {
-		slog.Warn("UpdateProfile: phone already used", "phone", cmd.PhoneNumber)
-		return nil, errors.New("이미 사용 중인 전화번호입니다")
+		slog.WarnContext(ctx, "UpdateProfile: phone already used", "phone", utils.MaskPhone(phoneNumber))
+		err = ErrPhoneNumberInUse
+		return nil, err
+	}
+	phoneChanged := phoneNumber != profile.PhoneNumber
+	if phoneChanged {
+		failDetails = map[string]string{"phone": utils.MaskPhone(phoneNumber)}
+		// 전화번호 변경은 새 번호로 받은 인증번호가 있어야 한다
+		if err = s.verifyPhoneCode(ctx, phoneNumber, PhonePurposeVerifyPhone, cmd.PhoneVerificationCode); err != nil {
+			slog.WarnContext(ctx, "UpdateProfile: phone verification failed", "userId", userID, "error", err)
+			return nil, err
+		}
+		now := time.Now()
+		profile.PhoneVerifiedAt = &now
 	}
 
 	profile.Name = cmd.Name
 	profile.BirthDate, err = time.Parse("2006-01-02", cmd.BirthDate)
 	if err != nil {
-		slog.Error("UpdateProfile: parse birthdate failed", "error", err)
+		slog.WarnContext(ctx, "UpdateProfile: parse birthdate failed", "error", err)
+		err = ErrInvalidBirthDate
 		return nil, err
 	}
 	profile.GenderCode = entity.GenderCode(cmd.GenderCode)
-	profile.PhoneNumber = cmd.PhoneNumber
+	profile.PhoneNumber = phoneNumber
+	if cmd.Locale != "" {
+		profile.Locale = cmd.Locale
+	}
 	profile.UpdatedAt = time.Now()
 	err = s.profileRepo.Update(ctx, profile)
 	if err != nil {
-		slog.Error("UpdateProfile: update failed", "error", err)
+		slog.ErrorContext(ctx, "UpdateProfile: update failed", "error", err)
 		return nil, err
 	}
 	if err := commit(); err != nil {
-		slog.Error("UpdateProfile: commit failed", "error", err)
+		slog.ErrorContext(ctx, "UpdateProfile: commit failed", "error", err)
 		return nil, err
 	}
-	slog.Info("UpdateProfile: success", "userId", userID)
+	s.events.Publish(ctx, event.ProfileUpdated{
+		UserID:       userID,
+		Name:         profile.Name,
+		PhoneNumber:  profile.PhoneNumber,
+		Locale:       profile.Locale,
+		PhoneChanged: phoneChanged,
+	})
+	slog.InfoContext(ctx, "UpdateProfile: success", "userId", userID)
 	result := &dto.ProfileResponse{
-		Name:        profile.Name,
-		BirthDate:   profile.BirthDate.Format("2006-01-02"),
-		GenderCode:  string(profile.GenderCode),
-		PhoneNumber: profile.PhoneNumber,
+		Name:               profile.Name,
+		BirthDate:          profile.BirthDate.Format("2006-01-02"),
+		GenderCode:         string(profile.GenderCode),
+		PhoneNumber:        profile.PhoneNumber,
+		PhoneNumberDisplay: s.phones.Format(profile.PhoneNumber),
+		PhoneVerified:      profile.PhoneVerifiedAt != nil,
+		Locale:             profile.Locale,
 	}
 	return result, nil
 }
@@ -484,19 +982,31 @@ func (s *AuthService) JwtSvc() *JwtService {
 
 // Logout deletes the refresh token for the given user.
 // deviceInfo is unused but kept for interface compatibility.
-func (s *AuthService) Logout(ctx context.Context, userID int64, refreshToken, _ string) error {
-	return s.userRepo.DeleteRefreshToken(ctx, userID, refreshToken)
+func (s *AuthService) Logout(ctx context.Context, userID int64, refreshToken, _ string) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Logout")
+	defer func() { tracing.End(span, err) }()
+	defer func() { s.publishFailed(ctx, event.NameLoggedOut, userID, err, nil) }()
+	if err := s.userRepo.DeleteRefreshToken(ctx, userID, refreshToken); err != nil {
+		return err
+	}
+	s.events.Publish(ctx, event.LoggedOut{UserID: userID})
+	return nil
 }
 
 // ChangePassword changes the user's password after verifying the current password.
-func (s *AuthService) ChangePassword(ctx context.Context, userID int64, currentPassword, newPassword string) error {
+func (s *AuthService) ChangePassword(ctx context.Context, userID int64, currentPassword, newPassword string) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.ChangePassword")
+	defer func() { tracing.End(span, err) }()
+	defer func() { s.publishFailed(ctx, event.NamePasswordChanged, userID, err, nil) }()
+	var tx interface{}
 	var commit, rollback func() error
 	if s.dbPool != nil {
 		pgxTx, err := s.dbPool.Begin(ctx)
 		if err != nil {
-			slog.Error("ChangePassword: begin tx failed", "error", err)
+			slog.ErrorContext(ctx, "ChangePassword: begin tx failed", "error", err)
 			return err
 		}
+		tx = pgxTx
 		commit = func() error { return pgxTx.Commit(ctx) }
 		rollback = func() error { return pgxTx.Rollback(ctx) }
 	} else {
@@ -504,7 +1014,6 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID int64, currentP
 		commit = func() error { return nil }
 		rollback = func() error { return nil }
 	}
-	var err error
 	defer func() {
 		if p := recover(); p != nil {
 			_ = rollback()
@@ -516,57 +1025,73 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID int64, currentP
 
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		slog.Error("ChangePassword: find user failed", "error", err)
+		slog.ErrorContext(ctx, "ChangePassword: find user failed", "error", err)
 		return err
 	}
 	if user == nil {
-		slog.Warn("ChangePassword: user not found", "userId", userID)
-		return errors.New("user not found")
+		slog.WarnContext(ctx, "ChangePassword: user not found", "userId", userID)
+		return ErrUserNotFound
+	}
+	if !s.verifyPassword(currentPassword, user.PasswordHash) {
+		slog.WarnContext(ctx, "ChangePassword: current password incorrect", "userId", userID)
+		return ErrIncorrectPassword
 	}
-	if !utils.CheckPasswordHash(currentPassword, user.PasswordHash) {
-		slog.Warn("ChangePassword: current password incorrect", "userId", userID)
-		return errors.New("current password is incorrect")
+	if err = s.checkNewPassword(ctx, userID, user.PasswordHash, newPassword, s.personalInfo(ctx, user)...); err != nil {
+		slog.WarnContext(ctx, "ChangePassword: password policy violation", "userId", userID, "error", err)
+		return err
 	}
-	hashed, err := utils.HashPassword(newPassword)
+	hashed, err := s.hasher.Hash(newPassword)
 	if err != nil {
-		slog.Error("ChangePassword: hash failed", "error", err)
+		slog.ErrorContext(ctx, "ChangePassword: hash failed", "error", err)
 		return err
 	}
-	if err := s.userRepo.UpdatePassword(ctx, userID, hashed); err != nil {
-		slog.Error("ChangePassword: update password failed", "error", err)
+	if err := s.userRepo.UpdatePasswordTx(ctx, tx, userID, hashed); err != nil {
+		slog.ErrorContext(ctx, "ChangePassword: update password failed", "error", err)
 		return err
 	}
+	if s.policy.HistorySize > 0 {
+		if err := s.userRepo.InsertPasswordHistoryTx(ctx, tx, userID, hashed, s.policy.HistorySize); err != nil {
+			slog.ErrorContext(ctx, "ChangePassword: insert password history failed", "error", err)
+			return err
+		}
+	}
 	_ = s.userRepo.DeleteAllRefreshTokens(ctx, userID)
 	if err := commit(); err != nil {
-		slog.Error("ChangePassword: commit failed", "error", err)
+		slog.ErrorContext(ctx, "ChangePassword: commit failed", "error", err)
 		return err
 	}
-	slog.Info("ChangePassword: success", "userId", userID)
+	s.events.Publish(ctx, event.PasswordChanged{UserID: userID, Email: user.Email})
+	slog.InfoContext(ctx, "ChangePassword: success", "userId", userID)
 	return nil
 }
 
 // CheckPassword checks if the provided password matches the user's current password.
 func (s *AuthService) CheckPassword(ctx context.Context, userID int64, password string) error {
+	ctx, span := tracing.Start(ctx, "AuthService.CheckPassword")
+	defer span.End()
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return err
 	}
 	if user == nil {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
-	if !utils.CheckPasswordHash(password, user.PasswordHash) {
-		return errors.New("current password is incorrect")
+	if !s.verifyPassword(password, user.PasswordHash) {
+		return ErrIncorrectPassword
 	}
 	return nil
 }
 
 // DeleteProfile deletes the user's profile and all related refresh tokens.
-func (s *AuthService) DeleteProfile(ctx context.Context, userID int64) error {
+func (s *AuthService) DeleteProfile(ctx context.Context, userID int64) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.DeleteProfile")
+	defer func() { tracing.End(span, err) }()
+	defer func() { s.publishFailed(ctx, event.NameAccountDeleted, userID, err, nil) }()
 	var commit, rollback func() error
 	if s.dbPool != nil {
 		pgxTx, err := s.dbPool.Begin(ctx)
 		if err != nil {
-			slog.Error("DeleteProfile: begin tx failed", "error", err)
+			slog.ErrorContext(ctx, "DeleteProfile: begin tx failed", "error", err)
 			return err
 		}
 		commit = func() error { return pgxTx.Commit(ctx) }
@@ -576,7 +1101,6 @@ func (s *AuthService) DeleteProfile(ctx context.Context, userID int64) error {
 		commit = func() error { return nil }
 		rollback = func() error { return nil }
 	}
-	var err error
 	defer func() {
 		if p := recover(); p != nil {
 			_ = rollback()
@@ -585,15 +1109,26 @@ func (s *AuthService) DeleteProfile(ctx context.Context, userID int64) error {
 			_ = rollback()
 		}
 	}()
+	// 탈퇴 후에는 조회되지 않으므로 알림 받을 주소를 먼저 확인
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "DeleteProfile: find user failed", "error", err)
+		return err
+	}
+	if user == nil {
+		slog.WarnContext(ctx, "DeleteProfile: user not found", "userId", userID)
+		return ErrUserNotFound
+	}
 	if err := s.userRepo.Delete(ctx, userID); err != nil {
-		slog.Error("DeleteProfile: user soft delete failed", "error", err)
+		slog.ErrorContext(ctx, "DeleteProfile: user soft delete failed", "error", err)
 		return err
 	}
 	_ = s.userRepo.DeleteAllRefreshTokens(ctx, userID)
 	if err := commit(); err != nil {
-		slog.Error("DeleteProfile: commit failed", "error", err)
+		slog.ErrorContext(ctx, "DeleteProfile: commit failed", "error", err)
 		return err
 	}
-	slog.Info("DeleteProfile: success", "userId", userID)
+	s.events.Publish(ctx, event.AccountDeleted{UserID: userID, Email: user.Email})
+	slog.InfoContext(ctx, "DeleteProfile: success", "userId", userID)
 	return nil
 }
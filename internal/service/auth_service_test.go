@@ -0,0 +1,166 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"sync"
+	"testing"
+	"time"
+
+	"auth/internal/entity"
+	"auth/internal/repository"
+	"auth/internal/service"
+	"auth/internal/service/email"
+	"auth/internal/service/event"
+	"auth/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"zombiezen.com/go/sqlite"
+)
+
+// mailRecorder keeps the emails sent.
+type mailRecorder struct {
+	mu       sync.Mutex
+	messages []*email.Message
+}
+
+func (r *mailRecorder) Send(_ context.Context, msg *email.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, msg)
+	return nil
+}
+
+func (r *mailRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.messages)
+}
+
+// smsRecorder keeps the messages sent, so tests can read the codes.
+type smsRecorder struct {
+	messages []string
+}
+
+func (r *smsRecorder) Send(_ context.Context, _, message string) error {
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+type authFixture struct {
+	users    repository.UserRepository
+	profiles repository.ProfileRepository
+	phones   repository.PhoneVerificationRepository
+	sms      *smsRecorder
+	mails    *mailRecorder
+	svc      *service.AuthService
+}
+
+func newAuthFixture(t *testing.T, opts ...service.AuthServiceOption) *authFixture {
+	conn, err := sqlite.OpenConn(":memory:", 0)
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	f := &authFixture{
+		users:    repository.NewUserRepositorySqlite(conn),
+		profiles: repository.NewProfileRepositorySqlite(conn),
+		phones:   repository.NewPhoneVerificationRepositorySqlite(conn),
+		sms:      &smsRecorder{},
+		mails:    &mailRecorder{},
+	}
+	// sqlite 연결을 다른 고루틴에서 쓰지 않도록 알림도 동기로 보낸다
+	bus := event.NewSyncBus()
+	opts = append([]service.AuthServiceOption{service.WithPhoneVerification(f.phones, f.sms), service.WithEventBus(bus)}, opts...)
+	f.svc = service.NewAuthService(nil, f.users, f.profiles, service.NewJwtService("secret"),
+		email.NewEmailServiceWithMailer(f.mails, mail.Address{Address: "noreply@example.com"}), opts...)
+	f.svc.SubscribeNotifications(bus)
+	return f
+}
+
+func (f *authFixture) createUser(t *testing.T, email string) int64 {
+	now := time.Now()
+	id, err := f.users.CreateTx(context.Background(), nil, &entity.UserEntity{Email: email, PasswordHash: "x", Provider: "local", CreatedAt: now, UpdatedAt: now})
+	assert.Nil(t, err)
+	return id
+}
+
+func TestVerifyMagicLink_한번만사용(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	userID := f.createUser(t, "a@example.com")
+	assert.Nil(t, f.users.SaveMagicLinkToken(ctx, &entity.MagicLinkTokenEntity{
+		UserID:     userID,
+		TokenHash:  utils.HashToken("link-token"),
+		DeviceInfo: "device",
+		ExpiredAt:  time.Now().Add(10 * time.Minute),
+	}))
+
+	res, err := f.svc.VerifyMagicLink(ctx, "link-token", "device")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, res.AccessToken)
+
+	_, err = f.svc.VerifyMagicLink(ctx, "link-token", "device")
+	assert.ErrorIs(t, err, service.ErrInvalidMagicLink)
+}
+
+func TestVerifyMagicLink_만료(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	userID := f.createUser(t, "a@example.com")
+	assert.Nil(t, f.users.SaveMagicLinkToken(ctx, &entity.MagicLinkTokenEntity{
+		UserID:     userID,
+		TokenHash:  utils.HashToken("link-token"),
+		DeviceInfo: "device",
+		ExpiredAt:  time.Now().Add(-time.Minute),
+	}))
+
+	_, err := f.svc.VerifyMagicLink(ctx, "link-token", "device")
+	assert.ErrorIs(t, err, service.ErrInvalidMagicLink)
+}
+
+func TestConsumeMagicLinkToken_조건부사용처리(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	userID := f.createUser(t, "a@example.com")
+	hash := utils.HashToken("link-token")
+	assert.Nil(t, f.users.SaveMagicLinkToken(ctx, &entity.MagicLinkTokenEntity{UserID: userID, TokenHash: hash, ExpiredAt: time.Now().Add(time.Minute)}))
+
+	// 먼저 읽은 값과 상관없이 두 번째 사용 처리는 실패한다
+	ok, err := f.users.ConsumeMagicLinkToken(ctx, hash)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = f.users.ConsumeMagicLinkToken(ctx, hash)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+// failingHistory fails every password history insert.
+type failingHistory struct {
+	repository.UserRepository
+}
+
+func (r failingHistory) InsertPasswordHistoryTx(context.Context, interface{}, int64, string, int) error {
+	return errors.New("history unavailable")
+}
+
+func TestChangePassword_이력도함께저장(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	hash, err := utils.DefaultPasswordHasher.Hash("Xk9#mP2$vLq")
+	assert.Nil(t, err)
+	now := time.Now()
+	userID, err := f.users.CreateTx(ctx, nil, &entity.UserEntity{Email: "a@example.com", PasswordHash: hash, Provider: "local", CreatedAt: now, UpdatedAt: now})
+	assert.Nil(t, err)
+
+	assert.Nil(t, f.svc.ChangePassword(ctx, userID, "Xk9#mP2$vLq", "Qw7!rT5@zNp"))
+	history, err := f.users.FindPasswordHistory(ctx, userID, 5)
+	assert.Nil(t, err)
+	assert.Len(t, history, 1)
+
+	// 이력을 남기지 못하면 재사용 금지를 지킬 수 없으므로 변경도 실패한다
+	svc := service.NewAuthService(nil, failingHistory{f.users}, f.profiles, service.NewJwtService("secret"), nil)
+	err = svc.ChangePassword(ctx, userID, "Qw7!rT5@zNp", "Hj3%kL8&wEr")
+	assert.EqualError(t, err, "history unavailable")
+}
@@ -0,0 +1,352 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Mailer delivers email messages.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Pinger is implemented by mailers that can check the mail server is reachable without sending anything.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// SMTP connection security modes.
+const (
+	SecuritySTARTTLS = "starttls" // 평문 연결 후 STARTTLS 업그레이드 (587)
+	SecurityTLS      = "tls"      // implicit TLS (465)
+	SecurityNone     = "none"     // 암호화 없음, 로컬 개발용
+)
+
+// SMTP authentication mechanisms.
+const (
+	AuthPlain   = "plain"
+	AuthLogin   = "login"
+	AuthCRAMMD5 = "cram-md5"
+	AuthNone    = "none"
+)
+
+// SMTPMailer sends messages through an SMTP server.
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	Security string // SecuritySTARTTLS, SecurityTLS or SecurityNone
+	Auth     string // AuthPlain, AuthLogin, AuthCRAMMD5 or AuthNone
+	Timeout  time.Duration
+	// TLSConfig overrides the TLS configuration, e.g. for a private CA.
+	TLSConfig *tls.Config
+}
+
+// NewSMTPMailer creates an SMTPMailer using STARTTLS and PLAIN auth.
+func NewSMTPMailer(host, port, username, password string) *SMTPMailer {
+	return &SMTPMailer{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		Security: SecuritySTARTTLS,
+		Auth:     AuthPlain,
+		Timeout:  10 * time.Second,
+	}
+}
+
+// Send delivers the message over SMTP.
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+	c, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+	if m.Security == SecuritySTARTTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := c.StartTLS(m.tlsConfig()); err != nil {
+			return err
+		}
+	}
+	if auth, err := m.auth(); err != nil {
+		return err
+	} else if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(msg.From.Address); err != nil {
+		return err
+	}
+	for _, rcpt := range msg.Recipients() {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		_ = w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// Ping connects to the SMTP server, says hello and quits, without authenticating.
+func (m *SMTPMailer) Ping(ctx context.Context) error {
+	c, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+	if err := c.Hello("localhost"); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (m *SMTPMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.Host, m.Port)
+	timeout := m.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	var (
+		conn net.Conn
+		err  error
+	)
+	switch m.Security {
+	case SecurityTLS:
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: m.tlsConfig()}).DialContext(ctx, "tcp", addr)
+	case SecuritySTARTTLS, SecurityNone, "":
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	default:
+		return nil, fmt.Errorf("unsupported smtp security: %s", m.Security)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// smtp.Client 는 context 를 지원하지 않으므로 전체 대화에 연결 deadline 을 건다
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+	c, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (m *SMTPMailer) tlsConfig() *tls.Config {
+	if m.TLSConfig != nil {
+		return m.TLSConfig
+	}
+	return &tls.Config{ServerName: m.Host, MinVersion: tls.VersionTLS12}
+}
+
+func (m *SMTPMailer) auth() (smtp.Auth, error) {
+	switch m.Auth {
+	case AuthNone:
+		return nil, nil
+	case AuthPlain, "":
+		if m.Username == "" {
+			return nil, nil
+		}
+		return smtp.PlainAuth("", m.Username, m.Password, m.Host), nil
+	case AuthLogin:
+		return &loginAuth{username: m.Username, password: m.Password}, nil
+	case AuthCRAMMD5:
+		return smtp.CRAMMD5Auth(m.Username, m.Password), nil
+	default:
+		return nil, fmt.Errorf("unsupported smtp auth: %s", m.Auth)
+	}
+}
+
+// loginAuth implements the LOGIN mechanism, still required by some providers (e.g. Office 365).
+type loginAuth struct {
+	username, password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch strings.ToLower(strings.TrimSuffix(string(fromServer), ":")) {
+	case "username":
+		return []byte(a.username), nil
+	case "password":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected LOGIN challenge: %q", fromServer)
+	}
+}
+
+// APIMailer sends messages through a SendGrid v3 compatible HTTP API.
+type APIMailer struct {
+	Endpoint   string
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// NewAPIMailer creates an APIMailer posting to endpoint, e.g. https://api.sendgrid.com/v3/mail/send.
+func NewAPIMailer(endpoint, apiKey string) *APIMailer {
+	return &APIMailer{
+		Endpoint:   endpoint,
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type apiAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type apiContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type apiPersonalization struct {
+	To []apiAddress `json:"to"`
+}
+
+type apiRequest struct {
+	Personalizations []apiPersonalization `json:"personalizations"`
+	From             apiAddress           `json:"from"`
+	Subject          string               `json:"subject"`
+	Content          []apiContent         `json:"content"`
+	Headers          map[string]string    `json:"headers,omitempty"`
+}
+
+// Send posts the message to the API. Any non-2xx response is an error.
+func (m *APIMailer) Send(ctx context.Context, msg *Message) error {
+	req := apiRequest{
+		From:    apiAddress{Email: msg.From.Address, Name: msg.From.Name},
+		Subject: msg.Subject,
+		Content: []apiContent{{Type: "text/plain", Value: msg.Text}},
+		Headers: map[string]string{"Message-ID": msg.MessageID()},
+	}
+	for k, v := range msg.Headers {
+		req.Headers[k] = v
+	}
+	if msg.HTML != "" {
+		req.Content = append(req.Content, apiContent{Type: "text/html", Value: msg.HTML})
+	}
+	var p apiPersonalization
+	for _, to := range msg.To {
+		p.To = append(p.To, apiAddress{Email: to.Address, Name: to.Name})
+	}
+	req.Personalizations = []apiPersonalization{p}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+m.APIKey)
+	resp, err := m.HTTPClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("mail api returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// Ping opens a TCP connection to the API host. The API has no side-effect free endpoint to call.
+func (m *APIMailer) Ping(ctx context.Context) error {
+	u, err := url.Parse(m.Endpoint)
+	if err != nil {
+		return err
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	conn, err := (&net.Dialer{Timeout: 10 * time.Second}).DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// FileMailer writes each message as an .eml file into Dir instead of sending it.
+// It is meant for local development and tests.
+type FileMailer struct {
+	Dir string
+}
+
+// NewFileMailer creates a FileMailer writing to dir.
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{Dir: dir}
+}
+
+// Send writes the rendered message to <Dir>/<unix nanos>.eml.
+func (m *FileMailer) Send(_ context.Context, msg *Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+	name := filepath.Join(m.Dir, fmt.Sprintf("%d.eml", time.Now().UnixNano()))
+	return os.WriteFile(name, data, 0o600)
+}
+
+// Ping checks that Dir exists or can be created.
+func (m *FileMailer) Ping(_ context.Context) error {
+	return os.MkdirAll(m.Dir, 0o755)
+}
@@ -0,0 +1,209 @@
+package email_test
+
+import (
+	"auth/internal/service/email"
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var sender = mail.Address{Name: "홍길동 서비스", Address: "no-reply@example.com"}
+
+func TestMessage_MIME구조(t *testing.T) {
+	msg, err := email.NewMessage(sender, "user@example.com", "[YourApp] 비밀번호 재설정 안내", "",
+		`<html><head><style>p{}</style></head><body><p>안녕하세요,</p><a href="https://example.com/reset?token=abc">재설정하기</a></body></html>`)
+	assert.Nil(t, err)
+	raw, err := msg.Bytes()
+	assert.Nil(t, err)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	assert.Nil(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	assert.Nil(t, err)
+	assert.Equal(t, "[YourApp] 비밀번호 재설정 안내", subject)
+	assert.Equal(t, msg.MessageID(), parsed.Header.Get("Message-ID"))
+	assert.True(t, strings.HasSuffix(msg.MessageID(), "@example.com>"))
+	from, err := parsed.Header.AddressList("From")
+	assert.Nil(t, err)
+	assert.Equal(t, sender.Name, from[0].Name)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	var types, bodies []string
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		body, _ := io.ReadAll(part) // quoted-printable 은 multipart.Reader 가 디코딩한다
+		types = append(types, part.Header.Get("Content-Type"))
+		bodies = append(bodies, strings.ReplaceAll(string(body), "\r\n", "\n"))
+	}
+	assert.Equal(t, []string{"text/plain; charset=UTF-8", "text/html; charset=UTF-8"}, types)
+	assert.Equal(t, "안녕하세요,\n재설정하기 (https://example.com/reset?token=abc)\n", bodies[0])
+	assert.Contains(t, bodies[1], `href="https://example.com/reset?token=abc"`)
+}
+
+func TestMessage_헤더인젝션방지(t *testing.T) {
+	msg, err := email.NewMessage(sender, "user@example.com", "hello\r\nBcc: victim@example.com", "text", "")
+	assert.Nil(t, err)
+	raw, err := msg.Bytes()
+	assert.Nil(t, err)
+	parsed, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	assert.Nil(t, err)
+	assert.Empty(t, parsed.Header.Get("Bcc"))
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := t.TempDir()
+	svc := email.NewEmailServiceWithMailer(email.NewFileMailer(dir), sender)
+	assert.Nil(t, svc.SendMagicLink("user@example.com", "https://example.com/magic?token=abc", 15))
+
+	files, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+	raw, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	assert.Nil(t, err)
+	parsed, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	assert.Nil(t, err)
+	assert.Equal(t, "<user@example.com>", parsed.Header.Get("To"))
+}
+
+func TestAPIMailer(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	msg, err := email.NewMessage(sender, "user@example.com", "subject", "", "<p>hi</p>")
+	assert.Nil(t, err)
+	assert.Nil(t, email.NewAPIMailer(srv.URL, "test-key").Send(context.Background(), msg))
+	assert.Equal(t, "subject", got["subject"])
+	assert.Len(t, got["content"], 2)
+}
+
+func TestAPIMailer_오류응답(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad key", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	msg, _ := email.NewMessage(sender, "user@example.com", "subject", "text", "")
+	err := email.NewAPIMailer(srv.URL, "wrong").Send(context.Background(), msg)
+	assert.ErrorContains(t, err, "401")
+}
+
+// fakeSMTP accepts a single unencrypted, unauthenticated SMTP session and returns the DATA payload.
+func fakeSMTP(t *testing.T) (string, <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	data := make(chan string, 1)
+	go func() {
+		defer func() {
+			_ = ln.Close()
+		}()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+		reply("220 fake ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"):
+				reply("250 fake")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var body strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					body.WriteString(l)
+				}
+				data <- body.String()
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().String(), data
+}
+
+func TestSMTPMailer_암호화없음(t *testing.T) {
+	addr, data := fakeSMTP(t)
+	host, port, _ := net.SplitHostPort(addr)
+	mailer := &email.SMTPMailer{Host: host, Port: port, Security: email.SecurityNone, Auth: email.AuthNone}
+
+	msg, err := email.NewMessage(sender, "user@example.com", "subject", "hello", "")
+	assert.Nil(t, err)
+	assert.Nil(t, mailer.Send(context.Background(), msg))
+	assert.Contains(t, <-data, "Message-ID: "+msg.MessageID())
+}
+
+func TestSMTPMailer_STARTTLS미지원서버(t *testing.T) {
+	addr, _ := fakeSMTP(t)
+	host, port, _ := net.SplitHostPort(addr)
+	mailer := &email.SMTPMailer{Host: host, Port: port, Security: email.SecuritySTARTTLS}
+
+	msg, _ := email.NewMessage(sender, "user@example.com", "subject", "hello", "")
+	assert.ErrorContains(t, mailer.Send(context.Background(), msg), "STARTTLS")
+}
+
+func TestMailer_Ping(t *testing.T) {
+	addr, data := fakeSMTP(t)
+	host, port, _ := net.SplitHostPort(addr)
+	smtpMailer := &email.SMTPMailer{Host: host, Port: port, Security: email.SecurityNone, Auth: email.AuthNone}
+	assert.Nil(t, smtpMailer.Ping(context.Background()))
+	assert.Len(t, data, 0) // 메일은 보내지 않는다
+
+	// 서버가 닫혀 있으면 실패
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	closed := ln.Addr().String()
+	_ = ln.Close()
+	host, port, _ = net.SplitHostPort(closed)
+	assert.NotNil(t, (&email.SMTPMailer{Host: host, Port: port, Security: email.SecurityNone}).Ping(context.Background()))
+	assert.NotNil(t, email.NewAPIMailer("http://"+closed+"/v3/mail/send", "key").Ping(context.Background()))
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	assert.Nil(t, email.NewAPIMailer(srv.URL+"/v3/mail/send", "key").Ping(context.Background()))
+
+	dir := filepath.Join(t.TempDir(), "mail")
+	svc := email.NewEmailServiceWithMailer(email.NewFileMailer(dir), sender)
+	assert.Nil(t, svc.Ping(context.Background()))
+	_, err := os.Stat(dir)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,161 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Message is an email with a plain text and an HTML alternative.
+type Message struct {
+	From    mail.Address
+	To      []mail.Address
+	Subject string
+	Text    string
+	HTML    string
+	// Headers holds additional headers, e.g. List-Unsubscribe.
+	Headers map[string]string
+
+	messageID string
+}
+
+// NewMessage creates a message to a single recipient. The text part is derived from
+// the HTML body when text is empty. It fails if an address cannot be parsed.
+func NewMessage(from mail.Address, to, subject, text, htmlBody string) (*Message, error) {
+	rcpt, err := mail.ParseAddress(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", to, err)
+	}
+	if _, err := mail.ParseAddress(from.Address); err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", from.Address, err)
+	}
+	if text == "" && htmlBody != "" {
+		text = htmlToText(htmlBody)
+	}
+	return &Message{From: from, To: []mail.Address{*rcpt}, Subject: subject, Text: text, HTML: htmlBody}, nil
+}
+
+// MessageID returns the Message-ID header value, generating it on first use.
+func (m *Message) MessageID() string {
+	if m.messageID == "" {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		domain := "localhost"
+		if at := strings.LastIndex(m.From.Address, "@"); at >= 0 {
+			domain = m.From.Address[at+1:]
+		}
+		m.messageID = fmt.Sprintf("<%s.%d@%s>", hex.EncodeToString(b), time.Now().UnixNano(), domain)
+	}
+	return m.messageID
+}
+
+// Recipients returns the bare recipient addresses for the SMTP envelope.
+func (m *Message) Recipients() []string {
+	rcpts := make([]string, 0, len(m.To))
+	for _, to := range m.To {
+		rcpts = append(rcpts, to.Address)
+	}
+	return rcpts
+}
+
+// Bytes renders the message as RFC 5322 with a multipart/alternative body.
+// Non-ASCII header values are RFC 2047 encoded and parts are quoted-printable.
+func (m *Message) Bytes() ([]byte, error) {
+	if len(m.To) == 0 {
+		return nil, errors.New("message has no recipients")
+	}
+	var buf bytes.Buffer
+	to := make([]string, 0, len(m.To))
+	for _, addr := range m.To {
+		to = append(to, addr.String())
+	}
+	writeHeader(&buf, "From", m.From.String())
+	writeHeader(&buf, "To", strings.Join(to, ", "))
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader(&buf, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", m.MessageID())
+	writeHeader(&buf, "MIME-Version", "1.0")
+	for k, v := range m.Headers {
+		writeHeader(&buf, textproto.CanonicalMIMEHeaderKey(k), mime.QEncoding.Encode("utf-8", v))
+	}
+
+	mw := multipart.NewWriter(&buf)
+	writeHeader(&buf, "Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", mw.Boundary()))
+	buf.WriteString("\r\n")
+	if err := writePart(mw, "text/plain; charset=UTF-8", m.Text); err != nil {
+		return nil, err
+	}
+	if m.HTML != "" {
+		if err := writePart(mw, "text/html; charset=UTF-8", m.HTML); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	// 헤더 인젝션 방지
+	value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+	buf.WriteString(key + ": " + value + "\r\n")
+}
+
+func writePart(mw *multipart.Writer, contentType, body string) error {
+	w, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+var (
+	reHead   = regexp.MustCompile(`(?is)<(head|style|script)[^>]*>.*?</(head|style|script)>`)
+	reLink   = regexp.MustCompile(`(?is)<a\s[^>]*href="([^"]*)"[^>]*>(.*?)</a>`)
+	reBreak  = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|h[1-6]|li|tr)>`)
+	reTag    = regexp.MustCompile(`<[^>]+>`)
+	reSpaces = regexp.MustCompile(`[ \t]+`)
+	reBlank  = regexp.MustCompile(`\n\s*\n+`)
+)
+
+// htmlToText converts an HTML email body to a readable plain text alternative.
+func htmlToText(s string) string {
+	s = reHead.ReplaceAllString(s, "")
+	s = reLink.ReplaceAllStringFunc(s, func(a string) string {
+		m := reLink.FindStringSubmatch(a)
+		label := strings.TrimSpace(reTag.ReplaceAllString(m[2], ""))
+		if label == "" || label == m[1] {
+			return m[1]
+		}
+		return label + " (" + m[1] + ")"
+	})
+	s = reBreak.ReplaceAllString(s, "\n")
+	s = reTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(reSpaces.ReplaceAllString(line, " "))
+	}
+	s = strings.Join(lines, "\n")
+	s = reBlank.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s) + "\n"
+}
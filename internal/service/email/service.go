@@ -2,63 +2,175 @@
 package email
 
 import (
-	"bytes"
-	"html/template"
-	"net/smtp"
-
-	"github.com/gofiber/fiber/v2/log"
+	"auth/internal/tracing"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/mail"
 )
 
-// Service provides email sending functionalities.
+// Service renders email templates and sends them through a Mailer.
 type Service struct {
-	SMTPHost string
-	SMTPPort string
-	Username string
-	Password string
+	mailer    Mailer
+	from      mail.Address
+	templates *Templates
 }
 
-// NewEmailService creates a new EmailService instance.
+// Option configures a Service.
+type Option func(*Service)
+
+// WithTemplates sets the template registry. By default the embedded templates are used
+// with the default locale and a placeholder brand.
+func WithTemplates(t *Templates) Option {
+	return func(s *Service) {
+		s.templates = t
+	}
+}
+
+// NewEmailService creates a new EmailService instance sending through SMTP
+// (STARTTLS, PLAIN auth) from the given account.
 func NewEmailService(host, port, username, password string) *Service {
-	return &Service{
-		SMTPHost: host,
-		SMTPPort: port,
-		Username: username,
-		Password: password,
+	return NewEmailServiceWithMailer(NewSMTPMailer(host, port, username, password), mail.Address{Address: username})
+}
+
+// NewEmailServiceWithMailer creates a new EmailService sending through mailer from the given address.
+func NewEmailServiceWithMailer(mailer Mailer, from mail.Address, opts ...Option) *Service {
+	s := &Service{mailer: mailer, from: from}
+	for _, opt := range opts {
+		opt(s)
 	}
+	if s.templates == nil {
+		t, err := NewTemplates("", DefaultLocale, Brand{Name: "YourApp"})
+		if err != nil {
+			panic(err) // 내장 템플릿은 항상 유효해야 한다
+		}
+		s.templates = t
+	}
+	return s
 }
 
-// SendEmailHTML sends an HTML email.
+// SendEmailHTML sends an HTML email with a plain text alternative derived from it.
 func (s *Service) SendEmailHTML(to, subject, htmlBody string) error {
-	from := s.Username
-	msg := "From: " + from + "\n" +
-		"To: " + to + "\n" +
-		"Subject: " + subject + "\n" +
-		"Content-Type: text/html; charset=UTF-8\n\n" +
-		htmlBody
-
-	auth := smtp.PlainAuth("", s.Username, s.Password, s.SMTPHost)
-	err := smtp.SendMail(s.SMTPHost+":"+s.SMTPPort, auth, from, []string{to}, []byte(msg))
+	msg, err := NewMessage(s.from, to, subject, "", htmlBody)
 	if err != nil {
-		log.Error("Error sending HTML email: %v", err)
+		slog.Error("Error building HTML email", "error", err)
+		return err
+	}
+	if err := s.mailer.Send(context.Background(), msg); err != nil {
+		slog.Error("Error sending HTML email", "error", err)
 		return err
 	}
 	return nil
 }
 
-// SendPasswordReset sends a password reset email.
-func (s *Service) SendPasswordReset(email, link string, expireMinutes int) error {
-	tmpl, err := template.New("reset").Parse(passwordResetEmailTemplate)
+// OutboxKind is the outbox message kind for emails.
+const OutboxKind = "email"
+
+// Payload is an already rendered email stored in the outbox.
+type Payload struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Text    string `json:"text,omitempty"`
+	HTML    string `json:"html"`
+}
+
+// Deliver sends an email stored in the outbox as a JSON encoded Payload.
+func (s *Service) Deliver(ctx context.Context, payload string) (err error) {
+	ctx, span := tracing.Start(ctx, "email.Deliver")
+	defer func() { tracing.End(span, err) }()
+	var p Payload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return err
+	}
+	msg, err := NewMessage(s.from, p.To, p.Subject, p.Text, p.HTML)
 	if err != nil {
 		return err
 	}
-	var body bytes.Buffer
-	err = tmpl.Execute(&body, PasswordResetEmailData{
+	return s.mailer.Send(ctx, msg)
+}
+
+// Locale returns the template locale for the preferences, e.g. a profile locale followed
+// by an Accept-Language header.
+func (s *Service) Locale(preferences ...string) string {
+	return s.templates.Match(preferences...)
+}
+
+// Render renders the named template in locale.
+func (s *Service) Render(name, locale string, data any) (*Rendered, error) {
+	return s.templates.Render(name, locale, data)
+}
+
+// Preview renders the named template in locale with sample data.
+func (s *Service) Preview(name, locale string) (*Rendered, error) {
+	data, ok := previewData[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	return s.templates.Render(name, locale, data)
+}
+
+// Send sends a rendered email.
+func (s *Service) Send(ctx context.Context, to string, r *Rendered) (err error) {
+	ctx, span := tracing.Start(ctx, "email.Send")
+	defer func() { tracing.End(span, err) }()
+	msg, err := NewMessage(s.from, to, r.Subject, r.Text, r.HTML)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error building email", "error", err)
+		return err
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		slog.ErrorContext(ctx, "Error sending email", "error", err)
+		return err
+	}
+	return nil
+}
+
+// Ping checks the mailer can reach its server. Mailers that do not implement Pinger are always reachable.
+func (s *Service) Ping(ctx context.Context) error {
+	if p, ok := s.mailer.(Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+// RenderPasswordReset renders a password reset email.
+func (s *Service) RenderPasswordReset(locale, link string, expireMinutes int) (*Rendered, error) {
+	return s.Render(TemplatePasswordReset, locale, PasswordResetEmailData{
 		ResetLink:     link,
 		ExpireMinutes: expireMinutes,
 	})
+}
+
+// SendPasswordReset sends a password reset email in the default language. Render it with
+// RenderPasswordReset and send it with Send for another language.
+func (s *Service) SendPasswordReset(email, link string, expireMinutes int) error {
+	r, err := s.RenderPasswordReset("", link, expireMinutes)
+	if err != nil {
+		return err
+	}
+	return s.Send(context.Background(), email, r)
+}
+
+// RenderMagicLink renders a passwordless sign-in email.
+func (s *Service) RenderMagicLink(locale, link string, expireMinutes int) (*Rendered, error) {
+	return s.Render(TemplateMagicLink, locale, MagicLinkEmailData{
+		LoginLink:     link,
+		ExpireMinutes: expireMinutes,
+	})
+}
+
+// SendMagicLink sends a passwordless sign-in link email in the default language. Render it
+// with RenderMagicLink and send it with Send for another language.
+func (s *Service) SendMagicLink(email, link string, expireMinutes int) error {
+	r, err := s.RenderMagicLink("", link, expireMinutes)
 	if err != nil {
 		return err
 	}
-	subject := "[YourApp] 비밀번호 재설정 안내"
-	return s.SendEmailHTML(email, subject, body.String())
+	return s.Send(context.Background(), email, r)
+}
+
+// RenderSecurityAlert renders a security notification email for an account event.
+func (s *Service) RenderSecurityAlert(locale string, data SecurityAlertEmailData) (*Rendered, error) {
+	return s.Render(TemplateSecurityAlert, locale, data)
 }
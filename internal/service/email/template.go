@@ -1,34 +1,296 @@
 package email
 
-const passwordResetEmailTemplate = `
-<!DOCTYPE html>
-<html>
-<head>
-  <meta charset="UTF-8">
-  <title>비밀번호 재설정 안내</title>
-</head>
-<body style="font-family: Arial, sans-serif; background: #f8f8f8; padding: 30px;">
-  <div style="max-width: 480px; margin: auto; background: #fff; border-radius: 8px; box-shadow: 0 2px 8px #eee; padding: 32px;">
-    <h2 style="color: #1a73e8;">비밀번호 재설정 요청</h2>
-    <p>안녕하세요,</p>
-    <p>비밀번호 재설정 요청을 받았습니다.<br>
-      아래 버튼을 클릭하여 새로운 비밀번호를 설정하세요.</p>
-    <p style="text-align: center;">
-      <a href="{{.ResetLink}}" style="display:inline-block; background:#1a73e8; color:#fff; padding:12px 24px; border-radius:5px; text-decoration:none; font-weight:bold;">
-        비밀번호 재설정하기
-      </a>
-    </p>
-    <p>이 링크는 <b>{{.ExpireMinutes}}분</b> 동안만 유효합니다.<br>
-      만약 본인이 요청하지 않았다면 이 메일을 무시하셔도 됩니다.</p>
-    <hr style="margin:32px 0 16px 0;">
-    <small style="color:#888;">본 메일은 자동 발송된 메일입니다.</small>
-  </div>
-</body>
-</html>
-`
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+
+	"golang.org/x/text/language"
+)
+
+// Template names. Each template is a pair of files per locale directory:
+// <name>.txt defines the "subject" template and the plain text body, and
+// <name>.html is the HTML body rendered inside the locale's layout.html.
+const (
+	TemplatePasswordReset = "password_reset"
+	TemplateMagicLink     = "magic_link"
+	TemplateSecurityAlert = "security_alert"
+)
+
+// DefaultLocale is the locale used when no preference matches a template locale.
+const DefaultLocale = "ko"
+
+//go:embed templates
+var embeddedTemplates embed.FS
+
+// ErrUnknownTemplate is returned when rendering a template that does not exist.
+var ErrUnknownTemplate = errors.New("unknown email template")
+
+// Brand holds the brand variables available to templates as .Brand.
+type Brand struct {
+	Name         string // 제목과 본문에 표시되는 서비스 이름
+	URL          string
+	LogoURL      string
+	SupportEmail string
+	Color        string // 제목과 버튼 색상
+}
+
+// Rendered is a rendered email.
+type Rendered struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// templateView is the value templates are executed with.
+type templateView struct {
+	Brand  Brand
+	Locale string
+	Data   any
+}
+
+type localized struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Templates is a registry of email templates per name and locale.
+type Templates struct {
+	brand   Brand
+	locales []string // 기본 로케일이 첫 번째
+	matcher language.Matcher
+	byKey   map[string]localized // "<locale>/<name>"
+	names   []string
+}
+
+// NewTemplates loads the embedded templates. Files in dir, laid out the same way
+// (<locale>/<name>.txt, <locale>/<name>.html, <locale>/layout.html), override the
+// embedded ones and may add locales or templates. An empty dir uses only the embedded templates.
+func NewTemplates(dir, defaultLocale string, brand Brand) (*Templates, error) {
+	embedded, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	sources := []fs.FS{embedded}
+	if dir != "" {
+		sources = append([]fs.FS{os.DirFS(dir)}, sources...)
+	}
+	if defaultLocale == "" {
+		defaultLocale = DefaultLocale
+	}
+	if brand.Color == "" {
+		brand.Color = "#1a73e8"
+	}
+
+	// 로케일별 템플릿 목록 수집
+	found := map[string]map[string]bool{}
+	for _, src := range sources {
+		entries, err := fs.ReadDir(src, ".")
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			files, err := fs.Glob(src, e.Name()+"/*.txt")
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range files {
+				if found[e.Name()] == nil {
+					found[e.Name()] = map[string]bool{}
+				}
+				found[e.Name()][strings.TrimSuffix(path.Base(f), ".txt")] = true
+			}
+		}
+	}
+	if found[defaultLocale] == nil {
+		return nil, fmt.Errorf("no email templates for default locale %q", defaultLocale)
+	}
+
+	t := &Templates{brand: brand, byKey: map[string]localized{}}
+	t.locales = append(t.locales, defaultLocale)
+	for locale := range found {
+		if locale != defaultLocale {
+			t.locales = append(t.locales, locale)
+		}
+	}
+	sort.Strings(t.locales[1:])
+	tags := make([]language.Tag, 0, len(t.locales))
+	for _, locale := range t.locales {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return nil, fmt.Errorf("email template locale %q: %w", locale, err)
+		}
+		tags = append(tags, tag)
+	}
+	t.matcher = language.NewMatcher(tags)
+
+	names := map[string]bool{}
+	for locale, set := range found {
+		for name := range set {
+			l, err := parseLocalized(sources, locale, name)
+			if err != nil {
+				return nil, err
+			}
+			t.byKey[locale+"/"+name] = l
+			names[name] = true
+		}
+	}
+	for name := range names {
+		t.names = append(t.names, name)
+	}
+	sort.Strings(t.names)
+	return t, nil
+}
+
+// readFirst returns the named file from the first source that has it.
+func readFirst(sources []fs.FS, name string) (string, error) {
+	for _, src := range sources {
+		b, err := fs.ReadFile(src, name)
+		if err == nil {
+			return string(b), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+}
+
+func parseLocalized(sources []fs.FS, locale, name string) (localized, error) {
+	key := locale + "/" + name
+	txt, err := readFirst(sources, key+".txt")
+	if err != nil {
+		return localized{}, err
+	}
+	text, err := texttemplate.New(name).Option("missingkey=error").Parse(txt)
+	if err != nil {
+		return localized{}, err
+	}
+	if text.Lookup("subject") == nil {
+		return localized{}, fmt.Errorf("%s.txt: missing subject template", key)
+	}
+	body, err := readFirst(sources, key+".html")
+	if err != nil {
+		return localized{}, err
+	}
+	layout, err := readFirst(sources, locale+"/layout.html")
+	if err != nil {
+		return localized{}, err
+	}
+	html, err := htmltemplate.New(name).Option("missingkey=error").Parse(layout)
+	if err != nil {
+		return localized{}, err
+	}
+	if html, err = html.Parse(body); err != nil {
+		return localized{}, fmt.Errorf("%s.html: %w", key, err)
+	}
+	return localized{text: text, html: html}, nil
+}
+
+// Names returns the template names, sorted.
+func (t *Templates) Names() []string {
+	return t.names
+}
+
+// Locales returns the template locales, default first.
+func (t *Templates) Locales() []string {
+	return t.locales
+}
+
+// Match returns the template locale best matching the first preference that matches any.
+// Each preference is a language tag or an Accept-Language header value; empty ones are skipped.
+func (t *Templates) Match(preferences ...string) string {
+	for _, pref := range preferences {
+		if pref == "" {
+			continue
+		}
+		tags, _, err := language.ParseAcceptLanguage(pref)
+		if err != nil || len(tags) == 0 {
+			continue
+		}
+		_, i, confidence := t.matcher.Match(tags...)
+		if confidence != language.No {
+			return t.locales[i]
+		}
+	}
+	return t.locales[0]
+}
+
+// Render renders the named template in locale, falling back to the default locale
+// when the template is not translated.
+func (t *Templates) Render(name, locale string, data any) (*Rendered, error) {
+	l, ok := t.byKey[locale+"/"+name]
+	if !ok {
+		locale = t.locales[0]
+		if l, ok = t.byKey[locale+"/"+name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+		}
+	}
+	view := templateView{Brand: t.brand, Locale: locale, Data: data}
+	var subject, text, html bytes.Buffer
+	if err := l.text.ExecuteTemplate(&subject, "subject", view); err != nil {
+		return nil, err
+	}
+	if err := l.text.Execute(&text, view); err != nil {
+		return nil, err
+	}
+	if err := l.html.Execute(&html, view); err != nil {
+		return nil, err
+	}
+	return &Rendered{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    strings.TrimSpace(html.String()) + "\n",
+	}, nil
+}
+
+// MagicLinkEmailData holds data for the magic link email template.
+type MagicLinkEmailData struct {
+	LoginLink     string
+	ExpireMinutes int
+}
 
 // PasswordResetEmailData holds data for the password reset email template.
 type PasswordResetEmailData struct {
 	ResetLink     string
 	ExpireMinutes int
 }
+
+// Security events reported by the security alert email.
+const (
+	SecurityEventNewDeviceLogin  = "new_device_login"
+	SecurityEventPasswordChanged = "password_changed"
+	SecurityEventPasswordReset   = "password_reset"
+	SecurityEventPhoneChanged    = "phone_changed"
+	SecurityEventAccountDeleted  = "account_deleted"
+)
+
+// SecurityAlertEmailData holds data for the security alert email template.
+type SecurityAlertEmailData struct {
+	Event      string // SecurityEvent* 중 하나
+	OccurredAt string
+	DeviceInfo string // 없으면 표시하지 않음
+	Detail     string // 예: 마스킹된 새 전화번호
+}
+
+// previewData is sample data for rendering each template in the dev preview.
+var previewData = map[string]any{
+	TemplatePasswordReset: PasswordResetEmailData{ResetLink: "https://example.com/reset-password?token=preview", ExpireMinutes: 30},
+	TemplateMagicLink:     MagicLinkEmailData{LoginLink: "https://example.com/magic-link?token=preview", ExpireMinutes: 15},
+	TemplateSecurityAlert: SecurityAlertEmailData{
+		Event:      SecurityEventNewDeviceLogin,
+		OccurredAt: "2025-01-01 09:00 UTC",
+		DeviceInfo: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36",
+	},
+}
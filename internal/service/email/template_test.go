@@ -0,0 +1,119 @@
+package email_test
+
+import (
+	"auth/internal/service/email"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTemplates(t *testing.T, dir string) *email.Templates {
+	tmpl, err := email.NewTemplates(dir, "ko", email.Brand{Name: "Acme", SupportEmail: "help@acme.test"})
+	assert.Nil(t, err)
+	return tmpl
+}
+
+func TestTemplates_로케일별렌더링(t *testing.T) {
+	tmpl := newTemplates(t, "")
+	assert.Equal(t, []string{"magic_link", "password_reset", "security_alert"}, tmpl.Names())
+	assert.Equal(t, []string{"ko", "en"}, tmpl.Locales())
+
+	data := email.PasswordResetEmailData{ResetLink: "https://acme.test/reset?token=a&b", ExpireMinutes: 30}
+	ko, err := tmpl.Render(email.TemplatePasswordReset, "ko", data)
+	assert.Nil(t, err)
+	assert.Equal(t, "[Acme] 비밀번호 재설정 안내", ko.Subject)
+	assert.Contains(t, ko.Text, "https://acme.test/reset?token=a&b")
+	assert.Contains(t, ko.Text, "30분")
+	assert.Contains(t, ko.HTML, `href="https://acme.test/reset?token=a&amp;b"`)
+	assert.Contains(t, ko.HTML, "help@acme.test")
+
+	en, err := tmpl.Render(email.TemplatePasswordReset, "en", data)
+	assert.Nil(t, err)
+	assert.Equal(t, "[Acme] Reset your password", en.Subject)
+	assert.Contains(t, en.HTML, `<html lang="en">`)
+
+	_, err = tmpl.Render("unknown", "ko", data)
+	assert.ErrorIs(t, err, email.ErrUnknownTemplate)
+}
+
+func TestTemplates_보안알림(t *testing.T) {
+	tmpl := newTemplates(t, "")
+	ko, err := tmpl.Render(email.TemplateSecurityAlert, "ko", email.SecurityAlertEmailData{
+		Event:      email.SecurityEventNewDeviceLogin,
+		OccurredAt: "2025-01-01 09:00 UTC",
+		DeviceInfo: "curl/8.0",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "[Acme] 보안 알림: 새 기기에서 로그인", ko.Subject)
+	assert.Contains(t, ko.Text, "curl/8.0")
+	assert.Contains(t, ko.HTML, "curl/8.0")
+
+	en, err := tmpl.Render(email.TemplateSecurityAlert, "en", email.SecurityAlertEmailData{
+		Event:      email.SecurityEventPhoneChanged,
+		OccurredAt: "2025-01-01 09:00 UTC",
+		Detail:     "*******5678",
+	})
+	assert.Nil(t, err)
+	assert.Contains(t, en.Text, "*******5678")
+	assert.NotContains(t, en.Text, "Device")
+}
+
+func TestTemplates_Match(t *testing.T) {
+	tmpl := newTemplates(t, "")
+	assert.Equal(t, "en", tmpl.Match("", "en-US,en;q=0.9,ko;q=0.8"))
+	assert.Equal(t, "ko", tmpl.Match("", "ko-KR"))
+	// 프로필 언어가 Accept-Language 보다 우선한다
+	assert.Equal(t, "ko", tmpl.Match("ko", "en-US"))
+	// 지원하지 않는 언어는 다음 선호도 또는 기본 언어로
+	assert.Equal(t, "en", tmpl.Match("fr", "en"))
+	assert.Equal(t, "ko", tmpl.Match("fr", "de-DE"))
+	assert.Equal(t, "ko", tmpl.Match("", ""))
+}
+
+func TestTemplates_디렉토리덮어쓰기(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "ja"), 0o755))
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "en"), 0o755))
+	// 영어 제목만 덮어쓰고 HTML 은 내장 템플릿 사용
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "en", "magic_link.txt"),
+		[]byte(`{{define "subject"}}Sign in to {{.Brand.Name}}{{end}}{{.Data.LoginLink}}`), 0o644))
+	// 새 로케일 추가
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "ja", "layout.html"),
+		[]byte(`{{define "layout"}}<p>{{template "content" .}}</p>{{end}}`), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "ja", "magic_link.txt"),
+		[]byte(`{{define "subject"}}ログイン{{end}}{{.Data.LoginLink}}`), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "ja", "magic_link.html"),
+		[]byte(`{{define "content"}}<a href="{{.Data.LoginLink}}">ログイン</a>{{end}}{{template "layout" .}}`), 0o644))
+
+	tmpl := newTemplates(t, dir)
+	assert.Equal(t, []string{"ko", "en", "ja"}, tmpl.Locales())
+	data := email.MagicLinkEmailData{LoginLink: "https://acme.test/m", ExpireMinutes: 15}
+
+	en, err := tmpl.Render(email.TemplateMagicLink, "en", data)
+	assert.Nil(t, err)
+	assert.Equal(t, "Sign in to Acme", en.Subject)
+	assert.Contains(t, en.HTML, "Sign-in link")
+
+	ja, err := tmpl.Render(email.TemplateMagicLink, tmpl.Match("ja-JP"), data)
+	assert.Nil(t, err)
+	assert.Equal(t, "ログイン", ja.Subject)
+	assert.Equal(t, "<p><a href=\"https://acme.test/m\">ログイン</a></p>\n", ja.HTML)
+
+	// 번역이 없는 템플릿은 기본 언어로
+	ko, err := tmpl.Render(email.TemplatePasswordReset, "ja", email.PasswordResetEmailData{ResetLink: "x", ExpireMinutes: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, "[Acme] 비밀번호 재설정 안내", ko.Subject)
+}
+
+func TestTemplates_잘못된템플릿(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "ko"), 0o755))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "ko", "magic_link.txt"), []byte(`no subject`), 0o644))
+	_, err := email.NewTemplates(dir, "ko", email.Brand{Name: "Acme"})
+	assert.NotNil(t, err)
+
+	_, err = email.NewTemplates("", "fr", email.Brand{Name: "Acme"})
+	assert.NotNil(t, err)
+}
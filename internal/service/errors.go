@@ -0,0 +1,35 @@
+package service
+
+import "errors"
+
+// Domain errors returned by AuthService and JwtService. Callers match them with errors.Is;
+// the handler package maps each of them to an HTTP status and error code.
+// SMS verification errors are declared in phone_verification.go.
+var (
+	// ErrEmailExists is returned when registering an email that already has an account.
+	ErrEmailExists = errors.New("email already exists")
+	// ErrInvalidCredentials is returned by Login for an unknown email or a wrong password.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrAccountDisabled is returned when signing in to an account an administrator disabled.
+	ErrAccountDisabled = errors.New("account disabled")
+	// ErrIncorrectPassword is returned when the current password given to confirm a change is wrong.
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	// ErrUserNotFound is returned when the user does not exist or was deleted.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrProfileNotFound is returned when the user has no profile.
+	ErrProfileNotFound = errors.New("profile not found")
+	// ErrInvalidBirthDate is returned for a birth date not in YYYY-MM-DD format.
+	ErrInvalidBirthDate = errors.New("birth date must be in YYYY-MM-DD format")
+
+	// ErrInvalidResetToken is returned for an unknown, expired or already used password reset token.
+	ErrInvalidResetToken = errors.New("invalid, expired, or already used token")
+	// ErrInvalidMagicLink is returned for an unknown, expired or already used magic link.
+	ErrInvalidMagicLink = errors.New("invalid, expired, or already used magic link")
+	// ErrMagicLinkDeviceMismatch is returned when a magic link is opened on another device than it was requested from.
+	ErrMagicLinkDeviceMismatch = errors.New("magic link was requested from another device")
+
+	// ErrInvalidToken is returned for a malformed or wrongly signed JWT, or a refresh token that was revoked.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrTokenExpired is returned for an expired JWT or refresh token.
+	ErrTokenExpired = errors.New("token expired")
+)
@@ -0,0 +1,120 @@
+// Package event publishes domain events of the service layer to in-process subscribers, so
+// side effects such as audit records, webhooks, metrics and notification emails can be added
+// without touching the core flows.
+package event
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"sync"
+)
+
+// Event is a domain event. Events are published after the change they describe was committed.
+type Event interface {
+	// EventName identifies the event, e.g. "user.registered".
+	EventName() string
+}
+
+// Handler handles an event of type T. Errors are logged by the Bus; they do not reach the
+// publisher.
+type Handler[T Event] func(ctx context.Context, ev T) error
+
+type subscriber struct {
+	handle func(context.Context, Event) error
+	async  bool
+}
+
+// anyEvent is the key of subscribers to every event.
+var anyEvent = reflect.TypeFor[Event]()
+
+// Bus delivers published events to the subscribers of their type. The zero value is not usable;
+// use NewBus.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[reflect.Type][]subscriber
+	wg          sync.WaitGroup
+	sync        bool // 비동기 구독자도 Publish 안에서 호출
+}
+
+// NewBus creates a Bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[reflect.Type][]subscriber)}
+}
+
+// NewSyncBus creates a Bus that also calls asynchronous subscribers before Publish returns,
+// for stores that must not be used from several goroutines, e.g. a single sqlite connection.
+func NewSyncBus() *Bus {
+	b := NewBus()
+	b.sync = true
+	return b
+}
+
+// Subscribe calls h synchronously for every published event of type T, in subscription order,
+// before Publish returns. With T = Event, h receives all events.
+func Subscribe[T Event](b *Bus, h Handler[T]) {
+	add(b, h, false)
+}
+
+// SubscribeAsync calls h in its own goroutine for every published event of type T, so slow
+// side effects, e.g. sending emails, do not delay the request. The context passed to h keeps
+// the publisher's values but is not canceled with it.
+func SubscribeAsync[T Event](b *Bus, h Handler[T]) {
+	add(b, h, true)
+}
+
+func add[T Event](b *Bus, h Handler[T], async bool) {
+	handle := func(ctx context.Context, ev Event) error {
+		return h(ctx, ev.(T))
+	}
+	key := reflect.TypeFor[T]()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[key] = append(b.subscribers[key], subscriber{handle: handle, async: async})
+}
+
+// Publish delivers ev to its subscribers. Handler errors and panics are logged and do not
+// affect the publisher or the other subscribers.
+func (b *Bus) Publish(ctx context.Context, ev Event) {
+	b.mu.RLock()
+	subs := append(append([]subscriber(nil), b.subscribers[reflect.TypeOf(ev)]...), b.subscribers[anyEvent]...)
+	b.mu.RUnlock()
+	for _, sub := range subs {
+		if !sub.async || b.sync {
+			call(ctx, sub, ev)
+			continue
+		}
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			call(context.WithoutCancel(ctx), sub, ev)
+		}()
+	}
+}
+
+func call(ctx context.Context, sub subscriber, ev Event) {
+	defer func() {
+		if p := recover(); p != nil {
+			slog.ErrorContext(ctx, "event: subscriber panicked", "event", ev.EventName(), "panic", p)
+		}
+	}()
+	if err := sub.handle(ctx, ev); err != nil {
+		slog.ErrorContext(ctx, "event: subscriber failed", "event", ev.EventName(), "error", err)
+	}
+}
+
+// Wait blocks until the running asynchronous handlers return or ctx is done. Call it on
+// shutdown, after the last Publish, before closing what the handlers use.
+func (b *Bus) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,74 @@
+package event_test
+
+import (
+	"auth/internal/service/event"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublish_동기구독(t *testing.T) {
+	bus := event.NewBus()
+	var got []string
+	event.Subscribe(bus, func(_ context.Context, e event.UserRegistered) error {
+		got = append(got, "first:"+e.Email)
+		return errors.New("ignored")
+	})
+	event.Subscribe(bus, func(_ context.Context, e event.UserRegistered) error {
+		panic("boom")
+	})
+	event.Subscribe(bus, func(_ context.Context, e event.UserRegistered) error {
+		got = append(got, "second:"+e.Email)
+		return nil
+	})
+	event.Subscribe(bus, func(_ context.Context, e event.Event) error {
+		got = append(got, "all:"+e.EventName())
+		return nil
+	})
+
+	// 에러나 패닉이 난 구독자가 있어도 나머지는 순서대로 호출된다
+	bus.Publish(context.Background(), event.UserRegistered{UserID: 1, Email: "kim@example.com"})
+	assert.Equal(t, []string{"first:kim@example.com", "second:kim@example.com", "all:" + event.NameUserRegistered}, got)
+
+	got = nil
+	bus.Publish(context.Background(), event.LoggedOut{UserID: 1})
+	assert.Equal(t, []string{"all:" + event.NameLoggedOut}, got)
+}
+
+func TestPublish_비동기구독(t *testing.T) {
+	bus := event.NewBus()
+	received := make(chan event.PasswordChanged, 1)
+	release := make(chan struct{})
+	event.SubscribeAsync(bus, func(ctx context.Context, e event.PasswordChanged) error {
+		<-release
+		assert.Nil(t, ctx.Err(), "발행한 요청이 끝나도 취소되지 않는다")
+		received <- e
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bus.Publish(ctx, event.PasswordChanged{UserID: 7})
+	cancel()
+
+	short, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	assert.ErrorIs(t, bus.Wait(short), context.DeadlineExceeded)
+
+	close(release)
+	assert.Nil(t, bus.Wait(context.Background()))
+	assert.Equal(t, int64(7), (<-received).UserID)
+}
+
+func TestNewSyncBus_비동기구독자도동기호출(t *testing.T) {
+	bus := event.NewSyncBus()
+	var got []string
+	event.SubscribeAsync(bus, func(_ context.Context, e event.UserRegistered) error {
+		got = append(got, e.Email)
+		return nil
+	})
+	bus.Publish(context.Background(), event.UserRegistered{UserID: 1, Email: "kim@example.com"})
+	assert.Equal(t, []string{"kim@example.com"}, got)
+}
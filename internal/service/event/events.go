@@ -0,0 +1,131 @@
+package event
+
+// Event names.
+const (
+	NameUserRegistered         = "user.registered"
+	NameLoggedIn               = "user.logged_in"
+	NameTokenRefreshed         = "user.token_refreshed"
+	NameLoggedOut              = "user.logged_out"
+	NamePasswordChanged        = "user.password_changed"
+	NamePasswordResetRequested = "user.password_reset_requested"
+	NamePasswordReset          = "user.password_reset"
+	NameProfileUpdated         = "user.profile_updated"
+	NamePhoneVerified          = "user.phone_verified"
+	NameAccountDeleted         = "user.account_deleted"
+	NameFailed                 = "operation.failed"
+)
+
+// Login methods of LoggedIn.
+const (
+	MethodPassword  = "password"
+	MethodMagicLink = "magic_link"
+)
+
+// UserRegistered is published when an account was created.
+type UserRegistered struct {
+	UserID      int64
+	Email       string
+	Name        string
+	PhoneNumber string
+}
+
+// EventName implements Event.
+func (UserRegistered) EventName() string { return NameUserRegistered }
+
+// LoggedIn is published when tokens were issued to a user who signed in.
+type LoggedIn struct {
+	UserID     int64
+	Email      string
+	Method     string // MethodPassword 또는 MethodMagicLink
+	DeviceInfo string
+	NewDevice  bool // 처음 보는 기기, 계정의 첫 기기는 제외
+}
+
+// EventName implements Event.
+func (LoggedIn) EventName() string { return NameLoggedIn }
+
+// TokenRefreshed is published when a refresh token was exchanged for new tokens.
+type TokenRefreshed struct {
+	UserID int64
+}
+
+// EventName implements Event.
+func (TokenRefreshed) EventName() string { return NameTokenRefreshed }
+
+// LoggedOut is published when a refresh token was revoked by its user.
+type LoggedOut struct {
+	UserID int64
+}
+
+// EventName implements Event.
+func (LoggedOut) EventName() string { return NameLoggedOut }
+
+// PasswordChanged is published when a user changed their password.
+type PasswordChanged struct {
+	UserID int64
+	Email  string
+}
+
+// EventName implements Event.
+func (PasswordChanged) EventName() string { return NamePasswordChanged }
+
+// PasswordResetRequested is published when a password reset email was sent.
+type PasswordResetRequested struct {
+	UserID int64
+	Email  string
+}
+
+// EventName implements Event.
+func (PasswordResetRequested) EventName() string { return NamePasswordResetRequested }
+
+// PasswordReset is published when a password was set with a reset token.
+type PasswordReset struct {
+	UserID int64
+	Email  string
+}
+
+// EventName implements Event.
+func (PasswordReset) EventName() string { return NamePasswordReset }
+
+// ProfileUpdated is published when a user changed their profile. It carries the profile after
+// the change.
+type ProfileUpdated struct {
+	UserID       int64
+	Name         string
+	PhoneNumber  string
+	Locale       string
+	PhoneChanged bool
+}
+
+// EventName implements Event.
+func (ProfileUpdated) EventName() string { return NameProfileUpdated }
+
+// PhoneVerified is published when a user confirmed their phone number with a code.
+type PhoneVerified struct {
+	UserID      int64
+	PhoneNumber string
+}
+
+// EventName implements Event.
+func (PhoneVerified) EventName() string { return NamePhoneVerified }
+
+// AccountDeleted is published when a user deleted their account.
+type AccountDeleted struct {
+	UserID int64
+	Email  string
+}
+
+// EventName implements Event.
+func (AccountDeleted) EventName() string { return NameAccountDeleted }
+
+// Failed is published when an operation that would have published the Action event failed.
+type Failed struct {
+	Action  string // 성공했다면 발행했을 이벤트 이름, 예: NameLoggedIn
+	UserID  int64  // 0 이면 알 수 없음
+	Outcome string // "failure": 요청 때문에 거부됨, "error": 서버 쪽 오류
+	Err     error
+	Details map[string]string
+}
+
+// EventName implements Event.
+func (Failed) EventName() string { return NameFailed }
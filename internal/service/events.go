@@ -0,0 +1,25 @@
+package service
+
+import (
+	"auth/internal/service/event"
+	"context"
+)
+
+// WithEventBus publishes the domain events of the service, e.g. event.UserRegistered, on bus,
+// so audit, webhooks and other features can subscribe to them. The caller subscribes the
+// metrics and notifications with SubscribeMetrics and AuthService.SubscribeNotifications.
+// Without it the service uses a bus of its own with both subscribed.
+func WithEventBus(bus *event.Bus) AuthServiceOption {
+	return func(s *AuthService) {
+		s.events = bus
+	}
+}
+
+// publishFailed publishes event.Failed for the action when err is not nil, with the outcome
+// classified like the metrics outcome. It is deferred by the operations with the err result.
+func (s *AuthService) publishFailed(ctx context.Context, action string, userID int64, err error, details map[string]string) {
+	if err == nil {
+		return
+	}
+	s.events.Publish(ctx, event.Failed{Action: action, UserID: userID, Outcome: metricsOutcome(err), Err: err, Details: details})
+}
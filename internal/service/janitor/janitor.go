@@ -0,0 +1,166 @@
+// Package janitor periodically deletes expired refresh, password reset and magic link tokens.
+package janitor
+
+import (
+	"auth/internal/metrics"
+	"auth/internal/repository"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// lockKey is the advisory lock key held while purging, so only one instance purges at a time.
+const lockKey = 0x746f6b6e
+
+// Token kinds, the values of the metrics kind label.
+const (
+	KindRefresh       = "refresh"
+	KindPasswordReset = "password_reset"
+	KindMagicLink     = "magic_link"
+)
+
+// Result counts the tokens deleted by a run.
+type Result struct {
+	RefreshTokens       int64 `json:"refreshTokens"`
+	PasswordResetTokens int64 `json:"passwordResetTokens"`
+	MagicLinkTokens     int64 `json:"magicLinkTokens"`
+}
+
+// Total returns the number of tokens deleted.
+func (r Result) Total() int64 {
+	return r.RefreshTokens + r.PasswordResetTokens + r.MagicLinkTokens
+}
+
+// Janitor deletes the refresh tokens that expired and the password reset and magic link tokens
+// that expired or were used. Runs hold a lock, so with several instances on Postgres only one
+// purges at a time and the others skip the run.
+type Janitor struct {
+	repo   repository.UserAdminRepository
+	locker repository.Locker
+
+	Interval  time.Duration // 실행 주기
+	BatchSize int           // 삭제 문 하나가 지우는 최대 행 수
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// New creates a Janitor with default settings.
+func New(repo repository.UserAdminRepository, locker repository.Locker) *Janitor {
+	return &Janitor{
+		repo:      repo,
+		locker:    locker,
+		Interval:  time.Hour,
+		BatchSize: 1000,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start runs the purge every Interval in a background goroutine until Stop is called.
+// It does nothing if Interval is not positive.
+func (j *Janitor) Start() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.started || j.stopped || j.Interval <= 0 {
+		return
+	}
+	j.started = true
+	go func() {
+		defer close(j.done)
+		ticker := time.NewTicker(j.Interval)
+		defer ticker.Stop()
+		// 종료 요청 시 진행 중인 삭제를 배치 사이에서 멈춘다
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			<-j.stop
+			cancel()
+		}()
+		for {
+			if res, ran, err := j.RunOnce(ctx, time.Now()); err != nil {
+				if ctx.Err() == nil {
+					slog.Error("janitor: purge failed", "error", err)
+				}
+			} else if ran && res.Total() > 0 {
+				slog.Info("janitor: purged expired tokens", "refresh", res.RefreshTokens,
+					"reset", res.PasswordResetTokens, "magicLink", res.MagicLinkTokens)
+			}
+			select {
+			case <-j.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop signals the loop to exit, cancelling a purge in progress, and waits for it to finish or
+// ctx to end. It does nothing if the janitor was never started.
+func (j *Janitor) Stop(ctx context.Context) error {
+	j.mu.Lock()
+	if !j.stopped {
+		j.stopped = true
+		close(j.stop)
+	}
+	started := j.started
+	j.mu.Unlock()
+	if !started {
+		return nil
+	}
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RunOnce deletes the tokens expired at now, BatchSize rows per statement until none are left.
+// ran is false if another instance was purging. Tokens deleted before an error are counted.
+func (j *Janitor) RunOnce(ctx context.Context, now time.Time) (res Result, ran bool, err error) {
+	ran, err = j.locker.TryLock(ctx, lockKey, func(ctx context.Context) error {
+		var err error
+		if res.RefreshTokens, err = j.purgeAll(ctx, KindRefresh, j.repo.PurgeRefreshTokens, now); err != nil {
+			return err
+		}
+		if res.PasswordResetTokens, err = j.purgeAll(ctx, KindPasswordReset, j.repo.PurgePasswordResetTokens, now); err != nil {
+			return err
+		}
+		res.MagicLinkTokens, err = j.purgeAll(ctx, KindMagicLink, j.repo.PurgeMagicLinkTokens, now)
+		return err
+	})
+	switch {
+	case err != nil:
+		metrics.TokenPurgeRuns.WithLabelValues(metrics.OutcomeError).Inc()
+	case !ran:
+		metrics.TokenPurgeRuns.WithLabelValues("skipped").Inc()
+	default:
+		metrics.TokenPurgeRuns.WithLabelValues(metrics.OutcomeSuccess).Inc()
+	}
+	return res, ran, err
+}
+
+// purgeAll calls purge until it deletes less than a full batch.
+func (j *Janitor) purgeAll(ctx context.Context, kind string, purge func(context.Context, time.Time, int) (int64, error), now time.Time) (int64, error) {
+	batchSize := j.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	var total int64
+	for {
+		n, err := purge(ctx, now, batchSize)
+		total += n
+		metrics.TokensPurged.WithLabelValues(kind).Add(float64(n))
+		if err != nil || n < int64(batchSize) {
+			return total, err
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
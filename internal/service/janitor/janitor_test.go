@@ -0,0 +1,109 @@
+package janitor_test
+
+import (
+	"auth/internal/entity"
+	"auth/internal/metrics"
+	"auth/internal/repository"
+	"auth/internal/service/janitor"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"zombiezen.com/go/sqlite"
+)
+
+func newRepos(t *testing.T) (repository.UserRepository, repository.UserAdminRepository) {
+	conn, err := sqlite.OpenConn(":memory:", 0)
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return repository.NewUserRepositorySqlite(conn), repository.NewUserAdminRepositorySqlite(conn)
+}
+
+func TestRunOnce_만료토큰삭제(t *testing.T) {
+	users, admin := newRepos(t)
+	ctx := context.Background()
+	now := time.Now()
+	var ids []int64
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		id, err := users.CreateTx(ctx, nil, &entity.UserEntity{Email: email, PasswordHash: "x", Provider: "local"})
+		assert.Nil(t, err)
+		ids = append(ids, id)
+	}
+	for _, token := range []string{"expired1", "expired2", "expired3"} {
+		assert.Nil(t, users.InsertRefreshToken(ctx, &entity.RefreshTokenEntity{UserID: ids[0], Token: token, ExpiredAt: now.Add(-time.Hour)}))
+	}
+	assert.Nil(t, users.InsertRefreshToken(ctx, &entity.RefreshTokenEntity{UserID: ids[0], Token: "valid", ExpiredAt: now.Add(time.Hour)}))
+	assert.Nil(t, users.SavePasswordResetToken(ctx, ids[0], "expired", now.Add(-time.Minute)))
+	assert.Nil(t, users.SavePasswordResetToken(ctx, ids[1], "used", now.Add(time.Hour)))
+	assert.Nil(t, users.ExpirePasswordResetToken(ctx, "used"))
+	assert.Nil(t, users.SavePasswordResetToken(ctx, ids[2], "valid", now.Add(time.Hour)))
+	assert.Nil(t, users.SaveMagicLinkToken(ctx, &entity.MagicLinkTokenEntity{UserID: ids[0], TokenHash: "expired", ExpiredAt: now.Add(-time.Minute)}))
+
+	purged := testutil.ToFloat64(metrics.TokensPurged.WithLabelValues(janitor.KindRefresh))
+	j := janitor.New(admin, repository.NewLockerSqlite())
+	j.BatchSize = 2 // 배치 크기보다 많아도 모두 지운다
+	res, ran, err := j.RunOnce(ctx, now)
+	assert.Nil(t, err)
+	assert.True(t, ran)
+	assert.Equal(t, janitor.Result{RefreshTokens: 3, PasswordResetTokens: 2, MagicLinkTokens: 1}, res)
+	assert.Equal(t, purged+3, testutil.ToFloat64(metrics.TokensPurged.WithLabelValues(janitor.KindRefresh)))
+
+	rt, err := users.FindRefreshToken(ctx, "valid")
+	assert.Nil(t, err)
+	assert.NotNil(t, rt)
+	reset, err := users.FindByPasswordResetToken(ctx, "valid")
+	assert.Nil(t, err)
+	assert.NotNil(t, reset)
+
+	res, _, err = j.RunOnce(ctx, now)
+	assert.Nil(t, err)
+	assert.Equal(t, janitor.Result{}, res)
+}
+
+func TestRunOnce_다른인스턴스실행중이면건너뜀(t *testing.T) {
+	_, admin := newRepos(t)
+	ctx := context.Background()
+	locker := repository.NewLockerSqlite()
+	j := janitor.New(admin, locker)
+
+	skipped := testutil.ToFloat64(metrics.TokenPurgeRuns.WithLabelValues("skipped"))
+	ran, err := locker.TryLock(ctx, 1, func(ctx context.Context) error {
+		_, ran, err := j.RunOnce(ctx, time.Now())
+		assert.Nil(t, err)
+		assert.False(t, ran)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.True(t, ran)
+	assert.Equal(t, skipped+1, testutil.ToFloat64(metrics.TokenPurgeRuns.WithLabelValues("skipped")))
+}
+
+func TestStartStop(t *testing.T) {
+	users, admin := newRepos(t)
+	ctx := context.Background()
+	id, err := users.CreateTx(ctx, nil, &entity.UserEntity{Email: "a@example.com", PasswordHash: "x", Provider: "local"})
+	assert.Nil(t, err)
+	assert.Nil(t, users.InsertRefreshToken(ctx, &entity.RefreshTokenEntity{UserID: id, Token: "expired", ExpiredAt: time.Now().Add(-time.Hour)}))
+
+	runs := testutil.ToFloat64(metrics.TokenPurgeRuns.WithLabelValues(metrics.OutcomeSuccess))
+	j := janitor.New(admin, repository.NewLockerSqlite())
+	j.Interval = time.Hour
+	j.Start() // 시작하자마자 한 번 실행한다
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.TokenPurgeRuns.WithLabelValues(metrics.OutcomeSuccess)) > runs
+	}, time.Second, 10*time.Millisecond)
+	assert.Nil(t, j.Stop(ctx))
+	rt, err := users.FindRefreshToken(ctx, "expired")
+	assert.Nil(t, err)
+	assert.Nil(t, rt)
+
+	// Interval 이 0 이면 시작하지 않는다
+	j = janitor.New(admin, repository.NewLockerSqlite())
+	j.Interval = 0
+	j.Start()
+	assert.Nil(t, j.Stop(ctx))
+}
@@ -0,0 +1,303 @@
+// Package jwks manages the ES256 key pairs that sign access tokens and publishes their public
+// keys as a JSON Web Key Set, so other services can verify tokens offline.
+package jwks
+
+import (
+	"auth/internal/entity"
+	"auth/internal/repository"
+	"auth/pkg/utils"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Algorithm is the JWS algorithm of the keys.
+const Algorithm = "ES256"
+
+// reloadSpacing is the minimum time between reloads caused by tokens with an unknown key ID,
+// so garbage tokens cannot make every request hit the database.
+const reloadSpacing = 10 * time.Second
+
+// Key is a signing key pair.
+type Key struct {
+	ID        string
+	Private   *ecdsa.PrivateKey
+	CreatedAt time.Time
+	RetiredAt *time.Time // 교체된 키, RetainFor 동안 검증에만 쓴다
+}
+
+// JWK is the public half of a key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
+
+// Document is a JSON Web Key Set, served at /.well-known/jwks.json.
+type Document struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeySet caches the signing keys of the signing_keys table. Every instance of the service
+// reloads them every Interval, so a key created by Rotate on one instance is picked up by the
+// others; tokens with a key ID that is not cached yet trigger an early reload.
+type KeySet struct {
+	repo repository.SigningKeyRepository
+
+	Interval time.Duration
+	// RetainFor is how long retired keys stay in the set after rotation. It must be longer
+	// than the access token lifetime plus the cache time of verifiers, or tokens signed just
+	// before a rotation are rejected.
+	RetainFor time.Duration
+
+	mu         sync.RWMutex
+	signing    *Key
+	keys       map[string]*Key
+	loadedAt   time.Time
+	reloadMu   sync.Mutex
+	lastReload time.Time
+
+	lifecycle sync.Mutex
+	started   bool
+	stopped   bool
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewKeySet creates an empty KeySet; call Load or Ensure before signing tokens.
+func NewKeySet(repo repository.SigningKeyRepository) *KeySet {
+	return &KeySet{
+		repo:      repo,
+		Interval:  time.Minute,
+		RetainFor: time.Hour,
+		keys:      map[string]*Key{},
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Load reads the keys from the database, dropping keys retired more than RetainFor ago.
+// The newest active key becomes the signing key.
+func (s *KeySet) Load(ctx context.Context) error {
+	entities, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	keys := make(map[string]*Key, len(entities))
+	var signing *Key
+	for _, e := range entities {
+		if e.RetiredAt != nil && now.Sub(*e.RetiredAt) > s.RetainFor {
+			continue
+		}
+		k, err := parseKey(e)
+		if err != nil {
+			slog.WarnContext(ctx, "jwks: skipping unreadable signing key", "kid", e.KID, "error", err)
+			continue
+		}
+		keys[k.ID] = k
+		// List 는 최신순이므로 처음 만나는 활성 키가 서명 키다
+		if signing == nil && k.RetiredAt == nil {
+			signing = k
+		}
+	}
+	s.mu.Lock()
+	s.keys = keys
+	s.signing = signing
+	s.loadedAt = now
+	s.mu.Unlock()
+	return nil
+}
+
+// Ensure loads the keys and creates one if there is no active key, e.g. on first start.
+func (s *KeySet) Ensure(ctx context.Context) error {
+	if err := s.Load(ctx); err != nil {
+		return err
+	}
+	if s.SigningKey() != nil {
+		return nil
+	}
+	_, err := s.Rotate(ctx)
+	return err
+}
+
+// Rotate creates a new signing key and retires the others. Retired keys keep verifying tokens
+// for RetainFor; other instances start signing with the new key on their next reload.
+func (s *KeySet) Rotate(ctx context.Context) (*Key, error) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	e := &entity.SigningKeyEntity{
+		KID:        utils.GenerateRandomString(16),
+		Algorithm:  Algorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+	}
+	if err := s.repo.Create(ctx, e); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.RetireOthers(ctx, e.KID, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.Load(ctx); err != nil {
+		return nil, err
+	}
+	return &Key{ID: e.KID, Private: private, CreatedAt: e.CreatedAt}, nil
+}
+
+// SigningKey returns the key that signs new tokens, or nil if there is none.
+func (s *KeySet) SigningKey() *Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.signing
+}
+
+// Keys returns the cached keys, the signing key and the retired keys still in the set,
+// newest first.
+func (s *KeySet) Keys() []*Key {
+	s.mu.RLock()
+	keys := make([]*Key, 0, len(s.keys))
+	for _, k := range s.keys {
+		keys = append(keys, k)
+	}
+	s.mu.RUnlock()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys
+}
+
+// PublicKey returns the public key with the key ID. An unknown ID reloads the keys first,
+// at most once every few seconds, since another instance may have just rotated.
+func (s *KeySet) PublicKey(kid string) (*ecdsa.PublicKey, bool) {
+	if k := s.key(kid); k != nil {
+		return &k.Private.PublicKey, true
+	}
+	s.reloadMu.Lock()
+	if time.Since(s.lastReload) >= reloadSpacing {
+		s.lastReload = time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := s.Load(ctx); err != nil {
+			slog.Warn("jwks: reload failed", "error", err)
+		}
+		cancel()
+	}
+	s.reloadMu.Unlock()
+	if k := s.key(kid); k != nil {
+		return &k.Private.PublicKey, true
+	}
+	return nil, false
+}
+
+func (s *KeySet) key(kid string) *Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys[kid]
+}
+
+// Document returns the public keys as a JSON Web Key Set.
+func (s *KeySet) Document() Document {
+	doc := Document{Keys: []JWK{}}
+	for _, k := range s.Keys() {
+		doc.Keys = append(doc.Keys, publicJWK(k))
+	}
+	return doc
+}
+
+// publicJWK encodes the public key of k. The uncompressed point is 0x04 || X || Y with
+// 32-byte coordinates for P-256.
+func publicJWK(k *Key) JWK {
+	pub, _ := k.Private.PublicKey.ECDH() // P-256 키는 항상 변환된다
+	point := pub.Bytes()
+	return JWK{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(point[1:33]),
+		Y:   base64.RawURLEncoding.EncodeToString(point[33:]),
+		Kid: k.ID,
+		Use: "sig",
+		Alg: Algorithm,
+	}
+}
+
+func parseKey(e *entity.SigningKeyEntity) (*Key, error) {
+	if e.Algorithm != Algorithm {
+		return nil, fmt.Errorf("unsupported algorithm %q", e.Algorithm)
+	}
+	block, _ := pem.Decode([]byte(e.PrivateKey))
+	if block == nil {
+		return nil, errors.New("invalid PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok || private.Curve != elliptic.P256() {
+		return nil, errors.New("not a P-256 key")
+	}
+	return &Key{ID: e.KID, Private: private, CreatedAt: e.CreatedAt, RetiredAt: e.RetiredAt}, nil
+}
+
+// Start reloads the keys every Interval in a background goroutine until Stop is called.
+// It does nothing if Interval is not positive.
+func (s *KeySet) Start() {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+	if s.started || s.stopped || s.Interval <= 0 {
+		return
+	}
+	s.started = true
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+			if err := s.Load(context.Background()); err != nil {
+				slog.Error("jwks: reload failed", "error", err)
+			}
+		}
+	}()
+}
+
+// Stop signals the reload loop to exit and waits for it to finish or ctx to end.
+// It does nothing if the loop was never started.
+func (s *KeySet) Stop(ctx context.Context) error {
+	s.lifecycle.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.stop)
+	}
+	started := s.started
+	s.lifecycle.Unlock()
+	if !started {
+		return nil
+	}
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
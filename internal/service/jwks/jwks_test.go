@@ -0,0 +1,86 @@
+package jwks_test
+
+import (
+	"auth/internal/repository"
+	"auth/internal/service/jwks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"zombiezen.com/go/sqlite"
+)
+
+func newRepo(t *testing.T) repository.SigningKeyRepository {
+	conn, err := sqlite.OpenConn(":memory:", 0)
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return repository.NewSigningKeyRepositoryAuto("sqlite", nil, conn)
+}
+
+func TestKeySet_Ensure_처음에만생성(t *testing.T) {
+	repo := newRepo(t)
+	ctx := context.Background()
+	keys := jwks.NewKeySet(repo)
+	assert.Nil(t, keys.SigningKey())
+
+	assert.Nil(t, keys.Ensure(ctx))
+	first := keys.SigningKey()
+	assert.NotNil(t, first)
+
+	// 다른 인스턴스는 같은 키를 읽는다
+	other := jwks.NewKeySet(repo)
+	assert.Nil(t, other.Ensure(ctx))
+	assert.Equal(t, first.ID, other.SigningKey().ID)
+
+	doc := keys.Document()
+	assert.Len(t, doc.Keys, 1)
+	assert.Equal(t, first.ID, doc.Keys[0].Kid)
+	assert.Equal(t, "EC", doc.Keys[0].Kty)
+	assert.Equal(t, "P-256", doc.Keys[0].Crv)
+	assert.Equal(t, "ES256", doc.Keys[0].Alg)
+	assert.Len(t, doc.Keys[0].X, 43) // 32바이트 base64url
+	assert.Len(t, doc.Keys[0].Y, 43)
+}
+
+func TestKeySet_Rotate(t *testing.T) {
+	repo := newRepo(t)
+	ctx := context.Background()
+	keys := jwks.NewKeySet(repo)
+	assert.Nil(t, keys.Ensure(ctx))
+	old := keys.SigningKey()
+	other := jwks.NewKeySet(repo)
+	assert.Nil(t, other.Load(ctx))
+
+	rotated, err := keys.Rotate(ctx)
+	assert.Nil(t, err)
+	assert.NotEqual(t, old.ID, rotated.ID)
+	assert.Equal(t, rotated.ID, keys.SigningKey().ID)
+	// 교체된 키도 RetainFor 동안은 검증에 쓴다
+	assert.Len(t, keys.Document().Keys, 2)
+	_, ok := keys.PublicKey(old.ID)
+	assert.True(t, ok)
+
+	// 다른 인스턴스는 모르는 kid 를 만나면 다시 읽는다
+	_, ok = other.PublicKey(rotated.ID)
+	assert.True(t, ok)
+	assert.Equal(t, rotated.ID, other.SigningKey().ID)
+	_, ok = other.PublicKey("unknown")
+	assert.False(t, ok)
+
+	// 보존 기간이 지난 키는 빠진다
+	expired := jwks.NewKeySet(repo)
+	expired.RetainFor = 0
+	assert.Nil(t, expired.Load(ctx))
+	assert.Len(t, expired.Document().Keys, 1)
+	_, ok = expired.PublicKey(old.ID)
+	assert.False(t, ok)
+}
+
+func TestKeySet_Interval0이면시작하지않음(t *testing.T) {
+	keys := jwks.NewKeySet(newRepo(t))
+	keys.Interval = 0
+	keys.Start()
+	assert.Nil(t, keys.Stop(context.Background()))
+}
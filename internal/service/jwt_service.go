@@ -2,6 +2,7 @@
 package service
 
 import (
+	"auth/internal/service/jwks"
 	"errors"
 	"fmt"
 	"time"
@@ -13,14 +14,32 @@ import (
 type JwtService struct {
 	accessTokenSecret  []byte
 	refreshTokenSecret []byte
+	keys               *jwks.KeySet // 설정하면 access token 을 ES256 으로 서명
+}
+
+// JwtOption configures a JwtService.
+type JwtOption func(*JwtService)
+
+// WithSigningKeys signs access tokens with the ES256 signing key of keys, with its ID in the
+// kid header, so other services can verify them with the published JWKS. HS256 access tokens
+// issued before are still accepted until they expire. Refresh tokens stay HS256, since only
+// this service reads them.
+func WithSigningKeys(keys *jwks.KeySet) JwtOption {
+	return func(s *JwtService) {
+		s.keys = keys
+	}
 }
 
 // NewJwtService creates a new JwtService.
-func NewJwtService(secret string) *JwtService {
-	return &JwtService{
+func NewJwtService(secret string, opts ...JwtOption) *JwtService {
+	s := &JwtService{
 		accessTokenSecret:  []byte(secret),
 		refreshTokenSecret: []byte(secret + "-refresh"),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // GenerateToken generates a JWT access token for the given user ID.
@@ -30,6 +49,15 @@ func (s *JwtService) GenerateToken(userID int64) (string, error) {
 		ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
 		IssuedAt:  jwt.NewNumericDate(time.Now()),
 	}
+	if s.keys != nil {
+		key := s.keys.SigningKey()
+		if key == nil {
+			return "", errors.New("no signing key")
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = key.ID
+		return token.SignedString(key.Private)
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(s.accessTokenSecret)
 }
@@ -49,29 +77,47 @@ func (s *JwtService) GenerateRefreshToken(userID int64, deviceInfo string) (stri
 // ValidateAccessToken validates the access token and returns the user ID.
 func (s *JwtService) ValidateAccessToken(tokenString string) (userID int64, err error) {
 	// RegisteredClaims 사용
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(_ *jwt.Token) (interface{}, error) {
-		return s.accessTokenSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, s.accessTokenKey)
 	if err != nil {
-		return 0, err
+		return 0, tokenError(err)
 	}
 	claims, ok := token.Claims.(*jwt.RegisteredClaims)
 	if !ok || !token.Valid {
-		return 0, errors.New("invalid access token")
+		return 0, ErrInvalidToken
 	}
 	// 만료 검증
-	if claims.ExpiresAt.Before(time.Now()) {
-		return 0, errors.New("access token expired")
+	if claims.ExpiresAt == nil || claims.ExpiresAt.Before(time.Now()) {
+		return 0, ErrTokenExpired
 	}
 	// Subject에 저장된 userID 파싱
 	var id int64
 	_, err = fmt.Sscan(claims.Subject, &id)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf("%w: invalid subject", ErrInvalidToken)
 	}
 	return id, nil
 }
 
+// accessTokenKey returns the key that verifies an access token: the shared secret for HS256,
+// or the public key named by the kid header for ES256 when signing keys are configured.
+func (s *JwtService) accessTokenKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method {
+	case jwt.SigningMethodHS256:
+		return s.accessTokenSecret, nil
+	case jwt.SigningMethodES256:
+		if s.keys == nil {
+			break
+		}
+		kid, _ := token.Header["kid"].(string)
+		key, ok := s.keys.PublicKey(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+}
+
 // ValidateRefreshToken validates the refresh token and returns the user ID and device info.
 func (s *JwtService) ValidateRefreshToken(tokenString string) (userID int64, deviceInfo string, err error) {
 	// MapClaims 사용
@@ -79,27 +125,35 @@ func (s *JwtService) ValidateRefreshToken(tokenString string) (userID int64, dev
 		return s.refreshTokenSecret, nil
 	})
 	if err != nil {
-		return 0, "", err
+		return 0, "", tokenError(err)
 	}
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok || !token.Valid {
-		return 0, "", errors.New("invalid refresh token")
+		return 0, "", ErrInvalidToken
 	}
 	// 만료 검증
 	exp, ok := claims["exp"].(float64)
 	if !ok || time.Unix(int64(exp), 0).Before(time.Now()) {
-		return 0, "", errors.New("refresh token expired")
+		return 0, "", ErrTokenExpired
 	}
 	// sub, dev 정보 추출
 	sub, ok1 := claims["sub"].(string)
 	dev, ok2 := claims["dev"].(string)
 	if !ok1 || !ok2 {
-		return 0, "", errors.New("invalid token claims")
+		return 0, "", fmt.Errorf("%w: missing claims", ErrInvalidToken)
 	}
 	var id int64
 	_, err = fmt.Sscan(sub, &id)
 	if err != nil {
-		return 0, "", err
+		return 0, "", fmt.Errorf("%w: invalid subject", ErrInvalidToken)
 	}
 	return id, dev, nil
 }
+
+// tokenError classifies a JWT parse error as ErrTokenExpired or ErrInvalidToken.
+func tokenError(err error) error {
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return ErrTokenExpired
+	}
+	return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+}
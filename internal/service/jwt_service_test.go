@@ -1,16 +1,20 @@
 package service_test
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
 	"time"
 	"unsafe"
 
+	"auth/internal/repository"
 	"auth/internal/service"
+	"auth/internal/service/jwks"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/stretchr/testify/assert"
+	"zombiezen.com/go/sqlite"
 )
 
 func Test_JwtService_AccessToken(t *testing.T) {
@@ -51,6 +55,7 @@ func Test_JwtService_RefreshToken_Invalid(t *testing.T) {
 	// 잘못된 토큰
 	_, _, err := jwtSvc.ValidateRefreshToken("invalid.token.value")
 	assert.NotNil(t, err)
+	assert.ErrorIs(t, err, service.ErrInvalidToken)
 }
 
 // 테스트용: 만료 시간 지정해서 access token 생성
@@ -90,6 +95,7 @@ func Test_JwtService_AccessToken_Expired(t *testing.T) {
 	_, err = jwtSvc.ValidateAccessToken(token)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "expired")
+	assert.ErrorIs(t, err, service.ErrTokenExpired)
 }
 
 func Test_JwtService_RefreshToken_Expired(t *testing.T) {
@@ -130,3 +136,34 @@ func getUnexportedField(obj interface{}, field string) interface{} {
 	v := reflect.ValueOf(obj).Elem().FieldByName(field)
 	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem().Interface()
 }
+
+func Test_JwtService_ES256(t *testing.T) {
+	conn, err := sqlite.OpenConn(":memory:", 0)
+	assert.Nil(t, err)
+	defer conn.Close()
+	keys := jwks.NewKeySet(repository.NewSigningKeyRepositoryAuto("sqlite", nil, conn))
+	assert.Nil(t, keys.Ensure(context.Background()))
+	jwtSvc := service.NewJwtService("test-secret", service.WithSigningKeys(keys))
+
+	token, err := jwtSvc.GenerateToken(42)
+	assert.Nil(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+	assert.Nil(t, err)
+	assert.Equal(t, "ES256", parsed.Header["alg"])
+	assert.Equal(t, keys.SigningKey().ID, parsed.Header["kid"])
+
+	userID, err := jwtSvc.ValidateAccessToken(token)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), userID)
+
+	// 전환 전에 발급한 HS256 토큰도 만료될 때까지 받는다
+	legacy, err := service.NewJwtService("test-secret").GenerateToken(7)
+	assert.Nil(t, err)
+	userID, err = jwtSvc.ValidateAccessToken(legacy)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), userID)
+
+	// 서명 키가 없는 서비스는 ES256 토큰을 받지 않는다
+	_, err = service.NewJwtService("test-secret").ValidateAccessToken(token)
+	assert.ErrorIs(t, err, service.ErrInvalidToken)
+}
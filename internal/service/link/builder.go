@@ -0,0 +1,208 @@
+// Package link builds the frontend URLs sent in emails.
+package link
+
+import (
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Flows with a link in their email.
+const (
+	FlowPasswordReset = "password_reset"
+	FlowMagicLink     = "magic_link"
+)
+
+// Query parameter names added by the builder.
+const (
+	ParamRedirect  = "redirect"
+	ParamSignature = "sig"
+)
+
+// DefaultPaths are the path templates used for flows without a configured path.
+var DefaultPaths = map[string]string{
+	FlowPasswordReset: "/reset-password.html",
+	FlowMagicLink:     "/magic-link.html",
+}
+
+var (
+	// ErrRedirectNotAllowed is returned for a redirect URL that is not in the allowlist.
+	ErrRedirectNotAllowed = errors.New("redirect url not allowed")
+	// ErrInvalidSignature is returned when link parameters do not match their signature.
+	ErrInvalidSignature = errors.New("invalid link signature")
+)
+
+// Builder builds links as base URL + per-flow path template + query parameters.
+//
+// A path template may contain {name} placeholders filled from the parameters of the same name,
+// e.g. "/auth/reset/{token}"; the remaining parameters are added to the query string.
+// When a secret is set, the parameters are signed with HMAC-SHA256 in the "sig" parameter so the
+// redirect URL cannot be altered without the server noticing.
+type Builder struct {
+	base      *url.URL
+	paths     map[string]string
+	redirects []*url.URL
+	secret    []byte
+}
+
+// DeriveSecret derives a link signing secret from another secret, e.g. the JWT secret, with
+// HKDF-SHA256 and the "link" label, so a link signature never doubles as a token signature.
+func DeriveSecret(secret []byte) ([]byte, error) {
+	return hkdf.Key(sha256.New, secret, nil, "link", sha256.Size)
+}
+
+// NewBuilder creates a Builder. paths overrides DefaultPaths per flow; allowedRedirects lists
+// origins ("https://app.example.com") or URL prefixes ("https://app.example.com/callback")
+// clients may redirect to. The origin of baseURL is always allowed.
+func NewBuilder(baseURL string, paths map[string]string, allowedRedirects []string, secret []byte) (*Builder, error) {
+	base, err := parseAbsolute(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("public base url: %w", err)
+	}
+	base.Path = strings.TrimSuffix(base.Path, "/")
+	b := &Builder{base: base, paths: map[string]string{}, secret: secret}
+	for flow, p := range DefaultPaths {
+		b.paths[flow] = p
+	}
+	for flow, p := range paths {
+		if p != "" {
+			b.paths[flow] = p
+		}
+	}
+	b.redirects = append(b.redirects, &url.URL{Scheme: base.Scheme, Host: base.Host})
+	for _, r := range allowedRedirects {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		u, err := parseAbsolute(r)
+		if err != nil {
+			return nil, fmt.Errorf("redirect allowlist entry %q: %w", r, err)
+		}
+		b.redirects = append(b.redirects, u)
+	}
+	return b, nil
+}
+
+func parseAbsolute(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("must be an absolute http(s) url")
+	}
+	return u, nil
+}
+
+// ValidateRedirect returns ErrRedirectNotAllowed unless redirect is an absolute http(s) URL on an
+// allowed origin and under the allowed path, if the allowlist entry has one. An empty redirect is valid.
+func (b *Builder) ValidateRedirect(redirect string) error {
+	if redirect == "" {
+		return nil
+	}
+	u, err := parseAbsolute(redirect)
+	if err != nil || u.User != nil {
+		return ErrRedirectNotAllowed
+	}
+	for _, allowed := range b.redirects {
+		if !strings.EqualFold(u.Scheme, allowed.Scheme) || !strings.EqualFold(u.Host, allowed.Host) {
+			continue
+		}
+		prefix := strings.TrimSuffix(allowed.Path, "/")
+		if prefix == "" || u.Path == prefix || strings.HasPrefix(u.Path, prefix+"/") {
+			return nil
+		}
+	}
+	return ErrRedirectNotAllowed
+}
+
+// Build returns the link for flow. A non-empty redirect is validated against the allowlist
+// and added as the "redirect" parameter.
+func (b *Builder) Build(flow string, params map[string]string, redirect string) (string, error) {
+	tmpl, ok := b.paths[flow]
+	if !ok {
+		return "", fmt.Errorf("unknown link flow %q", flow)
+	}
+	if err := b.ValidateRedirect(redirect); err != nil {
+		return "", err
+	}
+	query := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+	if redirect != "" {
+		query.Set(ParamRedirect, redirect)
+	}
+	if len(b.secret) > 0 {
+		query.Set(ParamSignature, b.sign(flow, query))
+	}
+
+	// 경로의 {name} 자리표시자를 채우고 나머지는 쿼리 문자열로
+	path, rawQuery, _ := strings.Cut(tmpl, "?")
+	fixed, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("link path for %q: %w", flow, err)
+	}
+	for k, v := range query {
+		placeholder := "{" + k + "}"
+		if strings.Contains(path, placeholder) {
+			path = strings.ReplaceAll(path, placeholder, url.PathEscape(v[0]))
+			continue
+		}
+		fixed[k] = v
+	}
+	ref, err := url.Parse(b.base.EscapedPath() + "/" + strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return "", fmt.Errorf("link path for %q: %w", flow, err)
+	}
+	u := *b.base
+	u.Path, u.RawPath = ref.Path, ref.RawPath
+	u.RawQuery = fixed.Encode()
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// Verify checks the signature of the parameters of a link built for flow. It does nothing
+// when no secret is configured.
+func (b *Builder) Verify(flow string, params map[string]string, signature string) error {
+	if len(b.secret) == 0 {
+		return nil
+	}
+	query := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+	expected := b.sign(flow, query)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// sign returns the HMAC of the flow and the parameters sorted by name.
+func (b *Builder) sign(flow string, query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		if k != ParamSignature {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	mac := hmac.New(sha256.New, b.secret)
+	mac.Write([]byte("link:" + flow))
+	for _, k := range keys {
+		mac.Write([]byte("\n" + url.QueryEscape(k) + "=" + url.QueryEscape(query.Get(k))))
+	}
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
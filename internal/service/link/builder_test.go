@@ -0,0 +1,101 @@
+package link_test
+
+import (
+	"auth/internal/service/link"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild_기본경로(t *testing.T) {
+	b, err := link.NewBuilder("https://app.example.com/", nil, nil, nil)
+	assert.Nil(t, err)
+	u, err := b.Build(link.FlowPasswordReset, map[string]string{"token": "a b&c"}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://app.example.com/reset-password.html?token=a+b%26c", u)
+
+	_, err = b.Build("unknown", nil, "")
+	assert.NotNil(t, err)
+}
+
+func TestBuild_경로템플릿(t *testing.T) {
+	b, err := link.NewBuilder("https://example.com/app", map[string]string{
+		link.FlowMagicLink: "/auth/magic/{token}?source=email",
+	}, nil, nil)
+	assert.Nil(t, err)
+	u, err := b.Build(link.FlowMagicLink, map[string]string{"token": "x/y"}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/app/auth/magic/x%2Fy?source=email", u)
+}
+
+func TestValidateRedirect(t *testing.T) {
+	b, err := link.NewBuilder("https://app.example.com", nil, []string{"https://partner.example.org/callback", "http://localhost:5173"}, nil)
+	assert.Nil(t, err)
+
+	allowed := []string{
+		"",
+		"https://app.example.com/anything",
+		"https://partner.example.org/callback",
+		"https://partner.example.org/callback/done?x=1",
+		"http://localhost:5173/",
+	}
+	for _, r := range allowed {
+		assert.Nil(t, b.ValidateRedirect(r), r)
+	}
+	denied := []string{
+		"https://partner.example.org/callbackevil",
+		"https://partner.example.org/other",
+		"https://app.example.com.evil.com/",
+		"http://app.example.com/",
+		"https://user@app.example.com/",
+		"//app.example.com/",
+		"/relative",
+		"javascript:alert(1)",
+	}
+	for _, r := range denied {
+		assert.ErrorIs(t, b.ValidateRedirect(r), link.ErrRedirectNotAllowed, r)
+	}
+
+	_, err = link.NewBuilder("https://app.example.com", nil, []string{"not a url"}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestBuild_서명(t *testing.T) {
+	b, err := link.NewBuilder("https://app.example.com", nil, nil, []byte("secret"))
+	assert.Nil(t, err)
+	redirect := "https://app.example.com/welcome"
+	raw, err := b.Build(link.FlowMagicLink, map[string]string{"token": "t1"}, redirect)
+	assert.Nil(t, err)
+
+	u, err := url.Parse(raw)
+	assert.Nil(t, err)
+	q := u.Query()
+	assert.Equal(t, redirect, q.Get(link.ParamRedirect))
+	sig := q.Get(link.ParamSignature)
+	assert.NotEmpty(t, sig)
+
+	params := map[string]string{"token": "t1", link.ParamRedirect: redirect}
+	assert.Nil(t, b.Verify(link.FlowMagicLink, params, sig))
+	// 다른 흐름, 바뀐 redirect, 다른 키는 거부
+	assert.ErrorIs(t, b.Verify(link.FlowPasswordReset, params, sig), link.ErrInvalidSignature)
+	assert.ErrorIs(t, b.Verify(link.FlowMagicLink, map[string]string{"token": "t1", link.ParamRedirect: "https://app.example.com/other"}, sig), link.ErrInvalidSignature)
+	other, _ := link.NewBuilder("https://app.example.com", nil, nil, []byte("other"))
+	assert.ErrorIs(t, other.Verify(link.FlowMagicLink, params, sig), link.ErrInvalidSignature)
+
+	_, err = b.Build(link.FlowMagicLink, map[string]string{"token": "t1"}, "https://evil.example.com/")
+	assert.ErrorIs(t, err, link.ErrRedirectNotAllowed)
+}
+
+func TestDeriveSecret(t *testing.T) {
+	a, err := link.DeriveSecret([]byte("jwt-secret"))
+	assert.Nil(t, err)
+	assert.Len(t, a, 32)
+	assert.NotEqual(t, []byte("jwt-secret"), a)
+	b, err := link.DeriveSecret([]byte("jwt-secret"))
+	assert.Nil(t, err)
+	assert.Equal(t, a, b, "같은 키에서는 같은 서명 키가 나온다")
+	c, err := link.DeriveSecret([]byte("other-secret"))
+	assert.Nil(t, err)
+	assert.NotEqual(t, a, c)
+}
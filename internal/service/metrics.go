@@ -0,0 +1,68 @@
+package service
+
+import (
+	"auth/internal/metrics"
+	"auth/internal/service/event"
+	"auth/internal/service/link"
+	"auth/internal/service/password"
+	"auth/internal/service/phone"
+	"context"
+	"errors"
+)
+
+// requestErrors are the errors caused by the request rather than by the server.
+var requestErrors = []error{
+	ErrEmailExists, ErrInvalidCredentials, ErrAccountDisabled, ErrIncorrectPassword, ErrUserNotFound, ErrProfileNotFound,
+	ErrInvalidBirthDate, ErrInvalidResetToken, ErrInvalidMagicLink, ErrMagicLinkDeviceMismatch,
+	ErrInvalidToken, ErrTokenExpired, ErrPhoneNumberInUse,
+	ErrVerificationCodeRequired, ErrInvalidVerificationCode, ErrVerificationCodeExpired,
+	ErrTooManyVerificationAttempts, ErrVerificationCodeRecentlySent, ErrTooManyVerificationCodes,
+	phone.ErrInvalidNumber, phone.ErrNotMobile, link.ErrRedirectNotAllowed, link.ErrInvalidSignature,
+}
+
+// metricsOutcome returns the outcome label of the business counters for an operation's error:
+// failure for requestErrors and password policy violations, error for anything else.
+func metricsOutcome(err error) string {
+	if err == nil {
+		return metrics.OutcomeSuccess
+	}
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		return metrics.OutcomeFailure
+	}
+	for _, e := range requestErrors {
+		if errors.Is(err, e) {
+			return metrics.OutcomeFailure
+		}
+	}
+	return metrics.OutcomeError
+}
+
+// SubscribeMetrics counts the business operations, successful and failed, from the events
+// published on bus. Subscribe each bus once.
+func SubscribeMetrics(bus *event.Bus) {
+	event.Subscribe(bus, func(_ context.Context, e event.Event) error {
+		switch e := e.(type) {
+		case event.UserRegistered:
+			metrics.Registrations.WithLabelValues(metrics.OutcomeSuccess).Inc()
+		case event.LoggedIn:
+			metrics.Logins.WithLabelValues(e.Method, metrics.OutcomeSuccess).Inc()
+		case event.TokenRefreshed:
+			metrics.TokenRefreshes.WithLabelValues(metrics.OutcomeSuccess).Inc()
+		case event.PasswordResetRequested:
+			metrics.PasswordResetRequests.WithLabelValues(metrics.OutcomeSuccess).Inc()
+		case event.Failed:
+			switch e.Action {
+			case event.NameUserRegistered:
+				metrics.Registrations.WithLabelValues(e.Outcome).Inc()
+			case event.NameLoggedIn:
+				metrics.Logins.WithLabelValues(e.Details["method"], e.Outcome).Inc()
+			case event.NameTokenRefreshed:
+				metrics.TokenRefreshes.WithLabelValues(e.Outcome).Inc()
+			case event.NamePasswordResetRequested:
+				metrics.PasswordResetRequests.WithLabelValues(e.Outcome).Inc()
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"auth/internal/dto"
+	"auth/internal/entity"
+	"auth/internal/service/email"
+	"auth/internal/service/event"
+	"auth/internal/tracing"
+	"auth/pkg/utils"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// notificationEnabled reports whether the preferences allow emails for the security event.
+func notificationEnabled(p *entity.NotificationPreferenceEntity, event string) bool {
+	switch event {
+	case email.SecurityEventNewDeviceLogin:
+		return p.NewDeviceLogin
+	case email.SecurityEventPasswordChanged, email.SecurityEventPasswordReset:
+		return p.PasswordChanged
+	case email.SecurityEventPhoneChanged:
+		return p.PhoneChanged
+	case email.SecurityEventAccountDeleted:
+		return p.AccountDeleted
+	}
+	return true
+}
+
+// NotifySecurityEvent emails the user about a security-relevant account event (email.SecurityEvent*),
+// unless they turned off notifications for it. to defaults to the user's email address.
+// Failures are logged and do not affect the caller.
+func (s *AuthService) NotifySecurityEvent(ctx context.Context, userID int64, to, event, deviceInfo, detail string) {
+	ctx, span := tracing.Start(ctx, "AuthService.NotifySecurityEvent")
+	defer span.End()
+	pref, err := s.profileRepo.FindNotificationPreference(ctx, userID)
+	if err != nil {
+		slog.WarnContext(ctx, "NotifySecurityEvent: find preference failed", "userId", userID, "error", err)
+		return
+	}
+	if !notificationEnabled(pref, event) {
+		return
+	}
+	if to == "" {
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil || user == nil {
+			slog.WarnContext(ctx, "NotifySecurityEvent: user not found", "userId", userID, "error", err)
+			return
+		}
+		to = user.Email
+	}
+	msg, err := s.emailService.RenderSecurityAlert(s.emailLocale(ctx, userID, ""), email.SecurityAlertEmailData{
+		Event:      event,
+		OccurredAt: time.Now().UTC().Format("2006-01-02 15:04 MST"),
+		DeviceInfo: deviceInfo,
+		Detail:     detail,
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "NotifySecurityEvent: render email failed", "userId", userID, "event", event, "error", err)
+		return
+	}
+	if err := s.sendEmail(ctx, nil, to, msg); err != nil {
+		slog.WarnContext(ctx, "NotifySecurityEvent: send email failed", "userId", userID, "event", event, "error", err)
+		return
+	}
+	slog.InfoContext(ctx, "NotifySecurityEvent: sent", "userId", userID, "event", event)
+}
+
+// recordDevice remembers the device the user signed in from and reports whether it is new.
+// The first device of an account is not reported as new.
+func (s *AuthService) recordDevice(ctx context.Context, u *entity.UserEntity, deviceInfo string) bool {
+	if deviceInfo == "" {
+		return false
+	}
+	newDevice, err := s.userRepo.RecordDevice(ctx, u.ID, deviceInfo)
+	if err != nil {
+		slog.WarnContext(ctx, "recordDevice: record device failed", "userId", u.ID, "error", err)
+		return false
+	}
+	return newDevice
+}
+
+// SubscribeNotifications emails security alerts for the events published on bus. Emails are
+// sent asynchronously so they do not delay the request. Subscribe each bus once.
+func (s *AuthService) SubscribeNotifications(bus *event.Bus) {
+	event.SubscribeAsync(bus, func(ctx context.Context, e event.LoggedIn) error {
+		if e.NewDevice {
+			s.NotifySecurityEvent(ctx, e.UserID, e.Email, email.SecurityEventNewDeviceLogin, e.DeviceInfo, "")
+		}
+		return nil
+	})
+	event.SubscribeAsync(bus, func(ctx context.Context, e event.PasswordChanged) error {
+		s.NotifySecurityEvent(ctx, e.UserID, e.Email, email.SecurityEventPasswordChanged, "", "")
+		return nil
+	})
+	event.SubscribeAsync(bus, func(ctx context.Context, e event.PasswordReset) error {
+		s.NotifySecurityEvent(ctx, e.UserID, e.Email, email.SecurityEventPasswordReset, "", "")
+		return nil
+	})
+	event.SubscribeAsync(bus, func(ctx context.Context, e event.ProfileUpdated) error {
+		if e.PhoneChanged {
+			s.NotifySecurityEvent(ctx, e.UserID, "", email.SecurityEventPhoneChanged, "", utils.MaskPhone(e.PhoneNumber))
+		}
+		return nil
+	})
+	event.SubscribeAsync(bus, func(ctx context.Context, e event.AccountDeleted) error {
+		s.NotifySecurityEvent(ctx, e.UserID, e.Email, email.SecurityEventAccountDeleted, "", "")
+		return nil
+	})
+}
+
+// GetNotificationPreferences returns which security notification emails the user receives.
+func (s *AuthService) GetNotificationPreferences(ctx context.Context, userID int64) (*dto.NotificationPreferencesResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.GetNotificationPreferences")
+	defer span.End()
+	pref, err := s.profileRepo.FindNotificationPreference(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "GetNotificationPreferences: find failed", "userId", userID, "error", err)
+		return nil, err
+	}
+	return toNotificationPreferencesResponse(pref), nil
+}
+
+// UpdateNotificationPreferences changes the preferences present in the request and keeps the others.
+func (s *AuthService) UpdateNotificationPreferences(ctx context.Context, userID int64, cmd *dto.UpdateNotificationPreferencesRequest) (*dto.NotificationPreferencesResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.UpdateNotificationPreferences")
+	defer span.End()
+	pref, err := s.profileRepo.FindNotificationPreference(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "UpdateNotificationPreferences: find failed", "userId", userID, "error", err)
+		return nil, err
+	}
+	for _, f := range []struct {
+		value *bool
+		field *bool
+	}{
+		{cmd.NewDeviceLogin, &pref.NewDeviceLogin},
+		{cmd.PasswordChanged, &pref.PasswordChanged},
+		{cmd.PhoneChanged, &pref.PhoneChanged},
+		{cmd.AccountDeleted, &pref.AccountDeleted},
+	} {
+		if f.value != nil {
+			*f.field = *f.value
+		}
+	}
+	if err := s.profileRepo.SaveNotificationPreference(ctx, pref); err != nil {
+		slog.ErrorContext(ctx, "UpdateNotificationPreferences: save failed", "userId", userID, "error", err)
+		return nil, err
+	}
+	slog.InfoContext(ctx, "UpdateNotificationPreferences: success", "userId", userID)
+	return toNotificationPreferencesResponse(pref), nil
+}
+
+func toNotificationPreferencesResponse(p *entity.NotificationPreferenceEntity) *dto.NotificationPreferencesResponse {
+	return &dto.NotificationPreferencesResponse{
+		NewDeviceLogin:  p.NewDeviceLogin,
+		PasswordChanged: p.PasswordChanged,
+		PhoneChanged:    p.PhoneChanged,
+		AccountDeleted:  p.AccountDeleted,
+	}
+}
@@ -0,0 +1,80 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth/internal/dto"
+	"auth/internal/entity"
+	"auth/internal/service/email"
+	"auth/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifySecurityEvent_수신설정(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	userID := f.createUser(t, "a@example.com")
+
+	f.svc.NotifySecurityEvent(ctx, userID, "", email.SecurityEventPasswordChanged, "", "")
+	assert.Equal(t, 1, f.mails.count(), "기본값은 모두 수신")
+
+	off := false
+	_, err := f.svc.UpdateNotificationPreferences(ctx, userID, &dto.UpdateNotificationPreferencesRequest{PasswordChanged: &off})
+	assert.Nil(t, err)
+	f.svc.NotifySecurityEvent(ctx, userID, "", email.SecurityEventPasswordChanged, "", "")
+	f.svc.NotifySecurityEvent(ctx, userID, "", email.SecurityEventPasswordReset, "", "")
+	assert.Equal(t, 1, f.mails.count(), "끈 항목은 보내지 않는다")
+
+	// 다른 항목은 영향을 받지 않는다
+	f.svc.NotifySecurityEvent(ctx, userID, "", email.SecurityEventAccountDeleted, "", "")
+	assert.Equal(t, 2, f.mails.count())
+	assert.Equal(t, "a@example.com", f.mails.messages[1].To[0].Address)
+}
+
+func TestRecordDevice_첫기기는새기기아님(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	userID := f.createUser(t, "a@example.com")
+
+	newDevice, err := f.users.RecordDevice(ctx, userID, "laptop")
+	assert.Nil(t, err)
+	assert.False(t, newDevice, "계정의 첫 기기")
+	newDevice, err = f.users.RecordDevice(ctx, userID, "laptop")
+	assert.Nil(t, err)
+	assert.False(t, newDevice, "이미 쓴 기기")
+	newDevice, err = f.users.RecordDevice(ctx, userID, "phone")
+	assert.Nil(t, err)
+	assert.True(t, newDevice)
+
+	// 다른 사용자의 기기는 세지 않는다
+	other := f.createUser(t, "b@example.com")
+	newDevice, err = f.users.RecordDevice(ctx, other, "phone")
+	assert.Nil(t, err)
+	assert.False(t, newDevice)
+}
+
+func TestLogin_새기기알림(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	hash, err := utils.HashPassword("Sup3r-Secret-Pw!")
+	assert.Nil(t, err)
+	now := time.Now()
+	_, err = f.users.CreateTx(ctx, nil, &entity.UserEntity{Email: "a@example.com", PasswordHash: hash, Provider: "local", CreatedAt: now, UpdatedAt: now})
+	assert.Nil(t, err)
+	login := &dto.LoginRequest{Email: "a@example.com", Password: "Sup3r-Secret-Pw!"}
+
+	_, err = f.svc.Login(ctx, login, "laptop")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, f.mails.count(), "첫 기기는 알리지 않는다")
+	_, err = f.svc.Login(ctx, login, "laptop")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, f.mails.count())
+
+	// 주입한 버스에도 알림은 한 번만 구독된다
+	_, err = f.svc.Login(ctx, login, "phone")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, f.mails.count())
+}
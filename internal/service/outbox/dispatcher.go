@@ -0,0 +1,176 @@
+// Package outbox delivers messages recorded in the transactional outbox table.
+package outbox
+
+import (
+	"auth/internal/entity"
+	"auth/internal/repository"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Handler delivers the payload of one outbox message.
+type Handler func(ctx context.Context, payload string) error
+
+// Dispatcher periodically claims due outbox messages and hands them to the handler of their kind.
+// Failed messages are retried with exponential backoff and marked dead after MaxAttempts.
+type Dispatcher struct {
+	repo     repository.OutboxRepository
+	handlers map[string]Handler
+
+	Interval    time.Duration // 폴링 주기
+	BatchSize   int
+	MaxAttempts int
+	BaseBackoff time.Duration // 첫 재시도까지의 대기 시간, 시도마다 두 배
+	MaxBackoff  time.Duration
+	Lease       time.Duration // 발송 중인 메시지를 다른 디스패처가 가져가지 않도록 미뤄 두는 시간
+	SendTimeout time.Duration
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewDispatcher creates a Dispatcher with default settings.
+func NewDispatcher(repo repository.OutboxRepository) *Dispatcher {
+	return &Dispatcher{
+		repo:        repo,
+		handlers:    map[string]Handler{},
+		Interval:    5 * time.Second,
+		BatchSize:   20,
+		MaxAttempts: 8,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  time.Hour,
+		Lease:       5 * time.Minute,
+		SendTimeout: 30 * time.Second,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Handle registers the handler for a message kind.
+func (d *Dispatcher) Handle(kind string, h Handler) {
+	d.handlers[kind] = h
+}
+
+// Start runs the dispatch loop in a background goroutine until Stop is called.
+// It does nothing if Interval is not positive.
+func (d *Dispatcher) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.started || d.stopped || d.Interval <= 0 {
+		return
+	}
+	d.started = true
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(d.Interval)
+		defer ticker.Stop()
+		for {
+			if _, err := d.RunOnce(context.Background()); err != nil {
+				slog.Error("outbox: dispatch failed", "error", err)
+			}
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop signals the loop to exit and waits for the batch in progress to finish or ctx to end.
+// It does nothing if the dispatcher was never started.
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.stopped {
+		d.stopped = true
+		close(d.stop)
+	}
+	started := d.started
+	d.mu.Unlock()
+	if !started {
+		return nil
+	}
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RunOnce delivers one batch of due messages and returns how many were sent.
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	messages, err := d.repo.ClaimDue(ctx, time.Now(), d.Lease, d.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, m := range messages {
+		if err := d.deliver(ctx, m); err != nil {
+			d.fail(ctx, m, err)
+			continue
+		}
+		if err := d.repo.MarkSent(ctx, m.ID); err != nil {
+			slog.ErrorContext(ctx, "outbox: mark sent failed", "id", m.ID, "error", err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, m *entity.OutboxMessageEntity) error {
+	h, ok := d.handlers[m.Kind]
+	if !ok {
+		return fmt.Errorf("no handler for kind %q", m.Kind)
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.SendTimeout)
+	defer cancel()
+	return h(ctx, m.Payload)
+}
+
+func (d *Dispatcher) fail(ctx context.Context, m *entity.OutboxMessageEntity, cause error) {
+	attempts := m.Attempts + 1
+	status := entity.OutboxStatusPending
+	next := time.Now().Add(Backoff(attempts, d.BaseBackoff, d.MaxBackoff))
+	if attempts >= d.MaxAttempts {
+		status = entity.OutboxStatusDead
+		slog.ErrorContext(ctx, "outbox: message dead-lettered", "id", m.ID, "kind", m.Kind, "attempts", attempts, "error", cause)
+	} else {
+		slog.WarnContext(ctx, "outbox: delivery failed, will retry", "id", m.ID, "kind", m.Kind, "attempts", attempts, "retryAt", next, "error", cause)
+	}
+	if err := d.repo.MarkFailed(ctx, m.ID, status, next, cause.Error()); err != nil {
+		slog.ErrorContext(ctx, "outbox: record failure failed", "id", m.ID, "error", err)
+	}
+}
+
+// Backoff returns the delay before retry number attempts: base * 2^(attempts-1), capped at max.
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+// List returns messages with the status, oldest first, for admin inspection.
+func (d *Dispatcher) List(ctx context.Context, status string, limit int) ([]*entity.OutboxMessageEntity, error) {
+	return d.repo.List(ctx, status, limit)
+}
+
+// Retry puts a pending or dead message back in the queue for immediate delivery.
+func (d *Dispatcher) Retry(ctx context.Context, id int64) (bool, error) {
+	return d.repo.Requeue(ctx, id)
+}
@@ -0,0 +1,102 @@
+package outbox_test
+
+import (
+	"auth/internal/entity"
+	"auth/internal/repository"
+	"auth/internal/service/outbox"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zombiezen.com/go/sqlite"
+)
+
+func newRepo(t *testing.T) repository.OutboxRepository {
+	conn, err := sqlite.OpenConn(":memory:", 0)
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return repository.NewOutboxRepositorySqlite(conn)
+}
+
+func TestBackoff(t *testing.T) {
+	base, max := 30*time.Second, 10*time.Minute
+	assert.Equal(t, 30*time.Second, outbox.Backoff(1, base, max))
+	assert.Equal(t, 60*time.Second, outbox.Backoff(2, base, max))
+	assert.Equal(t, 4*time.Minute, outbox.Backoff(4, base, max))
+	assert.Equal(t, max, outbox.Backoff(10, base, max))
+}
+
+func TestDispatcher_발송성공(t *testing.T) {
+	repo := newRepo(t)
+	ctx := context.Background()
+	assert.Nil(t, repo.EnqueueTx(ctx, nil, &entity.OutboxMessageEntity{Kind: "email", Payload: `{"to":"a@example.com"}`}))
+
+	var got []string
+	d := outbox.NewDispatcher(repo)
+	d.Handle("email", func(_ context.Context, payload string) error {
+		got = append(got, payload)
+		return nil
+	})
+	sent, err := d.RunOnce(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []string{`{"to":"a@example.com"}`}, got)
+
+	// 이미 발송된 메시지는 다시 가져오지 않는다
+	sent, err = d.RunOnce(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, sent)
+}
+
+func TestDispatcher_재시도후DeadLetter(t *testing.T) {
+	repo := newRepo(t)
+	ctx := context.Background()
+	assert.Nil(t, repo.EnqueueTx(ctx, nil, &entity.OutboxMessageEntity{Kind: "email", Payload: "{}"}))
+
+	d := outbox.NewDispatcher(repo)
+	d.MaxAttempts = 3
+	d.BaseBackoff = 0 // 바로 재시도 가능하도록
+	d.Handle("email", func(context.Context, string) error { return errors.New("smtp down") })
+
+	for i := 0; i < 3; i++ {
+		_, err := d.RunOnce(ctx)
+		assert.Nil(t, err)
+	}
+	dead, err := d.List(ctx, entity.OutboxStatusDead, 10)
+	assert.Nil(t, err)
+	assert.Len(t, dead, 1)
+	assert.Equal(t, 3, dead[0].Attempts)
+	assert.Equal(t, "smtp down", dead[0].LastError)
+
+	// 관리자가 재시도하면 다시 발송된다
+	ok, err := d.Retry(ctx, dead[0].ID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	d.Handle("email", func(context.Context, string) error { return nil })
+	sent, err := d.RunOnce(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+}
+
+func TestDispatcher_StartStop(t *testing.T) {
+	d := outbox.NewDispatcher(newRepo(t))
+	// 시작하지 않은 디스패처는 바로 멈춘다
+	assert.Nil(t, d.Stop(context.Background()))
+
+	d = outbox.NewDispatcher(newRepo(t))
+	d.Interval = 10 * time.Millisecond
+	d.Start()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, d.Stop(ctx))
+
+	// Interval 이 0 이면 시작하지 않는다
+	d = outbox.NewDispatcher(newRepo(t))
+	d.Interval = 0
+	d.Start()
+	assert.Nil(t, d.Stop(ctx))
+}
@@ -0,0 +1,220 @@
+package password
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1" // HIBP 코퍼스 형식이 SHA-1 을 사용한다
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// prefixLength is the length of the SHA-1 prefix used for k-anonymity range lookups.
+const prefixLength = 5
+
+// BreachChecker reports whether a password appears in a known breach corpus.
+type BreachChecker interface {
+	// Count returns how many times the password appears in breaches, 0 if it does not.
+	Count(ctx context.Context, password string) (int, error)
+}
+
+// RangeClient returns the HIBP range response for a 5 character uppercase SHA-1 prefix:
+// one "SUFFIX:COUNT" line per hash starting with the prefix.
+type RangeClient interface {
+	Range(ctx context.Context, prefix string) (io.ReadCloser, error)
+}
+
+// RangeChecker is a BreachChecker that looks up passwords through a RangeClient,
+// so only the hash prefix ever leaves the checker.
+type RangeChecker struct {
+	client RangeClient
+}
+
+// NewRangeChecker creates a RangeChecker using the given RangeClient.
+func NewRangeChecker(client RangeClient) *RangeChecker {
+	return &RangeChecker{client: client}
+}
+
+// Count returns the breach count of the password.
+func (c *RangeChecker) Count(ctx context.Context, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	body, err := c.client.Range(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		lineSuffix, count, ok := parseRangeLine(scanner.Text())
+		if ok && strings.EqualFold(lineSuffix, suffix) {
+			return count, nil
+		}
+	}
+	return 0, scanner.Err()
+}
+
+// parseRangeLine parses "SUFFIX:COUNT". A missing count is treated as 1.
+func parseRangeLine(line string) (string, int, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", 0, false
+	}
+	hash, countStr, found := strings.Cut(line, ":")
+	if !found {
+		return hash, 1, true
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(countStr))
+	if err != nil {
+		return "", 0, false
+	}
+	return hash, count, true
+}
+
+// LocalCorpus is a RangeClient backed by an offline copy of the HIBP corpus,
+// partitioned into one <PREFIX>.txt file per SHA-1 prefix (see ImportCorpus).
+type LocalCorpus struct {
+	Dir string
+}
+
+// NewLocalCorpus creates a LocalCorpus reading from dir.
+func NewLocalCorpus(dir string) *LocalCorpus {
+	return &LocalCorpus{Dir: dir}
+}
+
+// Range opens the partition file of the prefix. A missing partition means no hash has that prefix.
+func (c *LocalCorpus) Range(_ context.Context, prefix string) (io.ReadCloser, error) {
+	if !isHexPrefix(prefix) {
+		return nil, fmt.Errorf("invalid hash prefix: %q", prefix)
+	}
+	f, err := os.Open(filepath.Join(c.Dir, strings.ToUpper(prefix)+".txt"))
+	if errors.Is(err, os.ErrNotExist) {
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+	return f, err
+}
+
+// HTTPRangeClient is a RangeClient for the Pwned Passwords range API
+// (GET <BaseURL>/range/<PREFIX>).
+type HTTPRangeClient struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewHTTPRangeClient creates an HTTPRangeClient for baseURL, e.g. https://api.pwnedpasswords.com.
+func NewHTTPRangeClient(baseURL string) *HTTPRangeClient {
+	return &HTTPRangeClient{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Range requests the range response for the prefix. Responses are padded so their size
+// does not reveal the prefix; padding entries have a count of 0.
+func (c *HTTPRangeClient) Range(ctx context.Context, prefix string) (io.ReadCloser, error) {
+	if !isHexPrefix(prefix) {
+		return nil, fmt.Errorf("invalid hash prefix: %q", prefix)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/range/"+strings.ToUpper(prefix), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Add-Padding", "true")
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("range api returned status %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// ImportCorpus reads a HIBP "SHA1:COUNT" hash list from r and writes it into dir as
+// one <PREFIX>.txt file per 5 character prefix containing "SUFFIX:COUNT" lines.
+// Input sorted by hash (the official "ordered by hash" download) is written sequentially;
+// unsorted input is appended to the matching partitions. A partition is replaced the first time
+// an import touches it, so re-importing an updated list does not duplicate entries.
+// It returns the number of hashes imported.
+func ImportCorpus(r io.Reader, dir string) (int, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, err
+	}
+	var (
+		current string
+		file    *os.File
+		writer  *bufio.Writer
+		count   int
+		opened  = make(map[string]bool)
+	)
+	closeCurrent := func() error {
+		if file == nil {
+			return nil
+		}
+		if err := writer.Flush(); err != nil {
+			_ = file.Close()
+			return err
+		}
+		return file.Close()
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		hash, n, ok := parseRangeLine(scanner.Text())
+		if !ok || len(hash) != sha1.Size*2 || !isHexPrefix(hash) {
+			continue
+		}
+		hash = strings.ToUpper(hash)
+		prefix := hash[:prefixLength]
+		if prefix != current {
+			if err := closeCurrent(); err != nil {
+				return count, err
+			}
+			mode := os.O_TRUNC
+			if opened[prefix] {
+				mode = os.O_APPEND
+			}
+			f, err := os.OpenFile(filepath.Join(dir, prefix+".txt"), os.O_CREATE|os.O_WRONLY|mode, 0o644)
+			if err != nil {
+				return count, err
+			}
+			opened[prefix] = true
+			current, file, writer = prefix, f, bufio.NewWriter(f)
+		}
+		if _, err := fmt.Fprintf(writer, "%s:%d\n", hash[prefixLength:], n); err != nil {
+			_ = closeCurrent()
+			return count, err
+		}
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		_ = closeCurrent()
+		return count, err
+	}
+	return count, closeCurrent()
+}
+
+func isHexPrefix(s string) bool {
+	if len(s) < prefixLength {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
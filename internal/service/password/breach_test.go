@@ -0,0 +1,90 @@
+package password_test
+
+import (
+	"auth/internal/service/password"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// SHA1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+const corpus = `5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824
+5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD9:3
+7C4A8D09CA3762AF61E59520943DC26494F8941B:37359195
+not-a-hash:1
+`
+
+func Test_LocalCorpus_가져오기및조회(t *testing.T) {
+	dir := t.TempDir()
+	n, err := password.ImportCorpus(strings.NewReader(corpus), dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+
+	checker := password.NewRangeChecker(password.NewLocalCorpus(dir))
+	count, err := checker.Count(context.Background(), "password")
+	assert.Nil(t, err)
+	assert.Equal(t, 9545824, count)
+
+	count, err = checker.Count(context.Background(), "123456")
+	assert.Nil(t, err)
+	assert.Equal(t, 37359195, count)
+
+	// 파티션 파일이 없는 접두사는 유출되지 않은 것으로 본다
+	count, err = checker.Count(context.Background(), "Xk9#mP2$vLq")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func Test_LocalCorpus_재가져오기시파티션교체(t *testing.T) {
+	dir := t.TempDir()
+	_, err := password.ImportCorpus(strings.NewReader(corpus), dir)
+	assert.Nil(t, err)
+
+	// 정렬되지 않은 입력으로 다시 가져와도 같은 파티션에 중복 없이 교체된다
+	updated := `5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545900
+7C4A8D09CA3762AF61E59520943DC26494F8941B:37359200
+5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD9:4
+`
+	n, err := password.ImportCorpus(strings.NewReader(updated), dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+
+	data, err := os.ReadFile(filepath.Join(dir, "5BAA6.txt"))
+	assert.Nil(t, err)
+	assert.Equal(t, "1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545900\n1E4C9B93F3F0682250B6CF8331B7EE68FD9:4\n", string(data))
+
+	count, err := password.NewRangeChecker(password.NewLocalCorpus(dir)).Count(context.Background(), "123456")
+	assert.Nil(t, err)
+	assert.Equal(t, 37359200, count)
+}
+
+func Test_HTTPRangeClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/range/5BAA6", r.URL.Path)
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+		_, _ = w.Write([]byte("1E4C9B93F3F0682250B6CF8331B7EE68FD8:42\r\n0000000000000000000000000000000000A:0\r\n"))
+	}))
+	defer srv.Close()
+
+	checker := password.NewRangeChecker(password.NewHTTPRangeClient(srv.URL))
+	count, err := checker.Count(context.Background(), "password")
+	assert.Nil(t, err)
+	assert.Equal(t, 42, count)
+}
+
+func Test_HTTPRangeClient_오류응답(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	checker := password.NewRangeChecker(password.NewHTTPRangeClient(srv.URL))
+	_, err := checker.Count(context.Background(), "password")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,199 @@
+// Package password implements the password policy used when users choose a new password.
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Violation rule identifiers.
+const (
+	RuleMinLength    = "min_length"
+	RuleMaxLength    = "max_length"
+	RuleUppercase    = "uppercase"
+	RuleLowercase    = "lowercase"
+	RuleDigit        = "digit"
+	RuleSymbol       = "symbol"
+	RuleCharClasses  = "char_classes"
+	RuleStrength     = "strength"
+	RulePersonalInfo = "personal_info"
+	RuleReused       = "reused"
+	RuleBreached     = "breached"
+)
+
+// Violation describes a single password policy rule that was not satisfied.
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PolicyError is returned when a password does not satisfy the policy.
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	rules := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		rules = append(rules, v.Rule)
+	}
+	return "password policy violation: " + strings.Join(rules, ", ")
+}
+
+// Policy defines the rules a new password must satisfy.
+type Policy struct {
+	MinLength          int
+	MaxLength          int
+	MaxBytes           int // UTF-8 바이트 기준 최대 길이 (bcrypt 는 72바이트까지만 받는다)
+	RequireUpper       bool
+	RequireLower       bool
+	RequireDigit       bool
+	RequireSymbol      bool
+	MinCharClasses     int  // 문자 종류(대문자/소문자/숫자/기호) 최소 개수
+	MinStrength        int  // EstimateStrength 점수 0~4
+	RejectPersonalInfo bool // 이메일/이름 포함 금지
+	HistorySize        int  // 재사용 금지할 이전 비밀번호 개수
+}
+
+// DefaultPolicy is the policy used when none is configured.
+var DefaultPolicy = Policy{
+	MinLength:          8,
+	MaxLength:          128,
+	MinCharClasses:     2,
+	MinStrength:        2,
+	RejectPersonalInfo: true,
+	HistorySize:        5,
+}
+
+// LimitBytes returns the policy with MaxBytes lowered to n, for hashers that cannot hash longer passwords.
+func (p Policy) LimitBytes(n int) Policy {
+	if p.MaxBytes <= 0 || p.MaxBytes > n {
+		p.MaxBytes = n
+	}
+	return p
+}
+
+// Validate checks the password against the policy. personalInfo holds values such as
+// the user's email and name that must not appear in the password.
+// It returns nil when the password satisfies every rule.
+func (p Policy) Validate(password string, personalInfo ...string) []Violation {
+	var violations []Violation
+	add := func(rule, format string, args ...any) {
+		violations = append(violations, Violation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if p.MinLength > 0 && length < p.MinLength {
+		add(RuleMinLength, "password must be at least %d characters", p.MinLength)
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		add(RuleMaxLength, "password must be at most %d characters", p.MaxLength)
+	} else if p.MaxBytes > 0 && len(password) > p.MaxBytes {
+		add(RuleMaxLength, "password must be at most %d bytes", p.MaxBytes)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		add(RuleUppercase, "password must contain an uppercase letter")
+	}
+	if p.RequireLower && !hasLower {
+		add(RuleLowercase, "password must contain a lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		add(RuleDigit, "password must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		add(RuleSymbol, "password must contain a symbol")
+	}
+	if p.MinCharClasses > 0 {
+		classes := 0
+		for _, ok := range []bool{hasUpper, hasLower, hasDigit, hasSymbol} {
+			if ok {
+				classes++
+			}
+		}
+		if classes < p.MinCharClasses {
+			add(RuleCharClasses, "password must contain at least %d of uppercase, lowercase, digits and symbols", p.MinCharClasses)
+		}
+	}
+
+	if p.RejectPersonalInfo {
+		if containsPersonalInfo(password, personalInfo) {
+			add(RulePersonalInfo, "password must not contain your email or name")
+		}
+	}
+
+	if p.MinStrength > 0 {
+		if strength := EstimateStrength(password, personalInfo...); strength.Score < p.MinStrength {
+			add(RuleStrength, "password is too weak (score %d, required %d)", strength.Score, p.MinStrength)
+		}
+	}
+	return violations
+}
+
+// containsPersonalInfo reports whether any personal value appears in the password.
+func containsPersonalInfo(password string, personalInfo []string) bool {
+	lower := strings.ToLower(password)
+	for _, token := range personalTokens(personalInfo) {
+		if strings.Contains(lower, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// personalTokens splits personal values into lowercase tokens worth checking:
+// the full value, the local part of an email and each word of a name.
+func personalTokens(personalInfo []string) []string {
+	var tokens []string
+	addToken := func(t string) {
+		t = strings.ToLower(strings.TrimSpace(t))
+		// 너무 짧은 토큰은 우연히 포함될 수 있으므로 제외 (한글 이름은 2자부터)
+		n := utf8.RuneCountInString(t)
+		if n < 2 || (n == 2 && isASCII(t)) {
+			return
+		}
+		tokens = append(tokens, t)
+	}
+	for _, info := range personalInfo {
+		if info == "" {
+			continue
+		}
+		addToken(info)
+		words := info
+		if at := strings.Index(info, "@"); at > 0 {
+			// 도메인(gmail.com 등)은 개인정보로 보지 않는다
+			words = info[:at]
+			addToken(words)
+		}
+		for _, word := range strings.FieldsFunc(words, func(r rune) bool {
+			return unicode.IsSpace(r) || r == '.' || r == '_' || r == '-' || r == '+'
+		}) {
+			addToken(word)
+		}
+	}
+	return tokens
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package password_test
+
+import (
+	"auth/internal/service/password"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rules(violations []password.Violation) []string {
+	var out []string
+	for _, v := range violations {
+		out = append(out, v.Rule)
+	}
+	return out
+}
+
+func Test_Policy_통과(t *testing.T) {
+	violations := password.DefaultPolicy.Validate("Xk9#mP2$vLq", "user@example.com", "홍길동")
+	assert.Empty(t, violations)
+}
+
+func Test_Policy_문자종류(t *testing.T) {
+	policy := password.Policy{MinLength: 8, RequireUpper: true, RequireDigit: true, RequireSymbol: true}
+	got := rules(policy.Validate("abcdefghij"))
+	assert.Equal(t, []string{password.RuleUppercase, password.RuleDigit, password.RuleSymbol}, got)
+}
+
+func Test_Policy_길이(t *testing.T) {
+	policy := password.Policy{MinLength: 10, MaxLength: 12}
+	assert.Equal(t, []string{password.RuleMinLength}, rules(policy.Validate("short")))
+	assert.Equal(t, []string{password.RuleMaxLength}, rules(policy.Validate("much-too-long-password")))
+}
+
+func Test_Policy_바이트길이(t *testing.T) {
+	// 한글 30자는 128자 제한 안이지만 90바이트라 bcrypt 제한을 넘는다
+	long := strings.Repeat("가", 30)
+	policy := password.Policy{MaxLength: 128}.LimitBytes(72)
+	assert.Equal(t, 72, policy.MaxBytes)
+	assert.Equal(t, []string{password.RuleMaxLength}, rules(policy.Validate(long)))
+	assert.Empty(t, rules(policy.Validate(strings.Repeat("가", 24))))
+
+	// 이미 더 작은 제한은 유지한다
+	assert.Equal(t, 32, password.Policy{MaxBytes: 32}.LimitBytes(72).MaxBytes)
+}
+
+func Test_Policy_개인정보포함(t *testing.T) {
+	policy := password.Policy{RejectPersonalInfo: true}
+	assert.Contains(t, rules(policy.Validate("Gildong!2024", "gildong.hong@example.com")), password.RulePersonalInfo)
+	assert.Contains(t, rules(policy.Validate("홍길동만세!!", "user@example.com", "홍길동")), password.RulePersonalInfo)
+	// 이메일 도메인은 개인정보로 보지 않는다
+	assert.Empty(t, rules(policy.Validate("Example!2024", "user@example.com")))
+}
+
+func Test_Policy_강도(t *testing.T) {
+	policy := password.Policy{MinStrength: 3}
+	assert.Contains(t, rules(policy.Validate("password1")), password.RuleStrength)
+	assert.Empty(t, rules(policy.Validate("Xk9#mP2$vLq")))
+}
+
+func Test_EstimateStrength(t *testing.T) {
+	tests := []struct {
+		password string
+		maxScore int
+		minScore int
+	}{
+		{"password", 0, 0},
+		{"P@ssw0rd", 1, 0},
+		{"qwerty123", 1, 0},
+		{"abcd1234", 1, 0},
+		{"aaaaaaaaaa", 1, 0},
+		{"19900101", 1, 0},
+		{"Xk9#mP2$vLq", 4, 4},
+		{"correct horse battery staple", 4, 4},
+	}
+	for _, tt := range tests {
+		got := password.EstimateStrength(tt.password)
+		assert.GreaterOrEqual(t, got.Score, tt.minScore, tt.password)
+		assert.LessOrEqual(t, got.Score, tt.maxScore, tt.password)
+	}
+}
+
+func Test_EstimateStrength_사용자입력(t *testing.T) {
+	without := password.EstimateStrength("gildong2024")
+	with := password.EstimateStrength("gildong2024", "gildong@example.com")
+	assert.Less(t, with.Guesses, without.Guesses)
+}
+
+func Test_PolicyError(t *testing.T) {
+	err := &password.PolicyError{Violations: []password.Violation{{Rule: password.RuleReused}, {Rule: password.RuleStrength}}}
+	assert.Equal(t, "password policy violation: reused, strength", err.Error())
+}
@@ -0,0 +1,287 @@
+package password
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Strength is the result of EstimateStrength.
+type Strength struct {
+	Score   int     // 0 (매우 약함) ~ 4 (매우 강함)
+	Guesses float64 // 추정 추측 횟수
+}
+
+// 점수 구간 (zxcvbn 과 동일한 log10(guesses) 기준)
+var scoreThresholds = []float64{3, 6, 8, 10}
+
+// EstimateStrength estimates how many guesses an attacker needs for the password,
+// in the style of zxcvbn. The password is covered with the cheapest combination of
+// dictionary words (including userInputs), sequences, repeats, keyboard runs and dates,
+// falling back to brute force for the remaining characters.
+func EstimateStrength(password string, userInputs ...string) Strength {
+	runes := []rune(password)
+	n := len(runes)
+	if n == 0 {
+		return Strength{Score: 0, Guesses: 1}
+	}
+
+	matches := findMatches(runes, userInputs)
+	bruteLog := math.Log10(float64(bruteCardinality(runes)))
+
+	// best[i] = runes[:i] 를 덮는 최소 log10(guesses)
+	best := make([]float64, n+1)
+	for i := 1; i <= n; i++ {
+		best[i] = best[i-1] + bruteLog
+		for _, m := range matches[i] {
+			if cost := best[m.start] + m.log10Guesses; cost < best[i] {
+				best[i] = cost
+			}
+		}
+	}
+
+	log10Guesses := best[n]
+	score := 0
+	for _, threshold := range scoreThresholds {
+		if log10Guesses >= threshold {
+			score++
+		}
+	}
+	return Strength{Score: score, Guesses: math.Pow(10, log10Guesses)}
+}
+
+type match struct {
+	start        int
+	log10Guesses float64
+}
+
+// findMatches returns the pattern matches indexed by their (exclusive) end position.
+func findMatches(runes []rune, userInputs []string) map[int][]match {
+	matches := make(map[int][]match)
+	add := func(start, end int, guesses float64) {
+		matches[end] = append(matches[end], match{start: start, log10Guesses: math.Log10(math.Max(guesses, 1))})
+	}
+
+	dictionary := rankedDictionary(userInputs)
+	lower := []rune(strings.ToLower(string(runes)))
+	unleeted := unleet(lower)
+	n := len(runes)
+
+	for i := 0; i < n; i++ {
+		for j := i + 3; j <= n && j-i <= 32; j++ {
+			// 사전 단어 (대소문자, leet 치환 변형 포함)
+			word := string(lower[i:j])
+			if rank, ok := dictionary[word]; ok {
+				add(i, j, float64(rank)*uppercaseVariations(runes[i:j]))
+			} else if rank, ok := dictionary[string(unleeted[i:j])]; ok {
+				add(i, j, float64(rank)*uppercaseVariations(runes[i:j])*2)
+			}
+			// 키보드 배열
+			if j-i >= 4 && isKeyboardRun(word) {
+				add(i, j, 94*float64(j-i)*2)
+			}
+			// 날짜 (yyyymmdd, yymmdd)
+			if isDate(word) {
+				add(i, j, 365*100)
+			}
+		}
+		// 연도
+		if i+4 <= n {
+			if y := string(runes[i : i+4]); (strings.HasPrefix(y, "19") || strings.HasPrefix(y, "20")) && isDigits(y) {
+				add(i, i+4, 50)
+			}
+		}
+	}
+
+	// 연속 문자 (abcd, 4321)
+	for i := 0; i < n-2; {
+		delta := runes[i+1] - runes[i]
+		j := i + 1
+		if delta == 1 || delta == -1 {
+			for j+1 < n && runes[j+1]-runes[j] == delta {
+				j++
+			}
+		}
+		if j-i+1 >= 3 {
+			base := 26.0
+			if unicode.IsDigit(runes[i]) {
+				base = 10
+			}
+			if strings.ContainsRune("aAzZ019", runes[i]) {
+				base = 4
+			}
+			if delta < 0 {
+				base *= 2
+			}
+			add(i, j+1, base*float64(j-i+1))
+		}
+		i = j
+	}
+
+	// 반복 (aaaa, abcabc)
+	for i := 0; i < n; i++ {
+		for size := 1; size <= (n-i)/2; size++ {
+			chunk := string(runes[i : i+size])
+			count := 1
+			for k := i + size; k+size <= n && string(runes[k:k+size]) == chunk; k += size {
+				count++
+			}
+			if count >= 2 && size*count >= 3 {
+				chunkGuesses := math.Pow(float64(bruteCardinality(runes[i:i+size])), float64(size))
+				add(i, i+size*count, chunkGuesses*float64(count))
+			}
+		}
+	}
+	return matches
+}
+
+// bruteCardinality returns the size of the character set used by runes.
+func bruteCardinality(runes []rune) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	cardinality := 0
+	if lower {
+		cardinality += 26
+	}
+	if upper {
+		cardinality += 26
+	}
+	if digit {
+		cardinality += 10
+	}
+	if symbol {
+		cardinality += 33
+	}
+	if other {
+		cardinality += 100
+	}
+	if cardinality < 10 {
+		cardinality = 10
+	}
+	return cardinality
+}
+
+func uppercaseVariations(runes []rune) float64 {
+	upper := 0
+	for _, r := range runes {
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+	switch {
+	case upper == 0:
+		return 1
+	case upper == 1 && unicode.IsUpper(runes[0]), upper == len(runes):
+		return 2
+	default:
+		return math.Pow(2, float64(upper))
+	}
+}
+
+var leetTable = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '(': 'c', '3': 'e', '6': 'g', '1': 'i', '!': 'i',
+	'|': 'l', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z',
+}
+
+func unleet(runes []rune) []rune {
+	out := make([]rune, len(runes))
+	for i, r := range runes {
+		if sub, ok := leetTable[r]; ok {
+			out[i] = sub
+		} else {
+			out[i] = r
+		}
+	}
+	return out
+}
+
+var keyboardRows = []string{
+	"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./",
+	"1qaz2wsx3edc4rfv5tgb6yhn7ujm8ik,9ol.0p;/", "qazwsxedcrfvtgbyhnujmikolp",
+}
+
+func isKeyboardRun(word string) bool {
+	reversed := []rune(word)
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+	for _, row := range keyboardRows {
+		if strings.Contains(row, word) || strings.Contains(row, string(reversed)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isDate(s string) bool {
+	if !isDigits(s) {
+		return false
+	}
+	var month, day string
+	switch len(s) {
+	case 8:
+		if !strings.HasPrefix(s, "19") && !strings.HasPrefix(s, "20") {
+			return false
+		}
+		month, day = s[4:6], s[6:8]
+	case 6:
+		month, day = s[2:4], s[4:6]
+	default:
+		return false
+	}
+	return month >= "01" && month <= "12" && day >= "01" && day <= "31"
+}
+
+// rankedDictionary returns common passwords and user inputs ranked by popularity.
+// User inputs get rank 1 since attackers try them first.
+func rankedDictionary(userInputs []string) map[string]int {
+	dictionary := make(map[string]int, len(commonPasswords)+len(userInputs))
+	for i, word := range commonPasswords {
+		dictionary[word] = i + 1
+	}
+	for _, token := range personalTokens(userInputs) {
+		dictionary[token] = 1
+	}
+	return dictionary
+}
+
+// commonPasswords is a short list of the most common passwords and words, most common first.
+var commonPasswords = []string{
+	"password", "123456", "12345678", "qwerty", "123456789", "12345", "1234", "111111",
+	"1234567", "dragon", "123123", "baseball", "abc123", "football", "monkey", "letmein",
+	"696969", "shadow", "master", "666666", "qwertyuiop", "123321", "mustang", "1234567890",
+	"michael", "654321", "superman", "1qaz2wsx", "7777777", "121212", "000000", "qazwsx",
+	"123qwe", "killer", "trustno1", "jordan", "jennifer", "zxcvbnm", "asdfgh", "hunter",
+	"buster", "soccer", "harley", "batman", "andrew", "tigger", "sunshine", "iloveyou",
+	"fuckyou", "2000", "charlie", "robert", "thomas", "hockey", "ranger", "daniel",
+	"starwars", "klaster", "112233", "george", "computer", "michelle", "jessica", "pepper",
+	"1111", "zxcvbn", "555555", "11111111", "131313", "freedom", "777777", "pass",
+	"maggie", "159753", "aaaaaa", "ginger", "princess", "joshua", "cheese", "amanda",
+	"summer", "love", "ashley", "nicole", "chelsea", "biteme", "matthew", "access",
+	"yankees", "987654321", "dallas", "austin", "thunder", "taylor", "matrix", "admin",
+	"welcome", "login", "passw0rd", "qwer1234", "asdf1234", "zxcv1234", "q1w2e3r4",
+	"1q2w3e4r", "sarang", "saranghae", "apple", "samsung", "naver", "kakao", "korea",
+	"seoul", "test", "guest", "secret", "changeme", "default", "root", "user", "hello",
+}
@@ -0,0 +1,97 @@
+// Package phone parses user supplied phone numbers into canonical E.164 and formats them for display.
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+// DefaultRegion is the region used for numbers written without a country code when none is configured.
+const DefaultRegion = "KR"
+
+var (
+	// ErrInvalidNumber is returned for input that is not a valid phone number.
+	ErrInvalidNumber = errors.New("invalid phone number")
+	// ErrNotMobile is returned for a valid number that cannot receive SMS, e.g. a landline.
+	ErrNotMobile = errors.New("phone number is not a mobile number")
+)
+
+// Parser parses numbers in national format for its region, e.g. "010-1234-5678" for KR,
+// or in international format with a leading "+" for any region.
+type Parser struct {
+	region string
+}
+
+// NewParser creates a Parser for the region, an ISO 3166-1 alpha-2 code such as "KR" or "US".
+func NewParser(region string) (*Parser, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if phonenumbers.GetCountryCodeForRegion(region) == 0 {
+		return nil, fmt.Errorf("unknown phone region: %q", region)
+	}
+	return &Parser{region: region}, nil
+}
+
+// MustNewParser is like NewParser but panics if the region is unknown.
+// It simplifies initializing parsers for fixed regions such as DefaultRegion.
+func MustNewParser(region string) *Parser {
+	p, err := NewParser(region)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Region returns the region of numbers written without a country code.
+func (p *Parser) Region() string {
+	return p.region
+}
+
+// Normalize returns the number in E.164 format, e.g. "+821012345678". Numbers that are not valid
+// return ErrInvalidNumber and numbers that are not mobile numbers return ErrNotMobile.
+// In regions where mobile and fixed line numbers cannot be told apart, such as US, both are accepted.
+func (p *Parser) Normalize(raw string) (string, error) {
+	num, err := p.parse(raw)
+	if err != nil {
+		return "", err
+	}
+	switch phonenumbers.GetNumberType(num) {
+	case phonenumbers.MOBILE, phonenumbers.FIXED_LINE_OR_MOBILE:
+		return phonenumbers.Format(num, phonenumbers.E164), nil
+	}
+	return "", ErrNotMobile
+}
+
+// Valid reports whether Normalize accepts the number.
+func (p *Parser) Valid(raw string) bool {
+	_, err := p.Normalize(raw)
+	return err == nil
+}
+
+// Format returns the number for display: in national format for numbers of the parser's region,
+// e.g. "010-1234-5678", and in international format otherwise, e.g. "+1 201-555-0123".
+// Numbers that cannot be parsed are returned unchanged.
+func (p *Parser) Format(number string) string {
+	num, err := p.parse(number)
+	if err != nil {
+		return number
+	}
+	if phonenumbers.GetRegionCodeForNumber(num) == p.region {
+		return phonenumbers.Format(num, phonenumbers.NATIONAL)
+	}
+	return phonenumbers.Format(num, phonenumbers.INTERNATIONAL)
+}
+
+func (p *Parser) parse(raw string) (*phonenumbers.PhoneNumber, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, ErrInvalidNumber
+	}
+	num, err := phonenumbers.Parse(raw, p.region)
+	if err != nil || !phonenumbers.IsValidNumber(num) {
+		return nil, ErrInvalidNumber
+	}
+	return num, nil
+}
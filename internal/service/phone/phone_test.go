@@ -0,0 +1,58 @@
+package phone_test
+
+import (
+	"auth/internal/service/phone"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize_같은번호는같은E164(t *testing.T) {
+	p, err := phone.NewParser("kr")
+	assert.Nil(t, err)
+	assert.Equal(t, "KR", p.Region())
+
+	for _, raw := range []string{"010-1234-5678", "01012345678", "010 1234 5678", "+82 10-1234-5678", "+821012345678", " (010) 1234-5678 "} {
+		got, err := p.Normalize(raw)
+		assert.Nil(t, err, raw)
+		assert.Equal(t, "+821012345678", got, raw)
+	}
+
+	// 다른 지역 번호는 국가 코드로
+	got, err := p.Normalize("+1 201-555-0123")
+	assert.Nil(t, err)
+	assert.Equal(t, "+12015550123", got)
+}
+
+func TestNormalize_거부(t *testing.T) {
+	p, _ := phone.NewParser(phone.DefaultRegion)
+
+	for _, raw := range []string{"", "12345", "010-1234", "not a number", "+999123456789"} {
+		_, err := p.Normalize(raw)
+		assert.ErrorIs(t, err, phone.ErrInvalidNumber, raw)
+	}
+	// 유선 번호
+	_, err := p.Normalize("02-312-3456")
+	assert.ErrorIs(t, err, phone.ErrNotMobile)
+	assert.False(t, p.Valid("02-312-3456"))
+	assert.True(t, p.Valid("010-1234-5678"))
+
+	_, err = phone.NewParser("XX")
+	assert.NotNil(t, err)
+}
+
+func TestFormat(t *testing.T) {
+	kr, _ := phone.NewParser("KR")
+	assert.Equal(t, "010-1234-5678", kr.Format("+821012345678"))
+	assert.Equal(t, "+1 201-555-0123", kr.Format("+12015550123"))
+	assert.Equal(t, "garbage", kr.Format("garbage"))
+
+	us, _ := phone.NewParser("US")
+	assert.Equal(t, "+82 10-1234-5678", us.Format("+821012345678"))
+	assert.Equal(t, "(201) 555-0123", us.Format("+12015550123"))
+}
+
+func TestMustNewParser_알수없는지역은패닉(t *testing.T) {
+	assert.Equal(t, "KR", phone.MustNewParser(phone.DefaultRegion).Region())
+	assert.Panics(t, func() { phone.MustNewParser("ZZ") })
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"auth/internal/entity"
+	"auth/internal/metrics"
+	"auth/internal/service/event"
+	"auth/internal/tracing"
+	"auth/pkg/utils"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	// PhonePurposeFindEmail is the code purpose for recovering an account email by phone number.
+	PhonePurposeFindEmail = "find_email"
+	// PhonePurposeVerifyPhone is the code purpose for verifying the signed-in user's current or new phone number.
+	PhonePurposeVerifyPhone = "verify_phone"
+
+	phoneCodeLength         = 6
+	phoneCodeExpireMinutes  = 5
+	phoneCodeMaxAttempts    = 5
+	phoneCodeResendInterval = time.Minute
+
+	// 코드마다 세는 시도 횟수는 재발송으로 초기화되므로 번호와 용도별로 기간 안의 합계도 제한한다
+	phoneCodeWindow             = time.Hour
+	phoneCodeMaxSendsInWindow   = 5
+	phoneCodeMaxGuessesInWindow = 10
+)
+
+var (
+	// ErrPhoneVerificationUnavailable is returned when no SMS sender is configured.
+	ErrPhoneVerificationUnavailable = errors.New("phone verification is not configured")
+	// ErrVerificationCodeRequired is returned when a phone number change is not accompanied by a code.
+	ErrVerificationCodeRequired = errors.New("verification code required")
+	// ErrInvalidVerificationCode is returned for a wrong, unknown or already used code.
+	ErrInvalidVerificationCode = errors.New("invalid verification code")
+	// ErrVerificationCodeExpired is returned when the latest code has expired.
+	ErrVerificationCodeExpired = errors.New("verification code expired")
+	// ErrTooManyVerificationAttempts is returned once the latest code has been guessed wrong too often.
+	ErrTooManyVerificationAttempts = errors.New("too many verification attempts")
+	// ErrVerificationCodeRecentlySent is returned when a new code is requested before the resend interval.
+	ErrVerificationCodeRecentlySent = errors.New("verification code recently sent")
+	// ErrTooManyVerificationCodes is returned when too many codes were sent to the number recently.
+	ErrTooManyVerificationCodes = errors.New("too many verification codes sent")
+	// ErrPhoneNumberInUse is returned when the phone number belongs to another user.
+	ErrPhoneNumberInUse = errors.New("phone number already in use")
+)
+
+// SendPhoneCode sends a one-time verification code by SMS.
+// For PhonePurposeFindEmail (userID 0) nothing is sent to numbers without an account,
+// without telling the caller. For PhonePurposeVerifyPhone the number must not belong to another user;
+// an empty number means the user's current number.
+func (s *AuthService) SendPhoneCode(ctx context.Context, userID int64, phoneNumber, purpose string) error {
+	ctx, span := tracing.Start(ctx, "AuthService.SendPhoneCode")
+	defer span.End()
+	if s.phoneRepo == nil || s.smsSender == nil {
+		return ErrPhoneVerificationUnavailable
+	}
+	if phoneNumber == "" && purpose == PhonePurposeVerifyPhone {
+		current, err := s.profileRepo.FindByUserID(ctx, userID)
+		if err != nil || current == nil {
+			return ErrProfileNotFound
+		}
+		phoneNumber = current.PhoneNumber
+	}
+	phoneNumber, err := s.phones.Normalize(phoneNumber)
+	if err != nil {
+		return err
+	}
+	profile, err := s.profileRepo.FindByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		slog.ErrorContext(ctx, "SendPhoneCode: find by phone failed", "error", err)
+		return err
+	}
+	switch purpose {
+	case PhonePurposeFindEmail:
+		if profile == nil {
+			slog.WarnContext(ctx, "SendPhoneCode: no account for phone", "phone", utils.MaskPhone(phoneNumber))
+			return nil
+		}
+	case PhonePurposeVerifyPhone:
+		if profile != nil && profile.UserID != userID {
+			slog.WarnContext(ctx, "SendPhoneCode: phone already used", "phone", utils.MaskPhone(phoneNumber))
+			return ErrPhoneNumberInUse
+		}
+	default:
+		return fmt.Errorf("unknown verification purpose: %s", purpose)
+	}
+
+	latest, err := s.phoneRepo.FindLatest(ctx, phoneNumber, purpose)
+	if err != nil {
+		slog.ErrorContext(ctx, "SendPhoneCode: find latest code failed", "error", err)
+		return err
+	}
+	if latest != nil && time.Since(latest.CreatedAt) < phoneCodeResendInterval {
+		return ErrVerificationCodeRecentlySent
+	}
+	sent, guesses, err := s.phoneRepo.CountSince(ctx, phoneNumber, purpose, time.Now().Add(-phoneCodeWindow))
+	if err != nil {
+		slog.ErrorContext(ctx, "SendPhoneCode: count recent codes failed", "error", err)
+		return err
+	}
+	if sent >= phoneCodeMaxSendsInWindow {
+		return ErrTooManyVerificationCodes
+	}
+	if guesses >= phoneCodeMaxGuessesInWindow {
+		// 새 코드도 확인할 수 없으므로 보내지 않는다
+		return ErrTooManyVerificationAttempts
+	}
+
+	code, err := utils.GenerateNumericCode(phoneCodeLength)
+	if err != nil {
+		return err
+	}
+	err = s.phoneRepo.Create(ctx, &entity.PhoneVerificationEntity{
+		PhoneNumber: phoneNumber,
+		Purpose:     purpose,
+		CodeHash:    utils.HashToken(code),
+		ExpiredAt:   time.Now().Add(phoneCodeExpireMinutes * time.Minute),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "SendPhoneCode: save code failed", "error", err)
+		return err
+	}
+	message := fmt.Sprintf("[인증번호] %s\n%d분 안에 입력해 주세요. 타인에게 알려주지 마세요.", code, phoneCodeExpireMinutes)
+	if err := s.smsSender.Send(ctx, phoneNumber, message); err != nil {
+		slog.ErrorContext(ctx, "SendPhoneCode: send sms failed", "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "SendPhoneCode: success", "purpose", purpose, "userId", userID)
+	return nil
+}
+
+// verifyPhoneCode checks the code against the latest code sent to the number for the purpose
+// and consumes it on success. Every attempt counts towards phoneCodeMaxAttempts; the attempt is
+// counted before the code is compared, so parallel guesses cannot exceed the limit.
+// Attempts against all codes sent within phoneCodeWindow are capped at phoneCodeMaxGuessesInWindow,
+// so requesting a new code does not allow more guesses.
+func (s *AuthService) verifyPhoneCode(ctx context.Context, phoneNumber, purpose, code string) error {
+	if s.phoneRepo == nil {
+		return ErrPhoneVerificationUnavailable
+	}
+	if code == "" {
+		return ErrVerificationCodeRequired
+	}
+	v, err := s.phoneRepo.FindLatest(ctx, phoneNumber, purpose)
+	if err != nil {
+		return err
+	}
+	if v == nil || v.Used {
+		return ErrInvalidVerificationCode
+	}
+	if time.Now().After(v.ExpiredAt) {
+		return ErrVerificationCodeExpired
+	}
+	_, guesses, err := s.phoneRepo.CountSince(ctx, phoneNumber, purpose, time.Now().Add(-phoneCodeWindow))
+	if err != nil {
+		return err
+	}
+	if guesses >= phoneCodeMaxGuessesInWindow {
+		return ErrTooManyVerificationAttempts
+	}
+	attempts, ok, err := s.phoneRepo.IncrementAttempts(ctx, v.ID, phoneCodeMaxAttempts)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTooManyVerificationAttempts
+	}
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(code)), []byte(v.CodeHash)) != 1 {
+		slog.WarnContext(ctx, "verifyPhoneCode: wrong code", "purpose", purpose, "attempts", attempts)
+		if attempts == phoneCodeMaxAttempts {
+			metrics.Lockouts.WithLabelValues("phone_verification").Inc()
+		}
+		return ErrInvalidVerificationCode
+	}
+	used, err := s.phoneRepo.MarkUsed(ctx, v.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		// 동시에 같은 코드로 인증한 다른 요청이 먼저 사용함
+		return ErrInvalidVerificationCode
+	}
+	return nil
+}
+
+// VerifyPhone marks the user's current phone number as verified with a code sent by SendPhoneCode.
+func (s *AuthService) VerifyPhone(ctx context.Context, userID int64, code string) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.VerifyPhone")
+	defer func() { tracing.End(span, err) }()
+	defer func() { s.publishFailed(ctx, event.NamePhoneVerified, userID, err, nil) }()
+	profile, err := s.profileRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "VerifyPhone: find profile failed", "error", err)
+		return err
+	}
+	if profile == nil {
+		return ErrProfileNotFound
+	}
+	if err := s.verifyPhoneCode(ctx, profile.PhoneNumber, PhonePurposeVerifyPhone, code); err != nil {
+		slog.WarnContext(ctx, "VerifyPhone: verification failed", "userId", userID, "error", err)
+		return err
+	}
+	now := time.Now()
+	profile.PhoneVerifiedAt = &now
+	profile.UpdatedAt = now
+	if err := s.profileRepo.Update(ctx, profile); err != nil {
+		slog.ErrorContext(ctx, "VerifyPhone: update profile failed", "error", err)
+		return err
+	}
+	s.events.Publish(ctx, event.PhoneVerified{UserID: userID, PhoneNumber: profile.PhoneNumber})
+	slog.InfoContext(ctx, "VerifyPhone: success", "userId", userID)
+	return nil
+}
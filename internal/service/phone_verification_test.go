@@ -0,0 +1,166 @@
+package service_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"auth/internal/dto"
+	"auth/internal/entity"
+	"auth/internal/repository"
+	"auth/internal/service"
+	"auth/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var smsCode = regexp.MustCompile(`\d{6}`)
+
+// createProfile gives the user a profile with the phone number, in E.164.
+func (f *authFixture) createProfile(t *testing.T, userID int64, phoneNumber string) {
+	now := time.Now()
+	assert.Nil(t, f.profiles.CreateTx(context.Background(), nil, &entity.ProfileEntity{
+		UserID:      userID,
+		Name:        "Kim",
+		GenderCode:  entity.GenderCodeUnspecified,
+		PhoneNumber: phoneNumber,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}))
+}
+
+func TestFindEmail_코드인증(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	f.createProfile(t, f.createUser(t, "a@example.com"), "+821012345678")
+
+	assert.Nil(t, f.svc.SendPhoneCode(ctx, 0, "010-1234-5678", service.PhonePurposeFindEmail))
+	assert.Len(t, f.sms.messages, 1)
+	code := smsCode.FindString(f.sms.messages[0])
+
+	res, err := f.svc.FindEmail(ctx, &dto.FindEmailRequest{PhoneNumber: "010-1234-5678", Code: code})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, res.Email)
+
+	// 사용한 코드는 다시 쓸 수 없다
+	_, err = f.svc.FindEmail(ctx, &dto.FindEmailRequest{PhoneNumber: "010-1234-5678", Code: code})
+	assert.ErrorIs(t, err, service.ErrInvalidVerificationCode)
+}
+
+func TestFindEmail_시도횟수초과(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	f.createProfile(t, f.createUser(t, "a@example.com"), "+821012345678")
+	assert.Nil(t, f.svc.SendPhoneCode(ctx, 0, "010-1234-5678", service.PhonePurposeFindEmail))
+	code := smsCode.FindString(f.sms.messages[0])
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	for i := 0; i < 5; i++ {
+		_, err := f.svc.FindEmail(ctx, &dto.FindEmailRequest{PhoneNumber: "010-1234-5678", Code: wrong})
+		assert.ErrorIs(t, err, service.ErrInvalidVerificationCode)
+	}
+	// 한도를 넘으면 맞는 코드도 거부한다
+	_, err := f.svc.FindEmail(ctx, &dto.FindEmailRequest{PhoneNumber: "010-1234-5678", Code: code})
+	assert.ErrorIs(t, err, service.ErrTooManyVerificationAttempts)
+}
+
+func TestIncrementAttempts_한도까지만증가(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	v := &entity.PhoneVerificationEntity{PhoneNumber: "+821012345678", Purpose: service.PhonePurposeFindEmail, CodeHash: utils.HashToken("123456"), ExpiredAt: time.Now().Add(time.Minute)}
+	assert.Nil(t, f.phones.Create(ctx, v))
+
+	for want := 1; want <= 2; want++ {
+		attempts, ok, err := f.phones.IncrementAttempts(ctx, v.ID, 2)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, want, attempts)
+	}
+	_, ok, err := f.phones.IncrementAttempts(ctx, v.ID, 2)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestFindEmail_코드만료(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	f.createProfile(t, f.createUser(t, "a@example.com"), "+821012345678")
+	assert.Nil(t, f.phones.Create(ctx, &entity.PhoneVerificationEntity{
+		PhoneNumber: "+821012345678",
+		Purpose:     service.PhonePurposeFindEmail,
+		CodeHash:    utils.HashToken("123456"),
+		ExpiredAt:   time.Now().Add(-time.Second),
+	}))
+
+	_, err := f.svc.FindEmail(ctx, &dto.FindEmailRequest{PhoneNumber: "010-1234-5678", Code: "123456"})
+	assert.ErrorIs(t, err, service.ErrVerificationCodeExpired)
+}
+
+// resendablePhones reports the latest code as older than the resend interval,
+// so tests can request new codes without waiting.
+type resendablePhones struct {
+	repository.PhoneVerificationRepository
+}
+
+func (r resendablePhones) FindLatest(ctx context.Context, phoneNumber, purpose string) (*entity.PhoneVerificationEntity, error) {
+	v, err := r.PhoneVerificationRepository.FindLatest(ctx, phoneNumber, purpose)
+	if v != nil {
+		v.CreatedAt = v.CreatedAt.Add(-2 * time.Minute)
+	}
+	return v, err
+}
+
+func newResendableFixture(t *testing.T) *authFixture {
+	f := newAuthFixture(t)
+	f.svc = service.NewAuthService(nil, f.users, f.profiles, service.NewJwtService("secret"), nil,
+		service.WithPhoneVerification(resendablePhones{f.phones}, f.sms))
+	return f
+}
+
+func TestSendPhoneCode_기간내발송횟수제한(t *testing.T) {
+	f := newResendableFixture(t)
+	ctx := context.Background()
+	f.createProfile(t, f.createUser(t, "a@example.com"), "+821012345678")
+
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, f.svc.SendPhoneCode(ctx, 0, "010-1234-5678", service.PhonePurposeFindEmail))
+	}
+	err := f.svc.SendPhoneCode(ctx, 0, "010-1234-5678", service.PhonePurposeFindEmail)
+	assert.ErrorIs(t, err, service.ErrTooManyVerificationCodes)
+	assert.Len(t, f.sms.messages, 5)
+}
+
+func TestFindEmail_재발송해도시도횟수누적(t *testing.T) {
+	f := newResendableFixture(t)
+	ctx := context.Background()
+	f.createProfile(t, f.createUser(t, "a@example.com"), "+821012345678")
+	guess := func(wrong int) string {
+		assert.Nil(t, f.svc.SendPhoneCode(ctx, 0, "010-1234-5678", service.PhonePurposeFindEmail))
+		code := smsCode.FindString(f.sms.messages[len(f.sms.messages)-1])
+		bad := "000000"
+		if code == bad {
+			bad = "111111"
+		}
+		for i := 0; i < wrong; i++ {
+			_, err := f.svc.FindEmail(ctx, &dto.FindEmailRequest{PhoneNumber: "010-1234-5678", Code: bad})
+			assert.ErrorIs(t, err, service.ErrInvalidVerificationCode)
+		}
+		return code
+	}
+
+	guess(5)
+	guess(4)
+	// 새 코드는 한 번만 틀렸지만 기간 안의 시도 합계가 한도에 닿았다
+	code := guess(1)
+	_, err := f.svc.FindEmail(ctx, &dto.FindEmailRequest{PhoneNumber: "010-1234-5678", Code: code})
+	assert.ErrorIs(t, err, service.ErrTooManyVerificationAttempts)
+
+	// 확인할 수 없는 코드는 더 보내지 않는다
+	err = f.svc.SendPhoneCode(ctx, 0, "010-1234-5678", service.PhonePurposeFindEmail)
+	assert.ErrorIs(t, err, service.ErrTooManyVerificationAttempts)
+	assert.Len(t, f.sms.messages, 3)
+}
@@ -0,0 +1,82 @@
+// Package sms provides SMS sending for phone number verification.
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Sender sends SMS messages.
+type Sender interface {
+	Send(ctx context.Context, to, message string) error
+}
+
+// NewSender returns the Sender for the given provider: "log" or "file".
+func NewSender(provider, filePath string) (Sender, error) {
+	switch provider {
+	case "log", "":
+		return LogSender{}, nil
+	case "file":
+		return NewFileSender(filePath), nil
+	default:
+		return nil, fmt.Errorf("unsupported sms provider: %s", provider)
+	}
+}
+
+// LogSender writes messages to the application log instead of sending them.
+// It is meant for local development only.
+type LogSender struct{}
+
+// Send logs the message.
+func (LogSender) Send(ctx context.Context, to, message string) error {
+	slog.InfoContext(ctx, "SMS (log provider)", "to", to, "message", message)
+	return nil
+}
+
+// FileSender appends messages as JSON lines to a file, so tests and developers
+// can read the codes that would have been sent.
+type FileSender struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewFileSender creates a FileSender writing to path.
+func NewFileSender(path string) *FileSender {
+	return &FileSender{Path: path}
+}
+
+// Message is a single SMS recorded by FileSender.
+type Message struct {
+	To      string    `json:"to"`
+	Message string    `json:"message"`
+	SentAt  time.Time `json:"sentAt"`
+}
+
+// Send appends the message to the file.
+func (s *FileSender) Send(_ context.Context, to, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(Message{To: to, Message: message, SentAt: time.Now()})
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,43 @@
+package sms_test
+
+import (
+	"auth/internal/service/sms"
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSender(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sms", "outbox.log")
+	sender, err := sms.NewSender("file", path)
+	assert.Nil(t, err)
+
+	assert.Nil(t, sender.Send(context.Background(), "+821012345678", "code 123456"))
+	assert.Nil(t, sender.Send(context.Background(), "+821087654321", "code 654321"))
+
+	f, err := os.Open(path)
+	assert.Nil(t, err)
+	defer func() {
+		_ = f.Close()
+	}()
+	var messages []sms.Message
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var m sms.Message
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &m))
+		messages = append(messages, m)
+	}
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "+821012345678", messages[0].To)
+	assert.Equal(t, "code 654321", messages[1].Message)
+}
+
+func TestNewSender_지원하지않는공급자(t *testing.T) {
+	_, err := sms.NewSender("carrier-pigeon", "")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,237 @@
+package useradmin
+
+import (
+	"auth/internal/entity"
+	"auth/internal/service/audit"
+	"auth/pkg/utils"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// exportPageSize is how many users Export reads per query.
+const exportPageSize = 500
+
+// Export writes every user, including disabled and deleted users, to w as JSON lines in ID
+// order, with their password hashes, roles and profiles. It returns how many were written.
+// The output holds password hashes and personal data and must be protected accordingly.
+func (s *Service) Export(ctx context.Context, w io.Writer) (int, error) {
+	enc := json.NewEncoder(w)
+	var afterID int64
+	count := 0
+	for {
+		users, err := s.admin.List(ctx, afterID, exportPageSize)
+		if err != nil {
+			return count, err
+		}
+		for _, u := range users {
+			v, err := s.view(ctx, u)
+			if err != nil {
+				return count, err
+			}
+			v.PasswordHash = u.PasswordHash
+			if err := enc.Encode(v); err != nil {
+				return count, err
+			}
+			count++
+			afterID = u.ID
+		}
+		if len(users) < exportPageSize {
+			return count, nil
+		}
+	}
+}
+
+// ImportResult reports the outcome of Import.
+type ImportResult struct {
+	Created int           `json:"created"`
+	Skipped int           `json:"skipped"` // 이미 있는 이메일
+	Failed  int           `json:"failed"`
+	Errors  []ImportError `json:"errors,omitempty"`
+}
+
+// ImportError is a line Import could not import.
+type ImportError struct {
+	Line  int    `json:"line"`
+	Email string `json:"email,omitempty"`
+	Error string `json:"error"`
+}
+
+// Import creates the users read from r as written by Export. Users get new IDs; users whose
+// email already exists are skipped, and lines that cannot be imported are reported in the
+// result without stopping the import. Only reading r and database errors are returned as errors.
+func (s *Service) Import(ctx context.Context, r io.Reader) (*ImportResult, error) {
+	result := &ImportResult{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var u User
+		if err := json.Unmarshal([]byte(text), &u); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, ImportError{Line: line, Error: err.Error()})
+			continue
+		}
+		created, err := s.importUser(ctx, &u)
+		var lineErr *importLineError
+		switch {
+		case errors.As(err, &lineErr):
+			result.Failed++
+			result.Errors = append(result.Errors, ImportError{Line: line, Email: u.Email, Error: lineErr.Error()})
+		case err != nil:
+			return result, fmt.Errorf("line %d: %w", line, err)
+		case created:
+			result.Created++
+		default:
+			result.Skipped++
+		}
+	}
+	return result, scanner.Err()
+}
+
+// importLineError is an invalid user in the import, as opposed to a database error.
+type importLineError struct {
+	msg string
+}
+
+func (e *importLineError) Error() string {
+	return e.msg
+}
+
+func (s *Service) importUser(ctx context.Context, in *User) (created bool, err error) {
+	var userID int64
+	defer func() {
+		if created || err != nil {
+			s.record(ctx, audit.EventAdminUserImport, userID, err, map[string]string{"email": utils.MaskEmail(in.Email)})
+		}
+	}()
+	email := strings.TrimSpace(in.Email)
+	if email == "" {
+		return false, &importLineError{"email is required"}
+	}
+	for _, role := range in.Roles {
+		if !rolePattern.MatchString(role) {
+			return false, &importLineError{fmt.Sprintf("%v: %q", ErrInvalidRole, role)}
+		}
+	}
+	// 다른 제공자로 가입한 사용자는 비밀번호가 없을 수 있다
+	if in.PasswordHash != "" || in.Provider == "" || in.Provider == "local" {
+		if err := utils.ValidateHash(in.PasswordHash); err != nil {
+			return false, &importLineError{fmt.Sprintf("password hash: %v", err)}
+		}
+	}
+	var profile *entity.ProfileEntity
+	if in.Profile != nil {
+		if profile, err = s.importProfile(in.Profile); err != nil {
+			return false, &importLineError{err.Error()}
+		}
+	}
+	existing, err := s.admin.FindByEmail(ctx, email)
+	if err != nil || existing != nil {
+		return false, err
+	}
+	if profile != nil {
+		other, err := s.profiles.FindByPhoneNumber(ctx, profile.PhoneNumber)
+		if err != nil {
+			return false, err
+		}
+		if other != nil {
+			return false, &importLineError{fmt.Sprintf("phone number is used by user %d", other.UserID)}
+		}
+	}
+
+	tx, commit, rollback, err := s.begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			_ = rollback()
+		}
+	}()
+	now := time.Now()
+	u := &entity.UserEntity{
+		Email:        email,
+		PasswordHash: in.PasswordHash,
+		Provider:     in.Provider,
+		ProviderID:   in.ProviderID,
+		CreatedAt:    in.CreatedAt,
+		UpdatedAt:    now,
+		DisabledAt:   in.DisabledAt,
+		DeletedAt:    in.DeletedAt,
+	}
+	if u.Provider == "" {
+		u.Provider = "local"
+	}
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = now
+	}
+	if userID, err = s.admin.CreateTx(ctx, tx, u); err != nil {
+		return false, err
+	}
+	if profile != nil {
+		profile.UserID = userID
+		profile.CreatedAt, profile.UpdatedAt = u.CreatedAt, now
+		if err = s.profiles.CreateTx(ctx, tx, profile); err != nil {
+			return false, err
+		}
+	}
+	if err = commit(); err != nil {
+		return false, err
+	}
+	if profile != nil && profile.PhoneVerifiedAt != nil {
+		// CreateTx 는 인증 시각을 저장하지 않는다
+		if err = s.profiles.Update(ctx, profile); err != nil {
+			return false, err
+		}
+	}
+	for _, role := range in.Roles {
+		if _, err = s.admin.AddRole(ctx, userID, role); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (s *Service) importProfile(p *Profile) (*entity.ProfileEntity, error) {
+	if p.PhoneNumber == "" {
+		return nil, errors.New("profile phone number is required")
+	}
+	phoneNumber, err := s.phones.Normalize(p.PhoneNumber)
+	if err != nil {
+		return nil, fmt.Errorf("profile phone number: %w", err)
+	}
+	e := &entity.ProfileEntity{
+		Name:            p.Name,
+		GenderCode:      entity.GenderCode(p.GenderCode),
+		PhoneNumber:     phoneNumber,
+		PhoneVerifiedAt: p.PhoneVerifiedAt,
+		Locale:          p.Locale,
+	}
+	if e.GenderCode == "" {
+		e.GenderCode = entity.GenderCodeUnspecified
+	}
+	switch e.GenderCode {
+	case entity.GenderCodeMale, entity.GenderCodeFemale, entity.GenderCodeOther, entity.GenderCodeNonBinary, entity.GenderCodeUnspecified:
+	default:
+		return nil, fmt.Errorf("gender code must be one of M, F, O, N, U: %q", p.GenderCode)
+	}
+	if p.BirthDate != "" {
+		t, err := time.Parse("2006-01-02", p.BirthDate)
+		if err != nil {
+			return nil, errors.New("profile birth date must be in YYYY-MM-DD format")
+		}
+		e.BirthDate = t
+	}
+	return e, nil
+}
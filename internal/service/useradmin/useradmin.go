@@ -0,0 +1,531 @@
+// Package useradmin implements the user administration of the authctl command: creating,
+// disabling and restoring users, resetting passwords, revoking sessions, assigning roles and
+// exporting and importing users.
+package useradmin
+
+import (
+	"auth/internal/entity"
+	"auth/internal/repository"
+	"auth/internal/service"
+	"auth/internal/service/audit"
+	"auth/internal/service/password"
+	"auth/internal/service/phone"
+	"auth/pkg/utils"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// ErrInvalidRole is returned for role names other than lowercase letters, digits and "_.:-".
+var ErrInvalidRole = errors.New("role must be 1-64 lowercase letters, digits or _.:- starting with a letter")
+
+var rolePattern = regexp.MustCompile(`^[a-z][a-z0-9_.:-]{0,63}$`)
+
+// Service performs administrative operations on users. Every change is recorded in the
+// audit log, if one is set, with the admin actor.
+type Service struct {
+	dbPool   *pgxpool.Pool
+	users    repository.UserRepository
+	admin    repository.UserAdminRepository
+	profiles repository.ProfileRepository
+	hasher   utils.PasswordHasher
+	policy   password.Policy
+	phones   *phone.Parser
+	auditLog *audit.Service
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithPasswordHasher sets the hasher of new passwords, utils.DefaultPasswordHasher by default.
+func WithPasswordHasher(hasher utils.PasswordHasher) Option {
+	return func(s *Service) {
+		s.hasher = hasher
+	}
+}
+
+// WithPasswordPolicy sets the policy new passwords must satisfy, password.DefaultPolicy by default.
+func WithPasswordPolicy(policy password.Policy) Option {
+	return func(s *Service) {
+		s.policy = policy
+	}
+}
+
+// WithPhoneParser sets the parser of profile phone numbers.
+func WithPhoneParser(p *phone.Parser) Option {
+	return func(s *Service) {
+		s.phones = p
+	}
+}
+
+// WithAuditLog records the operations in the audit log.
+func WithAuditLog(l *audit.Service) Option {
+	return func(s *Service) {
+		s.auditLog = l
+	}
+}
+
+// NewService creates a Service. dbPool is nil with sqlite, which has no transactions.
+func NewService(dbPool *pgxpool.Pool, users repository.UserRepository, admin repository.UserAdminRepository, profiles repository.ProfileRepository, opts ...Option) *Service {
+	s := &Service{
+		dbPool:   dbPool,
+		users:    users,
+		admin:    admin,
+		profiles: profiles,
+		hasher:   utils.DefaultPasswordHasher,
+		policy:   password.DefaultPolicy,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.hasher.Algorithm() == utils.AlgorithmBcrypt {
+		// bcrypt 가 해시하지 못하는 긴 비밀번호는 정책 위반으로 거부한다
+		s.policy = s.policy.LimitBytes(utils.BcryptMaxPasswordBytes)
+	}
+	if s.phones == nil {
+		s.phones = phone.MustNewParser(phone.DefaultRegion)
+	}
+	return s
+}
+
+// User is a user as shown to administrators and written by Export.
+type User struct {
+	ID           int64      `json:"id"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"passwordHash,omitempty"` // Export 에서만 채움
+	Provider     string     `json:"provider"`
+	ProviderID   *string    `json:"providerId,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	DisabledAt   *time.Time `json:"disabledAt,omitempty"`
+	DeletedAt    *time.Time `json:"deletedAt,omitempty"`
+	Roles        []string   `json:"roles"`
+	Profile      *Profile   `json:"profile,omitempty"`
+}
+
+// Profile is the profile of a User.
+type Profile struct {
+	Name            string     `json:"name"`
+	BirthDate       string     `json:"birthDate,omitempty"` // YYYY-MM-DD
+	GenderCode      string     `json:"genderCode"`
+	PhoneNumber     string     `json:"phoneNumber"`
+	PhoneVerifiedAt *time.Time `json:"phoneVerifiedAt,omitempty"`
+	Locale          string     `json:"locale,omitempty"`
+}
+
+// Find returns the user with the ID or email, including deleted users.
+func (s *Service) Find(ctx context.Context, ref string) (*User, error) {
+	u, err := s.find(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(ctx, u)
+}
+
+// find resolves ref, a user ID or an email, to a user, deleted or not.
+func (s *Service) find(ctx context.Context, ref string) (*entity.UserEntity, error) {
+	var u *entity.UserEntity
+	var err error
+	if id, convErr := strconv.ParseInt(ref, 10, 64); convErr == nil {
+		u, err = s.admin.FindByID(ctx, id)
+	} else {
+		u, err = s.admin.FindByEmail(ctx, strings.TrimSpace(ref))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, fmt.Errorf("%w: %s", service.ErrUserNotFound, ref)
+	}
+	return u, nil
+}
+
+func (s *Service) view(ctx context.Context, u *entity.UserEntity) (*User, error) {
+	roles, err := s.admin.FindRoles(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if roles == nil {
+		roles = []string{}
+	}
+	v := &User{
+		ID:         u.ID,
+		Email:      u.Email,
+		Provider:   u.Provider,
+		ProviderID: u.ProviderID,
+		CreatedAt:  u.CreatedAt,
+		DisabledAt: u.DisabledAt,
+		DeletedAt:  u.DeletedAt,
+		Roles:      roles,
+	}
+	p, err := s.profiles.FindByUserID(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if p != nil {
+		v.Profile = &Profile{
+			Name:            p.Name,
+			GenderCode:      string(p.GenderCode),
+			PhoneNumber:     p.PhoneNumber,
+			PhoneVerifiedAt: p.PhoneVerifiedAt,
+			Locale:          p.Locale,
+		}
+		if !p.BirthDate.IsZero() {
+			v.Profile.BirthDate = p.BirthDate.Format("2006-01-02")
+		}
+	}
+	return v, nil
+}
+
+// record writes the event to the audit log, if one is set.
+func (s *Service) record(ctx context.Context, eventType string, userID int64, err error, details map[string]string) {
+	if s.auditLog == nil {
+		return
+	}
+	outcome := audit.OutcomeSuccess
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrEmailExists), errors.Is(err, ErrInvalidRole):
+		outcome = audit.OutcomeFailure
+	case err != nil:
+		var policyErr *password.PolicyError
+		outcome = audit.OutcomeError
+		if errors.As(err, &policyErr) {
+			outcome = audit.OutcomeFailure
+		}
+	}
+	s.auditLog.Record(ctx, audit.Event{Type: eventType, Actor: audit.ActorAdmin, UserID: userID, Outcome: outcome, Details: details})
+}
+
+// begin starts a transaction on Postgres. With sqlite tx is nil and commit and rollback do nothing.
+func (s *Service) begin(ctx context.Context) (tx interface{}, commit, rollback func() error, err error) {
+	if s.dbPool == nil {
+		// sqlite 등 트랜잭션 없는 경우
+		return nil, func() error { return nil }, func() error { return nil }, nil
+	}
+	pgxTx, err := s.dbPool.Begin(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return pgxTx, func() error { return pgxTx.Commit(ctx) }, func() error { return pgxTx.Rollback(ctx) }, nil
+}
+
+// CreateUserInput describes a user to create. The profile is created only when Name is set,
+// and then needs a phone number like a registration.
+type CreateUserInput struct {
+	Email       string
+	Password    string // 비어 있으면 생성해 CreateUser 가 돌려줌
+	Roles       []string
+	Name        string
+	PhoneNumber string
+	BirthDate   string // YYYY-MM-DD, 선택
+	GenderCode  string // 비어 있으면 "U"
+}
+
+// CreateUser creates a local user that can sign in right away. It returns the user and the
+// generated password, if in.Password was empty.
+func (s *Service) CreateUser(ctx context.Context, in CreateUserInput) (_ *User, generated string, err error) {
+	var userID int64
+	defer func() {
+		s.record(ctx, audit.EventAdminUserCreate, userID, err, map[string]string{"email": utils.MaskEmail(in.Email)})
+	}()
+	email := strings.TrimSpace(in.Email)
+	if email == "" {
+		return nil, "", errors.New("email is required")
+	}
+	for _, role := range in.Roles {
+		if !rolePattern.MatchString(role) {
+			return nil, "", fmt.Errorf("%w: %q", ErrInvalidRole, role)
+		}
+	}
+	var profile *entity.ProfileEntity
+	if in.Name != "" {
+		if profile, err = s.newProfile(in); err != nil {
+			return nil, "", err
+		}
+	}
+	existing, err := s.admin.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, "", err
+	}
+	if existing != nil {
+		return nil, "", emailExists(existing)
+	}
+	pw := in.Password
+	if pw == "" {
+		if pw, err = GeneratePassword(); err != nil {
+			return nil, "", err
+		}
+		generated = pw
+	}
+	if violations := s.policy.Validate(pw, email, in.Name); len(violations) > 0 {
+		return nil, "", &password.PolicyError{Violations: violations}
+	}
+	hashed, err := s.hasher.Hash(pw)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tx, commit, rollback, err := s.begin(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if err != nil {
+			_ = rollback()
+		}
+	}()
+	now := time.Now()
+	userID, err = s.admin.CreateTx(ctx, tx, &entity.UserEntity{
+		Email:        email,
+		PasswordHash: hashed,
+		Provider:     "local",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if profile != nil {
+		profile.UserID = userID
+		profile.CreatedAt, profile.UpdatedAt = now, now
+		if err = s.profiles.CreateTx(ctx, tx, profile); err != nil {
+			return nil, "", err
+		}
+	}
+	if s.policy.HistorySize > 0 {
+		if err = s.users.InsertPasswordHistoryTx(ctx, tx, userID, hashed, s.policy.HistorySize); err != nil {
+			return nil, "", err
+		}
+	}
+	if err = commit(); err != nil {
+		return nil, "", err
+	}
+	for _, role := range in.Roles {
+		if _, err = s.admin.AddRole(ctx, userID, role); err != nil {
+			return nil, "", err
+		}
+	}
+	u, err := s.Find(ctx, strconv.FormatInt(userID, 10))
+	return u, generated, err
+}
+
+// emailExists returns service.ErrEmailExists naming the user that has the email, also
+// when that user was deleted and so does not show up for the service.
+func emailExists(u *entity.UserEntity) error {
+	if u.DeletedAt != nil {
+		return fmt.Errorf("%w: deleted user %d", service.ErrEmailExists, u.ID)
+	}
+	return fmt.Errorf("%w: user %d", service.ErrEmailExists, u.ID)
+}
+
+func (s *Service) newProfile(in CreateUserInput) (*entity.ProfileEntity, error) {
+	phoneNumber, err := s.phones.Normalize(in.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	if phoneNumber == "" {
+		return nil, errors.New("phone number is required with a name")
+	}
+	p := &entity.ProfileEntity{
+		Name:        in.Name,
+		GenderCode:  entity.GenderCodeUnspecified,
+		PhoneNumber: phoneNumber,
+	}
+	if in.GenderCode != "" {
+		p.GenderCode = entity.GenderCode(in.GenderCode)
+	}
+	switch p.GenderCode {
+	case entity.GenderCodeMale, entity.GenderCodeFemale, entity.GenderCodeOther, entity.GenderCodeNonBinary, entity.GenderCodeUnspecified:
+	default:
+		return nil, fmt.Errorf("gender code must be one of M, F, O, N, U: %q", in.GenderCode)
+	}
+	if in.BirthDate != "" {
+		if p.BirthDate, err = time.Parse("2006-01-02", in.BirthDate); err != nil {
+			return nil, service.ErrInvalidBirthDate
+		}
+	}
+	return p, nil
+}
+
+// Disable stops the user from signing in and revokes their sessions. Access tokens already
+// issued stay valid until they expire.
+func (s *Service) Disable(ctx context.Context, ref string) (_ *User, err error) {
+	var userID int64
+	defer func() { s.record(ctx, audit.EventAdminUserDisable, userID, err, nil) }()
+	u, err := s.find(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	userID = u.ID
+	if u.DisabledAt == nil {
+		now := time.Now()
+		if _, err = s.admin.SetDisabled(ctx, u.ID, &now); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.users.DeleteAllRefreshTokens(ctx, u.ID); err != nil {
+		return nil, err
+	}
+	return s.Find(ctx, strconv.FormatInt(u.ID, 10))
+}
+
+// Restore lets a disabled or deleted user sign in again.
+func (s *Service) Restore(ctx context.Context, ref string) (_ *User, err error) {
+	var userID int64
+	defer func() { s.record(ctx, audit.EventAdminUserRestore, userID, err, nil) }()
+	u, err := s.find(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	userID = u.ID
+	if _, err = s.admin.Restore(ctx, u.ID); err != nil {
+		return nil, err
+	}
+	return s.Find(ctx, strconv.FormatInt(u.ID, 10))
+}
+
+// ResetPassword sets the user's password and revokes their sessions. The password is checked
+// against the password policy; if it is empty one is generated and returned.
+func (s *Service) ResetPassword(ctx context.Context, ref, newPassword string) (generated string, err error) {
+	var userID int64
+	defer func() { s.record(ctx, audit.EventAdminPasswordReset, userID, err, nil) }()
+	u, err := s.find(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	userID = u.ID
+	if newPassword == "" {
+		if newPassword, err = GeneratePassword(); err != nil {
+			return "", err
+		}
+		generated = newPassword
+	}
+	if violations := s.policy.Validate(newPassword, u.Email); len(violations) > 0 {
+		return "", &password.PolicyError{Violations: violations}
+	}
+	hashed, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return "", err
+	}
+	tx, commit, rollback, err := s.begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			_ = rollback()
+		}
+	}()
+	if err = s.users.UpdatePasswordTx(ctx, tx, u.ID, hashed); err != nil {
+		return "", err
+	}
+	// 관리자가 정한 비밀번호로 되돌아가지 못하도록 이력에도 남긴다
+	if s.policy.HistorySize > 0 {
+		if err = s.users.InsertPasswordHistoryTx(ctx, tx, u.ID, hashed, s.policy.HistorySize); err != nil {
+			return "", err
+		}
+	}
+	if err = commit(); err != nil {
+		return "", err
+	}
+	if err = s.users.DeleteAllRefreshTokens(ctx, u.ID); err != nil {
+		return "", err
+	}
+	return generated, nil
+}
+
+// RevokeSessions deletes the user's refresh tokens, signing them out on every device once
+// their access tokens expire.
+func (s *Service) RevokeSessions(ctx context.Context, ref string) (_ *User, err error) {
+	var userID int64
+	defer func() { s.record(ctx, audit.EventAdminSessionsRevoke, userID, err, nil) }()
+	u, err := s.find(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	userID = u.ID
+	if err = s.users.DeleteAllRefreshTokens(ctx, u.ID); err != nil {
+		return nil, err
+	}
+	return s.view(ctx, u)
+}
+
+// AddRole grants the role to the user and returns the user.
+func (s *Service) AddRole(ctx context.Context, ref, role string) (_ *User, err error) {
+	var userID int64
+	defer func() {
+		s.record(ctx, audit.EventAdminRoleAdd, userID, err, map[string]string{"role": role})
+	}()
+	if !rolePattern.MatchString(role) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRole, role)
+	}
+	u, err := s.find(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	userID = u.ID
+	if _, err = s.admin.AddRole(ctx, u.ID, role); err != nil {
+		return nil, err
+	}
+	return s.view(ctx, u)
+}
+
+// RemoveRole revokes the role from the user and returns the user.
+func (s *Service) RemoveRole(ctx context.Context, ref, role string) (_ *User, err error) {
+	var userID int64
+	defer func() {
+		s.record(ctx, audit.EventAdminRoleRemove, userID, err, map[string]string{"role": role})
+	}()
+	u, err := s.find(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	userID = u.ID
+	if _, err = s.admin.RemoveRole(ctx, u.ID, role); err != nil {
+		return nil, err
+	}
+	return s.view(ctx, u)
+}
+
+// passwordAlphabets are the character classes of generated passwords; each is used at least once.
+var passwordAlphabets = []string{
+	"ABCDEFGHJKLMNPQRSTUVWXYZ",
+	"abcdefghijkmnopqrstuvwxyz",
+	"23456789",
+	"!#$%&*+-=?@^_",
+}
+
+// GeneratePassword returns a random 20 character password with upper and lower case letters,
+// digits and symbols, so it passes the character class and strength rules of the password policy.
+func GeneratePassword() (string, error) {
+	const length = 20
+	all := strings.Join(passwordAlphabets, "")
+	b := make([]byte, 0, length)
+	for i := 0; i < length; i++ {
+		alphabet := all
+		if i < len(passwordAlphabets) {
+			alphabet = passwordAlphabets[i]
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+		b = append(b, alphabet[n.Int64()])
+	}
+	// 종류별 문자가 앞에 몰리지 않게 섞는다
+	for i := len(b) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		b[i], b[j.Int64()] = b[j.Int64()], b[i]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,363 @@
+// Package webhook sends user lifecycle events to subscribed HTTP endpoints with signed,
+// retried deliveries.
+package webhook
+
+import (
+	"auth/internal/entity"
+	"auth/internal/metrics"
+	"auth/internal/repository"
+	"auth/internal/service/outbox"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Events sent to webhooks.
+const (
+	EventUserRegistered = "user.registered"
+	EventUserVerified   = "user.verified" // 전화번호 인증 완료
+	EventUserUpdated    = "user.updated"
+	EventUserDeleted    = "user.deleted"
+	// EventAll subscribes an endpoint to every event, including ones added later.
+	EventAll = "*"
+)
+
+// Events lists every event, for validating subscriptions.
+var Events = []string{EventUserRegistered, EventUserVerified, EventUserUpdated, EventUserDeleted}
+
+// ErrInvalidEndpoint is returned for an endpoint with a URL that is not absolute http(s)
+// or with no or unknown events.
+var ErrInvalidEndpoint = errors.New("webhook: invalid endpoint")
+
+// Payload is the JSON body of a webhook request.
+type Payload struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// Dispatcher stores webhook endpoints, queues a delivery per subscribed endpoint when an event
+// is emitted, and sends due deliveries in the background. Failed deliveries are retried with
+// exponential backoff and marked dead after MaxAttempts.
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+
+	Interval    time.Duration // 폴링 주기
+	BatchSize   int
+	MaxAttempts int
+	BaseBackoff time.Duration // 첫 재시도까지의 대기 시간, 시도마다 두 배
+	MaxBackoff  time.Duration
+	Lease       time.Duration // 발송 중인 항목을 다른 디스패처가 가져가지 않도록 미뤄 두는 시간
+	Timeout     time.Duration // 요청 한 번의 제한 시간
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewDispatcher creates a Dispatcher with default settings.
+func NewDispatcher(repo repository.WebhookRepository) *Dispatcher {
+	return &Dispatcher{
+		repo: repo,
+		client: &http.Client{
+			// 리다이렉트는 따라가지 않고 실패로 기록한다
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		Interval:    5 * time.Second,
+		BatchSize:   20,
+		MaxAttempts: 10,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  time.Hour,
+		Lease:       5 * time.Minute,
+		Timeout:     10 * time.Second,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Emit queues the event for every active endpoint subscribed to it. All deliveries of one
+// event share its ID and payload.
+func (d *Dispatcher) Emit(ctx context.Context, event string, data any) error {
+	endpoints, err := d.repo.ListEndpoints(ctx)
+	if err != nil {
+		return err
+	}
+	var body []byte
+	eventID := uuid.NewString()
+	for _, e := range endpoints {
+		if !e.Active || !e.Subscribes(event) {
+			continue
+		}
+		if body == nil {
+			body, err = json.Marshal(Payload{ID: eventID, Type: event, CreatedAt: time.Now().UTC(), Data: data})
+			if err != nil {
+				return err
+			}
+		}
+		if err := d.repo.Enqueue(ctx, &entity.WebhookDeliveryEntity{
+			EndpointID: e.ID,
+			EventID:    eventID,
+			Event:      event,
+			Payload:    string(body),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start runs the delivery loop in a background goroutine until Stop is called.
+// It does nothing if Interval is not positive.
+func (d *Dispatcher) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.started || d.stopped || d.Interval <= 0 {
+		return
+	}
+	d.started = true
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(d.Interval)
+		defer ticker.Stop()
+		for {
+			if _, err := d.RunOnce(context.Background()); err != nil {
+				slog.Error("webhook: dispatch failed", "error", err)
+			}
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop signals the loop to exit and waits for the batch in progress to finish or ctx to end.
+// It does nothing if the dispatcher was never started.
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.stopped {
+		d.stopped = true
+		close(d.stop)
+	}
+	started := d.started
+	d.mu.Unlock()
+	if !started {
+		return nil
+	}
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RunOnce sends one batch of due deliveries and returns how many succeeded.
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	deliveries, err := d.repo.ClaimDue(ctx, time.Now(), d.Lease, d.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	delivered := 0
+	for _, w := range deliveries {
+		status, err := d.send(ctx, w)
+		if err != nil {
+			metrics.WebhookDeliveries.WithLabelValues(w.Event, metrics.OutcomeError).Inc()
+			d.fail(ctx, w, status, err)
+			continue
+		}
+		metrics.WebhookDeliveries.WithLabelValues(w.Event, metrics.OutcomeSuccess).Inc()
+		if err := d.repo.MarkDelivered(ctx, w.ID, status); err != nil {
+			slog.ErrorContext(ctx, "webhook: mark delivered failed", "id", w.ID, "error", err)
+			continue
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// send posts the delivery to its endpoint and returns the response status, 0 if there was none.
+// Any status other than 2xx is an error.
+func (d *Dispatcher) send(ctx context.Context, w *entity.WebhookDeliveryEntity) (int, error) {
+	endpoint, err := d.repo.FindEndpoint(ctx, w.EndpointID)
+	if err != nil {
+		return 0, err
+	}
+	if endpoint == nil {
+		return 0, errors.New("endpoint deleted")
+	}
+	if !endpoint.Active {
+		return 0, errors.New("endpoint disabled")
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout)
+	defer cancel()
+	body := []byte(w.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "auth-webhooks/1.0")
+	req.Header.Set(HeaderID, w.EventID)
+	req.Header.Set(HeaderEvent, w.Event)
+	// 재시도마다 새 시각으로 서명하여 수신 측이 오래된 요청을 거부할 수 있게 한다
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, time.Now(), body))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) fail(ctx context.Context, w *entity.WebhookDeliveryEntity, responseStatus int, cause error) {
+	attempts := w.Attempts + 1
+	status := entity.WebhookStatusPending
+	next := time.Now().Add(outbox.Backoff(attempts, d.BaseBackoff, d.MaxBackoff))
+	if attempts >= d.MaxAttempts {
+		status = entity.WebhookStatusDead
+		slog.ErrorContext(ctx, "webhook: delivery dead-lettered", "id", w.ID, "endpointId", w.EndpointID, "event", w.Event, "attempts", attempts, "error", cause)
+	} else {
+		slog.WarnContext(ctx, "webhook: delivery failed, will retry", "id", w.ID, "endpointId", w.EndpointID, "event", w.Event, "attempts", attempts, "retryAt", next, "error", cause)
+	}
+	if err := d.repo.MarkFailed(ctx, w.ID, status, next, responseStatus, cause.Error()); err != nil {
+		slog.ErrorContext(ctx, "webhook: record failure failed", "id", w.ID, "error", err)
+	}
+}
+
+// NewSecret returns a random signing secret.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// validate checks the endpoint URL and events and normalizes the events.
+func validate(e *entity.WebhookEndpointEntity) error {
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidEndpoint)
+	}
+	events := e.EventList()
+	if len(events) == 0 {
+		return fmt.Errorf("%w: no events", ErrInvalidEndpoint)
+	}
+	for _, ev := range events {
+		known := ev == EventAll
+		for _, k := range Events {
+			known = known || ev == k
+		}
+		if !known {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidEndpoint, ev)
+		}
+	}
+	return nil
+}
+
+// CreateEndpoint validates and stores a new endpoint. A random secret is generated if it has none.
+func (d *Dispatcher) CreateEndpoint(ctx context.Context, e *entity.WebhookEndpointEntity) error {
+	if err := validate(e); err != nil {
+		return err
+	}
+	if e.Secret == "" {
+		secret, err := NewSecret()
+		if err != nil {
+			return err
+		}
+		e.Secret = secret
+	}
+	return d.repo.CreateEndpoint(ctx, e)
+}
+
+// UpdateEndpoint validates and saves an endpoint, returning false if there is none with its ID.
+// The endpoint keeps its secret unless rotateSecret is set, in which case e.Secret is the new one.
+func (d *Dispatcher) UpdateEndpoint(ctx context.Context, e *entity.WebhookEndpointEntity, rotateSecret bool) (bool, error) {
+	if err := validate(e); err != nil {
+		return false, err
+	}
+	current, err := d.repo.FindEndpoint(ctx, e.ID)
+	if err != nil || current == nil {
+		return false, err
+	}
+	e.Secret = current.Secret
+	if rotateSecret {
+		if e.Secret, err = NewSecret(); err != nil {
+			return false, err
+		}
+	}
+	e.CreatedAt = current.CreatedAt
+	return d.repo.UpdateEndpoint(ctx, e)
+}
+
+// DeleteEndpoint deletes the endpoint and its delivery log, returning false if there is none with the id.
+func (d *Dispatcher) DeleteEndpoint(ctx context.Context, id int64) (bool, error) {
+	return d.repo.DeleteEndpoint(ctx, id)
+}
+
+// Endpoint returns the endpoint, or nil if there is none with the id.
+func (d *Dispatcher) Endpoint(ctx context.Context, id int64) (*entity.WebhookEndpointEntity, error) {
+	return d.repo.FindEndpoint(ctx, id)
+}
+
+// Endpoints returns all endpoints, oldest first.
+func (d *Dispatcher) Endpoints(ctx context.Context) ([]*entity.WebhookEndpointEntity, error) {
+	return d.repo.ListEndpoints(ctx)
+}
+
+// Deliveries returns the endpoint's delivery log, newest first. An empty status matches all.
+func (d *Dispatcher) Deliveries(ctx context.Context, endpointID int64, status string, limit int) ([]*entity.WebhookDeliveryEntity, error) {
+	return d.repo.ListDeliveries(ctx, endpointID, status, limit)
+}
+
+// Replay queues a copy of the delivery, with the same event ID and payload, for immediate
+// sending. The original stays in the log. It returns nil if there is no delivery with the id.
+func (d *Dispatcher) Replay(ctx context.Context, id int64) (*entity.WebhookDeliveryEntity, error) {
+	w, err := d.repo.FindDelivery(ctx, id)
+	if err != nil || w == nil {
+		return nil, err
+	}
+	replay := &entity.WebhookDeliveryEntity{
+		EndpointID: w.EndpointID,
+		EventID:    w.EventID,
+		Event:      w.Event,
+		Payload:    w.Payload,
+	}
+	if err := d.repo.Enqueue(ctx, replay); err != nil {
+		return nil, err
+	}
+	return replay, nil
+}
+
+// UserData is the data of the user events. Fields that did not change or are not known
+// to the event are left out.
+type UserData struct {
+	UserID      int64  `json:"userId"`
+	Email       string `json:"email,omitempty"`
+	Name        string `json:"name,omitempty"`
+	PhoneNumber string `json:"phoneNumber,omitempty"` // E.164
+	Locale      string `json:"locale,omitempty"`
+}
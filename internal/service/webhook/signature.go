@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of a webhook request.
+const (
+	HeaderID        = "X-Webhook-Id"    // 이벤트 ID, 재전송해도 같다
+	HeaderEvent     = "X-Webhook-Event" // 예: "user.registered"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// DefaultTolerance is how old a signature timestamp Verify accepts by default.
+const DefaultTolerance = 5 * time.Minute
+
+// Errors returned by Verify.
+var (
+	ErrMissingSignature = errors.New("webhook: missing or malformed signature header")
+	ErrSignatureExpired = errors.New("webhook: signature timestamp outside tolerance")
+	ErrInvalidSignature = errors.New("webhook: signature mismatch")
+)
+
+// Sign returns the X-Webhook-Signature header value for body sent at t:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<body>">".
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, body))
+}
+
+func mac(secret, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte{'.'})
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// Verify checks a signature header made by Sign, for receivers of webhooks. The header may
+// hold several v1 values, e.g. while the secret is rotated; one matching is enough.
+// Signatures older or newer than tolerance relative to now are rejected to limit replays.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts string
+	var sigs [][]byte
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			if sig, err := hex.DecodeString(v); err == nil {
+				sigs = append(sigs, sig)
+			}
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return ErrMissingSignature
+	}
+	if d := now.Sub(time.Unix(unix, 0)); d > tolerance || d < -tolerance {
+		return ErrSignatureExpired
+	}
+	expected := mac(secret, ts, body)
+	for _, sig := range sigs {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
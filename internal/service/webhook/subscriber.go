@@ -0,0 +1,23 @@
+package webhook
+
+import (
+	"auth/internal/service/event"
+	"context"
+)
+
+// Subscribe queues webhooks for the user lifecycle events published on bus. Only queueing runs
+// in the publisher's goroutine; the deliveries are sent by the dispatcher loop.
+func (d *Dispatcher) Subscribe(bus *event.Bus) {
+	event.Subscribe(bus, func(ctx context.Context, e event.UserRegistered) error {
+		return d.Emit(ctx, EventUserRegistered, UserData{UserID: e.UserID, Email: e.Email, Name: e.Name, PhoneNumber: e.PhoneNumber})
+	})
+	event.Subscribe(bus, func(ctx context.Context, e event.PhoneVerified) error {
+		return d.Emit(ctx, EventUserVerified, UserData{UserID: e.UserID, PhoneNumber: e.PhoneNumber})
+	})
+	event.Subscribe(bus, func(ctx context.Context, e event.ProfileUpdated) error {
+		return d.Emit(ctx, EventUserUpdated, UserData{UserID: e.UserID, Name: e.Name, PhoneNumber: e.PhoneNumber, Locale: e.Locale})
+	})
+	event.Subscribe(bus, func(ctx context.Context, e event.AccountDeleted) error {
+		return d.Emit(ctx, EventUserDeleted, UserData{UserID: e.UserID, Email: e.Email})
+	})
+}
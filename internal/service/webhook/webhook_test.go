@@ -0,0 +1,248 @@
+package webhook_test
+
+import (
+	"auth/internal/entity"
+	"auth/internal/repository"
+	"auth/internal/service/event"
+	"auth/internal/service/webhook"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"zombiezen.com/go/sqlite"
+)
+
+func newDispatcher(t *testing.T) *webhook.Dispatcher {
+	conn, err := sqlite.OpenConn(":memory:", 0)
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	d := webhook.NewDispatcher(repository.NewWebhookRepositorySqlite(conn))
+	d.BaseBackoff = 0 // 바로 재시도 가능하도록
+	return d
+}
+
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver records requests and answers with status.
+func receiver(t *testing.T, status *int) (*httptest.Server, func() []received) {
+	var mu sync.Mutex
+	var got []received
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		got = append(got, received{header: r.Header.Clone(), body: body})
+		mu.Unlock()
+		w.WriteHeader(*status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []received {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]received(nil), got...)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"1"}`)
+	header := webhook.Sign("secret", now, body)
+	assert.Equal(t, "t=1700000000,v1=", header[:16])
+
+	assert.Nil(t, webhook.Verify("secret", header, body, webhook.DefaultTolerance, now.Add(time.Minute)))
+	// 키 교체 중에는 서명이 여러 개일 수 있다
+	assert.Nil(t, webhook.Verify("secret", header+",v1=00ff", body, webhook.DefaultTolerance, now))
+
+	assert.ErrorIs(t, webhook.Verify("other", header, body, webhook.DefaultTolerance, now), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify("secret", header, []byte(`{"id":"2"}`), webhook.DefaultTolerance, now), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify("secret", header, body, webhook.DefaultTolerance, now.Add(time.Hour)), webhook.ErrSignatureExpired)
+	assert.ErrorIs(t, webhook.Verify("secret", "v1=abcd", body, webhook.DefaultTolerance, now), webhook.ErrMissingSignature)
+}
+
+func TestCreateEndpoint_검증(t *testing.T) {
+	d := newDispatcher(t)
+	ctx := context.Background()
+
+	err := d.CreateEndpoint(ctx, &entity.WebhookEndpointEntity{URL: "ftp://example.com", Events: webhook.EventAll})
+	assert.ErrorIs(t, err, webhook.ErrInvalidEndpoint)
+	err = d.CreateEndpoint(ctx, &entity.WebhookEndpointEntity{URL: "https://example.com/hook", Events: "user.login"})
+	assert.ErrorIs(t, err, webhook.ErrInvalidEndpoint)
+
+	e := &entity.WebhookEndpointEntity{URL: "https://example.com/hook", Events: webhook.EventUserRegistered, Active: true}
+	assert.Nil(t, d.CreateEndpoint(ctx, e))
+	assert.NotZero(t, e.ID)
+	assert.Contains(t, e.Secret, "whsec_")
+
+	// 수정해도 키는 유지되고, rotateSecret 이면 새로 발급된다
+	secret := e.Secret
+	ok, err := d.UpdateEndpoint(ctx, &entity.WebhookEndpointEntity{ID: e.ID, URL: "https://example.com/v2", Events: webhook.EventAll}, false)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	got, err := d.Endpoint(ctx, e.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, secret, got.Secret)
+	assert.Equal(t, "https://example.com/v2", got.URL)
+	assert.False(t, got.Active)
+
+	rotated := &entity.WebhookEndpointEntity{ID: e.ID, URL: got.URL, Events: got.Events}
+	ok, err = d.UpdateEndpoint(ctx, rotated, true)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.NotEqual(t, secret, rotated.Secret)
+
+	ok, err = d.UpdateEndpoint(ctx, &entity.WebhookEndpointEntity{ID: 999, URL: got.URL, Events: got.Events}, false)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestDispatcher_서명된발송(t *testing.T) {
+	d := newDispatcher(t)
+	ctx := context.Background()
+	status := http.StatusNoContent
+	srv, got := receiver(t, &status)
+
+	subscribed := &entity.WebhookEndpointEntity{URL: srv.URL, Events: webhook.EventUserRegistered + "," + webhook.EventUserDeleted, Active: true}
+	assert.Nil(t, d.CreateEndpoint(ctx, subscribed))
+	// 구독하지 않았거나 비활성인 엔드포인트에는 보내지 않는다
+	assert.Nil(t, d.CreateEndpoint(ctx, &entity.WebhookEndpointEntity{URL: srv.URL, Events: webhook.EventUserUpdated, Active: true}))
+	assert.Nil(t, d.CreateEndpoint(ctx, &entity.WebhookEndpointEntity{URL: srv.URL, Events: webhook.EventAll, Active: false}))
+
+	assert.Nil(t, d.Emit(ctx, webhook.EventUserRegistered, webhook.UserData{UserID: 7, Email: "kim@example.com"}))
+	sent, err := d.RunOnce(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+
+	requests := got()
+	assert.Len(t, requests, 1)
+	r := requests[0]
+	assert.Equal(t, webhook.EventUserRegistered, r.header.Get(webhook.HeaderEvent))
+	assert.Nil(t, webhook.Verify(subscribed.Secret, r.header.Get(webhook.HeaderSignature), r.body, webhook.DefaultTolerance, time.Now()))
+	var payload struct {
+		ID   string           `json:"id"`
+		Type string           `json:"type"`
+		Data webhook.UserData `json:"data"`
+	}
+	assert.Nil(t, json.Unmarshal(r.body, &payload))
+	assert.Equal(t, r.header.Get(webhook.HeaderID), payload.ID)
+	assert.Equal(t, webhook.EventUserRegistered, payload.Type)
+	assert.Equal(t, int64(7), payload.Data.UserID)
+
+	deliveries, err := d.Deliveries(ctx, subscribed.ID, entity.WebhookStatusDelivered, 10)
+	assert.Nil(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, http.StatusNoContent, deliveries[0].ResponseStatus)
+	assert.NotNil(t, deliveries[0].DeliveredAt)
+}
+
+func TestSubscribe_도메인이벤트(t *testing.T) {
+	d := newDispatcher(t)
+	ctx := context.Background()
+	e := &entity.WebhookEndpointEntity{URL: "https://example.com/hook", Events: webhook.EventAll, Active: true}
+	assert.Nil(t, d.CreateEndpoint(ctx, e))
+	bus := event.NewBus()
+	d.Subscribe(bus)
+
+	bus.Publish(ctx, event.UserRegistered{UserID: 1, Email: "kim@example.com"})
+	bus.Publish(ctx, event.PhoneVerified{UserID: 1, PhoneNumber: "+821012345678"})
+	bus.Publish(ctx, event.LoggedIn{UserID: 1}) // 웹훅 이벤트가 아니다
+	bus.Publish(ctx, event.AccountDeleted{UserID: 1})
+
+	deliveries, err := d.Deliveries(ctx, e.ID, entity.WebhookStatusPending, 10)
+	assert.Nil(t, err)
+	assert.Len(t, deliveries, 3)
+	assert.Equal(t, webhook.EventUserDeleted, deliveries[0].Event)
+	assert.Equal(t, webhook.EventUserVerified, deliveries[1].Event)
+	assert.Equal(t, webhook.EventUserRegistered, deliveries[2].Event)
+}
+
+func TestDispatcher_재시도후Dead와재전송(t *testing.T) {
+	d := newDispatcher(t)
+	d.MaxAttempts = 3
+	ctx := context.Background()
+	status := http.StatusInternalServerError
+	srv, got := receiver(t, &status)
+
+	e := &entity.WebhookEndpointEntity{URL: srv.URL, Events: webhook.EventAll, Active: true}
+	assert.Nil(t, d.CreateEndpoint(ctx, e))
+	assert.Nil(t, d.Emit(ctx, webhook.EventUserDeleted, webhook.UserData{UserID: 1}))
+
+	for i := 0; i < 3; i++ {
+		sent, err := d.RunOnce(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, sent)
+	}
+	dead, err := d.Deliveries(ctx, e.ID, entity.WebhookStatusDead, 10)
+	assert.Nil(t, err)
+	assert.Len(t, dead, 1)
+	assert.Equal(t, 3, dead[0].Attempts)
+	assert.Equal(t, http.StatusInternalServerError, dead[0].ResponseStatus)
+	assert.Contains(t, dead[0].LastError, "unexpected status 500")
+	assert.Len(t, got(), 3)
+
+	// 재전송은 같은 이벤트 ID 로 새 발송을 만든다
+	status = http.StatusOK
+	replay, err := d.Replay(ctx, dead[0].ID)
+	assert.Nil(t, err)
+	assert.NotEqual(t, dead[0].ID, replay.ID)
+	sent, err := d.RunOnce(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+	requests := got()
+	assert.Equal(t, dead[0].EventID, requests[len(requests)-1].header.Get(webhook.HeaderID))
+
+	all, err := d.Deliveries(ctx, e.ID, "", 10)
+	assert.Nil(t, err)
+	assert.Len(t, all, 2)
+
+	missing, err := d.Replay(ctx, 999)
+	assert.Nil(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestDispatcher_삭제된엔드포인트(t *testing.T) {
+	d := newDispatcher(t)
+	d.MaxAttempts = 1
+	ctx := context.Background()
+	e := &entity.WebhookEndpointEntity{URL: "http://127.0.0.1:1/hook", Events: webhook.EventAll, Active: true}
+	assert.Nil(t, d.CreateEndpoint(ctx, e))
+	assert.Nil(t, d.Emit(ctx, webhook.EventUserUpdated, webhook.UserData{UserID: 1}))
+
+	ok, err := d.DeleteEndpoint(ctx, e.ID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	// 발송 기록도 함께 지워져 보낼 것이 없다
+	sent, err := d.RunOnce(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, sent)
+	ok, err = d.DeleteEndpoint(ctx, e.ID)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestDispatcher_StartStop(t *testing.T) {
+	d := newDispatcher(t)
+	assert.Nil(t, d.Stop(context.Background()))
+
+	d = newDispatcher(t)
+	d.Interval = 10 * time.Millisecond
+	d.Start()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, d.Stop(ctx))
+
+	// Interval 이 0 이면 시작하지 않는다
+	d = newDispatcher(t)
+	d.Interval = 0
+	d.Start()
+	assert.Nil(t, d.Stop(ctx))
+}
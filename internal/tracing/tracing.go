@@ -0,0 +1,101 @@
+// Package tracing sets up OpenTelemetry tracing and provides the helpers the other layers use to
+// create spans.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of every span created by the service.
+const ScopeName = "auth"
+
+// Exporters accepted by Setup.
+const (
+	ExporterNone   = "none"   // 스팬을 기록하지 않고 trace context 만 전파
+	ExporterStdout = "stdout" // 표준 출력에 JSON 으로, 로컬 확인용
+	ExporterOTLP   = "otlp"   // OTLP/HTTP 로 collector 에 전송
+)
+
+// Config configures Setup.
+type Config struct {
+	Exporter    string
+	Endpoint    string // OTLP 주소, 예: "http://otel-collector:4318". 비어 있으면 OTEL_EXPORTER_OTLP_* 환경 변수
+	ServiceName string
+	SampleRatio float64 // 새로 시작하는 trace 중 기록할 비율, 0~1. 들어온 요청의 sampled 플래그는 따른다
+}
+
+// Setup installs the global tracer provider for the exporter and the W3C trace-context and baggage
+// propagators. The returned function flushes pending spans and stops the provider.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter: %s", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tp := NewTracerProvider(cfg.ServiceName, cfg.SampleRatio, sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// NewTracerProvider creates a tracer provider for the service, sampling sampleRatio of new traces.
+// opts registers the exporter, e.g. sdktrace.WithBatcher, or sdktrace.WithSyncer with an in-memory
+// exporter from sdk/trace/tracetest in tests.
+func NewTracerProvider(serviceName string, sampleRatio float64, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	opts = append(opts,
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+// Start starts a span named name as a child of the span in ctx, if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(ScopeName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if not nil, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceID returns the trace ID of the span in ctx, or "" if ctx has no valid span.
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
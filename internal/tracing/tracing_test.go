@@ -0,0 +1,114 @@
+package tracing_test
+
+import (
+	"auth/internal/handler"
+	"auth/internal/tracing"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setup installs a tracer provider that records spans in memory until the test ends.
+func setup(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.NewTracerProvider("auth-test", 1, sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return exporter
+}
+
+func newApp() *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	app.Use(tracing.Middleware())
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		_, span := tracing.Start(c.UserContext(), "AuthService.GetProfile")
+		span.End()
+		return c.SendString("ok")
+	})
+	app.Get("/boom", func(_ *fiber.Ctx) error { return errors.New("db down") })
+	return app
+}
+
+func TestMiddleware_스팬(t *testing.T) {
+	exporter := setup(t)
+	app := newApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/1", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	child, server := spans[0], spans[1]
+	assert.Equal(t, "GET /users/:id", server.Name)
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, "AuthService.GetProfile", child.Name)
+	assert.Equal(t, server.SpanContext.SpanID(), child.Parent.SpanID(), "핸들러의 스팬은 요청 스팬의 자식")
+	assert.Equal(t, server.SpanContext.TraceID(), child.SpanContext.TraceID())
+}
+
+func TestMiddleware_traceparent전파(t *testing.T) {
+	exporter := setup(t)
+	app := newApp()
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, err := app.Test(req)
+	assert.Nil(t, err)
+
+	spans := exporter.GetSpans()
+	server := spans[len(spans)-1]
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.True(t, server.Parent.IsRemote())
+}
+
+func TestMiddleware_오류응답에traceId(t *testing.T) {
+	exporter := setup(t)
+	app := newApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/boom", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	var body handler.APIResponse
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, spans[0].SpanContext.TraceID().String(), body.TraceID)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+}
+
+func TestLogHandler(t *testing.T) {
+	setup(t)
+	var buf bytes.Buffer
+	logger := slog.New(tracing.NewLogHandler(slog.NewJSONHandler(&buf, nil)))
+
+	ctx, span := tracing.Start(context.Background(), "test")
+	defer span.End()
+	logger.InfoContext(ctx, "hello")
+
+	var record map[string]any
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, tracing.TraceID(ctx), record["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), record["span_id"])
+
+	// 스팬이 없는 로그에는 붙이지 않는다
+	buf.Reset()
+	logger.Info("no span")
+	assert.NotContains(t, buf.String(), "trace_id")
+}
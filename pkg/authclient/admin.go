@@ -0,0 +1,167 @@
+package authclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// The admin calls need WithAdminKey and a server with ADMIN_API_KEY set.
+
+// OutboxQuery selects outbox messages. Zero fields use the server defaults.
+type OutboxQuery struct {
+	Status string // "pending" 또는 "dead"
+	Limit  int
+}
+
+// ListOutbox returns outbox messages (GET /admin/outbox).
+func (c *Client) ListOutbox(ctx context.Context, q OutboxQuery) ([]OutboxMessageResponse, error) {
+	query := url.Values{}
+	if q.Status != "" {
+		query.Set("status", q.Status)
+	}
+	if q.Limit > 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+	var out []OutboxMessageResponse
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/admin/outbox", query: query, auth: authAdmin}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RetryOutbox requeues a dead outbox message (POST /admin/outbox/{id}/retry).
+func (c *Client) RetryOutbox(ctx context.Context, id int64) error {
+	return c.do(ctx, call{method: http.MethodPost, path: fmt.Sprintf("/admin/outbox/%d/retry", id), auth: authAdmin}, nil)
+}
+
+// AuditQuery selects audit events. Zero fields match everything.
+type AuditQuery struct {
+	UserID  int64
+	Event   string
+	Outcome string
+	Since   time.Time
+	Until   time.Time
+	Before  int64 // 이 ID 이전 이벤트만
+	Limit   int
+}
+
+// ListAudit returns audit events, newest first (GET /admin/audit).
+func (c *Client) ListAudit(ctx context.Context, q AuditQuery) ([]AuditEventResponse, error) {
+	query := url.Values{}
+	if q.UserID != 0 {
+		query.Set("userId", strconv.FormatInt(q.UserID, 10))
+	}
+	if q.Event != "" {
+		query.Set("event", q.Event)
+	}
+	if q.Outcome != "" {
+		query.Set("outcome", q.Outcome)
+	}
+	if !q.Since.IsZero() {
+		query.Set("since", q.Since.Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		query.Set("until", q.Until.Format(time.RFC3339))
+	}
+	if q.Before > 0 {
+		query.Set("before", strconv.FormatInt(q.Before, 10))
+	}
+	if q.Limit > 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+	var out []AuditEventResponse
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/admin/audit", query: query, auth: authAdmin}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VerifyAudit recomputes the hash chain of the audit log (GET /admin/audit/verify).
+func (c *Client) VerifyAudit(ctx context.Context) (*AuditVerifyResult, error) {
+	out := &AuditVerifyResult{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/admin/audit/verify", auth: authAdmin}, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListWebhooks returns the webhook endpoints (GET /admin/webhooks).
+func (c *Client) ListWebhooks(ctx context.Context) ([]WebhookEndpointResponse, error) {
+	var out []WebhookEndpointResponse
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/admin/webhooks", auth: authAdmin}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateWebhook creates a webhook endpoint (POST /admin/webhooks). The response is the only one
+// with the signing secret.
+func (c *Client) CreateWebhook(ctx context.Context, req *WebhookEndpointRequest) (*WebhookEndpointResponse, error) {
+	out := &WebhookEndpointResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/admin/webhooks", body: req, auth: authAdmin}, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetWebhook returns a webhook endpoint (GET /admin/webhooks/{id}).
+func (c *Client) GetWebhook(ctx context.Context, id int64) (*WebhookEndpointResponse, error) {
+	out := &WebhookEndpointResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: fmt.Sprintf("/admin/webhooks/%d", id), auth: authAdmin}, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateWebhook replaces a webhook endpoint (PUT /admin/webhooks/{id}).
+func (c *Client) UpdateWebhook(ctx context.Context, id int64, req *WebhookEndpointRequest) (*WebhookEndpointResponse, error) {
+	out := &WebhookEndpointResponse{}
+	if err := c.do(ctx, call{method: http.MethodPut, path: fmt.Sprintf("/admin/webhooks/%d", id), body: req, auth: authAdmin}, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteWebhook deletes a webhook endpoint and its deliveries (DELETE /admin/webhooks/{id}).
+func (c *Client) DeleteWebhook(ctx context.Context, id int64) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: fmt.Sprintf("/admin/webhooks/%d", id), auth: authAdmin}, nil)
+}
+
+// DeliveryQuery selects webhook deliveries. Zero fields use the server defaults.
+type DeliveryQuery struct {
+	Status string // "pending", "delivered" 또는 "dead"
+	Limit  int
+}
+
+// ListWebhookDeliveries returns the deliveries of an endpoint, newest first
+// (GET /admin/webhooks/{id}/deliveries).
+func (c *Client) ListWebhookDeliveries(ctx context.Context, endpointID int64, q DeliveryQuery) ([]WebhookDeliveryResponse, error) {
+	query := url.Values{}
+	if q.Status != "" {
+		query.Set("status", q.Status)
+	}
+	if q.Limit > 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+	var out []WebhookDeliveryResponse
+	if err := c.do(ctx, call{method: http.MethodGet, path: fmt.Sprintf("/admin/webhooks/%d/deliveries", endpointID), query: query, auth: authAdmin}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReplayWebhookDelivery sends a delivery again (POST /admin/webhooks/deliveries/{id}/replay) and
+// returns the ID of the new delivery.
+func (c *Client) ReplayWebhookDelivery(ctx context.Context, deliveryID int64) (int64, error) {
+	var out struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.do(ctx, call{method: http.MethodPost, path: fmt.Sprintf("/admin/webhooks/deliveries/%d/replay", deliveryID), auth: authAdmin}, &out); err != nil {
+		return 0, err
+	}
+	return out.ID, nil
+}
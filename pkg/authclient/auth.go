@@ -0,0 +1,176 @@
+package authclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Register creates an account (POST /auth/register).
+func (c *Client) Register(ctx context.Context, req *RegisterRequest) (*RegisterResponse, error) {
+	out := &RegisterResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/auth/register", body: req}, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Login signs in with email and password (POST /auth/login) and keeps the tokens.
+func (c *Client) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
+	out := &LoginResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/auth/login", body: req}, out); err != nil {
+		return nil, err
+	}
+	c.SetTokens(Tokens{AccessToken: out.AccessToken, RefreshToken: out.RefreshToken})
+	return out, nil
+}
+
+// RequestMagicLink emails a sign-in link (POST /auth/magic-link).
+func (c *Client) RequestMagicLink(ctx context.Context, req *MagicLinkRequest) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/auth/magic-link", body: req}, nil)
+}
+
+// VerifyMagicLink signs in with the token of a magic link (POST /auth/magic-link/verify) and
+// keeps the tokens.
+func (c *Client) VerifyMagicLink(ctx context.Context, req *MagicLinkVerifyRequest) (*LoginResponse, error) {
+	out := &LoginResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/auth/magic-link/verify", body: req}, out); err != nil {
+		return nil, err
+	}
+	c.SetTokens(Tokens{AccessToken: out.AccessToken, RefreshToken: out.RefreshToken})
+	return out, nil
+}
+
+// RefreshToken exchanges a refresh token for new tokens (POST /auth/refresh-token) and keeps
+// them. Calls that need an access token do this by themselves.
+func (c *Client) RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+	out := &RefreshTokenResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/auth/refresh-token", body: req}, out); err != nil {
+		return nil, err
+	}
+	c.SetTokens(Tokens{AccessToken: out.AccessToken, RefreshToken: out.RefreshToken})
+	return out, nil
+}
+
+// SendFindEmailCode sends an SMS code for email recovery (POST /auth/phone/code).
+func (c *Client) SendFindEmailCode(ctx context.Context, req *PhoneCodeRequest) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/auth/phone/code", body: req}, nil)
+}
+
+// FindEmail returns the masked email of the account with the phone number (POST /auth/email/recover).
+func (c *Client) FindEmail(ctx context.Context, req *FindEmailRequest) (*FindEmailResponse, error) {
+	out := &FindEmailResponse{}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/auth/email/recover", body: req}, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ForgotPassword emails a password reset link (POST /auth/password/forgot).
+func (c *Client) ForgotPassword(ctx context.Context, req *ForgotPasswordRequest) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/auth/password/forgot", body: req}, nil)
+}
+
+// ResetPassword sets a new password with the token of a reset link (POST /auth/password/reset)
+// and returns the verified redirect URL of the link, if it had one.
+func (c *Client) ResetPassword(ctx context.Context, req *ResetPasswordRequest) (string, error) {
+	var out struct {
+		Redirect string `json:"redirect"`
+	}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/auth/password/reset", body: req}, &out); err != nil {
+		return "", err
+	}
+	return out.Redirect, nil
+}
+
+// Logout revokes the refresh token (POST /auth/logout) and forgets the tokens, also when the
+// request fails.
+func (c *Client) Logout(ctx context.Context) error {
+	tokens := c.Tokens()
+	if tokens.RefreshToken == "" {
+		return ErrNotSignedIn
+	}
+	err := c.do(ctx, call{method: http.MethodPost, path: "/auth/logout", body: &LogoutRequest{RefreshToken: tokens.RefreshToken}, auth: authUser}, nil)
+	c.SetTokens(Tokens{})
+	return err
+}
+
+// GetProfile returns the profile of the signed-in user (GET /users/me).
+func (c *Client) GetProfile(ctx context.Context) (*ProfileResponse, error) {
+	out := &ProfileResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/users/me", auth: authUser}, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateProfile changes the profile of the signed-in user (PUT /users/me).
+func (c *Client) UpdateProfile(ctx context.Context, req *UpdateProfileRequest) error {
+	return c.do(ctx, call{method: http.MethodPut, path: "/users/me", body: req, auth: authUser}, nil)
+}
+
+// SendPhoneCode sends an SMS code to verify a phone number (POST /users/me/phone/code).
+func (c *Client) SendPhoneCode(ctx context.Context, req *SendPhoneCodeRequest) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/users/me/phone/code", body: req, auth: authUser}, nil)
+}
+
+// VerifyPhone verifies the current phone number with the SMS code (POST /users/me/phone/verify).
+func (c *Client) VerifyPhone(ctx context.Context, req *VerifyPhoneRequest) error {
+	return c.do(ctx, call{method: http.MethodPost, path: "/users/me/phone/verify", body: req, auth: authUser}, nil)
+}
+
+// DeleteProfile deletes the account of the signed-in user (DELETE /users/me) and forgets the tokens.
+func (c *Client) DeleteProfile(ctx context.Context, req *DeleteProfileRequest) error {
+	if err := c.do(ctx, call{method: http.MethodDelete, path: "/users/me", body: req, auth: authUser}, nil); err != nil {
+		return err
+	}
+	c.SetTokens(Tokens{})
+	return nil
+}
+
+// ChangePassword changes the password of the signed-in user (PUT /users/me/password).
+func (c *Client) ChangePassword(ctx context.Context, req *ChangePasswordRequest) error {
+	return c.do(ctx, call{method: http.MethodPut, path: "/users/me/password", body: req, auth: authUser}, nil)
+}
+
+// GetNotificationPreferences returns which security emails the user receives (GET /users/me/notifications).
+func (c *Client) GetNotificationPreferences(ctx context.Context) (*NotificationPreferencesResponse, error) {
+	out := &NotificationPreferencesResponse{}
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/users/me/notifications", auth: authUser}, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateNotificationPreferences changes the notification preferences and returns all of them
+// (PUT /users/me/notifications).
+func (c *Client) UpdateNotificationPreferences(ctx context.Context, req *UpdateNotificationPreferencesRequest) (*NotificationPreferencesResponse, error) {
+	out := &NotificationPreferencesResponse{}
+	if err := c.do(ctx, call{method: http.MethodPut, path: "/users/me/notifications", body: req, auth: authUser}, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ActivityQuery pages through the activity of the signed-in user. Zero fields use the server defaults.
+type ActivityQuery struct {
+	Before int64 // 이 ID 이전 이벤트만
+	Limit  int
+}
+
+// ListActivity returns the security events of the signed-in user, newest first (GET /users/me/activity).
+func (c *Client) ListActivity(ctx context.Context, q ActivityQuery) ([]AuditEventResponse, error) {
+	query := url.Values{}
+	if q.Before > 0 {
+		query.Set("before", strconv.FormatInt(q.Before, 10))
+	}
+	if q.Limit > 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+	var out []AuditEventResponse
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/users/me/activity", query: query, auth: authUser}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,302 @@
+package authclient_test
+
+import (
+	"auth/internal/handler"
+	"auth/internal/middleware"
+	"auth/internal/repository"
+	"auth/internal/service"
+	"auth/internal/service/email"
+	"auth/internal/service/jwks"
+	"auth/pkg/authclient"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"zombiezen.com/go/sqlite"
+)
+
+type nopMailer struct{}
+
+func (nopMailer) Send(context.Context, *email.Message) error { return nil }
+
+// fiberTransport sends requests to a Fiber app without a listener.
+type fiberTransport struct {
+	app      *fiber.App
+	jwksHits atomic.Int32
+}
+
+func (t *fiberTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Path == "/.well-known/jwks.json" {
+		t.jwksHits.Add(1)
+	}
+	return t.app.Test(req, -1)
+}
+
+// newServer serves the auth and user routes of the service with ES256 access tokens.
+func newServer(t *testing.T) (*fiberTransport, *jwks.KeySet) {
+	conn, err := sqlite.OpenConn(":memory:", 0)
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	keys := jwks.NewKeySet(repository.NewSigningKeyRepositoryAuto("sqlite", nil, conn))
+	assert.Nil(t, keys.Ensure(context.Background()))
+	jwtService := service.NewJwtService("secret", service.WithSigningKeys(keys))
+	authService := service.NewAuthService(nil,
+		repository.NewUserRepositoryAuto("sqlite", nil, conn),
+		repository.NewProfileRepositoryAuto("sqlite", nil, conn),
+		jwtService,
+		email.NewEmailServiceWithMailer(nopMailer{}, mail.Address{Address: "noreply@example.com"}))
+	authHandler := handler.NewAuthHandler(authService)
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	api := app.Group("/api/v1")
+	api.Post("/auth/register", authHandler.Register)
+	api.Post("/auth/login", authHandler.Login)
+	api.Post("/auth/refresh-token", authHandler.RefreshToken)
+	api.Post("/auth/logout", middleware.JwtMiddleware(jwtService), authHandler.Logout)
+	users := api.Group("/users", middleware.JwtMiddleware(jwtService))
+	users.Get("/me", authHandler.GetProfile)
+	users.Put("/me", authHandler.UpdateProfile)
+	app.Get("/.well-known/jwks.json", handler.NewJWKSHandler(keys).JWKS)
+	return &fiberTransport{app: app}, keys
+}
+
+var registerRequest = &authclient.RegisterRequest{
+	Email:       "kim@example.com",
+	Password:    "Str0ng!Passw0rd#x",
+	Name:        "Kim",
+	BirthDate:   "1990-01-01",
+	GenderCode:  "M",
+	PhoneNumber: "010-1234-5678",
+}
+
+func signIn(t *testing.T, client *authclient.Client) *authclient.LoginResponse {
+	_, err := client.Register(context.Background(), registerRequest)
+	assert.Nil(t, err)
+	login, err := client.Login(context.Background(), &authclient.LoginRequest{Email: registerRequest.Email, Password: registerRequest.Password})
+	assert.Nil(t, err)
+	return login
+}
+
+func TestClient_가입로그인프로필(t *testing.T) {
+	transport, _ := newServer(t)
+	var saved []authclient.Tokens
+	client := authclient.New("http://auth.test", authclient.WithHTTPClient(&http.Client{Transport: transport}),
+		authclient.OnTokenRefresh(func(tokens authclient.Tokens) { saved = append(saved, tokens) }))
+	ctx := context.Background()
+
+	_, err := client.GetProfile(ctx)
+	assert.ErrorIs(t, err, authclient.ErrNotSignedIn)
+
+	login := signIn(t, client)
+	assert.Equal(t, login.AccessToken, client.Tokens().AccessToken)
+	assert.Len(t, saved, 1)
+
+	_, err = client.Register(ctx, registerRequest)
+	assert.True(t, authclient.IsCode(err, authclient.CodeConflict))
+	var apiErr *authclient.APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+	profile, err := client.GetProfile(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, registerRequest.Email, profile.Email)
+
+	err = client.UpdateProfile(ctx, &authclient.UpdateProfileRequest{Name: "Lee", BirthDate: "1990", GenderCode: "M", PhoneNumber: "010-1234-5678"})
+	assert.True(t, authclient.IsCode(err, authclient.CodeValidationError))
+
+	assert.Nil(t, client.Logout(ctx))
+	assert.Equal(t, authclient.Tokens{}, client.Tokens())
+	_, err = client.GetProfile(ctx)
+	assert.ErrorIs(t, err, authclient.ErrNotSignedIn)
+}
+
+func TestClient_토큰자동재발급(t *testing.T) {
+	transport, keys := newServer(t)
+	client := authclient.New("http://auth.test", authclient.WithHTTPClient(&http.Client{Transport: transport}))
+	ctx := context.Background()
+	login := signIn(t, client)
+
+	// 곧 만료되는 access token 은 요청 전에 재발급한다
+	expiring := signed(t, keys, login.UserID, time.Now().Add(10*time.Second))
+	client.SetTokens(authclient.Tokens{AccessToken: expiring, RefreshToken: login.RefreshToken})
+	_, err := client.GetProfile(ctx)
+	assert.Nil(t, err)
+	assert.NotEqual(t, expiring, client.Tokens().AccessToken)
+
+	// 서버가 거부한 access token 은 재발급 후 한 번 다시 보낸다
+	client.SetTokens(authclient.Tokens{AccessToken: "garbage", RefreshToken: client.Tokens().RefreshToken})
+	_, err = client.GetProfile(ctx)
+	assert.Nil(t, err)
+
+	// 리프레시 토큰까지 거부되면 로그아웃 상태가 된다
+	client.SetTokens(authclient.Tokens{AccessToken: "garbage", RefreshToken: "garbage"})
+	_, err = client.GetProfile(ctx)
+	assert.True(t, authclient.IsCode(err, authclient.CodeUnauthorized))
+	assert.Equal(t, authclient.Tokens{}, client.Tokens())
+}
+
+// signed returns an access token of the user signed with the service's current key.
+func signed(t *testing.T, keys *jwks.KeySet, userID int64, expiresAt time.Time) string {
+	key := keys.SigningKey()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Subject:   fmt.Sprint(userID),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	})
+	token.Header["kid"] = key.ID
+	s, err := token.SignedString(key.Private)
+	assert.Nil(t, err)
+	return s
+}
+
+func TestVerifier(t *testing.T) {
+	transport, keys := newServer(t)
+	client := authclient.New("http://auth.test", authclient.WithHTTPClient(&http.Client{Transport: transport}))
+	ctx := context.Background()
+	login := signIn(t, client)
+	verifier := client.Verifier(authclient.WithRefetchInterval(0))
+
+	claims, err := verifier.Verify(ctx, login.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, login.UserID, claims.UserID)
+	// 키 집합은 캐시된다
+	_, err = verifier.Verify(ctx, login.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), transport.jwksHits.Load())
+
+	_, err = verifier.Verify(ctx, signed(t, keys, login.UserID, time.Now().Add(-time.Minute)))
+	assert.ErrorIs(t, err, authclient.ErrTokenExpired)
+	claims, err = client.Verifier(authclient.WithLeeway(2*time.Minute)).Verify(ctx, signed(t, keys, login.UserID, time.Now().Add(-time.Minute)))
+	assert.Nil(t, err)
+	assert.Equal(t, login.UserID, claims.UserID)
+
+	// HS256 토큰은 공유 비밀 없이 검증할 수 없으므로 거부한다
+	legacy, err := service.NewJwtService("secret").GenerateToken(login.UserID)
+	assert.Nil(t, err)
+	_, err = verifier.Verify(ctx, legacy)
+	assert.ErrorIs(t, err, authclient.ErrInvalidToken)
+
+	// 키를 교체하면 모르는 kid 를 보고 다시 가져온다
+	_, err = keys.Rotate(ctx)
+	assert.Nil(t, err)
+	_, err = verifier.Verify(ctx, signed(t, keys, login.UserID, time.Now().Add(time.Minute)))
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), transport.jwksHits.Load()) // WithLeeway 검증기가 한 번 가져갔다
+}
+
+// flakyTransport fails every request while down and drops Cache-Control, so the verifier's
+// cache TTL applies.
+type flakyTransport struct {
+	next http.RoundTripper
+	down atomic.Bool
+	hits atomic.Int32
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.hits.Add(1)
+	if t.down.Load() {
+		return nil, errors.New("connection refused")
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err == nil {
+		resp.Header.Del("Cache-Control")
+	}
+	return resp, err
+}
+
+func TestVerifier_서버장애시만료된키사용(t *testing.T) {
+	transport, keys := newServer(t)
+	client := authclient.New("http://auth.test", authclient.WithHTTPClient(&http.Client{Transport: transport}))
+	ctx := context.Background()
+	login := signIn(t, client)
+	flaky := &flakyTransport{next: transport}
+	verifier := authclient.NewVerifier("http://auth.test/.well-known/jwks.json",
+		authclient.WithVerifierHTTPClient(&http.Client{Transport: flaky}),
+		authclient.WithCacheTTL(time.Millisecond), authclient.WithRefetchInterval(time.Hour))
+
+	_, err := verifier.Verify(ctx, login.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), flaky.hits.Load())
+
+	// 캐시가 만료되어도 refetch 간격 안에서는 다시 가져오지 않고 알던 키로 검증한다
+	time.Sleep(5 * time.Millisecond)
+	flaky.down.Store(true)
+	_, err = verifier.Verify(ctx, login.AccessToken)
+	assert.Nil(t, err)
+	_, err = keys.Rotate(ctx)
+	assert.Nil(t, err)
+	_, err = verifier.Verify(ctx, signed(t, keys, login.UserID, time.Now().Add(time.Minute)))
+	assert.ErrorIs(t, err, authclient.ErrInvalidToken)
+	assert.Equal(t, int32(1), flaky.hits.Load())
+
+	// 가져오기가 실패해도 알던 키로 검증한다
+	eager := authclient.NewVerifier("http://auth.test/.well-known/jwks.json",
+		authclient.WithVerifierHTTPClient(&http.Client{Transport: flaky}),
+		authclient.WithCacheTTL(time.Millisecond), authclient.WithRefetchInterval(0))
+	flaky.down.Store(false)
+	_, err = eager.Verify(ctx, login.AccessToken)
+	assert.Nil(t, err)
+	time.Sleep(5 * time.Millisecond)
+	flaky.down.Store(true)
+	_, err = eager.Verify(ctx, login.AccessToken)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), flaky.hits.Load())
+}
+
+func TestMiddleware(t *testing.T) {
+	transport, _ := newServer(t)
+	client := authclient.New("http://auth.test", authclient.WithHTTPClient(&http.Client{Transport: transport}))
+	login := signIn(t, client)
+	verifier := client.Verifier()
+
+	h := authclient.Middleware(verifier)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := authclient.UserIDFrom(r.Context())
+		_, _ = fmt.Fprint(w, userID)
+	}))
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+login.AccessToken)
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, fmt.Sprint(login.UserID), rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.JSONEq(t, `{"success":false,"code":401,"message":"unauthorized","data":"missing token"}`, rec.Body.String())
+
+	app := fiber.New()
+	app.Get("/", authclient.FiberMiddleware(verifier), func(c *fiber.Ctx) error {
+		return c.SendString(fmt.Sprint(c.Locals("userID")))
+	})
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+login.AccessToken)
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer garbage")
+	resp, err = app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestErrorCodes_서버와일치(t *testing.T) {
+	assert.Equal(t, handler.Unauthorized, authclient.CodeUnauthorized)
+	assert.Equal(t, handler.ValidationError, authclient.CodeValidationError)
+	assert.Equal(t, handler.Conflict, authclient.CodeConflict)
+	assert.Equal(t, handler.PasswordPolicy, authclient.CodePasswordPolicy)
+	assert.Equal(t, handler.TooManyRequests, authclient.CodeTooManyRequests)
+	assert.Equal(t, handler.InvalidRedirect, authclient.CodeInvalidRedirect)
+}
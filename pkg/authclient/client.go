@@ -0,0 +1,287 @@
+// Package authclient is the Go SDK of the authentication service for consuming services: a
+// typed client for the /api/v1 REST endpoints that refreshes access tokens by itself, and
+// net/http and Fiber middleware that verify access tokens offline with the published JWKS.
+package authclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// apiPath is the path of the REST API under the base URL.
+const apiPath = "/api/v1"
+
+// refreshLeeway is how long before its expiry an access token is refreshed, so it does not
+// expire in flight.
+const refreshLeeway = 30 * time.Second
+
+// Tokens are the tokens of a signed-in user.
+type Tokens struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+// Client calls the REST API. Login and VerifyMagicLink keep the issued tokens in the client,
+// and calls that need them refresh the access token shortly before it expires or when the
+// server rejects it. A Client is safe for concurrent use, but holds the tokens of one user.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	language   string
+	adminKey   string
+	onRefresh  func(Tokens)
+
+	mu        sync.Mutex
+	tokens    Tokens
+	refreshMu sync.Mutex
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client, http.DefaultClient by default.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithUserAgent sets the User-Agent header. The server binds refresh tokens to it at login.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithLanguage sets the Accept-Language header, the language of emails and validation messages.
+func WithLanguage(language string) Option {
+	return func(c *Client) {
+		c.language = language
+	}
+}
+
+// WithAdminKey sets the X-Admin-Key header of the admin endpoints.
+func WithAdminKey(key string) Option {
+	return func(c *Client) {
+		c.adminKey = key
+	}
+}
+
+// WithTokens starts the client signed in, e.g. with tokens saved by a previous run.
+func WithTokens(tokens Tokens) Option {
+	return func(c *Client) {
+		c.tokens = tokens
+	}
+}
+
+// OnTokenRefresh registers fn to be called with the new tokens whenever they change, so they
+// can be saved. Refresh tokens are single use, so a saved old one no longer works.
+func OnTokenRefresh(fn func(Tokens)) Option {
+	return func(c *Client) {
+		c.onRefresh = fn
+	}
+}
+
+// New creates a Client for the service at baseURL, e.g. "https://auth.example.com".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Tokens returns the current tokens, zero if not signed in.
+func (c *Client) Tokens() Tokens {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tokens
+}
+
+// SetTokens replaces the tokens; zero Tokens signs the client out locally.
+func (c *Client) SetTokens(tokens Tokens) {
+	c.mu.Lock()
+	c.tokens = tokens
+	c.mu.Unlock()
+	if c.onRefresh != nil {
+		c.onRefresh(tokens)
+	}
+}
+
+// JWKSURL returns the address of the service's JSON Web Key Set.
+func (c *Client) JWKSURL() string {
+	return c.baseURL + "/.well-known/jwks.json"
+}
+
+// Verifier returns a Verifier for the service's access tokens using the client's HTTP client.
+func (c *Client) Verifier(opts ...VerifierOption) *Verifier {
+	return NewVerifier(c.JWKSURL(), append([]VerifierOption{WithVerifierHTTPClient(c.httpClient)}, opts...)...)
+}
+
+// authMode is the credential a call sends.
+type authMode int
+
+const (
+	authNone  authMode = iota
+	authUser           // access token, 만료 전후 자동 재발급
+	authAdmin          // X-Admin-Key
+)
+
+// call is one API request.
+type call struct {
+	method string
+	path   string // apiPath 아래 경로, 예: "/auth/login"
+	query  url.Values
+	body   any
+	auth   authMode
+}
+
+// do sends the call and decodes the data of a successful response into out, if not nil.
+// Calls with authUser are retried once with a refreshed access token when rejected with 401.
+func (c *Client) do(ctx context.Context, cl call, out any) error {
+	var body []byte
+	if cl.body != nil {
+		var err error
+		if body, err = json.Marshal(cl.body); err != nil {
+			return err
+		}
+	}
+	var accessToken string
+	if cl.auth == authUser {
+		var err error
+		if accessToken, err = c.accessToken(ctx); err != nil {
+			return err
+		}
+	}
+	err := c.send(ctx, cl, body, accessToken, out)
+	var apiErr *APIError
+	if cl.auth == authUser && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		// 서버에서 폐기되었거나 시계가 어긋난 토큰, 한 번만 재발급 후 다시 시도한다
+		if refreshErr := c.refresh(ctx, accessToken); refreshErr != nil {
+			return err
+		}
+		return c.send(ctx, cl, body, c.Tokens().AccessToken, out)
+	}
+	return err
+}
+
+func (c *Client) send(ctx context.Context, cl call, body []byte, accessToken string, out any) error {
+	u := c.baseURL + apiPath + cl.path
+	if len(cl.query) > 0 {
+		u += "?" + cl.query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, cl.method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.language != "" {
+		req.Header.Set("Accept-Language", c.language)
+	}
+	switch cl.auth {
+	case authUser:
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	case authAdmin:
+		req.Header.Set("X-Admin-Key", c.adminKey)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var envelope rawResponse
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		// 프록시 오류 페이지 등 봉투가 아닌 응답
+		if resp.StatusCode >= 300 {
+			details, _ := json.Marshal(strings.TrimSpace(string(data)))
+			return &APIError{StatusCode: resp.StatusCode, Details: details}
+		}
+		return fmt.Errorf("authclient: decode response: %w", err)
+	}
+	if resp.StatusCode >= 300 || !envelope.Success {
+		return &APIError{StatusCode: resp.StatusCode, Code: envelope.Message, Details: envelope.Data, TraceID: envelope.TraceID}
+	}
+	if out == nil || len(envelope.Data) == 0 || string(envelope.Data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("authclient: decode response data: %w", err)
+	}
+	return nil
+}
+
+// accessToken returns the access token, refreshed first if it is about to expire.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	tokens := c.Tokens()
+	if tokens.AccessToken == "" && tokens.RefreshToken == "" {
+		return "", ErrNotSignedIn
+	}
+	if tokens.AccessToken != "" && !expiresWithin(tokens.AccessToken, refreshLeeway) {
+		return tokens.AccessToken, nil
+	}
+	if err := c.refresh(ctx, tokens.AccessToken); err != nil {
+		return "", err
+	}
+	return c.Tokens().AccessToken, nil
+}
+
+// refresh exchanges the refresh token for new tokens, unless another goroutine already
+// replaced the stale access token. A rejected refresh token signs the client out.
+func (c *Client) refresh(ctx context.Context, stale string) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	tokens := c.Tokens()
+	if tokens.AccessToken != stale {
+		return nil
+	}
+	if tokens.RefreshToken == "" {
+		return ErrNotSignedIn
+	}
+	if _, err := c.RefreshToken(ctx, &RefreshTokenRequest{RefreshToken: tokens.RefreshToken}); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+			c.SetTokens(Tokens{})
+		}
+		return err
+	}
+	return nil
+}
+
+// expiresWithin reports whether the token expires within d. The signature is not checked; the
+// server does that, this only avoids sending a token that is about to expire.
+func expiresWithin(token string, d time.Duration) bool {
+	claims := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil || claims.ExpiresAt == nil {
+		return false
+	}
+	return time.Until(claims.ExpiresAt.Time) < d
+}
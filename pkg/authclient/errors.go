@@ -0,0 +1,59 @@
+package authclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Error codes of failed requests, the values of the server's handler error codes.
+const (
+	CodeBadRequest         = "badRequest"
+	CodeValidationError    = "validationError"
+	CodeConflict           = "conflict"
+	CodeInternalError      = "internalError"
+	CodeServiceUnavailable = "serviceUnavailable"
+	CodeUnauthorized       = "unauthorized"
+	CodeAccountDisabled    = "accountDisabled"
+	CodeNotFound           = "notFound"
+	CodePasswordPolicy     = "passwordPolicy"
+	CodeIncorrectPassword  = "incorrectPassword"
+	CodeInvalidToken       = "invalidToken"
+	CodeVerificationFailed = "verificationFailed"
+	CodeTooManyRequests    = "tooManyRequests"
+	CodeInvalidRedirect    = "invalidRedirect"
+)
+
+var (
+	// ErrNotSignedIn is returned by calls that need an access token when the client has none.
+	ErrNotSignedIn = errors.New("authclient: not signed in")
+	// ErrInvalidToken is returned by Verifier for malformed tokens, bad signatures and unknown keys.
+	ErrInvalidToken = errors.New("authclient: invalid token")
+	// ErrTokenExpired is returned by Verifier for expired tokens.
+	ErrTokenExpired = errors.New("authclient: token expired")
+)
+
+// APIError is a failed request, decoded from the error envelope.
+type APIError struct {
+	StatusCode int
+	Code       string // 예: CodeValidationError, 응답이 봉투 형식이 아니면 비어 있음
+	// Details is the data of the envelope: a message, validation errors or password policy
+	// violations depending on Code.
+	Details json.RawMessage
+	TraceID string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("authclient: %d %s", e.StatusCode, e.Code)
+	var s string
+	if json.Unmarshal(e.Details, &s) == nil && s != "" {
+		msg += ": " + s
+	}
+	return msg
+}
+
+// IsCode reports whether err is an APIError with the error code.
+func IsCode(err error, code string) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
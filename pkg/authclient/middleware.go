@@ -0,0 +1,91 @@
+package authclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type claimsKey struct{}
+
+// WithClaims returns a copy of ctx carrying the claims, as the middleware does.
+func WithClaims(ctx context.Context, c *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, c)
+}
+
+// ClaimsFrom returns the claims stored by the middleware.
+func ClaimsFrom(ctx context.Context) (*Claims, bool) {
+	c, ok := ctx.Value(claimsKey{}).(*Claims)
+	return c, ok
+}
+
+// UserIDFrom returns the ID of the user authenticated by the middleware.
+func UserIDFrom(ctx context.Context) (int64, bool) {
+	c, ok := ClaimsFrom(ctx)
+	if !ok {
+		return 0, false
+	}
+	return c.UserID, true
+}
+
+// authenticate verifies the "Bearer <token>" Authorization header and returns the status and
+// error code of the response when it fails.
+func authenticate(ctx context.Context, v *Verifier, authorization string) (*Claims, int, string, string) {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if authorization == "" {
+		return nil, http.StatusUnauthorized, CodeUnauthorized, "missing token"
+	}
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, http.StatusUnauthorized, CodeUnauthorized, "invalid token format"
+	}
+	claims, err := v.Verify(ctx, token)
+	switch {
+	case err == nil:
+		return claims, 0, "", ""
+	case errors.Is(err, ErrTokenExpired):
+		return nil, http.StatusUnauthorized, CodeUnauthorized, "token expired"
+	case errors.Is(err, ErrInvalidToken):
+		return nil, http.StatusUnauthorized, CodeUnauthorized, "invalid token"
+	default:
+		// 키 집합을 가져오지 못함, 토큰 문제가 아니므로 클라이언트가 다시 시도할 수 있게 한다
+		slog.ErrorContext(ctx, "authclient: token verification failed", "error", err)
+		return nil, http.StatusServiceUnavailable, CodeServiceUnavailable, "token verification unavailable"
+	}
+}
+
+// Middleware returns net/http middleware that requires a valid access token and stores its
+// claims in the request context for ClaimsFrom and UserIDFrom. Rejected requests get the
+// service's error envelope, e.g. 401 with code "unauthorized".
+func Middleware(v *Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, status, code, details := authenticate(r.Context(), v, r.Header.Get("Authorization"))
+			if claims == nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				_ = json.NewEncoder(w).Encode(Response[string]{Code: status, Message: code, Data: details})
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// FiberMiddleware is Middleware for Fiber. Like the service's own JWT middleware, it also
+// stores the user ID in c.Locals("userID") as an int64.
+func FiberMiddleware(v *Verifier) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, status, code, details := authenticate(c.UserContext(), v, c.Get(fiber.HeaderAuthorization))
+		if claims == nil {
+			return c.Status(status).JSON(Response[string]{Code: status, Message: code, Data: details})
+		}
+		c.Locals("userID", claims.UserID)
+		c.SetUserContext(WithClaims(c.UserContext(), claims))
+		return c.Next()
+	}
+}
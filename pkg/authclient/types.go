@@ -0,0 +1,60 @@
+package authclient
+
+import (
+	"auth/internal/dto"
+	"encoding/json"
+)
+
+// Request and response types of the REST API. They are aliases of the server's dto types, so
+// the JSON encoding is the same by construction.
+type (
+	RegisterRequest                      = dto.RegisterRequest
+	RegisterResponse                     = dto.RegisterResponse
+	LoginRequest                         = dto.LoginRequest
+	LoginResponse                        = dto.LoginResponse
+	RefreshTokenRequest                  = dto.RefreshTokenRequest
+	RefreshTokenResponse                 = dto.RefreshTokenResponse
+	MagicLinkRequest                     = dto.MagicLinkRequest
+	MagicLinkVerifyRequest               = dto.MagicLinkVerifyRequest
+	PhoneCodeRequest                     = dto.PhoneCodeRequest
+	FindEmailRequest                     = dto.FindEmailRequest
+	FindEmailResponse                    = dto.FindEmailResponse
+	ForgotPasswordRequest                = dto.ForgotPasswordRequest
+	ResetPasswordRequest                 = dto.ResetPasswordRequest
+	LogoutRequest                        = dto.LogoutRequest
+	ProfileResponse                      = dto.ProfileResponse
+	UpdateProfileRequest                 = dto.UpdateProfileRequest
+	SendPhoneCodeRequest                 = dto.SendPhoneCodeRequest
+	VerifyPhoneRequest                   = dto.VerifyPhoneRequest
+	ChangePasswordRequest                = dto.ChangePasswordRequest
+	DeleteProfileRequest                 = dto.DeleteProfileRequest
+	NotificationPreferencesResponse      = dto.NotificationPreferencesResponse
+	UpdateNotificationPreferencesRequest = dto.UpdateNotificationPreferencesRequest
+	AuditEventResponse                   = dto.AuditEventResponse
+	OutboxMessageResponse                = dto.OutboxMessageResponse
+	WebhookEndpointRequest               = dto.WebhookEndpointRequest
+	WebhookEndpointResponse              = dto.WebhookEndpointResponse
+	WebhookDeliveryResponse              = dto.WebhookDeliveryResponse
+)
+
+// Response is the envelope of every REST response, the same shape as the server's
+// handler.APIResponse. On failure Message is the error code and Data the details.
+type Response[T any] struct {
+	Success bool   `json:"success"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    T      `json:"data,omitempty"`
+	TraceID string `json:"traceId,omitempty"`
+}
+
+// AuditVerifyResult is the result of an audit chain verification.
+type AuditVerifyResult struct {
+	Valid    bool  `json:"valid"`
+	Checked  int   `json:"checked"`
+	FirstID  int64 `json:"firstId,omitempty"`
+	LastID   int64 `json:"lastId,omitempty"`
+	BrokenID int64 `json:"brokenId,omitempty"`
+}
+
+// rawResponse is a Response whose data is decoded later, once the status is known.
+type rawResponse = Response[json.RawMessage]
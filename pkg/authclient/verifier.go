@@ -0,0 +1,270 @@
+package authclient
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Claims are the verified claims of an access token.
+type Claims struct {
+	UserID    int64
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Verifier verifies access tokens offline with the service's JSON Web Key Set. The key set is
+// cached for the max-age of its response, or the WithCacheTTL duration without one, and fetched again early when
+// a token names a key that is not cached, e.g. after the service rotated its key.
+//
+// Only ES256 tokens are accepted; the service must run with JWT_SIGNING_ALGORITHM=ES256.
+type Verifier struct {
+	jwksURL    string
+	httpClient *http.Client
+	cacheTTL   time.Duration
+	refetch    time.Duration
+	leeway     time.Duration
+
+	mu        sync.RWMutex
+	keys      map[string]*ecdsa.PublicKey
+	expires   time.Time
+	fetchMu   sync.Mutex
+	lastFetch time.Time
+}
+
+// VerifierOption configures a Verifier.
+type VerifierOption func(*Verifier)
+
+// WithVerifierHTTPClient sets the HTTP client that fetches the key set, a client with a
+// ten-second timeout by default.
+func WithVerifierHTTPClient(hc *http.Client) VerifierOption {
+	return func(v *Verifier) {
+		v.httpClient = hc
+	}
+}
+
+// WithCacheTTL sets how long the key set is cached when the response has no max-age, five
+// minutes by default.
+func WithCacheTTL(d time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.cacheTTL = d
+	}
+}
+
+// WithRefetchInterval sets the minimum time between fetches, ten seconds by default, so forged
+// tokens with unknown key IDs or an unavailable service cannot make every request call it.
+func WithRefetchInterval(d time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.refetch = d
+	}
+}
+
+// WithLeeway accepts tokens that expired up to d ago, for clock skew between the services.
+func WithLeeway(d time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.leeway = d
+	}
+}
+
+// NewVerifier creates a Verifier for the key set at jwksURL, e.g.
+// "https://auth.example.com/.well-known/jwks.json". Keys are fetched on first use.
+func NewVerifier(jwksURL string, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		jwksURL:    jwksURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cacheTTL:   5 * time.Minute,
+		refetch:    10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Verify checks the signature and expiry of an access token and returns its claims. Invalid
+// tokens fail with ErrInvalidToken or ErrTokenExpired; failing to fetch the key set is
+// returned as it is.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	var fetchErr error
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()})).
+		ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+			kid, _ := t.Header["kid"].(string)
+			key, err := v.key(ctx, kid)
+			if err != nil {
+				fetchErr = err
+				return nil, err
+			}
+			if key == nil {
+				return nil, fmt.Errorf("unknown signing key %q", kid)
+			}
+			return key, nil
+		})
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+	if err != nil {
+		var validation *jwt.ValidationError
+		if errors.As(err, &validation) && validation.Errors == jwt.ValidationErrorExpired {
+			// 서명은 유효하고 만료만 된 경우 leeway 를 적용한다
+			if claims.ExpiresAt != nil && time.Since(claims.ExpiresAt.Time) <= v.leeway {
+				return v.claims(claims)
+			}
+			return nil, ErrTokenExpired
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: missing exp", ErrInvalidToken)
+	}
+	return v.claims(claims)
+}
+
+func (v *Verifier) claims(rc *jwt.RegisteredClaims) (*Claims, error) {
+	userID, err := strconv.ParseInt(rc.Subject, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid subject", ErrInvalidToken)
+	}
+	c := &Claims{UserID: userID, ExpiresAt: rc.ExpiresAt.Time}
+	if rc.IssuedAt != nil {
+		c.IssuedAt = rc.IssuedAt.Time
+	}
+	return c, nil
+}
+
+// key returns the cached key with the ID, fetching the key set at most every refetch interval
+// if the cache expired or the ID is unknown. Expired keys are still used while fetches are
+// throttled or fail. A nil key without error means unknown.
+func (v *Verifier) key(ctx context.Context, kid string) (*ecdsa.PublicKey, error) {
+	v.mu.RLock()
+	key, fresh := v.keys[kid], time.Now().Before(v.expires)
+	v.mu.RUnlock()
+	if key != nil && fresh {
+		return key, nil
+	}
+	v.fetchMu.Lock()
+	defer v.fetchMu.Unlock()
+	// 기다리는 동안 다른 요청이 가져왔을 수 있다
+	v.mu.RLock()
+	key, fresh = v.keys[kid], time.Now().Before(v.expires)
+	v.mu.RUnlock()
+	if key != nil && fresh {
+		return key, nil
+	}
+	// 캐시가 만료되었어도 마지막으로 가져온 뒤 refetch 가 지나지 않았으면 다시 가져오지 않는다
+	if !v.lastFetch.IsZero() && time.Since(v.lastFetch) < v.refetch {
+		return key, nil
+	}
+	v.lastFetch = time.Now()
+	if err := v.fetch(ctx); err != nil {
+		if key != nil {
+			// 인증 서비스가 잠시 응답하지 않아도 알던 키로 계속 검증한다
+			return key, nil
+		}
+		return nil, err
+	}
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.keys[kid], nil
+}
+
+// jwk is a key of the key set. Keys other than P-256 EC keys are ignored.
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	Kid string `json:"kid"`
+}
+
+func (v *Verifier) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.jwksURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("authclient: fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("authclient: fetch JWKS: status %d", resp.StatusCode)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("authclient: decode JWKS: %w", err)
+	}
+	keys := make(map[string]*ecdsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "EC" || k.Crv != "P-256" {
+			continue
+		}
+		key, err := parseECKey(k.X, k.Y)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	ttl := v.cacheTTL
+	if maxAge, ok := cacheMaxAge(resp.Header.Get("Cache-Control")); ok {
+		ttl = maxAge
+	}
+	v.mu.Lock()
+	v.keys = keys
+	v.expires = time.Now().Add(ttl)
+	v.mu.Unlock()
+	return nil
+}
+
+// parseECKey decodes the base64url coordinates of a P-256 public key and checks that the point
+// is on the curve.
+func parseECKey(x, y string) (*ecdsa.PublicKey, error) {
+	xb, err := base64.RawURLEncoding.DecodeString(x)
+	if err != nil {
+		return nil, err
+	}
+	yb, err := base64.RawURLEncoding.DecodeString(y)
+	if err != nil {
+		return nil, err
+	}
+	if len(xb) != 32 || len(yb) != 32 {
+		return nil, errors.New("invalid coordinate length")
+	}
+	point := append(append([]byte{4}, xb...), yb...)
+	if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+		return nil, err
+	}
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(xb), Y: new(big.Int).SetBytes(yb)}, nil
+}
+
+// cacheMaxAge returns the max-age directive of a Cache-Control header.
+func cacheMaxAge(header string) (time.Duration, bool) {
+	for _, directive := range strings.Split(header, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(directive), "=")
+		if !ok || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	return 0, false
+}
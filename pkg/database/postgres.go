@@ -3,6 +3,7 @@ package database
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"time"
 
@@ -37,3 +38,11 @@ func Connect(dataSourceName string) error {
 func GetPool() *pgxpool.Pool {
 	return pool
 }
+
+// Ping checks that the pool opened by Connect can reach the database.
+func Ping(ctx context.Context) error {
+	if pool == nil {
+		return errors.New("database not connected")
+	}
+	return pool.Ping(ctx)
+}
@@ -1,7 +1,9 @@
 package database
 
 import (
-	"log"
+	"errors"
+	"log/slog"
+
 	"zombiezen.com/go/sqlite"
 )
 
@@ -13,34 +15,21 @@ func ConnectSqlite(sqlitePath string) error {
 	if err != nil {
 		if conn != nil {
 			if cerr := conn.Close(); cerr != nil {
-				log.Printf("sqlite close error: %v", cerr)
+				slog.Error("sqlite close error", "error", cerr)
 			}
 		}
-		log.Printf("sqlite open error: %v", err)
+		slog.Error("sqlite open error", "error", err)
 		return err
 	}
-	// ping test
-	stmt, err := conn.Prepare("SELECT 1;")
-	if err != nil {
+	if err := pingSqlite(conn); err != nil {
 		if cerr := conn.Close(); cerr != nil {
-			log.Printf("sqlite close error: %v", cerr)
+			slog.Error("sqlite close error", "error", cerr)
 		}
-		log.Printf("sqlite ping error: %v", err)
-		return err
-	}
-	_, err = stmt.Step()
-	if ferr := stmt.Finalize(); ferr != nil {
-		log.Printf("sqlite finalize error: %v", ferr)
-	}
-	if err != nil {
-		if cerr := conn.Close(); cerr != nil {
-			log.Printf("sqlite close error: %v", cerr)
-		}
-		log.Printf("sqlite ping error: %v", err)
+		slog.Error("sqlite ping error", "error", err)
 		return err
 	}
 	sqliteConn = conn
-	log.Printf("sqlite connection established (zombiezen)")
+	slog.Info("sqlite connection established")
 	return nil
 }
 
@@ -48,3 +37,33 @@ func ConnectSqlite(sqlitePath string) error {
 func GetSqliteConn() *sqlite.Conn {
 	return sqliteConn
 }
+
+// PingSqlite checks that the connection opened by ConnectSqlite can run a query.
+func PingSqlite() error {
+	if sqliteConn == nil {
+		return errors.New("sqlite not connected")
+	}
+	return pingSqlite(sqliteConn)
+}
+
+// CloseSqlite closes the connection opened by ConnectSqlite. It does nothing if there is none.
+func CloseSqlite() error {
+	if sqliteConn == nil {
+		return nil
+	}
+	err := sqliteConn.Close()
+	sqliteConn = nil
+	return err
+}
+
+func pingSqlite(conn *sqlite.Conn) error {
+	stmt, err := conn.Prepare("SELECT 1;")
+	if err != nil {
+		return err
+	}
+	_, err = stmt.Step()
+	if ferr := stmt.Finalize(); ferr != nil {
+		slog.Error("sqlite finalize error", "error", ferr)
+	}
+	return err
+}
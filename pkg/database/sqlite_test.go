@@ -39,3 +39,15 @@ func Test_Sqlite연결후반환(t *testing.T) {
 	conn := database.GetSqliteConn()
 	assert.NotNil(t, conn, "Expected a non-nil sqlite connection")
 }
+
+func Test_Sqlite핑과종료(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test_sqlite3.db")
+	assert.Nil(t, database.ConnectSqlite(tmpFile))
+	assert.Nil(t, database.PingSqlite())
+	assert.Nil(t, database.PingSqlite(), "ping twice")
+
+	assert.Nil(t, database.CloseSqlite())
+	assert.Nil(t, database.GetSqliteConn())
+	assert.NotNil(t, database.PingSqlite())
+	assert.Nil(t, database.CloseSqlite(), "close twice")
+}
@@ -3,11 +3,23 @@ package utils
 
 import "strings"
 
-// MaskEmail masks the given email address for privacy.
+// MaskEmail masks the given email address for privacy. Values that are not email addresses
+// are masked entirely.
 func MaskEmail(email string) string {
 	parts := strings.Split(email, "@")
+	if len(parts) != 2 {
+		return strings.Repeat("*", len(email))
+	}
 	if len(parts[0]) <= 2 {
 		return "***@" + parts[1]
 	}
 	return parts[0][:2] + strings.Repeat("*", len(parts[0])-2) + "@" + parts[1]
 }
+
+// MaskPhone masks all but the last four digits of the given phone number.
+func MaskPhone(phone string) string {
+	if len(phone) <= 4 {
+		return strings.Repeat("*", len(phone))
+	}
+	return strings.Repeat("*", len(phone)-4) + phone[len(phone)-4:]
+}
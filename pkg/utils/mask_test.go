@@ -13,6 +13,8 @@ func TestMaskEmail(t *testing.T) {
 		{"ab@naver.com", "***@naver.com"},
 		{"testuser@gmail.com", "te******@gmail.com"},
 		{"a@domain.com", "***@domain.com"},
+		{"not-an-email", "************"},
+		{"", ""},
 	}
 	for _, tt := range tests {
 		got := utils.MaskEmail(tt.input)
@@ -21,3 +23,20 @@ func TestMaskEmail(t *testing.T) {
 		}
 	}
 }
+
+func TestMaskPhone(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"01012345678", "*******5678"},
+		{"1234", "****"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got := utils.MaskPhone(tt.input)
+		if got != tt.want {
+			t.Errorf("MaskPhone(%q) = %q; want %q", tt.input, got, tt.want)
+		}
+	}
+}
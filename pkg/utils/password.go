@@ -1,16 +1,20 @@
 // Package utils provides utility functions for password hashing and verification.
 package utils
 
-import "golang.org/x/crypto/bcrypt"
+// DefaultPasswordHasher is used by HashPassword and CheckPasswordHash.
+// It hashes with Argon2id and still accepts legacy bcrypt hashes.
+var DefaultPasswordHasher PasswordHasher = NewMigratingHasher(
+	NewArgon2idHasher(DefaultArgon2Params),
+	NewBcryptHasher(12),
+)
 
-// HashPassword hashes the given password using bcrypt.
+// HashPassword hashes the given password using DefaultPasswordHasher.
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
-	return string(bytes), err
+	return DefaultPasswordHasher.Hash(password)
 }
 
 // CheckPasswordHash checks if the password matches the given hash.
 func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+	ok, err := DefaultPasswordHasher.Verify(password, hash)
+	return err == nil && ok
 }
@@ -0,0 +1,325 @@
+// Package utils provides utility functions for password hashing and verification.
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Supported password hashing algorithm identifiers.
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+var (
+	// ErrUnsupportedHash is returned when an encoded hash has an unknown format.
+	ErrUnsupportedHash = errors.New("unsupported password hash format")
+	// ErrInvalidHash is returned when an encoded hash cannot be parsed.
+	ErrInvalidHash = errors.New("invalid password hash")
+)
+
+// PasswordHasher hashes and verifies passwords.
+type PasswordHasher interface {
+	// Algorithm returns the identifier of the hashing algorithm.
+	Algorithm() string
+	// Hash returns the encoded hash of the password, including its parameters.
+	Hash(password string) (string, error)
+	// Verify reports whether the password matches the encoded hash.
+	Verify(password, encoded string) (bool, error)
+	// NeedsRehash reports whether the encoded hash should be replaced
+	// because it was produced with another algorithm or outdated parameters.
+	NeedsRehash(encoded string) bool
+}
+
+// HashAlgorithm returns the algorithm identifier of an encoded hash, or "" if unknown.
+func HashAlgorithm(encoded string) string {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return AlgorithmArgon2id
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return AlgorithmBcrypt
+	default:
+		return ""
+	}
+}
+
+// ValidateHash checks that an encoded hash is a bcrypt or Argon2id hash that can be verified.
+// It returns ErrUnsupportedHash for other formats and ErrInvalidHash if the hash cannot be parsed.
+func ValidateHash(encoded string) error {
+	switch HashAlgorithm(encoded) {
+	case AlgorithmBcrypt:
+		if _, err := bcrypt.Cost([]byte(encoded)); err != nil || len(encoded) != 60 {
+			return ErrInvalidHash
+		}
+		return nil
+	case AlgorithmArgon2id:
+		_, _, _, err := decodeArgon2id(encoded)
+		return err
+	default:
+		return ErrUnsupportedHash
+	}
+}
+
+// BcryptMaxPasswordBytes is the longest password bcrypt accepts, in bytes.
+const BcryptMaxPasswordBytes = 72
+
+// BcryptHasher hashes passwords with bcrypt.
+// bcrypt only uses the first BcryptMaxPasswordBytes bytes of a password, so Hash rejects longer
+// passwords with bcrypt.ErrPasswordTooLong; callers limit the length with the password policy.
+type BcryptHasher struct {
+	Cost int
+}
+
+// NewBcryptHasher creates a BcryptHasher, falling back to bcrypt.DefaultCost for invalid costs.
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{Cost: cost}
+}
+
+// Algorithm returns "bcrypt".
+func (h *BcryptHasher) Algorithm() string {
+	return AlgorithmBcrypt
+}
+
+// Hash hashes the password using bcrypt.
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	return string(bytes), err
+}
+
+// Verify checks the password against a bcrypt hash.
+func (h *BcryptHasher) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return false, err
+}
+
+// NeedsRehash reports whether the hash is not bcrypt or uses a different cost.
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	if HashAlgorithm(encoded) != AlgorithmBcrypt {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.Cost
+}
+
+// Argon2Params holds the Argon2id cost parameters.
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follows the OWASP minimum recommendation for Argon2id.
+var DefaultArgon2Params = Argon2Params{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// minArgon2KeyLength is the shortest key accepted in a stored hash. Shorter keys, down to an
+// empty one that every password would match, cannot come from this hasher.
+const minArgon2KeyLength = 16
+
+// Upper bounds of the Argon2id parameters, for configuration and for stored hashes alike,
+// so a tampered or imported hash cannot make Verify allocate gigabytes or run for minutes.
+const (
+	maxArgon2Memory      = 1024 * 1024 // KiB (1 GiB)
+	maxArgon2Iterations  = 64
+	maxArgon2Parallelism = 64
+)
+
+// NewArgon2Params builds Argon2Params from configuration values, rejecting values that are
+// negative or above the limits Verify accepts in a stored hash. Zero values are filled with
+// defaults by NewArgon2idHasher.
+func NewArgon2Params(memory, iterations, parallelism int) (Argon2Params, error) {
+	if memory < 0 || memory > maxArgon2Memory {
+		return Argon2Params{}, fmt.Errorf("argon2 memory out of range: %d", memory)
+	}
+	if iterations < 0 || iterations > maxArgon2Iterations {
+		return Argon2Params{}, fmt.Errorf("argon2 iterations out of range: %d", iterations)
+	}
+	if parallelism < 0 || parallelism > maxArgon2Parallelism {
+		return Argon2Params{}, fmt.Errorf("argon2 parallelism out of range: %d", parallelism)
+	}
+	return Argon2Params{Memory: uint32(memory), Iterations: uint32(iterations), Parallelism: uint8(parallelism)}, nil
+}
+
+// Argon2idHasher hashes passwords with Argon2id and encodes them in PHC string format:
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>
+type Argon2idHasher struct {
+	Params Argon2Params
+}
+
+// NewArgon2idHasher creates an Argon2idHasher, filling zero parameters with defaults.
+func NewArgon2idHasher(params Argon2Params) *Argon2idHasher {
+	if params.Memory == 0 {
+		params.Memory = DefaultArgon2Params.Memory
+	}
+	if params.Iterations == 0 {
+		params.Iterations = DefaultArgon2Params.Iterations
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = DefaultArgon2Params.Parallelism
+	}
+	if params.SaltLength == 0 {
+		params.SaltLength = DefaultArgon2Params.SaltLength
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = DefaultArgon2Params.KeyLength
+	}
+	return &Argon2idHasher{Params: params}
+}
+
+// Algorithm returns "argon2id".
+func (h *Argon2idHasher) Algorithm() string {
+	return AlgorithmArgon2id
+}
+
+// Hash hashes the password using Argon2id with a random salt.
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.Params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Params.Iterations, h.Params.Memory, h.Params.Parallelism, h.Params.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Params.Memory, h.Params.Iterations, h.Params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks the password against an Argon2id PHC string using the parameters stored in it.
+func (h *Argon2idHasher) Verify(password, encoded string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+// NeedsRehash reports whether the hash is not Argon2id or uses different parameters.
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	params, _, _, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return params.Memory != h.Params.Memory ||
+		params.Iterations != h.Params.Iterations ||
+		params.Parallelism != h.Params.Parallelism ||
+		params.SaltLength != h.Params.SaltLength ||
+		params.KeyLength != h.Params.KeyLength
+}
+
+func decodeArgon2id(encoded string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return params, nil, nil, ErrInvalidHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, ErrInvalidHash
+	}
+	if version != argon2.Version {
+		return params, nil, nil, ErrUnsupportedHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrInvalidHash
+	}
+	// p=0 이면 argon2.IDKey 가 panic 하고, 너무 큰 값은 검증 한 번에 메모리와 시간을 과도하게 쓴다
+	if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 ||
+		params.Memory > maxArgon2Memory || params.Iterations > maxArgon2Iterations || params.Parallelism > maxArgon2Parallelism {
+		return params, nil, nil, ErrInvalidHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return params, nil, nil, ErrInvalidHash
+	}
+	// 빈 키는 어떤 비밀번호와도 일치하므로 거부한다
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) < minArgon2KeyLength {
+		return params, nil, nil, ErrInvalidHash
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
+
+// MigratingHasher hashes new passwords with a primary hasher while still verifying
+// hashes produced by legacy hashers. Hashes that are not produced by the primary hasher
+// with its current parameters are reported by NeedsRehash so they can be upgraded on login.
+type MigratingHasher struct {
+	primary PasswordHasher
+	hashers map[string]PasswordHasher
+}
+
+// NewMigratingHasher creates a MigratingHasher from a primary and optional legacy hashers.
+func NewMigratingHasher(primary PasswordHasher, legacy ...PasswordHasher) *MigratingHasher {
+	hashers := make(map[string]PasswordHasher, len(legacy)+1)
+	for _, h := range legacy {
+		hashers[h.Algorithm()] = h
+	}
+	hashers[primary.Algorithm()] = primary
+	return &MigratingHasher{primary: primary, hashers: hashers}
+}
+
+// Algorithm returns the algorithm of the primary hasher.
+func (h *MigratingHasher) Algorithm() string {
+	return h.primary.Algorithm()
+}
+
+// Hash hashes the password with the primary hasher.
+func (h *MigratingHasher) Hash(password string) (string, error) {
+	return h.primary.Hash(password)
+}
+
+// Verify checks the password with the hasher matching the encoded hash's algorithm.
+func (h *MigratingHasher) Verify(password, encoded string) (bool, error) {
+	hasher, ok := h.hashers[HashAlgorithm(encoded)]
+	if !ok {
+		return false, ErrUnsupportedHash
+	}
+	return hasher.Verify(password, encoded)
+}
+
+// NeedsRehash reports whether the encoded hash differs from what the primary hasher produces.
+func (h *MigratingHasher) NeedsRehash(encoded string) bool {
+	return h.primary.NeedsRehash(encoded)
+}
+
+// NewPasswordHasher returns a hasher that hashes with the named algorithm ("argon2id" or "bcrypt")
+// and verifies hashes of both algorithms.
+func NewPasswordHasher(algorithm string, bcryptCost int, argon2Params Argon2Params) (PasswordHasher, error) {
+	bcryptHasher := NewBcryptHasher(bcryptCost)
+	argon2Hasher := NewArgon2idHasher(argon2Params)
+	switch algorithm {
+	case AlgorithmArgon2id, "":
+		return NewMigratingHasher(argon2Hasher, bcryptHasher), nil
+	case AlgorithmBcrypt:
+		return NewMigratingHasher(bcryptHasher, argon2Hasher), nil
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm: %s", algorithm)
+	}
+}
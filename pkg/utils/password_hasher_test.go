@@ -0,0 +1,148 @@
+package utils_test
+
+import (
+	"auth/pkg/utils"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func Test_Argon2idHasher_PHC형식(t *testing.T) {
+	hasher := utils.NewArgon2idHasher(utils.DefaultArgon2Params)
+	encoded, err := hasher.Hash("test1234")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=19456,t=2,p=1$"), encoded)
+
+	ok, err := hasher.Verify("test1234", encoded)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = hasher.Verify("wrong-password", encoded)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func Test_Argon2idHasher_긴비밀번호(t *testing.T) {
+	hasher := utils.NewArgon2idHasher(utils.DefaultArgon2Params)
+	long := strings.Repeat("a", 100)
+	encoded, err := hasher.Hash(long)
+	assert.Nil(t, err)
+
+	// bcrypt 라면 72바이트 이후가 무시되지만 Argon2id 는 전체를 사용해야 한다
+	ok, err := hasher.Verify(strings.Repeat("a", 72)+strings.Repeat("b", 28), encoded)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func Test_Argon2idHasher_NeedsRehash(t *testing.T) {
+	weak := utils.NewArgon2idHasher(utils.Argon2Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1})
+	encoded, err := weak.Hash("test1234")
+	assert.Nil(t, err)
+
+	strong := utils.NewArgon2idHasher(utils.DefaultArgon2Params)
+	assert.True(t, strong.NeedsRehash(encoded))
+	assert.False(t, weak.NeedsRehash(encoded))
+
+	// 파라미터가 달라도 저장된 파라미터로 검증되어야 한다
+	ok, err := strong.Verify("test1234", encoded)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func Test_BcryptHasher_NeedsRehash(t *testing.T) {
+	hasher := utils.NewBcryptHasher(bcrypt.MinCost)
+	encoded, err := hasher.Hash("test1234")
+	assert.Nil(t, err)
+	assert.Equal(t, utils.AlgorithmBcrypt, utils.HashAlgorithm(encoded))
+	assert.False(t, hasher.NeedsRehash(encoded))
+	assert.True(t, utils.NewBcryptHasher(bcrypt.MinCost+1).NeedsRehash(encoded))
+}
+
+func Test_MigratingHasher_bcrypt에서Argon2id로(t *testing.T) {
+	legacy := utils.NewBcryptHasher(bcrypt.MinCost)
+	legacyHash, err := legacy.Hash("test1234")
+	assert.Nil(t, err)
+
+	hasher, err := utils.NewPasswordHasher(utils.AlgorithmArgon2id, bcrypt.MinCost, utils.DefaultArgon2Params)
+	assert.Nil(t, err)
+
+	ok, err := hasher.Verify("test1234", legacyHash)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.True(t, hasher.NeedsRehash(legacyHash))
+
+	upgraded, err := hasher.Hash("test1234")
+	assert.Nil(t, err)
+	assert.Equal(t, utils.AlgorithmArgon2id, utils.HashAlgorithm(upgraded))
+	assert.False(t, hasher.NeedsRehash(upgraded))
+}
+
+func Test_MigratingHasher_알수없는형식(t *testing.T) {
+	hasher, err := utils.NewPasswordHasher(utils.AlgorithmArgon2id, bcrypt.MinCost, utils.DefaultArgon2Params)
+	assert.Nil(t, err)
+
+	_, err = hasher.Verify("test1234", "plaintext")
+	assert.ErrorIs(t, err, utils.ErrUnsupportedHash)
+
+	_, err = utils.NewPasswordHasher("md5", bcrypt.MinCost, utils.DefaultArgon2Params)
+	assert.NotNil(t, err)
+}
+
+func Test_Argon2idHasher_잘못된파라미터(t *testing.T) {
+	hasher := utils.NewArgon2idHasher(utils.DefaultArgon2Params)
+	encoded, err := hasher.Hash("test1234")
+	assert.Nil(t, err)
+	parts := strings.Split(encoded, "$")
+
+	cases := map[string]string{
+		"p=0":    strings.Join([]string{"", "argon2id", "v=19", "m=19456,t=2,p=0", parts[4], parts[5]}, "$"),
+		"t=0":    strings.Join([]string{"", "argon2id", "v=19", "m=19456,t=0,p=1", parts[4], parts[5]}, "$"),
+		"m=0":    strings.Join([]string{"", "argon2id", "v=19", "m=0,t=2,p=1", parts[4], parts[5]}, "$"),
+		"빈 salt": strings.Join([]string{"", "argon2id", "v=19", "m=19456,t=2,p=1", "", parts[5]}, "$"),
+		// 빈 키는 어떤 비밀번호와도 일치하게 된다
+		"빈 키":  strings.Join([]string{"", "argon2id", "v=19", "m=19456,t=2,p=1", parts[4], ""}, "$"),
+		"짧은 키": strings.Join([]string{"", "argon2id", "v=19", "m=19456,t=2,p=1", parts[4], parts[5][:10]}, "$"),
+		// 저장된 해시가 검증에 수 GB 메모리나 긴 시간을 쓰게 만들 수 없다
+		"큰 m": strings.Join([]string{"", "argon2id", "v=19", "m=4194304,t=2,p=1", parts[4], parts[5]}, "$"),
+		"큰 t": strings.Join([]string{"", "argon2id", "v=19", "m=19456,t=100000,p=1", parts[4], parts[5]}, "$"),
+		"큰 p": strings.Join([]string{"", "argon2id", "v=19", "m=19456,t=2,p=255", parts[4], parts[5]}, "$"),
+	}
+	for name, bad := range cases {
+		assert.NotPanics(t, func() {
+			ok, err := hasher.Verify("anything", bad)
+			assert.ErrorIs(t, err, utils.ErrInvalidHash, name)
+			assert.False(t, ok, name)
+		}, name)
+	}
+}
+
+func Test_NewArgon2Params_범위검사(t *testing.T) {
+	params, err := utils.NewArgon2Params(64*1024, 3, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, utils.Argon2Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2}, params)
+
+	_, err = utils.NewArgon2Params(-1, 2, 1)
+	assert.NotNil(t, err)
+	_, err = utils.NewArgon2Params(19*1024, -1, 1)
+	assert.NotNil(t, err)
+	_, err = utils.NewArgon2Params(19*1024, 2, 256)
+	assert.NotNil(t, err, "uint8 로 잘리면 안 된다")
+	_, err = utils.NewArgon2Params(4*1024*1024, 2, 1)
+	assert.NotNil(t, err, "저장된 해시로 받지 않는 값은 설정할 수 없다")
+}
+
+func Test_ValidateHash(t *testing.T) {
+	argon2Hash, err := utils.NewArgon2idHasher(utils.DefaultArgon2Params).Hash("test1234")
+	assert.Nil(t, err)
+	bcryptHash, err := utils.NewBcryptHasher(bcrypt.MinCost).Hash("test1234")
+	assert.Nil(t, err)
+
+	assert.Nil(t, utils.ValidateHash(argon2Hash))
+	assert.Nil(t, utils.ValidateHash(bcryptHash))
+	assert.ErrorIs(t, utils.ValidateHash(""), utils.ErrUnsupportedHash)
+	assert.ErrorIs(t, utils.ValidateHash("plain-text"), utils.ErrUnsupportedHash)
+	assert.ErrorIs(t, utils.ValidateHash(bcryptHash[:30]), utils.ErrInvalidHash)
+	assert.ErrorIs(t, utils.ValidateHash(strings.Replace(argon2Hash, "p=1", "p=0", 1)), utils.ErrInvalidHash)
+}
@@ -4,6 +4,7 @@ package utils
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"math/big"
 )
 
 // GenerateRandomString generates a random string of length n.
@@ -15,3 +16,16 @@ func GenerateRandomString(n int) string {
 	}
 	return hex.EncodeToString(b)[:n]
 }
+
+// GenerateNumericCode generates a random code of n decimal digits, e.g. for SMS verification.
+func GenerateNumericCode(n int) (string, error) {
+	code := make([]byte, n)
+	for i := range code {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + d.Int64())
+	}
+	return string(code), nil
+}
@@ -29,3 +29,18 @@ func TestGenerateRandomString_ZeroLength(t *testing.T) {
 		t.Errorf("expected empty string, got: %s", s)
 	}
 }
+
+func TestGenerateNumericCode(t *testing.T) {
+	code, err := utils.GenerateNumericCode(6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(code) != 6 {
+		t.Errorf("expected length 6, got %d", len(code))
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			t.Errorf("expected digits only, got: %s", code)
+		}
+	}
+}
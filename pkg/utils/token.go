@@ -0,0 +1,14 @@
+// Package utils provides utility functions for the authentication service.
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashToken returns the hex encoded SHA-256 hash of a token, for storing tokens
+// without keeping the usable value in the database.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
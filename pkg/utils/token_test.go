@@ -0,0 +1,14 @@
+package utils_test
+
+import (
+	"auth/pkg/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashToken(t *testing.T) {
+	h := utils.HashToken("abc")
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", h)
+	assert.NotEqual(t, h, utils.HashToken("abd"))
+}